@@ -0,0 +1,161 @@
+package jsonschema
+
+import (
+	"errors"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BatchOption is an option of ValidateBatch.
+type BatchOption func(b *batch)
+
+type batch struct {
+	concurrency int
+	top         int
+}
+
+// Concurrency sets the number of workers which validate documents concurrently.
+// The default is runtime.GOMAXPROCS(0).
+func Concurrency(n int) BatchOption {
+	return func(b *batch) {
+		b.concurrency = n
+	}
+}
+
+// TopFailures sets the number of keywords and paths in reports of ValidateBatch.
+// The default is 10 and a negative number reports all of them.
+func TopFailures(n int) BatchOption {
+	return func(b *batch) {
+		b.top = n
+	}
+}
+
+// BatchReport is an aggregated report of documents which are validated by ValidateBatch.
+type BatchReport struct {
+	// Total is the number of documents.
+	Total int
+	// Valid is the number of valid documents.
+	Valid int
+	// Invalid is the number of documents which are not valid against the schema.
+	Invalid int
+	// Malformed is the number of documents which cannot be validated such as broken JSON.
+	Malformed int
+	// Keywords are keywords of errors such as "required" with the numbers of invalid documents
+	// which have them in descending order of the numbers.
+	Keywords []BatchCount
+	// Paths are JSON Pointers of errors with the numbers of invalid documents
+	// which have errors at them in descending order of the numbers.
+	// Tokens of digits such as indexes of arrays are replaced with "*" such as "/items/*/name",
+	// so errors of elements are aggregated.
+	Paths []BatchCount
+}
+
+// BatchCount is the number of documents which have errors of a keyword or a path.
+type BatchCount struct {
+	Name  string
+	Count int
+}
+
+// ValidateBatch validates documents which are received from docs concurrently until docs is closed
+// and returns the aggregated report, which is suited for audits of data quality over dumps of payloads.
+// Each document is counted once by a keyword or a path even if it has many errors of it.
+// If the schema cannot validate documents because of an error of compilation,
+// the error is returned without receiving documents.
+func ValidateBatch(s *Schema, docs <-chan []byte, opts ...BatchOption) (*BatchReport, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	b := &batch{concurrency: runtime.GOMAXPROCS(0), top: 10}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.concurrency < 1 {
+		b.concurrency = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		r        BatchReport
+		keywords = map[string]int{}
+		paths    = map[string]int{}
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < b.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for doc := range docs {
+				err := s.Validate(doc)
+				var verr *ValidationError
+				invalid := errors.As(err, &verr)
+
+				mu.Lock()
+				r.Total++
+				switch {
+				case err == nil:
+					r.Valid++
+				case invalid:
+					r.Invalid++
+					countOnce(keywords, verr.Errors, func(fe FieldError) string { return fe.Keyword })
+					countOnce(paths, verr.Errors, func(fe FieldError) string { return batchPath(fe.Pointer) })
+				default:
+					r.Malformed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	r.Keywords = topCounts(keywords, b.top)
+	r.Paths = topCounts(paths, b.top)
+	return &r, nil
+}
+
+// countOnce increments counts of names of the errors once for each name.
+func countOnce(counts map[string]int, errs []FieldError, name func(fe FieldError) string) {
+	seen := make(map[string]bool, len(errs))
+	for _, fe := range errs {
+		n := name(fe)
+		if !seen[n] {
+			seen[n] = true
+			counts[n]++
+		}
+	}
+}
+
+// batchPath replaces tokens of digits of the pointer with "*".
+func batchPath(ptr string) string {
+	if ptr == "" {
+		return ""
+	}
+	tokens := strings.Split(ptr, "/")
+	for i, t := range tokens {
+		if i != 0 && t != "" && strings.Trim(t, "0123456789") == "" {
+			tokens[i] = "*"
+		}
+	}
+	return strings.Join(tokens, "/")
+}
+
+// topCounts returns the top n counts in descending order of counts and ascending order of names.
+// If n is negative, all of them are returned.
+func topCounts(counts map[string]int, n int) []BatchCount {
+	cs := make([]BatchCount, 0, len(counts))
+	for name, c := range counts {
+		cs = append(cs, BatchCount{Name: name, Count: c})
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Count != cs[j].Count {
+			return cs[i].Count > cs[j].Count
+		}
+		return cs[i].Name < cs[j].Name
+	})
+	if n >= 0 && len(cs) > n {
+		cs = cs[:n]
+	}
+	return cs
+}
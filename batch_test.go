@@ -0,0 +1,72 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestValidateBatch(t *testing.T) {
+	type Item struct {
+		Name string `json:"name" jsonschema:"minLength=1"`
+	}
+	type Order struct {
+		ID    string `json:"id"`
+		Items []Item `json:"items"`
+	}
+	schema, err := GenerateBytes(Order{Items: []Item{{}}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	s, err := CompileBytes(schema)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	docs := []string{
+		`{"id": "1", "items": [{"name": "a"}]}`,
+		`{"id": "2", "items": []}`,
+		`{"items": [{"name": ""}, {"name": ""}]}`,
+		`{"id": "4", "items": [{"name": "a"}, {"name": ""}]}`,
+		`{"items": [{}]}`,
+		`{"id": `,
+	}
+
+	for _, n := range []int{1, 4} {
+		ch := make(chan []byte)
+		go func() {
+			defer close(ch)
+			for _, doc := range docs {
+				ch <- []byte(doc)
+			}
+		}()
+
+		got, err := ValidateBatch(s, ch, Concurrency(n), TopFailures(2))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		expect := &BatchReport{
+			Total:     6,
+			Valid:     2,
+			Invalid:   3,
+			Malformed: 1,
+			Keywords:  []BatchCount{{"required", 2}, {"string_gte", 2}},
+			Paths:     []BatchCount{{"", 2}, {"/items/*/name", 2}},
+		}
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("Concurrency(%d): want %+v but got %+v", n, expect, got)
+		}
+	}
+}
+
+func TestValidateBatch_compileError(t *testing.T) {
+	s, err := CompileBytes([]byte(`{"type": "array", "items": [{"type": "string"}]}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	// the channel is never received
+	if _, err := ValidateBatch(s.Items(), make(chan []byte)); err == nil {
+		t.Error("want an error of the schema")
+	}
+}
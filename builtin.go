@@ -0,0 +1,211 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// HTTPProblem is a problem details object of RFC 7807 (application/problem+json).
+// It implements Generator and generates the standard schema of the object,
+// so it can be used as a field of other structs.
+type HTTPProblem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// JSONSchema implements Generator.
+func (HTTPProblem) JSONSchema(w io.Writer, opts ...Option) error {
+	return GenerateHTTPProblemSchema(w, opts...)
+}
+
+// GenerateHTTPProblemSchema generates a schema of a problem details object of RFC 7807.
+// The options are applied to the root object of the schema.
+func GenerateHTTPProblemSchema(w io.Writer, opts ...Option) error {
+	return writeBuiltin(w, map[string]interface{}{
+		"title":       "Problem Details",
+		"description": "A problem details object of RFC 7807",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{
+				"type":    "string",
+				"format":  "uri-reference",
+				"default": "about:blank",
+			},
+			"title": map[string]interface{}{
+				"type": "string",
+			},
+			"status": map[string]interface{}{
+				"type":    "integer",
+				"minimum": 100,
+				"maximum": 599,
+			},
+			"detail": map[string]interface{}{
+				"type": "string",
+			},
+			"instance": map[string]interface{}{
+				"type":   "string",
+				"format": "uri-reference",
+			},
+		},
+	}, opts)
+}
+
+// JSONAPIError is an error object of JSON:API.
+// It implements Generator and generates the standard schema of the object.
+type JSONAPIError struct {
+	ID     string                 `json:"id,omitempty"`
+	Links  map[string]string      `json:"links,omitempty"`
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *JSONAPIErrorSource    `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIErrorSource is a source of JSONAPIError.
+type JSONAPIErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
+
+// JSONSchema implements Generator.
+func (JSONAPIError) JSONSchema(w io.Writer, opts ...Option) error {
+	return GenerateJSONAPIErrorSchema(w, opts...)
+}
+
+// GenerateJSONAPIErrorSchema generates a schema of an error object of JSON:API.
+// The options are applied to the root object of the schema.
+func GenerateJSONAPIErrorSchema(w io.Writer, opts ...Option) error {
+	return writeBuiltin(w, jsonAPIErrorSchema(), opts)
+}
+
+func jsonAPIErrorSchema() map[string]interface{} {
+	str := func() map[string]interface{} {
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{
+		"title":       "JSON:API Error",
+		"description": "An error object of JSON:API",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"id": str(),
+			"links": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"about": str(),
+					"type":  str(),
+				},
+			},
+			"status": map[string]interface{}{
+				"type":    "string",
+				"pattern": "^[1-5][0-9]{2}$",
+			},
+			"code":   str(),
+			"title":  str(),
+			"detail": str(),
+			"source": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"pointer": map[string]interface{}{
+						"type":   "string",
+						"format": "json-pointer",
+					},
+					"parameter": str(),
+					"header":    str(),
+				},
+			},
+			"meta": map[string]interface{}{
+				"type": "object",
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+// JSONPatch is a JSON Patch document of RFC 6902.
+// It implements Generator and generates the standard schema of the document.
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchOperation is an operation of JSONPatch.
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// JSONSchema implements Generator.
+func (JSONPatch) JSONSchema(w io.Writer, opts ...Option) error {
+	return GenerateJSONPatchSchema(w, opts...)
+}
+
+// GenerateJSONPatchSchema generates a schema of a JSON Patch document of RFC 6902.
+// The options are applied to the root object of the schema.
+func GenerateJSONPatchSchema(w io.Writer, opts ...Option) error {
+	pointer := map[string]interface{}{
+		"type":   "string",
+		"format": "json-pointer",
+	}
+
+	operation := func(required []string, ops ...string) map[string]interface{} {
+		enum := make([]interface{}, len(ops))
+		for i := range ops {
+			enum[i] = ops[i]
+		}
+
+		properties := map[string]interface{}{
+			"op":   map[string]interface{}{"enum": enum},
+			"path": pointer,
+		}
+		for _, r := range required {
+			switch r {
+			case "from":
+				properties["from"] = pointer
+			case "value":
+				properties["value"] = map[string]interface{}{}
+			}
+		}
+
+		return map[string]interface{}{
+			"type":       "object",
+			"required":   append([]string{"op", "path"}, required...),
+			"properties": properties,
+		}
+	}
+
+	return writeBuiltin(w, map[string]interface{}{
+		"title":       "JSON Patch",
+		"description": "A JSON Patch document of RFC 6902",
+		"type":        "array",
+		"items": map[string]interface{}{
+			"oneOf": []interface{}{
+				operation([]string{"value"}, "add", "replace", "test"),
+				operation(nil, "remove"),
+				operation([]string{"from"}, "move", "copy"),
+			},
+		},
+	}, opts)
+}
+
+// writeBuiltin applies the options to the root object of a built-in schema and writes it.
+func writeBuiltin(w io.Writer, m map[string]interface{}, opts []Option) error {
+	var o Object = &obj{
+		m:    m,
+		ref:  newConfig(opts).refs().Root(),
+		root: true,
+	}
+
+	for _, opt := range opts {
+		var err error
+		if o, err = opt(o); err != nil {
+			return err
+		}
+	}
+
+	return json.NewEncoder(w).Encode(m)
+}
@@ -0,0 +1,114 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestBuiltin(t *testing.T) {
+	cases := []struct {
+		name  string
+		gen   func(w io.Writer, opts ...Option) error
+		valid []string
+		inval []string
+	}{
+		{
+			name: "HTTP problem",
+			gen:  GenerateHTTPProblemSchema,
+			valid: []string{
+				`{}`,
+				`{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403,"detail":"Your current balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","balance":30}`,
+			},
+			inval: []string{
+				`{"status":"403"}`,
+				`{"status":999}`,
+			},
+		},
+		{
+			name: "JSON:API error",
+			gen:  GenerateJSONAPIErrorSchema,
+			valid: []string{
+				`{"status":"422","source":{"pointer":"/data/attributes/firstName"},"title":"Invalid Attribute"}`,
+			},
+			inval: []string{
+				`{"status":422}`,
+				`{"unknown":"member"}`,
+			},
+		},
+		{
+			name: "JSON Patch",
+			gen:  GenerateJSONPatchSchema,
+			valid: []string{
+				`[]`,
+				`[{"op":"add","path":"/a","value":1},{"op":"remove","path":"/b"},{"op":"move","from":"/c","path":"/d"}]`,
+			},
+			inval: []string{
+				`[{"op":"add","path":"/a"}]`,
+				`[{"op":"copy","path":"/a"}]`,
+				`[{"op":"unknown","path":"/a"}]`,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.gen(&buf, describeRef); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(buf.Bytes()))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			validate := func(doc string, want bool) {
+				r, err := s.Validate(gojsonschema.NewStringLoader(doc))
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if r.Valid() != want {
+					t.Errorf("valid of %s = %v, want %v", doc, r.Valid(), want)
+				}
+			}
+
+			for _, doc := range tt.valid {
+				validate(doc, true)
+			}
+			for _, doc := range tt.inval {
+				validate(doc, false)
+			}
+		})
+	}
+}
+
+func TestBuiltin_field(t *testing.T) {
+	type Response struct {
+		Error *HTTPProblem `json:"error"`
+		Patch JSONPatch    `json:"patch"`
+	}
+
+	got, err := GenerateString(Response{Error: &HTTPProblem{}, Patch: JSONPatch{}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	doc := `{"error":{"status":404},"patch":[{"op":"remove","path":"/a"}]}`
+	r, err := s.Validate(gojsonschema.NewStringLoader(doc))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !r.Valid() {
+		t.Errorf("%s must be valid: %v", doc, r.Errors())
+	}
+}
@@ -0,0 +1,297 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// GenerateAll generates a bundle of schemas of the values into w.
+// The bundle is a schema whose $defs have a schema of each value by the name of its type,
+// so other documents can refer to them such as "bundle.json#/$defs/User".
+// Definitions of each schema such as hoisted structs are also merged into $defs.
+//
+// The options are applied to all values and TypeOptions gives additional options
+// to values of specific types, e.g. strict options for some types and permissive ones for others.
+func GenerateAll(w io.Writer, values []interface{}, opts ...Option) error {
+	b, _, err := bundleValues(values, opts)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(b.doc())
+}
+
+// GenerateCatalog generates a catalog of schemas of the values into w,
+// which validates a stream of heterogeneous documents such as events against one schema.
+// Schemas of the values are put into $defs by the names of their types as GenerateAll
+// and the root is oneOf of references to them. Each subschema of oneOf requires
+// the discriminator property whose value is a const of the name of the type such as
+//
+//	{
+//		"oneOf": [
+//			{"allOf": [{"$ref": "#/$defs/UserCreated"}], "properties": {"type": {"const": "UserCreated"}}, "required": ["type"]},
+//			{"allOf": [{"$ref": "#/$defs/UserDeleted"}], "properties": {"type": {"const": "UserDeleted"}}, "required": ["type"]}
+//		],
+//		"$defs": {"UserCreated": {...}, "UserDeleted": {...}}
+//	}
+//
+// The discriminator can be a field of the types. If it is empty, oneOf only has the references.
+func GenerateCatalog(w io.Writer, values []interface{}, discriminator string, opts ...Option) error {
+	b, names, err := bundleValues(values, opts)
+	if err != nil {
+		return err
+	}
+
+	oneOf := make([]interface{}, len(names))
+	for i, name := range names {
+		o := &obj{m: map[string]interface{}{"$ref": "#/$defs/" + escapePointer(name)}}
+		if discriminator != "" {
+			setDiscriminator(o, discriminator, name)
+		}
+		oneOf[i] = o.m
+	}
+
+	doc := b.doc()
+	doc["oneOf"] = oneOf
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// bundleValues generates schemas of the values into a bundle
+// and returns names of the values in order without duplicates.
+func bundleValues(values []interface{}, opts []Option) (bundle, []string, error) {
+	c := newConfig(opts)
+	b := bundle{}
+	var names []string
+	added := map[string]bool{}
+	for _, v := range values {
+		t, err := bundleType(v)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		vopts := append(append([]Option{}, opts...), c.typeOptions[t]...)
+		schema, err := GenerateBytes(v, vopts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		name := typeName(t)
+		if err := b.add(name, schema); err != nil {
+			return nil, nil, err
+		}
+		if !added[name] {
+			added[name] = true
+			names = append(names, name)
+		}
+	}
+	return b, names, nil
+}
+
+// bundleType returns the named type of v which is bundled by its name.
+func bundleType(v interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, newError(ErrUnsupportedType, RefRoot, fmt.Errorf("nil cannot be bundled"))
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return nil, newError(ErrUnsupportedType, RefRoot, fmt.Errorf("unnamed type %s cannot be bundled", t))
+	}
+	return t, nil
+}
+
+// TypeOptions gives the options to values of the type of v in GenerateAll.
+// They are applied after options which are given to GenerateAll,
+// so they can override settings of the generator.
+func TypeOptions(v interface{}, opts ...Option) Option {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return configOption(func(c *config) {
+		if c.typeOptions == nil {
+			c.typeOptions = map[reflect.Type][]Option{}
+		}
+		c.typeOptions[t] = append(c.typeOptions[t], opts...)
+	})
+}
+
+// GenerateAll generates a bundle of all registered schemas into w as same as GenerateAll.
+// Each schema is generated with the options which are given at the registration
+// and it is put into $defs by its key such as "User@v1".
+func (r *Registry) GenerateAll(w io.Writer) error {
+	b := bundle{}
+	for _, k := range r.List() {
+		schema, ok := r.Get(k.Name, k.Version)
+		if !ok {
+			continue
+		}
+		if err := b.add(k.String(), schema); err != nil {
+			return err
+		}
+	}
+	return json.NewEncoder(w).Encode(b.doc())
+}
+
+// Bundle is a bundle of schemas of multiple root types such as
+//
+//	b := jsonschema.NewBundle()
+//	b.Add("User", User{})
+//	b.Add("Order", Order{})
+//	err := b.Generate(w)
+//
+// Named struct types are put into $defs and referred by $ref as SharedTypesRef,
+// so types which are shared by the roots such as Address are defined only once.
+// Shared definitions are named by their types and the roots are named by Add.
+type Bundle struct {
+	opts    []Option
+	entries []bundleEntry
+}
+
+type bundleEntry struct {
+	name string
+	v    interface{}
+	opts []Option
+}
+
+// NewBundle creates an empty bundle whose schemas are generated with the options.
+// TypeOptions gives additional options to values of specific types as GenerateAll.
+func NewBundle(opts ...Option) *Bundle {
+	return &Bundle{opts: opts}
+}
+
+// Add adds the value to the bundle as the root schema of the name.
+// The options are applied after options of the bundle.
+// It returns an error which matches ErrNameCollision if the name has been added.
+func (b *Bundle) Add(name string, v interface{}, opts ...Option) error {
+	ref := "#/$defs/" + escapePointer(name)
+	if v == nil {
+		return newError(ErrUnsupportedType, ref, fmt.Errorf("nil cannot be bundled"))
+	}
+	for _, e := range b.entries {
+		if e.name == name {
+			return newError(ErrNameCollision, ref, fmt.Errorf("%s has been added", name))
+		}
+	}
+	b.entries = append(b.entries, bundleEntry{name: name, v: v, opts: opts})
+	return nil
+}
+
+// Generate generates a document of the bundle into w whose $defs have schemas of the roots
+// and their shared definitions, so other documents can refer to them such as "bundle.json#/$defs/User".
+func (b *Bundle) Generate(w io.Writer) error {
+	bd, err := b.bundle()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(bd.doc())
+}
+
+// WriteFiles writes each schema of the bundle into its own file of the directory such as "User.json"
+// and returns names of the written files.
+// Shared definitions are also written into their files and
+// references to them are rewritten into relative references such as "Address.json".
+func (b *Bundle) WriteFiles(dir string) ([]string, error) {
+	bd, err := b.bundle()
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]map[string]interface{}{}
+	for name, def := range bd {
+		d, ok := def.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		file, err := defFile(name)
+		if err != nil {
+			return nil, err
+		}
+		rewriteRefs(d, TreeOptions{Refs: RefRelative}.treeRef)
+		files[file] = d
+	}
+	return writeFiles(dir, files, "")
+}
+
+// bundle generates schemas of the roots into definitions.
+func (b *Bundle) bundle() (bundle, error) {
+	c := newConfig(b.opts)
+	bd := bundle{}
+	for _, e := range b.entries {
+		t := reflect.TypeOf(e.v)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		opts := append([]Option{SharedTypes(SharedTypesRef)}, b.opts...)
+		opts = append(append(opts, c.typeOptions[t]...), e.opts...)
+		schema, err := GenerateBytes(e.v, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if err := bd.add(e.name, schema); err != nil {
+			return nil, err
+		}
+	}
+	return bd, nil
+}
+
+// bundle is definitions of a bundle of schemas.
+type bundle map[string]interface{}
+
+// add adds the schema and its definitions to the bundle.
+// References in the schema are rewritten to refer to the schema in $defs.
+func (b bundle) add(name string, schema []byte) error {
+	v, err := decodeJSON(schema)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot bundle %s: %w", name, err)
+	}
+	doc, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("jsonschema: cannot bundle %s: schema must be an object", name)
+	}
+
+	prefix := "#/$defs/" + escapePointer(name)
+	rewriteRefs(doc, func(ref string) string {
+		switch {
+		case ref == "#" || ref == RefRoot:
+			return prefix
+		case strings.HasPrefix(ref, "#/") && !strings.HasPrefix(ref, "#/$defs/"):
+			return prefix + strings.TrimPrefix(ref, "#")
+		}
+		return ref
+	})
+
+	// definitions are shared by all schemas in the bundle
+	defs, _ := doc["$defs"].(map[string]interface{})
+	delete(doc, "$defs")
+	if err := b.set(name, doc); err != nil {
+		return err
+	}
+	for _, k := range sortedKeys(defs) {
+		if err := b.set(k, defs[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set sets the definition. The same name can be used only by the same schemas.
+func (b bundle) set(name string, s interface{}) error {
+	if cur, ok := b[name]; ok {
+		if !reflect.DeepEqual(cur, s) {
+			return &Error{Kind: ErrNameCollision, Ref: "#/$defs/" + escapePointer(name), Err: fmt.Errorf("%s is defined by different schemas", name)}
+		}
+		return nil
+	}
+	b[name] = s
+	return nil
+}
+
+func (b bundle) doc() map[string]interface{} {
+	return map[string]interface{}{"$defs": map[string]interface{}(b)}
+}
@@ -0,0 +1,403 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type bundleUser struct {
+	Name string `json:"name"`
+	Home struct {
+		City string `json:"city"`
+	} `json:"home"`
+}
+
+type bundleItem struct {
+	ItemName string `json:"item_name"`
+}
+
+type bundleAddress struct {
+	City string `json:"city"`
+}
+
+type bundleCustomer struct {
+	Name    string        `json:"name"`
+	Address bundleAddress `json:"address"`
+}
+
+type bundleOrder struct {
+	Customer bundleCustomer `json:"customer"`
+	ShipTo   bundleAddress  `json:"ship_to"`
+}
+
+func TestGenerateAll(t *testing.T) {
+	var buf bytes.Buffer
+	err := GenerateAll(&buf, []interface{}{bundleUser{}, &bundleItem{}},
+		HoistAnonymousStructs(),
+		TypeOptions(bundleItem{}, PropertyTitles()),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"$defs": {
+			"bundleUser": {
+				"type": "object",
+				"title": "bundleUser",
+				"required": ["name", "home"],
+				"properties": {
+					"name": {"type": "string", "propertyOrder": 0},
+					"home": {"$ref": "#/$defs/bundleUser_Home", "propertyOrder": 1}
+				}
+			},
+			"bundleUser_Home": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {
+					"city": {"type": "string", "propertyOrder": 0}
+				}
+			},
+			"bundleItem": {
+				"type": "object",
+				"title": "bundleItem",
+				"required": ["item_name"],
+				"properties": {
+					"item_name": {"type": "string", "title": "Item Name", "propertyOrder": 0}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated bundle does not match to expected one: %v", diff)
+	}
+}
+
+type catalogCreated struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type catalogDeleted struct {
+	ID string `json:"id"`
+}
+
+func TestGenerateCatalog(t *testing.T) {
+	defs := `"$defs": {
+		"catalogCreated": {
+			"type": "object",
+			"title": "catalogCreated",
+			"required": ["type", "id"],
+			"properties": {
+				"type": {"type": "string"},
+				"id": {"type": "string"}
+			}
+		},
+		"catalogDeleted": {
+			"type": "object",
+			"title": "catalogDeleted",
+			"required": ["id"],
+			"properties": {
+				"id": {"type": "string"}
+			}
+		}
+	}`
+
+	cases := []struct {
+		name          string
+		values        []interface{}
+		discriminator string
+		expect        string
+	}{
+		{"discriminator", []interface{}{catalogCreated{}, &catalogDeleted{}}, "type", `{
+			"oneOf": [
+				{"allOf": [{"$ref": "#/$defs/catalogCreated"}], "properties": {"type": {"const": "catalogCreated"}}, "required": ["type"]},
+				{"allOf": [{"$ref": "#/$defs/catalogDeleted"}], "properties": {"type": {"const": "catalogDeleted"}}, "required": ["type"]}
+			],
+			` + defs + `
+		}`},
+		{"no discriminator", []interface{}{catalogCreated{}, catalogDeleted{}}, "", `{
+			"oneOf": [
+				{"$ref": "#/$defs/catalogCreated"},
+				{"$ref": "#/$defs/catalogDeleted"}
+			],
+			` + defs + `
+		}`},
+		{"duplicated", []interface{}{catalogCreated{}, catalogDeleted{}, &catalogCreated{}}, "", `{
+			"oneOf": [
+				{"$ref": "#/$defs/catalogCreated"},
+				{"$ref": "#/$defs/catalogDeleted"}
+			],
+			` + defs + `
+		}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateCatalog(&buf, tt.values, tt.discriminator, MapKeywords(KeywordRenames{"propertyOrder": ""}))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, buf.String(), tt.expect); diff != "" {
+				t.Errorf("generated catalog does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateCatalog_validate(t *testing.T) {
+	var buf bytes.Buffer
+	err := GenerateCatalog(&buf, []interface{}{catalogCreated{}, catalogDeleted{}}, "type")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	s, err := CompileBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		doc   string
+		valid bool
+	}{
+		{`{"type": "catalogCreated", "id": "1"}`, true},
+		{`{"type": "catalogDeleted", "id": "1"}`, true},
+		{`{"type": "catalogUpdated", "id": "1"}`, false},
+		{`{"id": "1"}`, false},
+	}
+
+	for _, tt := range cases {
+		err := s.Validate([]byte(tt.doc))
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: want valid %v but got %v", tt.doc, tt.valid, err)
+		}
+	}
+}
+
+func TestGenerateCatalog_errors(t *testing.T) {
+	var buf bytes.Buffer
+	err := GenerateCatalog(&buf, []interface{}{struct{ A string }{}}, "type")
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want %v but got %v", ErrUnsupportedType, err)
+	}
+}
+
+func TestGenerateAll_errors(t *testing.T) {
+	t1 := func() interface{} {
+		type T struct{ A string }
+		return T{}
+	}()
+	t2 := func() interface{} {
+		type T struct{ B int }
+		return T{}
+	}()
+
+	cases := []struct {
+		name   string
+		values []interface{}
+		kind   error
+	}{
+		{"collision", []interface{}{t1, t2}, ErrNameCollision},
+		{"unnamed", []interface{}{struct{ A string }{}}, ErrUnsupportedType},
+		{"nil", []interface{}{nil}, ErrUnsupportedType},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateAll(&buf, tt.values)
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_GenerateAll(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register("Item", "v1", bundleItem{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := r.Register("Item", "v2", bundleItem{}, PropertyTitles()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.GenerateAll(&buf); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"$defs": {
+			"Item@v1": {
+				"type": "object",
+				"title": "bundleItem",
+				"required": ["item_name"],
+				"properties": {
+					"item_name": {"type": "string", "propertyOrder": 0}
+				}
+			},
+			"Item@v2": {
+				"type": "object",
+				"title": "bundleItem",
+				"required": ["item_name"],
+				"properties": {
+					"item_name": {"type": "string", "title": "Item Name", "propertyOrder": 0}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated bundle does not match to expected one: %v", diff)
+	}
+}
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b := NewBundle()
+	if err := b.Add("bundleCustomer", bundleCustomer{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := b.Add("Order", &bundleOrder{}, ByReference("#/properties/ship_to", func(o Object) (Object, error) {
+		o.Set("description", "shipping address")
+		return o, nil
+	})); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	return b
+}
+
+func TestBundle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestBundle(t).Generate(&buf); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"$defs": {
+			"bundleCustomer": {
+				"type": "object",
+				"title": "bundleCustomer",
+				"required": ["name", "address"],
+				"properties": {
+					"name": {"type": "string", "propertyOrder": 0},
+					"address": {"$ref": "#/$defs/bundleAddress", "propertyOrder": 1}
+				}
+			},
+			"bundleAddress": {
+				"type": "object",
+				"title": "bundleAddress",
+				"required": ["city"],
+				"properties": {
+					"city": {"type": "string", "propertyOrder": 0}
+				}
+			},
+			"Order": {
+				"type": "object",
+				"title": "bundleOrder",
+				"required": ["customer", "ship_to"],
+				"properties": {
+					"customer": {"$ref": "#/$defs/bundleCustomer", "propertyOrder": 0},
+					"ship_to": {"$ref": "#/$defs/bundleAddress", "description": "shipping address", "propertyOrder": 1}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated bundle does not match to expected one: %v", diff)
+	}
+}
+
+func TestBundle_WriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	files, err := newTestBundle(t).WriteFiles(dir)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if expect := []string{"Order.json", "bundleAddress.json", "bundleCustomer.json"}; !reflect.DeepEqual(files, expect) {
+		t.Fatalf("want files %v but got %v", expect, files)
+	}
+
+	expect := map[string]string{
+		"Order.json": `{
+			"type": "object",
+			"title": "bundleOrder",
+			"required": ["customer", "ship_to"],
+			"properties": {
+				"customer": {"$ref": "bundleCustomer.json", "propertyOrder": 0},
+				"ship_to": {"$ref": "bundleAddress.json", "description": "shipping address", "propertyOrder": 1}
+			}
+		}`,
+		"bundleCustomer.json": `{
+			"type": "object",
+			"title": "bundleCustomer",
+			"required": ["name", "address"],
+			"properties": {
+				"name": {"type": "string", "propertyOrder": 0},
+				"address": {"$ref": "bundleAddress.json", "propertyOrder": 1}
+			}
+		}`,
+	}
+	for name, e := range expect {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if diff := jsonDiff(t, string(got), e); diff != "" {
+			t.Errorf("%s does not match to expected one: %v", name, diff)
+		}
+	}
+
+	s, err := Compile(os.DirFS(dir), "Order.json")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"customer": {"name": "a", "address": {"city": "b"}}, "ship_to": {"city": "c"}}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"customer": {"name": "a", "address": {}}, "ship_to": {"city": "c"}}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestBundle_errors(t *testing.T) {
+	t.Run("duplicated name", func(t *testing.T) {
+		b := NewBundle()
+		if err := b.Add("User", bundleUser{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if err := b.Add("User", bundleItem{}); !errors.Is(err, ErrNameCollision) {
+			t.Errorf("want %v but got %v", ErrNameCollision, err)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if err := NewBundle().Add("User", nil); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("want %v but got %v", ErrUnsupportedType, err)
+		}
+	})
+
+	t.Run("different schemas", func(t *testing.T) {
+		// the root bundleAddress differs from the shared definition of bundleCustomer
+		b := NewBundle()
+		if err := b.Add("bundleCustomer", bundleCustomer{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if err := b.Add("bundleAddress", bundleAddress{}, PropertyTitles()); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		var buf bytes.Buffer
+		if err := b.Generate(&buf); !errors.Is(err, ErrNameCollision) {
+			t.Errorf("want %v but got %v", ErrNameCollision, err)
+		}
+	})
+}
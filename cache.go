@@ -0,0 +1,380 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// cacheVersion is changed when generated schemas change for the same input.
+const cacheVersion = "jsonschema-cache-v1"
+
+// Cache caches generated schemas in a CacheStore such as a directory.
+// Schemas are addressed by a hash of the structural definition of the type,
+// the value, settings of the generator such as TimeFormat and StrictNames
+// and keys which are given by CacheKey options.
+// Options which set schema keywords such as ByReference and functions such as PropertyTitlesFunc
+// cannot be hashed, so schemas which are generated with them are not cached unless CacheKey describes them.
+type Cache struct {
+	store  CacheStore
+	hits   int64
+	misses int64
+}
+
+// NewCache creates a Cache which stores schemas in the directory.
+// The directory is created when a schema is stored.
+func NewCache(dir string) *Cache {
+	return NewStoreCache(DirStore(dir))
+}
+
+// NewStoreCache creates a Cache which stores schemas in the store,
+// so multi-instance services can share schemas through their cache infrastructure.
+// If the store is nil, schemas are stored in a MemoryStore.
+func NewStoreCache(s CacheStore) *Cache {
+	if s == nil {
+		s = NewMemoryStore()
+	}
+	return &Cache{store: s}
+}
+
+// CacheStore stores artifacts such as generated schemas by their fingerprints,
+// which are hex strings of hashes of their inputs.
+// Users can implement their own stores such as stores of Redis or memcached.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the artifact of the fingerprint and reports whether it is stored.
+	Get(fingerprint string) ([]byte, bool, error)
+	// Set stores the artifact by the fingerprint.
+	Set(fingerprint string, artifact []byte) error
+}
+
+// MemoryStore is a CacheStore in memory.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	artifacts map[string][]byte
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{artifacts: map[string][]byte{}}
+}
+
+// Get implements CacheStore.
+func (s *MemoryStore) Get(fingerprint string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.artifacts[fingerprint]
+	return append([]byte(nil), b...), ok, nil
+}
+
+// Set implements CacheStore.
+func (s *MemoryStore) Set(fingerprint string, artifact []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.artifacts[fingerprint] = append([]byte(nil), artifact...)
+	return nil
+}
+
+// DirStore is a CacheStore which stores artifacts as files in the directory,
+// which can be shared by processes.
+type DirStore string
+
+// Get implements CacheStore.
+func (d DirStore) Get(fingerprint string) ([]byte, bool, error) {
+	b, err := os.ReadFile(filepath.Join(string(d), fingerprint+".json"))
+	switch {
+	case err == nil:
+		return b, true, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, false, nil
+	}
+	return nil, false, err
+}
+
+// Set implements CacheStore.
+// The directory is created if it does not exist.
+func (d DirStore) Set(fingerprint string, artifact []byte) error {
+	dir := string(d)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it
+	// to avoid that other processes read a partial artifact
+	tmp, err := os.CreateTemp(dir, fingerprint+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(artifact); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, fingerprint+".json"))
+}
+
+// CacheKey adds keys which identify options to the cache key of a schema.
+// It does not affect generation.
+func CacheKey(keys ...string) Option {
+	return configOption(func(c *config) {
+		c.cacheKeys = append(c.cacheKeys, keys...)
+	})
+}
+
+// Generate writes a schema of v to w like Generate.
+// If a schema has been cached for the same input, it is written without generation.
+func (c *Cache) Generate(w io.Writer, v interface{}, opts ...Option) error {
+	schema, err := c.GenerateBytes(v, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(schema)
+	return err
+}
+
+// GenerateBytes returns a schema of v like GenerateBytes.
+// If a schema has been cached for the same input, it is returned without generation.
+func (c *Cache) GenerateBytes(v interface{}, opts ...Option) ([]byte, error) {
+	key, ok := c.key(v, opts)
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		c.count(MetricCacheMisses, v, opts)
+		return GenerateBytes(v, opts...)
+	}
+	schema, ok, err := c.store.Get(key)
+	switch {
+	case err != nil:
+		return nil, err
+	case ok:
+		atomic.AddInt64(&c.hits, 1)
+		c.count(MetricCacheHits, v, opts)
+		return schema, nil
+	}
+	atomic.AddInt64(&c.misses, 1)
+	c.count(MetricCacheMisses, v, opts)
+
+	schema, err = GenerateBytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.Set(key, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Key returns the cache key of a schema of v which is generated with the options.
+func (c *Cache) Key(v interface{}, opts ...Option) string {
+	key, _ := c.key(v, opts)
+	return key
+}
+
+// key returns the cache key and reports whether the settings of the generator can be hashed.
+func (c *Cache) key(v interface{}, opts []Option) (string, bool) {
+	h := sha256.New()
+	fmt.Fprintln(h, cacheVersion)
+
+	if v != nil {
+		writeTypeHash(h, reflect.TypeOf(v), map[reflect.Type]bool{})
+	}
+	fmt.Fprintln(h)
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		// values which cannot be encoded are hashed by their Go representation
+		b = []byte(fmt.Sprintf("%#v", v))
+	}
+	h.Write(b)
+	fmt.Fprintln(h)
+
+	cfg := newConfig(opts)
+	ok := writeConfigHash(h, cfg)
+	for _, opt := range opts {
+		// options of schema objects are functions
+		if !isConfigOption(opt) {
+			ok = false
+		}
+	}
+	ok = ok || len(cfg.cacheKeys) != 0
+	for _, k := range cfg.cacheKeys {
+		fmt.Fprintf(h, "%q\n", k)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), ok
+}
+
+// writeConfigHash writes settings of the generator which change generated schemas to h.
+// It reports false if some settings are functions which cannot be hashed.
+func writeConfigHash(h hash.Hash, c *config) bool {
+	fmt.Fprintf(h, "%d,%t,%q,%t,%t,%t,%d,%t,%t,%d,%t,%d,%q,%d,%d\n",
+		c.promotedRequired, c.strictNames, c.timeFormat, c.hoistAnonymous, c.compatTags,
+		c.integrity, c.mapIntKeyStyle, c.closedMaps, c.patternProps, c.requiredOrder, c.defaults,
+		c.defaultPolicy, c.errMsgKey, c.maxNodes, c.sharedTypes)
+	fmt.Fprintf(h, "draft:%d,%q,%t,%t,%t,%t,%d,%t,%t\n", c.draft, c.id, c.nestEmbedded, c.hashDefNames, c.sortedKeys, c.nullablePointers, c.propertiesOrder, c.validatesOutput(), c.dynamicRefs)
+	fmt.Fprintf(h, "flavor:%d,%d,%t,%t,%t,%d\n", c.flavor, c.arrayStyle, c.strictObjects, c.bytesAsArrays, c.isoDurations, c.compatibility)
+
+	for _, p := range c.namePatterns {
+		fmt.Fprintf(h, "pattern:%q\n", p.String())
+	}
+	for _, o := range c.typeOverrides {
+		fmt.Fprintf(h, "override:%q,%q,", o.pattern, o.typ)
+		if o.goType != nil {
+			writeTypeHash(h, o.goType, map[reflect.Type]bool{})
+		}
+		fmt.Fprintln(h)
+	}
+	writeTypeMapHash(h, "hal", c.hal)
+	writeTypeMapHash(h, "enum", c.enums)
+	writeTypeMapHash(h, "schema", c.typeSchemas)
+	writeTypeMapHash(h, "hint", c.marshalerHints)
+	writeTypeMapHash(h, "impl", c.implementationsHash())
+	for _, s := range c.shapes {
+		for _, name := range s.names() {
+			fmt.Fprintf(h, "shape:%q,%q,%s\n", s.pattern, name, typeHash(s.keys[name]))
+		}
+	}
+	annotations, err := json.Marshal(c.annotations)
+	if err != nil {
+		return false
+	}
+	fmt.Fprintf(h, "annotations:%s\n", annotations)
+
+	directives, err := json.Marshal(c.directives)
+	if err != nil {
+		return false
+	}
+	fmt.Fprintf(h, "directives:%s\n", directives)
+
+	docs, err := json.Marshal(c.docs)
+	if err != nil {
+		return false
+	}
+	fmt.Fprintf(h, "docs:%s\n", docs)
+
+	if c.naming != nil {
+		// functions of conventions cannot be hashed
+		nc, ok := c.naming.convention.(namingCase)
+		if !ok {
+			return false
+		}
+		fmt.Fprintf(h, "naming:%q,%d\n", nc, c.naming.action)
+	}
+
+	if c.refBuilder != nil {
+		b, err := json.Marshal(c.refBuilder)
+		if err != nil {
+			return false
+		}
+		fmt.Fprintf(h, "refs:%T:%s\n", c.refBuilder, b)
+	}
+
+	// locations of sources depend on files besides types
+	return c.propertyTitle == nil && c.typeTitle == nil && len(c.typeMappings) == 0 &&
+		len(c.keywordMappers) == 0 && len(c.forTypes) == 0 && c.sources == nil
+}
+
+// writeTypeMapHash writes the map whose keys are types to h in the order of the types.
+func writeTypeMapHash(h hash.Hash, name string, m interface{}) {
+	rv := reflect.ValueOf(m)
+	keys := rv.MapKeys()
+	hashes := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		t, _ := k.Interface().(reflect.Type)
+		hashes[typeHash(t)] = rv.MapIndex(k).Interface()
+	}
+
+	for _, k := range sortedKeys(hashes) {
+		b, err := json.Marshal(hashes[k])
+		if err != nil {
+			b = []byte(fmt.Sprintf("%#v", hashes[k]))
+		}
+		fmt.Fprintf(h, "%s:%s:%s\n", name, k, b)
+	}
+}
+
+// typeHash returns a hex encoded hash of the structural definition of t.
+func typeHash(t reflect.Type) string {
+	h := sha256.New()
+	if t != nil {
+		writeTypeHash(h, t, map[reflect.Type]bool{})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Stats returns the number of cache hits and misses.
+func (c *Cache) Stats() (hits, misses int64) {
+	return atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses)
+}
+
+// writeTypeHash writes the structural definition of t to h.
+func writeTypeHash(h hash.Hash, t reflect.Type, visited map[reflect.Type]bool) {
+	fmt.Fprintf(h, "%s.%s:%s", t.PkgPath(), t.Name(), t.Kind())
+
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+
+	// methods change generated schemas (e.g. Generator, Enumer and TextMarshaler)
+	for i := 0; i < t.NumMethod(); i++ {
+		fmt.Fprintf(h, "#%s", t.Method(i).Name)
+	}
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		pt := reflect.PtrTo(t)
+		for i := 0; i < pt.NumMethod(); i++ {
+			fmt.Fprintf(h, "*#%s", pt.Method(i).Name)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Array {
+			fmt.Fprintf(h, "[%d]", t.Len())
+		}
+		fmt.Fprint(h, "(")
+		writeTypeHash(h, t.Elem(), visited)
+		fmt.Fprint(h, ")")
+	case reflect.Map:
+		fmt.Fprint(h, "(")
+		writeTypeHash(h, t.Key(), visited)
+		fmt.Fprint(h, ",")
+		writeTypeHash(h, t.Elem(), visited)
+		fmt.Fprint(h, ")")
+	case reflect.Struct:
+		fmt.Fprint(h, "{")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fmt.Fprintf(h, "%s,%t,%q:", f.Name, f.Anonymous, f.Tag)
+			writeTypeHash(h, f.Type, visited)
+			fmt.Fprint(h, ";")
+		}
+		fmt.Fprint(h, "}")
+	}
+}
+
+// count reports a cache hit or miss to the meter which is given by WithMeter.
+func (c *Cache) count(name string, v interface{}, opts []Option) {
+	if m := newConfig(opts).meter; m != nil {
+		m.Count(name, 1, Attribute{Key: "type", Value: fmt.Sprintf("%T", v)})
+	}
+}
+
+// shortTypeHash returns a short hash of the structural definition of t.
+func shortTypeHash(t reflect.Type) string {
+	h := sha256.New()
+	writeTypeHash(h, indirectType(t), map[reflect.Type]bool{})
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
@@ -0,0 +1,196 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestCache(t *testing.T) {
+	type A struct {
+		Name string `json:"name"`
+	}
+	type B struct {
+		Name int `json:"name"`
+	}
+	type C struct {
+		Name string `json:"name" jsonschema:"format=email"`
+	}
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	c := NewCache(dir)
+
+	steps := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		hits   int64
+		misses int64
+	}{
+		{"first", A{}, nil, 0, 1},
+		{"same", A{}, nil, 1, 1},
+		{"field type", B{}, nil, 1, 2},
+		{"tag", C{}, nil, 1, 3},
+		{"cache key", A{}, []Option{PropertyTitles(), CacheKey("titles")}, 1, 4},
+		{"same cache key", A{}, []Option{PropertyTitles(), CacheKey("titles")}, 2, 4},
+		{"value", A{Name: "x"}, nil, 2, 5},
+	}
+
+	for _, s := range steps {
+		got, err := c.GenerateBytes(s.v, s.opts...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s.name, err)
+		}
+
+		want, err := GenerateBytes(s.v, s.opts...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s.name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: want %s but got %s", s.name, want, got)
+		}
+
+		if hits, misses := c.Stats(); hits != s.hits || misses != s.misses {
+			t.Errorf("%s: want %d hits and %d misses but got %d and %d", s.name, s.hits, s.misses, hits, misses)
+		}
+	}
+
+	// other processes share cached schemas
+	other := NewCache(dir)
+	if _, err := other.GenerateBytes(A{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if hits, _ := other.Stats(); hits != 1 {
+		t.Errorf("want a cache hit but got %d", hits)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(files) != 5 {
+		t.Errorf("want 5 cached schemas but got %d", len(files))
+	}
+}
+
+func TestCache_options(t *testing.T) {
+	type T struct {
+		Z         string            `json:"z"`
+		CreatedAt time.Time         `json:"created_at"`
+		Labels    map[string]string `json:"labels"`
+	}
+
+	// description sets the description of the root, which is not a setting of the generator
+	description := func(d string) Option {
+		return func(o Object) (Object, error) {
+			if o.Ref() == RefRoot {
+				o.Set("description", d)
+			}
+			return o, nil
+		}
+	}
+
+	c := NewCache(t.TempDir())
+	steps := []struct {
+		name   string
+		opts   []Option
+		hits   int64
+		misses int64
+	}{
+		{"default", nil, 0, 1},
+		{"time format", []Option{TimeFormat("date")}, 0, 2},
+		{"required order", []Option{OrderRequired(RequiredAlphabetical)}, 0, 3},
+		{"strict names", []Option{StrictNames(regexp.MustCompile(`^[a-z]+$`))}, 0, 4},
+		{"closed maps", []Option{ClosedMaps()}, 0, 5},
+		{"ref builder", []Option{WithRefBuilder(DottedRefs(""))}, 0, 6},
+		{"same ref builder", []Option{WithRefBuilder(DottedRefs(""))}, 1, 6},
+		{"keyword option", []Option{description("first")}, 1, 7},
+		{"keyword option not cached", []Option{description("second")}, 1, 8},
+		{"keyword option with cache key", []Option{ByReference("#/properties/z", PropertyOrder(1)), CacheKey("z:1")}, 1, 9},
+		{"same cache key", []Option{ByReference("#/properties/z", PropertyOrder(1)), CacheKey("z:1")}, 2, 9},
+		{"function", []Option{PropertyTitlesFunc(strings.ToUpper)}, 2, 10},
+		{"function not cached", []Option{PropertyTitlesFunc(strings.ToLower)}, 2, 11},
+		{"type schema", []Option{RegisterTypeSchema(time.Time{}, map[string]interface{}{"type": "number"})}, 2, 12},
+		{"type mapping not cached", []Option{TypeMapping(reflect.TypeOf(time.Time{}), func(o Object) error {
+			o.Set("type", "integer")
+			return nil
+		})}, 2, 13},
+	}
+
+	for _, s := range steps {
+		got, err := c.GenerateBytes(T{}, s.opts...)
+		want, wantErr := GenerateBytes(T{}, s.opts...)
+		if (err != nil) != (wantErr != nil) {
+			t.Fatalf("%s: want error %v but got %v", s.name, wantErr, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: want %s but got %s", s.name, want, got)
+		}
+
+		if hits, misses := c.Stats(); hits != s.hits || misses != s.misses {
+			t.Errorf("%s: want %d hits and %d misses but got %d and %d", s.name, s.hits, s.misses, hits, misses)
+		}
+	}
+}
+
+// countingStore is a CacheStore which counts operations of the underlying store.
+type countingStore struct {
+	CacheStore
+	gets, sets int
+}
+
+func (s *countingStore) Get(fingerprint string) ([]byte, bool, error) {
+	s.gets++
+	return s.CacheStore.Get(fingerprint)
+}
+
+func (s *countingStore) Set(fingerprint string, artifact []byte) error {
+	s.sets++
+	return s.CacheStore.Set(fingerprint, artifact)
+}
+
+func TestNewStoreCache(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	// instances share schemas through the store
+	store := &countingStore{CacheStore: NewMemoryStore()}
+	for i, c := range []*Cache{NewStoreCache(store), NewStoreCache(store)} {
+		got, err := c.GenerateBytes(T{})
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		want, err := GenerateBytes(T{})
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("want %s but got %s", want, got)
+		}
+		if hits, _ := c.Stats(); hits != int64(i) {
+			t.Errorf("instance %d: want %d hits but got %d", i, i, hits)
+		}
+	}
+	if store.gets != 2 || store.sets != 1 {
+		t.Errorf("want 2 gets and 1 set but got %d and %d", store.gets, store.sets)
+	}
+
+	// the default store is in memory
+	c := NewStoreCache(nil)
+	for i := 0; i < 2; i++ {
+		if _, err := c.GenerateBytes(T{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+	if hits, misses := c.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("want 1 hit and 1 miss but got %d and %d", hits, misses)
+	}
+}
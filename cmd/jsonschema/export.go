@@ -0,0 +1,250 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tenntenn/jsonschema/handler"
+)
+
+// Names of files in archives besides schemas and their documentation.
+const (
+	archiveManifest = "manifest.jsonl"
+	archiveSums     = "SHA256SUMS"
+	archiveIndex    = "index.html"
+)
+
+// archiveTime is the modification time of files in archives, so archives of the same schemas are identical.
+// It is the oldest time which zip files can have.
+var archiveTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// runExport runs the export subcommand.
+func runExport(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	all := fs.Bool("all", false, "export schemas of all exported struct types of the package")
+	draft := fs.String("draft", "", "draft of schemas: 04, 06, 07, 2019-09 or 2020-12")
+	indent := fs.String("indent", "  ", "indentation of schemas; an empty string writes compact schemas")
+	output := fs.String("o", "", "archive which schemas are written into: .tar.gz, .tgz or .zip")
+	version := fs.String("version", "", "version of schemas which is recorded in the manifest and the index")
+	impls := fs.Bool("implementations", false, "generate fields of interface types as oneOf of their implementations in the package")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	if _, ok := drafts[*draft]; *draft != "" && !ok {
+		fmt.Fprintf(stderr, "unknown draft %q\n", *draft)
+		return exitUsage
+	}
+	if *output == "" {
+		fmt.Fprintln(stderr, "export needs -o")
+		return exitUsage
+	}
+	root, format := archiveFormat(*output)
+	if format == "" {
+		fmt.Fprintf(stderr, "unknown archive format of %s: use .tar.gz, .tgz or .zip\n", *output)
+		return exitUsage
+	}
+
+	pkg, types, code := selectTypes(fs.Arg(0), fs.Args()[1:], *all, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	var ifaces []iface
+	if *impls {
+		var err error
+		if ifaces, err = discoverInterfaces(pkg.Dir); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	out, err := generate(pkg, types, drafts[*draft], ifaces, nil, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	schemas, failures := out.Schemas, out.Errors
+	if len(failures) != 0 {
+		for _, f := range failures {
+			fmt.Fprintf(stderr, "%s: %s\n", f.Type, f.Error)
+		}
+		fmt.Fprintf(stderr, "cannot generate schemas of %s\n", pkg.ImportPath)
+		return exitError
+	}
+
+	files, err := exportFiles(pkg.ImportPath, types, schemas, *indent, *version)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	var buf bytes.Buffer
+	if format == "zip" {
+		err = writeZip(&buf, root, files)
+	} else {
+		err = writeTarGz(&buf, root, files)
+	}
+	if err == nil {
+		err = writeFile(*output, buf.Bytes())
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// archiveFormat returns the directory of files in the archive, which is the name of the archive
+// without its extension such as "models-v1" for "dist/models-v1.tar.gz", and the format of the archive.
+// The format is empty if the extension is unknown.
+func archiveFormat(name string) (root, format string) {
+	base := filepath.Base(name)
+	for _, ext := range []struct{ ext, format string }{
+		{".tar.gz", "tar.gz"},
+		{".tgz", "tar.gz"},
+		{".zip", "zip"},
+	} {
+		if strings.HasSuffix(base, ext.ext) && len(base) > len(ext.ext) {
+			return strings.TrimSuffix(base, ext.ext), ext.format
+		}
+	}
+	return "", ""
+}
+
+// archiveFile is a file in an archive.
+type archiveFile struct {
+	name string
+	body []byte
+}
+
+// exportFiles returns files of the archive of the schemas of the types in the package:
+// schemas and their documentation which are named after their types, the manifest, the index
+// and SHA256SUMS of the other files.
+func exportFiles(importPath string, types []string, schemas map[string]json.RawMessage, indent, version string) ([]archiveFile, error) {
+	var (
+		files   []archiveFile
+		records []record
+	)
+	for _, name := range types {
+		schema, err := formatSchema(schemas[name], indent)
+		if err != nil {
+			return nil, err
+		}
+		r, err := newRecord(name, name+".json", archiveManifest, version, schema)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+
+		var doc bytes.Buffer
+		if err := handler.RenderDoc(&doc, schema, nil); err != nil {
+			return nil, fmt.Errorf("cannot render documentation of %s: %w", name, err)
+		}
+		files = append(files, archiveFile{name + ".json", schema}, archiveFile{name + ".html", doc.Bytes()})
+	}
+	manifest, err := encodeManifest(records)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, archiveFile{archiveManifest, manifest})
+
+	var index bytes.Buffer
+	if err := indexTemplate.Execute(&index, struct {
+		Package string
+		Version string
+		Records []record
+	}{importPath, version, records}); err != nil {
+		return nil, err
+	}
+	files = append(files, archiveFile{archiveIndex, index.Bytes()})
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+	var sums bytes.Buffer
+	for _, f := range files {
+		fmt.Fprintf(&sums, "%x  %s\n", sha256.Sum256(f.body), f.name)
+	}
+	return append(files, archiveFile{archiveSums, sums.Bytes()}), nil
+}
+
+// indexTemplate is the template of the index of an archive.
+var indexTemplate = template.Must(template.New("index").Parse(`<html>
+	<head>
+		<title>Schemas of {{.Package}}{{with .Version}} {{.}}{{end}}</title>
+	</head>
+	<body>
+		<h1>Schemas of <code>{{.Package}}</code>{{with .Version}} {{.}}{{end}}</h1>
+		<table>
+			<tr><th>Name</th><th>Schema</th><th>$id</th><th>Fingerprint</th></tr>
+			{{- range .Records}}
+			<tr>
+				<td><a href="{{.Name}}.html">{{.Name}}</a></td>
+				<td><a href="{{.File}}">{{.File}}</a></td>
+				<td>{{with .ID}}<code>{{.}}</code>{{end}}</td>
+				<td><code>{{.Fingerprint}}</code></td>
+			</tr>
+			{{- end}}
+		</table>
+	</body>
+</html>
+`))
+
+// writeTarGz writes the files into the directory of root of a tar archive which is compressed by gzip.
+func writeTarGz(w io.Writer, root string, files []archiveFile) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    path.Join(root, f.name),
+			Mode:    0o644,
+			Size:    int64(len(f.body)),
+			ModTime: archiveTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.body); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeZip writes the files into the directory of root of a zip archive.
+func writeZip(w io.Writer, root string, files []archiveFile) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     path.Join(root, f.name),
+			Method:   zip.Deflate,
+			Modified: archiveTime,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
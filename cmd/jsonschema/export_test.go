@@ -0,0 +1,185 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRunExport(t *testing.T) {
+	const pkg = "../../internal/directivetest"
+
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"no output", []string{"export", "-all", pkg}, exitUsage, "export needs -o"},
+		{"unknown format", []string{"export", "-all", "-o", "schemas.rar", pkg}, exitUsage, "unknown archive format of schemas.rar"},
+		{"unknown draft", []string{"export", "-draft", "08", "-o", "schemas.zip", pkg, "Config"}, exitUsage, `unknown draft "08"`},
+		{"unknown type", []string{"export", "-o", "schemas.zip", pkg, "Nope"}, exitUsage, `does not declare exported type "Nope"`},
+		{"no types", []string{"export", "-o", "schemas.zip", pkg}, exitUsage, "no types"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if code := run(tt.args, io.Discard, &stderr); code != tt.code {
+				t.Errorf("want exit code %d but got %d: %s", tt.code, code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("want %q in stderr but got %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunExport_archives(t *testing.T) {
+	for _, name := range []string{"models-v1.tar.gz", "models-v1.zip"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), name)
+			args := []string{"export", "-all", "-version", "v1", "-o", archive, "../../internal/directivetest"}
+			var stderr bytes.Buffer
+			if code := run(args, io.Discard, &stderr); code != exitOK {
+				t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+			}
+			first, err := os.ReadFile(archive)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			files := readArchive(t, name, first)
+			var names []string
+			for n := range files {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			want := []string{
+				"models-v1/Config.html", "models-v1/Config.json",
+				"models-v1/Debug.html", "models-v1/Debug.json",
+				"models-v1/Platform.html", "models-v1/Platform.json",
+				"models-v1/SHA256SUMS", "models-v1/index.html", "models-v1/manifest.jsonl",
+			}
+			if got := strings.Join(names, ","); got != strings.Join(want, ",") {
+				t.Fatalf("want files %v but got %v", want, names)
+			}
+
+			// records of the manifest refer to schemas in the archive
+			s := bufio.NewScanner(bytes.NewReader(files["models-v1/manifest.jsonl"]))
+			var records int
+			for s.Scan() {
+				var r record
+				if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+					t.Fatalf("invalid record %s: %v", s.Bytes(), err)
+				}
+				records++
+				schema, ok := files["models-v1/"+r.File]
+				if !ok {
+					t.Errorf("%s is not in the archive", r.File)
+					continue
+				}
+				if r.Version != "v1" {
+					t.Errorf("want version v1 but got %q", r.Version)
+				}
+				if fp, _ := newRecord(r.Name, r.File, archiveManifest, "v1", schema); fp.Fingerprint != r.Fingerprint {
+					t.Errorf("fingerprint of %s does not match", r.Name)
+				}
+			}
+			if records != 3 {
+				t.Errorf("want 3 records but got %d", records)
+			}
+
+			var sums strings.Builder
+			for _, n := range names {
+				if n != "models-v1/SHA256SUMS" {
+					fmt.Fprintf(&sums, "%x  %s\n", sha256.Sum256(files[n]), strings.TrimPrefix(n, "models-v1/"))
+				}
+			}
+			if got := string(files["models-v1/SHA256SUMS"]); got != sums.String() {
+				t.Errorf("want SHA256SUMS\n%s\nbut got\n%s", sums.String(), got)
+			}
+
+			index := string(files["models-v1/index.html"])
+			for _, s := range []string{`<a href="Config.html">Config</a>`, `<a href="Debug.json">Debug.json</a>`, "v1"} {
+				if !strings.Contains(index, s) {
+					t.Errorf("want %q in index.html but got %s", s, index)
+				}
+			}
+			if doc := string(files["models-v1/Config.html"]); !strings.Contains(doc, "<h1>Config</h1>") {
+				t.Errorf("unexpected documentation: %s", doc)
+			}
+
+			// archives of the same schemas are identical
+			if code := run(args, io.Discard, &stderr); code != exitOK {
+				t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+			}
+			second, err := os.ReadFile(archive)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Error("archives of the same schemas differ")
+			}
+		})
+	}
+}
+
+// readArchive returns contents of files in the archive by their names.
+func readArchive(t *testing.T, name string, b []byte) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	if strings.HasSuffix(name, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		for _, f := range zr.File {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			body, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			files[f.Name] = body
+		}
+		return files
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		files[hdr.Name] = body
+	}
+	return files
+}
@@ -0,0 +1,1032 @@
+// Command jsonschema generates JSON Schemas of Go types in a package:
+//
+//	jsonschema gen ./models User
+//	jsonschema gen -draft 07 -o user.json ./models User
+//	jsonschema gen -all -dir schemas ./models
+//
+// Go types cannot be looked up by their names at run time, so the command writes
+// a temporary program which imports the package and runs it in the directory of the package.
+// The program is removed after the generation.
+// The module of the package must require github.com/tenntenn/jsonschema.
+//
+// A schema is written to stdout or the file of -o. Schemas of multiple types are written
+// into files of -dir which are named after their types such as "User.json".
+//
+// -manifest writes a manifest of the written files in JSON Lines such as
+//
+//	{"name":"User","fingerprint":"sha256:...","file":"User.json","version":"v1"}
+//
+// which has $id of the schema if any and the version of -version.
+// Paths of files are relative to the directory of the manifest.
+//
+// -since reads a manifest of a previous generation and writes only schemas
+// whose fingerprints differ from records of the same names in it.
+// A summary of added, changed and removed schemas is written to stderr such as
+//
+//	added: Order
+//	changed: User
+//	removed: Item
+//	1 added, 1 changed, 1 removed, 2 unchanged
+//
+// which publishing pipelines can use to publish only changed schemas.
+// A manifest which does not exist is empty, so every schema is added at first.
+// -manifest still records all generated schemas, and it can be the same file as -since.
+//
+// -implementations discovers implementations of exported interface types of the package
+// among exported types of the package, so fields of the interface types are generated
+// as oneOf of schemas of their implementations. Implementations are discovered again
+// for each generation, so they are kept up to date with -watch.
+// A type or an interface type whose doc comment has a directive
+//
+//	//jsonschema:noimpl
+//
+// is not discovered as an implementation or does not discover its implementations.
+//
+// The export subcommand writes schemas into an archive which is ready to be published
+// such as a release artifact:
+//
+//	jsonschema export -all -version v1.2.0 -o models-v1.2.0.tar.gz ./models
+//
+// The archive is a tar.gz or zip file by the extension of -o. Its files are in the directory
+// of the name of the archive such as "models-v1.2.0/": schemas such as "User.json",
+// their documentation such as "User.html", the manifest "manifest.jsonl",
+// "index.html" which lists the schemas and "SHA256SUMS" of the other files.
+// Archives of the same schemas are identical.
+//
+// The serve subcommand runs a local HTTP server to browse schemas during development:
+//
+//	jsonschema serve -addr localhost:8080 ./models
+//
+// Its index lists all exported struct types of the package or the given types with links to
+// their schemas such as "/User.json" and their documentation such as "/User.html".
+// Schemas are regenerated whenever Go files of the package change like -watch,
+// and the previous schemas are served if the package cannot be built.
+//
+// -watch keeps running and regenerates schemas whenever Go files of the package change.
+// Types are selected again for each change, so -all discovers types which are added.
+// Schemas are cached by jsonschema.Cache during the watch, so only schemas of types
+// whose cache keys change are generated and written again.
+// Files of schemas which do not change are not rewritten.
+//
+// By default, no schemas are written if a schema of any type cannot be generated.
+// -keep-going writes schemas of the other types and exits with a non-zero code
+// only if more types than -max-failures fail. -report writes a report of failures in JSON such as
+//
+//	{"package":"example.com/models","generated":["User"],"failures":[{"type":"Order","error":"...","kind":"unsupported type","ref":"#/properties/total","field":"models.Order.Total"}]}
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tenntenn/jsonschema"
+)
+
+// Exit codes of run.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// drafts are values of -draft and names of their constants.
+var drafts = map[string]string{
+	"04":      "Draft04",
+	"06":      "Draft06",
+	"07":      "Draft07",
+	"2019-09": "Draft201909",
+	"2020-12": "Draft202012",
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs a subcommand of the args and returns an exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "gen":
+		return runGen(args[1:], stdout, stderr)
+	case "export":
+		return runExport(args[1:], stderr)
+	case "serve":
+		return runServe(args[1:], stderr)
+	}
+	usage(stderr)
+	return exitUsage
+}
+
+// runGen runs the gen subcommand.
+func runGen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	all := fs.Bool("all", false, "generate schemas of all exported struct types of the package")
+	draft := fs.String("draft", "", "draft of schemas: 04, 06, 07, 2019-09 or 2020-12")
+	indent := fs.String("indent", "  ", "indentation of schemas; an empty string writes compact schemas")
+	output := fs.String("o", "", "file which the schema is written into instead of stdout")
+	dir := fs.String("dir", "", "directory which schemas are written into as <type>.json")
+	manifest := fs.String("manifest", "", "file which a manifest of written schemas is written into in JSON Lines")
+	version := fs.String("version", "", "version of schemas which is recorded in the manifest")
+	since := fs.String("since", "", "manifest of a previous generation; only schemas whose fingerprints changed are written")
+	keepGoing := fs.Bool("keep-going", false, "write schemas of types which can be generated even if others fail")
+	maxFailures := fs.Int("max-failures", 0, "number of failed types which -keep-going tolerates")
+	reportFile := fs.String("report", "", "file which a report of failed types is written into in JSON")
+	impls := fs.Bool("implementations", false, "generate fields of interface types as oneOf of their implementations in the package")
+	watchMode := fs.Bool("watch", false, "regenerate schemas whenever Go files of the package change")
+	interval := fs.Duration("interval", time.Second, "interval of checking changes of Go files by -watch")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	if _, ok := drafts[*draft]; *draft != "" && !ok {
+		fmt.Fprintf(stderr, "unknown draft %q\n", *draft)
+		return exitUsage
+	}
+	if *output != "" && *dir != "" {
+		fmt.Fprintln(stderr, "-o and -dir cannot be used together")
+		return exitUsage
+	}
+	if *manifest != "" && *output == "" && *dir == "" {
+		fmt.Fprintln(stderr, "-manifest needs -o or -dir")
+		return exitUsage
+	}
+	if *maxFailures != 0 && !*keepGoing {
+		fmt.Fprintln(stderr, "-max-failures needs -keep-going")
+		return exitUsage
+	}
+	if *watchMode && *output == "" && *dir == "" {
+		fmt.Fprintln(stderr, "-watch needs -o or -dir")
+		return exitUsage
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(stderr, "-interval must be positive")
+		return exitUsage
+	}
+
+	pkg, types, code := selectTypes(fs.Arg(0), fs.Args()[1:], *all, stderr)
+	if code != exitOK {
+		return code
+	}
+	if len(types) > 1 && *dir == "" {
+		fmt.Fprintln(stderr, "schemas of multiple types need -dir")
+		return exitUsage
+	}
+
+	c := &genConfig{
+		pkg:         pkg,
+		types:       types,
+		draft:       drafts[*draft],
+		indent:      *indent,
+		output:      *output,
+		dir:         *dir,
+		manifest:    *manifest,
+		version:     *version,
+		since:       *since,
+		keepGoing:   *keepGoing,
+		maxFailures: *maxFailures,
+		report:      *reportFile,
+		impls:       *impls,
+	}
+	if !*watchMode {
+		return c.run(stdout, stderr)
+	}
+
+	// the cache lives as long as the watch
+	cache, err := os.MkdirTemp("", "jsonschema-cache")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	defer os.RemoveAll(cache)
+	c.cache, c.keys = cache, map[string]string{}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = watch(ctx, pkg.Dir, *interval, func() {
+		// types are selected again to discover types which are added by the change
+		_, types, code := selectTypes(fs.Arg(0), fs.Args()[1:], *all, stderr)
+		if code != exitOK {
+			return
+		}
+		if len(types) > 1 && *dir == "" {
+			fmt.Fprintln(stderr, "schemas of multiple types need -dir")
+			return
+		}
+		// cache keys hash definitions of types but not bodies of their methods such as Generator
+		methods, err := methodsHash(pkg.Dir)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return
+		}
+		c.types, c.methods = types, methods
+		if c.run(stdout, stderr) == exitOK {
+			fmt.Fprintf(stderr, "generated schemas of %s\n", pkg.ImportPath)
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// selectTypes loads the package of the path and returns the names of its types to generate,
+// which are all exported struct types of the package if all is true.
+// It returns an exit code which is not exitOK if the types cannot be selected.
+func selectTypes(path string, names []string, all bool, stderr io.Writer) (*pkg, []string, int) {
+	pkg, err := load(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return nil, nil, exitError
+	}
+
+	declared, err := declaredTypes(pkg.Dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return nil, nil, exitError
+	}
+	types := names
+	if all {
+		types = nil
+		for name, isStruct := range declared {
+			if isStruct {
+				types = append(types, name)
+			}
+		}
+		sort.Strings(types)
+	}
+	if len(types) == 0 {
+		fmt.Fprintln(stderr, "no types: give type names or -all")
+		return nil, nil, exitUsage
+	}
+	for _, name := range types {
+		if _, ok := declared[name]; !ok {
+			fmt.Fprintf(stderr, "%s does not declare exported type %q\n", pkg.ImportPath, name)
+			return nil, nil, exitUsage
+		}
+	}
+	return pkg, types, exitOK
+}
+
+// genConfig is a configuration of generation of schemas.
+type genConfig struct {
+	pkg         *pkg
+	types       []string
+	draft       string
+	indent      string
+	output      string
+	dir         string
+	manifest    string
+	version     string
+	since       string
+	keepGoing   bool
+	maxFailures int
+	report      string
+	impls       bool
+	// cache is the directory of a cache of schemas and keys are cache keys of written schemas,
+	// so schemas whose cache keys do not change are neither generated nor written again.
+	cache string
+	keys  map[string]string
+	// methods is a hash of methods of the package which is a part of cache keys.
+	methods string
+}
+
+// run generates schemas and writes them and returns an exit code.
+func (c *genConfig) run(stdout, stderr io.Writer) int {
+	var ifaces []iface
+	if c.impls {
+		var err error
+		if ifaces, err = discoverInterfaces(c.pkg.Dir); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+
+	var cache *programCache
+	if c.cache != "" {
+		cache = &programCache{Dir: c.cache, Key: c.methods}
+	}
+	out, err := generate(c.pkg, c.types, c.draft, ifaces, cache, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	schemas, failures := out.Schemas, out.Errors
+	for _, f := range failures {
+		fmt.Fprintf(stderr, "%s: %s\n", f.Type, f.Error)
+	}
+	if c.report != "" {
+		if err := writeReport(c.report, c.pkg.ImportPath, c.types, schemas, failures); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	if len(failures) != 0 && !c.keepGoing {
+		fmt.Fprintf(stderr, "cannot generate schemas of %s\n", c.pkg.ImportPath)
+		return exitError
+	}
+
+	var prev map[string]record
+	if c.since != "" {
+		if prev, err = readManifest(c.since); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+
+	var (
+		records []record
+		diff    changes
+	)
+	for _, name := range c.types {
+		schema, ok := schemas[name]
+		if !ok {
+			continue
+		}
+		if schema, err = formatSchema(schema, c.indent); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+
+		file := c.output
+		if c.dir != "" {
+			file = filepath.Join(c.dir, name+".json")
+		}
+		changed := true
+		if c.since != "" {
+			if changed, err = diff.add(prev, name, schema); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitError
+			}
+		}
+		key := out.Keys[name]
+		if key != "" && c.keys[name] == key {
+			changed = false
+		}
+		switch {
+		case !changed:
+		case file == "":
+			_, err = stdout.Write(schema)
+		default:
+			err = writeFile(file, schema)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		if c.keys != nil && key != "" {
+			c.keys[name] = key
+		}
+
+		if c.manifest != "" {
+			r, err := newRecord(name, file, c.manifest, c.version, schema)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitError
+			}
+			records = append(records, r)
+		}
+	}
+
+	if c.manifest != "" {
+		if err := writeManifest(c.manifest, records); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+	if c.since != "" {
+		diff.remove(prev, c.types)
+		diff.write(stderr)
+	}
+
+	if len(failures) > c.maxFailures {
+		fmt.Fprintf(stderr, "%d of %d types failed, which exceeds -max-failures %d\n", len(failures), len(c.types), c.maxFailures)
+		return exitError
+	}
+	return exitOK
+}
+
+// formatSchema indents the schema by the indent if it is not empty and terminates it by a newline.
+func formatSchema(schema []byte, indent string) ([]byte, error) {
+	if indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, schema, "", indent); err != nil {
+			return nil, err
+		}
+		schema = buf.Bytes()
+	}
+	return append(schema, '\n'), nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	fmt.Fprintln(w, "\tjsonschema gen [-draft version] [-indent string] [-o file] [-manifest file] [-report file] package type")
+	fmt.Fprintln(w, "\tjsonschema gen [flags] -dir directory package type...")
+	fmt.Fprintln(w, "\tjsonschema gen -all [flags] -dir directory package")
+	fmt.Fprintln(w, "\tjsonschema export [-all] [-draft version] [-indent string] [-version version] [-implementations] -o archive package [type...]")
+	fmt.Fprintln(w, "\tjsonschema serve [-addr address] [-draft version] [-implementations] [-interval duration] package [type...]")
+	fmt.Fprintln(w, "flags:")
+	fmt.Fprintln(w, "\t[-draft version] [-indent string] [-manifest file [-version version]] [-since manifest]")
+	fmt.Fprintln(w, "\t[-keep-going [-max-failures n]] [-report file] [-implementations] [-watch [-interval duration]]")
+}
+
+// pkg is a package which is given by go list.
+type pkg struct {
+	ImportPath string
+	Name       string
+	Dir        string
+}
+
+// load returns the package of the path such as "./models" by go list.
+func load(path string) (*pkg, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-json", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load %s: %s", path, strings.TrimSpace(stderr.String()))
+	}
+
+	var p pkg
+	if err := json.Unmarshal(out, &p); err != nil {
+		return nil, fmt.Errorf("cannot load %s: %w", path, err)
+	}
+	if p.Name == "main" {
+		return nil, fmt.Errorf("%s is a main package which cannot be imported", path)
+	}
+	return &p, nil
+}
+
+// declaredTypes returns names of exported types which are declared in the package in dir
+// and whether they are struct types.
+// Generic types are excluded because they cannot be generated without instantiation.
+func declaredTypes(dir string) (map[string]bool, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	types := map[string]bool{}
+	for _, name := range append(append([]string{}, bp.GoFiles...), bp.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.IsExported() && ts.TypeParams == nil {
+					_, isStruct := ts.Type.(*ast.StructType)
+					types[ts.Name.Name] = isStruct
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+// noImplDirective is a directive comment of a type which opts out of -implementations.
+const noImplDirective = "//jsonschema:noimpl"
+
+// iface is an exported interface type of a package and exported types of the package
+// which are candidates of its implementations.
+// Go types cannot be checked without type checking, so the program checks them.
+type iface struct {
+	Name       string
+	Candidates []string
+}
+
+// discoverInterfaces returns exported interface types of the package in dir which have methods
+// and candidates of their implementations in the order of their names.
+// Generic types, aliases and types with noImplDirective are excluded.
+func discoverInterfaces(dir string) ([]iface, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var names, candidates []string
+	for _, name := range append(append([]string{}, bp.GoFiles...), bp.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if doc == nil && !gd.Lparen.IsValid() {
+					doc = gd.Doc
+				}
+				if !ts.Name.IsExported() || ts.Assign.IsValid() || ts.TypeParams != nil || hasNoImpl(doc) {
+					continue
+				}
+				if it, ok := ts.Type.(*ast.InterfaceType); !ok {
+					candidates = append(candidates, ts.Name.Name)
+				} else if isMethodSet(it) {
+					names = append(names, ts.Name.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(candidates)
+
+	ifaces := make([]iface, len(names))
+	for i, name := range names {
+		ifaces[i] = iface{Name: name, Candidates: candidates}
+	}
+	return ifaces, nil
+}
+
+// hasNoImpl reports whether the doc comment has noImplDirective.
+func hasNoImpl(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == noImplDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// isMethodSet reports whether the interface type has methods and no type constraints,
+// so it can be a type of fields. Embedded interfaces are assumed to be method sets.
+func isMethodSet(it *ast.InterfaceType) bool {
+	if len(it.Methods.List) == 0 {
+		return false
+	}
+	for _, m := range it.Methods.List {
+		if len(m.Names) != 0 {
+			continue
+		}
+		switch typ := m.Type.(type) {
+		case *ast.Ident:
+			if typ.Name == "comparable" || typ.Name == "any" {
+				return false
+			}
+		case *ast.SelectorExpr:
+		default:
+			// type sets such as ~int | string
+			return false
+		}
+	}
+	return true
+}
+
+// failure is a type whose schema cannot be generated.
+type failure struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+	// Kind, Ref and Field are given by jsonschema.Error.
+	Kind  string `json:"kind,omitempty"`
+	Ref   string `json:"ref,omitempty"`
+	Field string `json:"field,omitempty"`
+}
+
+// output is an output of the program.
+type output struct {
+	Schemas map[string]json.RawMessage `json:"schemas"`
+	Errors  []failure                  `json:"errors"`
+	// Keys are cache keys of the schemas if the program uses a cache.
+	Keys map[string]string `json:"keys"`
+}
+
+// generate runs a temporary program which prints schemas of the types in the package
+// and failures of types which cannot be generated in the order of names of the types.
+// If cache is not nil, the program caches schemas by jsonschema.Cache,
+// so schemas of types whose cache keys do not change are not generated again.
+// Errors of the program are written to stderr.
+func generate(p *pkg, types []string, draft string, ifaces []iface, cache *programCache, stderr io.Writer) (*output, error) {
+	// the program is put in the package directory to import internal packages
+	// and in the temporary directory if the package is read-only such as modules of dependencies
+	tmp, err := os.MkdirTemp(p.Dir, ".jsonschema")
+	if err != nil {
+		tmp, err = os.MkdirTemp("", "jsonschema")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	src, err := program(p.ImportPath, types, draft, ifaces, cache)
+	if err != nil {
+		return nil, err
+	}
+	main := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(main, src, 0o644); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command("go", "run", main)
+	cmd.Dir = p.Dir
+	cmd.Stdout, cmd.Stderr = &out, stderr
+	if err := cmd.Run(); err != nil {
+		var eerr *exec.ExitError
+		if errors.As(err, &eerr) {
+			return nil, fmt.Errorf("cannot generate schemas of %s", p.ImportPath)
+		}
+		return nil, err
+	}
+
+	var o output
+	if err := json.Unmarshal(out.Bytes(), &o); err != nil {
+		return nil, fmt.Errorf("cannot decode schemas of %s: %w", p.ImportPath, err)
+	}
+	sort.Slice(o.Errors, func(i, j int) bool {
+		return o.Errors[i].Type < o.Errors[j].Type
+	})
+	return &o, nil
+}
+
+// programCache is a cache of schemas of the program in the directory.
+// Key is added to cache keys by jsonschema.CacheKey.
+type programCache struct {
+	Dir string
+	Key string
+}
+
+// programTemplate is the template of the program which prints schemas.
+var programTemplate = template.Must(template.New("main.go").Parse(`package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	{{- if .Interfaces}}
+	"reflect"
+	{{- end}}
+
+	"github.com/tenntenn/jsonschema"
+	pkg {{printf "%q" .Path}}
+)
+
+func main() {
+	var opts []jsonschema.Option
+	{{- if .Draft}}
+	opts = append(opts, jsonschema.Draft(jsonschema.{{.Draft}}))
+	{{- end}}
+	{{- range .Interfaces}}
+	opts = append(opts, implementations((*pkg.{{.Name}})(nil){{range .Candidates}}, *new(pkg.{{.}}), new(pkg.{{.}}){{end}}))
+	{{- end}}
+	{{- with .Cache}}
+	cache := jsonschema.NewCache({{printf "%q" .Dir}})
+	opts = append(opts, jsonschema.CacheKey({{printf "%q" .Key}}))
+	{{- end}}
+	values := map[string]interface{}{
+		{{- range .Types}}
+		{{printf "%q" .}}: *new(pkg.{{.}}),
+		{{- end}}
+	}
+	schemas := map[string]json.RawMessage{}
+	failures := []map[string]string{}
+	keys := map[string]string{}
+	for name, v := range values {
+		{{- if .Cache}}
+		keys[name] = cache.Key(v, opts...)
+		schema, err := cache.GenerateBytes(v, opts...)
+		{{- else}}
+		schema, err := jsonschema.GenerateBytes(v, opts...)
+		{{- end}}
+		if err != nil {
+			f := map[string]string{"type": name, "error": err.Error()}
+			var e *jsonschema.Error
+			if errors.As(err, &e) {
+				f["ref"], f["field"] = e.Ref, e.Field
+				if e.Kind != nil {
+					f["kind"] = e.Kind.Error()
+				}
+			}
+			failures = append(failures, f)
+			continue
+		}
+		schemas[name] = schema
+	}
+	out := map[string]interface{}{"schemas": schemas, "errors": failures, "keys": keys}
+	if err := json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+{{- if .Interfaces}}
+
+// implementations registers candidates which implement the interface type of iface.
+// Candidates are pairs of values and pointers to them and values have priority.
+func implementations(iface interface{}, candidates ...interface{}) jsonschema.Option {
+	it := reflect.TypeOf(iface).Elem()
+	var impls []interface{}
+	for i := 0; i+1 < len(candidates); i += 2 {
+		switch {
+		case reflect.TypeOf(candidates[i]).Implements(it):
+			impls = append(impls, candidates[i])
+		case reflect.TypeOf(candidates[i+1]).Implements(it):
+			impls = append(impls, candidates[i+1])
+		}
+	}
+	if len(impls) == 0 {
+		// an option which does nothing and can be hashed by caches
+		return jsonschema.CacheKey()
+	}
+	return jsonschema.RegisterImplementations(iface, impls...)
+}
+{{- end}}
+`))
+
+// program returns the source of the program which prints schemas of the types.
+func program(path string, types []string, draft string, ifaces []iface, cache *programCache) ([]byte, error) {
+	var buf bytes.Buffer
+	err := programTemplate.Execute(&buf, struct {
+		Path       string
+		Types      []string
+		Draft      string
+		Interfaces []iface
+		Cache      *programCache
+	}{path, types, draft, ifaces, cache})
+	return buf.Bytes(), err
+}
+
+// writeFile writes the schema into the file and creates its directory if needed.
+// A file which has the same content is not written, so tools which watch it are not notified.
+func writeFile(name string, schema []byte) error {
+	if cur, err := os.ReadFile(name); err == nil && bytes.Equal(cur, schema) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, schema, 0o644)
+}
+
+// watch calls f and calls it again whenever Go files of the package in dir change until ctx is done.
+// Changes are detected by contents of the files every interval.
+func watch(ctx context.Context, dir string, interval time.Duration, f func()) error {
+	last, err := sourceHash(dir)
+	if err != nil {
+		return err
+	}
+	f()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+
+		h, err := sourceHash(dir)
+		// files may be being saved
+		if err != nil || bytes.Equal(h, last) {
+			continue
+		}
+		last = h
+		f()
+	}
+}
+
+// sourceHash returns a hash of names and contents of Go files of the package in dir.
+func sourceHash(dir string) ([]byte, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	for _, name := range append(append([]string{}, bp.GoFiles...), bp.CgoFiles...) {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(h, "%s:%d\n", name, len(b))
+		h.Write(b)
+	}
+	return h.Sum(nil), nil
+}
+
+// methodsHash returns a hash of declarations of methods of the package in dir.
+func methodsHash(dir string) (string, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return "", err
+	}
+
+	fset := token.NewFileSet()
+	h := sha256.New()
+	for _, name := range append(append([]string{}, bp.GoFiles...), bp.CgoFiles...) {
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			return "", err
+		}
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv != nil {
+				start, end := fset.Position(fd.Pos()).Offset, fset.Position(fd.End()).Offset
+				fmt.Fprintf(h, "%s:%d\n", name, end-start)
+				h.Write(src[start:end])
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// record is a record of a written schema in the manifest.
+type record struct {
+	Name        string `json:"name"`
+	ID          string `json:"$id,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	File        string `json:"file"`
+	Version     string `json:"version,omitempty"`
+}
+
+// newRecord creates a record of the schema of the type which is written into the file.
+// The path of the file is relative to the directory of the manifest if possible.
+func newRecord(name, file, manifest, version string, schema []byte) (record, error) {
+	fp, err := jsonschema.Digest(schema)
+	if err != nil {
+		return record{}, fmt.Errorf("cannot compute a fingerprint of %s: %w", name, err)
+	}
+
+	var doc struct {
+		ID string `json:"$id"`
+	}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return record{}, fmt.Errorf("cannot decode the schema of %s: %w", name, err)
+	}
+
+	if rel, err := filepath.Rel(filepath.Dir(manifest), file); err == nil {
+		file = rel
+	}
+
+	return record{
+		Name:        name,
+		ID:          doc.ID,
+		Fingerprint: fp,
+		File:        filepath.ToSlash(file),
+		Version:     version,
+	}, nil
+}
+
+// readManifest reads records of the manifest in JSON Lines by their names.
+// A manifest which does not exist has no records.
+func readManifest(name string) (map[string]record, error) {
+	b, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := map[string]record{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for dec.More() {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			return nil, fmt.Errorf("cannot decode the manifest %s: %w", name, err)
+		}
+		records[r.Name] = r
+	}
+	return records, nil
+}
+
+// changes are names of schemas which are changed from a previous manifest.
+type changes struct {
+	added, changed, removed []string
+	unchanged               int
+}
+
+// add compares the fingerprint of the schema of the type with the record of prev
+// and reports whether the schema is added or changed.
+func (c *changes) add(prev map[string]record, name string, schema []byte) (bool, error) {
+	fp, err := jsonschema.Digest(schema)
+	if err != nil {
+		return false, fmt.Errorf("cannot compute a fingerprint of %s: %w", name, err)
+	}
+
+	r, ok := prev[name]
+	switch {
+	case !ok:
+		c.added = append(c.added, name)
+	case r.Fingerprint != fp:
+		c.changed = append(c.changed, name)
+	default:
+		c.unchanged++
+		return false, nil
+	}
+	return true, nil
+}
+
+// remove records names of prev which are not the types as removed.
+func (c *changes) remove(prev map[string]record, types []string) {
+	current := make(map[string]bool, len(types))
+	for _, t := range types {
+		current[t] = true
+	}
+	for name := range prev {
+		if !current[name] {
+			c.removed = append(c.removed, name)
+		}
+	}
+	sort.Strings(c.removed)
+}
+
+// write writes a summary of the changes into w.
+func (c *changes) write(w io.Writer) {
+	for _, kind := range []struct {
+		name  string
+		names []string
+	}{{"added", c.added}, {"changed", c.changed}, {"removed", c.removed}} {
+		for _, name := range kind.names {
+			fmt.Fprintf(w, "%s: %s\n", kind.name, name)
+		}
+	}
+	fmt.Fprintf(w, "%d added, %d changed, %d removed, %d unchanged\n", len(c.added), len(c.changed), len(c.removed), c.unchanged)
+}
+
+// writeManifest writes the records into the file in JSON Lines.
+func writeManifest(name string, records []record) error {
+	b, err := encodeManifest(records)
+	if err != nil {
+		return err
+	}
+	return writeFile(name, b)
+}
+
+// encodeManifest encodes the records in JSON Lines.
+func encodeManifest(records []record) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeReport writes a report of generated and failed types into the file.
+func writeReport(name, path string, types []string, schemas map[string]json.RawMessage, failures []failure) error {
+	generated := []string{}
+	for _, t := range types {
+		if _, ok := schemas[t]; ok {
+			generated = append(generated, t)
+		}
+	}
+	if failures == nil {
+		failures = []failure{}
+	}
+
+	b, err := json.MarshalIndent(struct {
+		Package   string    `json:"package"`
+		Generated []string  `json:"generated"`
+		Failures  []failure `json:"failures"`
+	}{path, generated, failures}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(name, append(b, '\n'))
+}
@@ -0,0 +1,545 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	const pkg = "../../internal/directivetest"
+
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"stdout", []string{"gen", pkg, "Config"}, exitOK, "{\n  \"properties\": {\n", ""},
+		{"compact", []string{"gen", "-indent", "", "-draft", "07", pkg, "Config"}, exitOK, `{"$schema":"http://json-schema.org/draft-07/schema#",`, ""},
+		{"unknown type", []string{"gen", pkg, "Nope"}, exitUsage, "", `does not declare exported type "Nope"`},
+		{"multiple types", []string{"gen", pkg, "Config", "Debug"}, exitUsage, "", "need -dir"},
+		{"manifest without files", []string{"gen", "-manifest", "manifest.jsonl", pkg, "Config"}, exitUsage, "", "-manifest needs -o or -dir"},
+		{"max failures without keep going", []string{"gen", "-max-failures", "1", pkg, "Config"}, exitUsage, "", "-max-failures needs -keep-going"},
+		{"watch stdout", []string{"gen", "-watch", pkg, "Config"}, exitUsage, "", "-watch needs -o or -dir"},
+		{"unknown draft", []string{"gen", "-draft", "08", pkg, "Config"}, exitUsage, "", `unknown draft "08"`},
+		{"unknown package", []string{"gen", "./nope", "Config"}, exitError, "", "cannot load ./nope"},
+		{"no command", nil, exitUsage, "", "usage:"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("want exit code %d but got %d: %s", tt.code, code, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.stdout) {
+				t.Errorf("want stdout beginning with %q but got %q", tt.stdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("want %q in stderr but got %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_all(t *testing.T) {
+	dir := t.TempDir()
+	var stderr bytes.Buffer
+	if code := run([]string{"gen", "-all", "-dir", dir, "../../internal/directivetest"}, os.Stdout, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for i := range names {
+		names[i] = filepath.Base(names[i])
+	}
+	sort.Strings(names)
+	if got, want := strings.Join(names, ","), "Config.json,Debug.json,Platform.json"; got != want {
+		t.Errorf("want %s but got %s", want, got)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "Config.json"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !bytes.Contains(b, []byte(`"title": "Config"`)) {
+		t.Errorf("unexpected schema: %s", b)
+	}
+
+	// the temporary program is removed
+	tmp, err := filepath.Glob("../../internal/directivetest/.jsonschema*")
+	if err != nil || len(tmp) != 0 {
+		t.Errorf("temporary programs remain: %v %v", tmp, err)
+	}
+}
+
+func TestRun_generic(t *testing.T) {
+	const pkg = "./testdata/generic"
+
+	// generic types are not selected by -all
+	for _, flags := range [][]string{nil, {"-keep-going"}} {
+		dir := t.TempDir()
+		args := append(append([]string{"gen", "-all", "-dir", dir}, flags...), pkg)
+		var stderr bytes.Buffer
+		if code := run(args, os.Stdout, &stderr); code != exitOK {
+			t.Fatalf("%v: want exit code %d but got %d: %s", flags, exitOK, code, stderr.String())
+		}
+		names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if len(names) != 1 || filepath.Base(names[0]) != "Item.json" {
+			t.Errorf("%v: want Item.json but got %v", flags, names)
+		}
+	}
+
+	var stderr bytes.Buffer
+	if code := run([]string{"gen", pkg, "Page"}, os.Stdout, &stderr); code != exitUsage {
+		t.Errorf("want exit code %d but got %d: %s", exitUsage, code, stderr.String())
+	}
+
+	// types which are not structs are generated by their names
+	var stdout bytes.Buffer
+	stderr.Reset()
+	if code := run([]string{"gen", "-indent", "", pkg, "Level"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+	}
+	if got, want := stdout.String(), `{"type":"number"}`+"\n"; got != want {
+		t.Errorf("want %q but got %q", want, got)
+	}
+}
+
+func TestRun_manifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "manifest.jsonl")
+	args := []string{"gen", "-dir", filepath.Join(dir, "schemas"), "-manifest", manifest, "-version", "v1", "../../internal/directivetest", "Debug", "Config"}
+	var stderr bytes.Buffer
+	if code := run(args, os.Stdout, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+	}
+
+	f, err := os.Open(manifest)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	defer f.Close()
+
+	var names []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var r record
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			t.Fatalf("invalid record %s: %v", s.Bytes(), err)
+		}
+		names = append(names, r.Name)
+
+		if want := "schemas/" + r.Name + ".json"; r.File != want {
+			t.Errorf("want file %s but got %s", want, r.File)
+		}
+		if r.Version != "v1" {
+			t.Errorf("want version v1 but got %q", r.Version)
+		}
+		schema, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(r.File)))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if fp, _ := newRecord(r.Name, r.File, "manifest.jsonl", "", schema); fp.Fingerprint != r.Fingerprint || !strings.HasPrefix(r.Fingerprint, "sha256:") {
+			t.Errorf("fingerprint %s does not match to the file %s", r.Fingerprint, r.File)
+		}
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if got, want := strings.Join(names, ","), "Debug,Config"; got != want {
+		t.Errorf("want records of %s but got %s", want, got)
+	}
+}
+
+func TestRun_since(t *testing.T) {
+	dir := t.TempDir()
+	schemas := filepath.Join(dir, "schemas")
+	manifest := filepath.Join(dir, "manifest.jsonl")
+	args := []string{"gen", "-dir", schemas, "-manifest", manifest, "-since", manifest, "../../internal/directivetest", "Config", "Debug"}
+
+	gen := func(t *testing.T) string {
+		t.Helper()
+		var stderr bytes.Buffer
+		if code := run(args, os.Stdout, &stderr); code != exitOK {
+			t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+		}
+		return stderr.String()
+	}
+
+	// the manifest does not exist at first
+	if got, want := gen(t), "added: Config\nadded: Debug\n2 added, 0 changed, 0 removed, 0 unchanged\n"; got != want {
+		t.Errorf("want summary %q but got %q", want, got)
+	}
+
+	// the fingerprint of Config is changed and Old is removed from the package
+	records, err := readManifest(manifest)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	config := records["Config"]
+	config.Fingerprint = "sha256:0"
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range []record{config, records["Debug"], {Name: "Old", Fingerprint: "sha256:0", File: "schemas/Old.json"}} {
+		if err := enc.Encode(r); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+	if err := os.WriteFile(manifest, buf.Bytes(), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for _, name := range []string{"Config.json", "Debug.json"} {
+		if err := os.Remove(filepath.Join(schemas, name)); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	if got, want := gen(t), "changed: Config\nremoved: Old\n0 added, 1 changed, 1 removed, 1 unchanged\n"; got != want {
+		t.Errorf("want summary %q but got %q", want, got)
+	}
+	if _, err := os.Stat(filepath.Join(schemas, "Config.json")); err != nil {
+		t.Error("changed schema is not written:", err)
+	}
+	if _, err := os.Stat(filepath.Join(schemas, "Debug.json")); err == nil {
+		t.Error("unchanged schema is written")
+	}
+
+	// the manifest records all schemas with their current fingerprints
+	records, err = readManifest(manifest)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(records) != 2 || records["Config"].Fingerprint == "sha256:0" {
+		t.Errorf("unexpected records: %v", records)
+	}
+	if got, want := gen(t), "0 added, 0 changed, 0 removed, 2 unchanged\n"; got != want {
+		t.Errorf("want summary %q but got %q", want, got)
+	}
+}
+
+func TestRun_keepGoing(t *testing.T) {
+	const pkg = "./testdata/partial"
+
+	cases := []struct {
+		name   string
+		flags  []string
+		code   int
+		files  string
+		stderr string
+	}{
+		{"stop", nil, exitError, "", "cannot generate schemas of"},
+		{"keep going", []string{"-keep-going"}, exitError, "Good.json", "2 of 3 types failed"},
+		{"under threshold", []string{"-keep-going", "-max-failures", "2"}, exitOK, "Good.json", "Complex: jsonschema: #/properties/n"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			report := filepath.Join(dir, "report.json")
+			args := append(append([]string{"gen", "-all", "-dir", filepath.Join(dir, "schemas"), "-report", report}, tt.flags...), pkg)
+			var stderr bytes.Buffer
+			if code := run(args, os.Stdout, &stderr); code != tt.code {
+				t.Errorf("want exit code %d but got %d: %s", tt.code, code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("want %q in stderr but got %q", tt.stderr, stderr.String())
+			}
+
+			names, err := filepath.Glob(filepath.Join(dir, "schemas", "*.json"))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			for i := range names {
+				names[i] = filepath.Base(names[i])
+			}
+			if got := strings.Join(names, ","); got != tt.files {
+				t.Errorf("want files %q but got %q", tt.files, got)
+			}
+
+			b, err := os.ReadFile(report)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			var r struct {
+				Generated []string
+				Failures  []failure
+			}
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got := strings.Join(r.Generated, ","); got != "Good" {
+				t.Errorf("want generated Good but got %s", got)
+			}
+			if len(r.Failures) != 2 {
+				t.Fatalf("want 2 failures but got %v", r.Failures)
+			}
+			expect := []failure{
+				{Type: "Complex", Kind: "unsupported type", Ref: "#/properties/n", Field: "partial.Complex.N"},
+				{Type: "Tag", Kind: "invalid tag syntax", Ref: "#/properties/n", Field: "partial.Tag.N"},
+			}
+			for i, f := range r.Failures {
+				f.Error = ""
+				if f != expect[i] {
+					t.Errorf("want failure %+v but got %+v", expect[i], f)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_implementations(t *testing.T) {
+	const pkg = "./testdata/shapes"
+
+	cases := []struct {
+		name   string
+		flags  []string
+		titles []string
+	}{
+		{"no implementations", nil, nil},
+		{"implementations", []string{"-implementations"}, []string{"Circle", "Square"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append(append([]string{"gen"}, tt.flags...), pkg, "Drawing")
+			if code := run(args, &stdout, &stderr); code != exitOK {
+				t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+			}
+
+			var schema struct {
+				Properties map[string]struct {
+					OneOf []struct {
+						Title string `json:"title"`
+					} `json:"oneOf"`
+				} `json:"properties"`
+			}
+			if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			var titles []string
+			for _, s := range schema.Properties["shape"].OneOf {
+				titles = append(titles, s.Title)
+			}
+			if got, want := strings.Join(titles, ","), strings.Join(tt.titles, ","); got != want {
+				t.Errorf("want implementations %q but got %q", want, got)
+			}
+			// the interface type opts out of discovery
+			if n := len(schema.Properties["named"].OneOf); n != 0 {
+				t.Errorf("want no implementations of Named but got %d", n)
+			}
+		})
+	}
+}
+
+func TestDiscoverInterfaces(t *testing.T) {
+	dir := t.TempDir()
+	src := `package a
+
+type I interface{ M() }
+
+type (
+	// J is an interface type which embeds another one.
+	J interface {
+		I
+		N()
+	}
+
+	// Hidden is not discovered.
+	//jsonschema:noimpl
+	Hidden interface{ M() }
+)
+
+type Number interface{ ~int | ~float64 }
+
+type Empty interface{}
+
+type A struct{}
+
+type B int
+
+//jsonschema:noimpl
+type C struct{}
+
+type G[T any] struct{ V T }
+
+type Alias = A
+
+type unexported struct{}
+`
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	got, err := discoverInterfaces(dir)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := []iface{
+		{Name: "I", Candidates: []string{"A", "B"}},
+		{Name: "J", Candidates: []string{"A", "B"}},
+	}
+	if g, e := fmt.Sprint(got), fmt.Sprint(expect); g != e {
+		t.Errorf("want %s but got %s", e, g)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(src, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, dir, 10*time.Millisecond, func() { calls <- struct{}{} })
+	}()
+
+	// generated at first
+	<-calls
+
+	if err := os.WriteFile(src, []byte("package a\n\ntype T struct{}\n"), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("changes are not detected")
+	}
+
+	// files which are not Go files of the package are ignored
+	if err := os.WriteFile(filepath.Join(dir, "a.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	select {
+	case <-calls:
+		t.Fatal("unexpected regeneration")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Error("unexpected error:", err)
+	}
+}
+
+func TestGenConfig_cache(t *testing.T) {
+	p, err := load("./testdata/generic")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	dir := t.TempDir()
+	c := &genConfig{
+		pkg:    p,
+		types:  []string{"Item", "Level"},
+		indent: "  ",
+		dir:    dir,
+		cache:  t.TempDir(),
+		keys:   map[string]string{},
+	}
+	var stderr bytes.Buffer
+	if code := c.run(io.Discard, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+	}
+	if len(c.keys) != 2 || c.keys["Item"] == "" || c.keys["Item"] == c.keys["Level"] {
+		t.Fatalf("unexpected cache keys: %v", c.keys)
+	}
+
+	// schemas whose cache keys do not change are not written again
+	item := filepath.Join(dir, "Item.json")
+	if err := os.WriteFile(item, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if code := c.run(io.Discard, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+	}
+	if b, err := os.ReadFile(item); err != nil || string(b) != "{}\n" {
+		t.Errorf("unchanged schema is written again: %s %v", b, err)
+	}
+
+	// a change of methods changes cache keys
+	keys := map[string]string{}
+	for k, v := range c.keys {
+		keys[k] = v
+	}
+	c.methods = "changed"
+	if code := c.run(io.Discard, &stderr); code != exitOK {
+		t.Fatalf("want exit code %d but got %d: %s", exitOK, code, stderr.String())
+	}
+	if c.keys["Item"] == keys["Item"] {
+		t.Error("cache keys do not change")
+	}
+	if b, err := os.ReadFile(item); err != nil || !bytes.Contains(b, []byte(`"title": "Item"`)) {
+		t.Errorf("changed schema is not written: %s %v", b, err)
+	}
+}
+
+func TestMethodsHash(t *testing.T) {
+	dir := t.TempDir()
+	write := func(src string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0o644); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		h, err := methodsHash(dir)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		return h
+	}
+
+	base := write("package a\n\ntype T struct{}\n\nfunc (T) A() int { return 1 }\n")
+	if h := write("package a\n\ntype T struct{ N int }\n\nfunc (T) A() int { return 1 }\n\nfunc f() {}\n"); h != base {
+		t.Error("changes of types and functions change the hash of methods")
+	}
+	if h := write("package a\n\ntype T struct{}\n\nfunc (T) A() int { return 2 }\n"); h == base {
+		t.Error("changes of methods do not change the hash")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "schemas", "T.json")
+	if err := writeFile(name, []byte("{}\n")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(name, past, past); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the same content is not written
+	if err := writeFile(name, []byte("{}\n")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !fi.ModTime().Equal(past) {
+		t.Error("the file which has the same content is rewritten")
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tenntenn/jsonschema/handler"
+)
+
+// runServe runs the serve subcommand.
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "localhost:8080", "address which the server listens on")
+	draft := fs.String("draft", "", "draft of schemas: 04, 06, 07, 2019-09 or 2020-12")
+	impls := fs.Bool("implementations", false, "generate fields of interface types as oneOf of their implementations in the package")
+	interval := fs.Duration("interval", time.Second, "interval of checking changes of Go files")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	if _, ok := drafts[*draft]; *draft != "" && !ok {
+		fmt.Fprintf(stderr, "unknown draft %q\n", *draft)
+		return exitUsage
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(stderr, "-interval must be positive")
+		return exitUsage
+	}
+
+	// all exported struct types are served without type names
+	names := fs.Args()[1:]
+	pkg, _, code := selectTypes(fs.Arg(0), names, len(names) == 0, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	b := &browser{pkg: pkg, names: names, draft: drafts[*draft], impls: *impls, stderr: stderr}
+	srv := &http.Server{Handler: b}
+	go srv.Serve(ln)
+	fmt.Fprintf(stderr, "serving schemas of %s on http://%s\n", pkg.ImportPath, ln.Addr())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = watch(ctx, pkg.Dir, *interval, b.update)
+	srv.Shutdown(context.Background())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// browser is an http.Handler which serves an index of schemas of types of the package,
+// the schemas as "/<type>.json" and their documentation as "/<type>.html".
+// The schemas are regenerated by update.
+type browser struct {
+	pkg *pkg
+	// names are names of the types; all exported struct types are served if it is empty,
+	// so types which are added to the package are discovered by update.
+	names  []string
+	draft  string
+	impls  bool
+	stderr io.Writer
+
+	mu       sync.RWMutex
+	types    []string
+	schemas  map[string]json.RawMessage
+	failures []failure
+	err      error
+	updated  time.Time
+}
+
+// update regenerates the schemas. If the schemas cannot be generated such as
+// compile errors of the package, the previous schemas are served with the error.
+func (b *browser) update() {
+	types, schemas, failures, err := b.generate()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+	if err != nil {
+		fmt.Fprintln(b.stderr, err)
+		return
+	}
+	b.types, b.schemas, b.failures, b.updated = types, schemas, failures, time.Now()
+	for _, f := range failures {
+		fmt.Fprintf(b.stderr, "%s: %s\n", f.Type, f.Error)
+	}
+	fmt.Fprintf(b.stderr, "generated schemas of %s\n", b.pkg.ImportPath)
+}
+
+// generate generates the schemas of the types and returns the types in the order of their names.
+func (b *browser) generate() ([]string, map[string]json.RawMessage, []failure, error) {
+	types := b.names
+	if len(types) == 0 {
+		declared, err := declaredTypes(b.pkg.Dir)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for name, isStruct := range declared {
+			if isStruct {
+				types = append(types, name)
+			}
+		}
+		sort.Strings(types)
+	}
+
+	var ifaces []iface
+	if b.impls {
+		var err error
+		if ifaces, err = discoverInterfaces(b.pkg.Dir); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	out, err := generate(b.pkg, types, b.draft, ifaces, nil, b.stderr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	schemas, failures := out.Schemas, out.Errors
+	for name, schema := range schemas {
+		if schemas[name], err = formatSchema(schema, "  "); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return types, schemas, failures, nil
+}
+
+func (b *browser) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if r.URL.Path == "/" {
+		b.serveIndex(w)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	for _, ext := range []string{".json", ".html"} {
+		schema, ok := b.schemas[strings.TrimSuffix(name, ext)]
+		if !strings.HasSuffix(name, ext) || !ok {
+			continue
+		}
+		if ext == ".json" {
+			w.Header().Set("Content-Type", handler.MediaTypeSchemaJSON)
+			w.Write(schema)
+			return
+		}
+		var doc bytes.Buffer
+		if err := handler.RenderDoc(&doc, schema, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(doc.Bytes())
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// serveIndex writes the index of the schemas and failures.
+func (b *browser) serveIndex(w http.ResponseWriter) {
+	var generated []string
+	for _, name := range b.types {
+		if _, ok := b.schemas[name]; ok {
+			generated = append(generated, name)
+		}
+	}
+	var updated string
+	if !b.updated.IsZero() {
+		updated = b.updated.Format(time.RFC3339)
+	}
+
+	var buf bytes.Buffer
+	err := browserTemplate.Execute(&buf, struct {
+		Package  string
+		Types    []string
+		Failures []failure
+		Error    error
+		Updated  string
+	}{b.pkg.ImportPath, generated, b.failures, b.err, updated})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// browserTemplate is the template of the index of the browser.
+var browserTemplate = template.Must(template.New("browser").Parse(`<html>
+	<head>
+		<title>Schemas of {{.Package}}</title>
+	</head>
+	<body>
+		<h1>Schemas of <code>{{.Package}}</code></h1>
+		{{- with .Error}}
+		<p>Schemas cannot be regenerated: <code>{{.}}</code></p>
+		{{- end}}
+		{{- with .Updated}}
+		<p>Generated at {{.}}</p>
+		{{- end}}
+		<table>
+			<tr><th>Name</th><th>Schema</th></tr>
+			{{- range .Types}}
+			<tr>
+				<td><a href="{{.}}.html">{{.}}</a></td>
+				<td><a href="{{.}}.json">{{.}}.json</a></td>
+			</tr>
+			{{- end}}
+		</table>
+		{{- with .Failures}}
+		<h2>Failures</h2>
+		<table>
+			<tr><th>Name</th><th>Error</th></tr>
+			{{- range .}}
+			<tr><td>{{.Type}}</td><td><code>{{.Error}}</code></td></tr>
+			{{- end}}
+		</table>
+		{{- end}}
+	</body>
+</html>
+`))
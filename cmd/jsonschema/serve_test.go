@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunServe(t *testing.T) {
+	const pkg = "../../internal/directivetest"
+
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"no package", []string{"serve"}, exitUsage, "usage:"},
+		{"unknown draft", []string{"serve", "-draft", "08", pkg}, exitUsage, `unknown draft "08"`},
+		{"interval", []string{"serve", "-interval", "0s", pkg}, exitUsage, "-interval must be positive"},
+		{"unknown type", []string{"serve", pkg, "Nope"}, exitUsage, `does not declare exported type "Nope"`},
+		{"invalid address", []string{"serve", "-addr", "localhost:-1", pkg}, exitError, "invalid port"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if code := run(tt.args, io.Discard, &stderr); code != tt.code {
+				t.Errorf("want exit code %d but got %d: %s", tt.code, code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("want %q in stderr but got %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestBrowser(t *testing.T) {
+	p, err := load("../../internal/directivetest")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	var stderr bytes.Buffer
+	b := &browser{pkg: p, stderr: &stderr}
+	b.update()
+	if !strings.Contains(stderr.String(), "generated schemas of "+p.ImportPath) {
+		t.Fatalf("schemas are not generated: %s", stderr.String())
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		b.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	cases := []struct {
+		path        string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/", http.StatusOK, "text/html; charset=utf-8", `<a href="Config.html">Config</a>`},
+		{"/Config.html", http.StatusOK, "text/html; charset=utf-8", "<h1>Config</h1>"},
+		{"/Debug.json", http.StatusOK, "application/schema+json", "{\n  \"properties\": {\n"},
+		{"/Nope.json", http.StatusNotFound, "", ""},
+		{"/Config", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range cases {
+		w := get(tt.path)
+		if w.Code != tt.code {
+			t.Errorf("%s: want status %d but got %d", tt.path, tt.code, w.Code)
+			continue
+		}
+		if tt.contentType != "" && w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: want Content-Type %q but got %q", tt.path, tt.contentType, w.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s: want %q in the body but got %s", tt.path, tt.body, w.Body.String())
+		}
+	}
+
+	// previous schemas are served with the error if the package cannot be regenerated
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\ntype (\n"), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	b.pkg = &pkg{ImportPath: p.ImportPath, Name: p.Name, Dir: dir}
+	b.update()
+	if index := get("/").Body.String(); !strings.Contains(index, "Schemas cannot be regenerated") || !strings.Contains(index, "Config.json") {
+		t.Errorf("unexpected index: %s", index)
+	}
+	if w := get("/Config.json"); w.Code != http.StatusOK {
+		t.Errorf("want status %d but got %d", http.StatusOK, w.Code)
+	}
+}
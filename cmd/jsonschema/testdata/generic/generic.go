@@ -0,0 +1,18 @@
+//go:build go1.18
+
+// Package generic has a generic type which cannot be generated without instantiation
+// and a type which is not a struct. It is used by tests of selections of types.
+package generic
+
+// Item is a type which can be generated.
+type Item struct {
+	Name string `json:"name"`
+}
+
+// Page is a generic type which is not selected by -all.
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+// Level is a type which is not a struct and generated only by its name.
+type Level int
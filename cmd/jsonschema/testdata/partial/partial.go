@@ -0,0 +1,18 @@
+// Package partial has types some of which cannot be generated.
+// It is used by tests of -keep-going.
+package partial
+
+// Good is a type which can be generated.
+type Good struct {
+	Name string `json:"name"`
+}
+
+// Complex is a type which cannot be generated because of a complex number.
+type Complex struct {
+	N complex128 `json:"n"`
+}
+
+// Tag is a type which cannot be generated because of a malformed tag.
+type Tag struct {
+	N int `json:"n" jsonschema:"default=x"`
+}
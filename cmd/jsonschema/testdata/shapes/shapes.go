@@ -0,0 +1,50 @@
+// Package shapes has an interface type and its implementations.
+// It is used by tests of -implementations.
+package shapes
+
+// Shape is an interface type whose implementations are discovered.
+type Shape interface {
+	Area() float64
+}
+
+// Named is an interface type which does not discover its implementations.
+//
+//jsonschema:noimpl
+type Named interface {
+	Name() string
+}
+
+// Drawing has fields of the interface types.
+type Drawing struct {
+	Shape Shape `json:"shape"`
+	Named Named `json:"named,omitempty"`
+}
+
+// Circle implements Shape by its value.
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+// Area implements Shape.
+func (c Circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+// Square implements Shape by its pointer.
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+// Area implements Shape.
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+// Legacy implements Shape but it is not discovered.
+//
+//jsonschema:noimpl
+type Legacy struct {
+	Width float64 `json:"width"`
+}
+
+// Area implements Shape.
+func (l Legacy) Area() float64 { return 0 }
+
+// Name implements Named.
+func (l Legacy) Name() string { return "legacy" }
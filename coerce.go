@@ -0,0 +1,136 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Warning is a problem of a document which is tolerated.
+type Warning struct {
+	// Pointer is a JSON Pointer of the location such as "/items/0".
+	Pointer string
+	// Message describes the problem.
+	Message string
+}
+
+func (w Warning) String() string {
+	p := w.Pointer
+	if p == "" {
+		p = "(root)"
+	}
+	return p + ": " + w.Message
+}
+
+// Coerce validates the document tolerating common sloppiness of clients
+// and returns the coerced document.
+// Numeric strings such as "1" are coerced to numbers and
+// "true" or "false" are coerced to booleans where the schema expects them.
+// Integers are always valid for numbers.
+// Each coercion is reported as a warning rather than an error.
+// If the document is still invalid after coercion, it returns a *ValidationError.
+func (s *Schema) Coerce(doc []byte) ([]byte, []Warning, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, nil, fmt.Errorf("jsonschema: cannot decode document: %w", err)
+	}
+
+	var warnings []Warning
+	for {
+		err := s.Validate(doc)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			return doc, warnings, err
+		}
+
+		coerced := false
+		for _, fe := range verr.Errors {
+			if fe.Keyword != "invalid_type" {
+				continue
+			}
+			expected, _ := fe.Details["expected"].(string)
+
+			var w *Warning
+			v, w = coerceAt(v, splitPointer(fe.Pointer), expected)
+			if w != nil {
+				w.Pointer = fe.Pointer
+				warnings = append(warnings, *w)
+				coerced = true
+			}
+		}
+
+		if !coerced {
+			return doc, warnings, err
+		}
+
+		if doc, err = json.Marshal(v); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// coerceAt coerces the value at the path to the expected type.
+// It returns a warning if the value is coerced.
+func coerceAt(v interface{}, path []string, expected string) (interface{}, *Warning) {
+	if len(path) == 0 {
+		return coerceValue(v, expected)
+	}
+
+	var w *Warning
+	switch c := v.(type) {
+	case map[string]interface{}:
+		if e, ok := c[path[0]]; ok {
+			c[path[0]], w = coerceAt(e, path[1:], expected)
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(c) {
+			c[i], w = coerceAt(c[i], path[1:], expected)
+		}
+	}
+	return v, w
+}
+
+func coerceValue(v interface{}, expected string) (interface{}, *Warning) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	for _, typ := range strings.Split(expected, ",") {
+		switch strings.TrimSpace(typ) {
+		case "number":
+			if n, ok := jsonNumber(s); ok {
+				return n, &Warning{Message: fmt.Sprintf("string %q is coerced to a number", s)}
+			}
+		case "integer":
+			if n, ok := jsonNumber(s); ok {
+				if _, err := n.Int64(); err == nil {
+					return n, &Warning{Message: fmt.Sprintf("string %q is coerced to an integer", s)}
+				}
+			}
+		case "boolean":
+			if s == "true" || s == "false" {
+				return s == "true", &Warning{Message: fmt.Sprintf("string %q is coerced to a boolean", s)}
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// jsonNumber parses s as a number of JSON.
+func jsonNumber(s string) (json.Number, bool) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return "", false
+	}
+	n, ok := v.(json.Number)
+	return n, ok
+}
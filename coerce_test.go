@@ -0,0 +1,99 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestSchema_Coerce(t *testing.T) {
+	s, err := CompileBytes([]byte(`{
+		"type": "object",
+		"properties": {
+			"price": {"type": "number", "minimum": 1},
+			"count": {"type": "integer"},
+			"ok": {"type": "boolean"},
+			"ids": {"type": "array", "items": {"type": "integer"}},
+			"name": {"type": "string"},
+			"v1.0": {"type": "object", "properties": {"a/b.c": {"type": "integer"}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		name     string
+		doc      string
+		expect   string
+		warnings []string
+		invalid  bool
+	}{
+		{
+			name:   "valid",
+			doc:    `{"price": 1.5, "count": 2}`,
+			expect: `{"price": 1.5, "count": 2}`,
+		},
+		{
+			name:   "coerced",
+			doc:    `{"price": "1.5", "count": "2", "ok": "true", "ids": [1, "2"], "name": "3"}`,
+			expect: `{"price": 1.5, "count": 2, "ok": true, "ids": [1, 2], "name": "3"}`,
+			warnings: []string{
+				`/count: string "2" is coerced to an integer`,
+				`/ids/1: string "2" is coerced to an integer`,
+				`/ok: string "true" is coerced to a boolean`,
+				`/price: string "1.5" is coerced to a number`,
+			},
+		},
+		{
+			name:     "dotted names",
+			doc:      `{"v1.0": {"a/b.c": "1"}}`,
+			expect:   `{"v1.0": {"a/b.c": 1}}`,
+			warnings: []string{`/v1.0/a~1b.c: string "1" is coerced to an integer`},
+		},
+		{
+			name:    "not coercible",
+			doc:     `{"count": "1.5", "ok": "yes", "price": "NaN"}`,
+			invalid: true,
+		},
+		{
+			name:     "invalid after coercion",
+			doc:      `{"price": "0"}`,
+			warnings: []string{`/price: string "0" is coerced to a number`},
+			invalid:  true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := s.Coerce([]byte(tt.doc))
+
+			var msgs []string
+			for _, w := range warnings {
+				msgs = append(msgs, w.String())
+			}
+			sort.Strings(msgs)
+			if !reflect.DeepEqual(msgs, tt.warnings) {
+				t.Errorf("want warnings %q but got %q", tt.warnings, msgs)
+			}
+
+			if tt.invalid {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Errorf("want *ValidationError but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, string(got), tt.expect); diff != "" {
+				t.Errorf("coerced document does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
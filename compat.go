@@ -0,0 +1,217 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CompatibleTags makes the generator understand struct tags in the manner of
+// github.com/invopop/jsonschema and github.com/alecthomas/jsonschema,
+// so that structs which are annotated for them can be used without rewriting tags.
+// It supports the following tags:
+//
+//	jsonschema:"title=...,description=...,minimum=1,enum=a,enum=b,required"
+//	jsonschema_description:"..."
+//	jsonschema_extras:"key=value,key=value"
+//
+// A field which is tagged `jsonschema:"-"` is ignored.
+// Values of default, enum and example are converted according to the type of the field.
+// Unknown keywords are ignored as same as these libraries.
+func CompatibleTags() Option {
+	return configOption(func(c *config) {
+		c.compatTags = true
+	})
+}
+
+type compatItem struct {
+	key   string
+	value string
+	flag  bool
+}
+
+// splitCompatTag splits a tag by commas.
+// A comma which is escaped by a backslash is not a separator.
+func splitCompatTag(tag string) []compatItem {
+	var (
+		items []compatItem
+		sb    strings.Builder
+	)
+
+	add := func() {
+		item := sb.String()
+		sb.Reset()
+		if item == "" {
+			return
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 1 {
+			items = append(items, compatItem{key: kv[0], flag: true})
+			return
+		}
+		items = append(items, compatItem{key: kv[0], value: kv[1]})
+	}
+
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			sb.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			add()
+		default:
+			sb.WriteByte(tag[i])
+		}
+	}
+	add()
+
+	return items
+}
+
+func compatIgnored(tag reflect.StructTag) bool {
+	return tag.Get("jsonschema") == "-"
+}
+
+func compatRequired(tag reflect.StructTag) bool {
+	for _, item := range splitCompatTag(tag.Get("jsonschema")) {
+		if item.key == "required" && item.flag {
+			return true
+		}
+	}
+	return false
+}
+
+// compatOption creates an Option which applies a tag in the manner of invopop/jsonschema.
+func compatOption(tag reflect.StructTag) Option {
+	return func(o Object) (Object, error) {
+		if desc, ok := tag.Lookup("jsonschema_description"); ok {
+			o.Set("description", desc)
+		}
+
+		for _, item := range splitCompatTag(tag.Get("jsonschema")) {
+			if err := applyCompatItem(o, item); err != nil {
+				return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid tag %s: %w", item.key, err))
+			}
+		}
+
+		extras := map[string]interface{}{}
+		var keys []string
+		for _, item := range splitCompatTag(tag.Get("jsonschema_extras")) {
+			switch v := extras[item.key].(type) {
+			case nil:
+				extras[item.key] = item.value
+				keys = append(keys, item.key)
+			case string:
+				extras[item.key] = []interface{}{v, item.value}
+			case []interface{}:
+				extras[item.key] = append(v, item.value)
+			}
+		}
+		for _, k := range keys {
+			o.Set(k, extras[k])
+		}
+
+		return o, nil
+	}
+}
+
+func applyCompatItem(o Object, item compatItem) error {
+	typ, _ := o.Get("type")
+
+	switch item.key {
+	case "title", "description", "format", "pattern", "type", "$comment", "contentEncoding", "contentMediaType":
+		o.Set(item.key, item.value)
+	case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf":
+		n, err := strconv.ParseFloat(item.value, 64)
+		if err != nil {
+			return err
+		}
+		o.Set(item.key, n)
+	case "minLength", "maxLength", "minItems", "maxItems", "minContains", "maxContains", "minProperties", "maxProperties":
+		n, err := strconv.Atoi(item.value)
+		if err != nil {
+			return err
+		}
+		o.Set(item.key, n)
+	case "readOnly", "writeOnly", "uniqueItems", "nullable", "deprecated":
+		b := true
+		if !item.flag {
+			var err error
+			if b, err = strconv.ParseBool(item.value); err != nil {
+				return err
+			}
+		}
+		o.Set(item.key, b)
+	case "default":
+		v, err := compatValue(typ, item.value)
+		if err != nil {
+			return err
+		}
+		o.Set("default", v)
+	case "example":
+		v, err := compatValue(typ, item.value)
+		if err != nil {
+			return err
+		}
+		examples, _ := o.Get("examples")
+		es, _ := examples.([]interface{})
+		o.Set("examples", append(es, v))
+	case "enum":
+		target, typ := enumTarget(o)
+		v, err := compatValue(typ, item.value)
+		if err != nil {
+			return err
+		}
+		enum, _ := target.Get("enum")
+		es, _ := enum.([]interface{})
+		target.Set("enum", append(es, v))
+	case "oneof_type", "anyof_type":
+		var schemas []interface{}
+		for _, t := range strings.Split(item.value, ";") {
+			schemas = append(schemas, map[string]interface{}{"type": t})
+		}
+		o.Delete("type")
+		o.Set(compatCombinator(item.key), schemas)
+	case "oneof_ref", "anyof_ref":
+		var schemas []interface{}
+		for _, ref := range strings.Split(item.value, ";") {
+			schemas = append(schemas, map[string]interface{}{"$ref": ref})
+		}
+		o.Set(compatCombinator(item.key), schemas)
+	}
+	return nil
+}
+
+// enumTarget returns the object which has enum of the field and its type.
+// Enum of an array field restricts its items.
+func enumTarget(o Object) (Object, interface{}) {
+	typ, _ := o.Get("type")
+	if typ == "array" {
+		items, _ := o.Get("items")
+		if m, ok := items.(map[string]interface{}); ok {
+			return &obj{m: m, ref: o.Ref() + "/items"}, m["type"]
+		}
+	}
+	return o, typ
+}
+
+func compatCombinator(key string) string {
+	if strings.HasPrefix(key, "oneof_") {
+		return "oneOf"
+	}
+	return "anyOf"
+}
+
+// compatValue converts s into a value of the JSON type.
+func compatValue(typ interface{}, s string) (interface{}, error) {
+	switch typ {
+	case "number":
+		return strconv.ParseFloat(s, 64)
+	case "integer":
+		return strconv.Atoi(s)
+	case "boolean":
+		return strconv.ParseBool(s)
+	}
+	return s, nil
+}
@@ -0,0 +1,88 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestCompatibleTags(t *testing.T) {
+	type User struct {
+		ID      int      `json:"id" jsonschema:"title=ID,minimum=1,maximum=100,default=10,example=42"`
+		Name    string   `json:"name" jsonschema:"minLength=1,maxLength=64,pattern=^[a-z]+$,required" jsonschema_description:"name of the user"`
+		Role    string   `json:"role" jsonschema:"enum=admin,enum=member,description=a\\, role"`
+		Tags    []string `json:"tags" jsonschema:"enum=a,enum=b,uniqueItems"`
+		Active  bool     `json:"active" jsonschema:"default=true,readOnly"`
+		Value   string   `json:"value" jsonschema:"oneof_type=string;number"`
+		Color   string   `json:"color" jsonschema_extras:"x-color=red,x-alias=c,x-alias=col"`
+		Ignored string   `json:"ignored" jsonschema:"-"`
+	}
+
+	expect := `{
+		"title": "User",
+		"type": "object",
+		"required": ["id", "name", "role", "tags", "active", "value", "color"],
+		"properties": {
+			"id": {
+				"type": "number",
+				"title": "ID",
+				"minimum": 1,
+				"maximum": 100,
+				"default": 10,
+				"examples": [42],
+				"propertyOrder": 0
+			},
+			"name": {
+				"type": "string",
+				"description": "name of the user",
+				"minLength": 1,
+				"maxLength": 64,
+				"pattern": "^[a-z]+$",
+				"propertyOrder": 1
+			},
+			"role": {
+				"type": "string",
+				"enum": ["admin", "member"],
+				"description": "a, role",
+				"propertyOrder": 2
+			},
+			"tags": {
+				"type": "array",
+				"items": {"type": "string", "enum": ["a", "b"]},
+				"uniqueItems": true,
+				"propertyOrder": 3
+			},
+			"active": {
+				"type": "boolean",
+				"default": true,
+				"readOnly": true,
+				"propertyOrder": 4
+			},
+			"value": {
+				"oneOf": [{"type": "string"}, {"type": "number"}],
+				"propertyOrder": 5
+			},
+			"color": {
+				"type": "string",
+				"x-color": "red",
+				"x-alias": ["c", "col"],
+				"propertyOrder": 6
+			}
+		}
+	}`
+
+	got, err := GenerateString(User{Tags: []string{}}, CompatibleTags())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	type Invalid struct {
+		N int `jsonschema:"minimum=one"`
+	}
+	if _, err := GenerateString(Invalid{}, CompatibleTags()); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
@@ -0,0 +1,204 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// CompatibilityLevel is a subset of keywords which validators of generated schemas implement.
+type CompatibilityLevel int
+
+const (
+	// CompatibilityFull allows all keywords of the draft. It is the default.
+	CompatibilityFull CompatibilityLevel = iota
+	// CompatibilityStandard allows keywords which most validators implement:
+	// keywords of CompatibilityBasic, references, definitions, annotations, const, format,
+	// numeric, string, array and object constraints, patternProperties,
+	// additionalProperties, additionalItems, allOf, anyOf, oneOf and not.
+	// Conditionals, unevaluated*, contains, dependencies, propertyNames,
+	// dynamic references and anchors are dropped and prefixItems become items of anyOf.
+	CompatibilityStandard
+	// CompatibilityBasic allows only type, properties, required, items and enum
+	// besides $schema, title and description.
+	// References are inlined, references to themselves are dropped
+	// and const becomes enum of the value.
+	CompatibilityBasic
+)
+
+// compatibilityKeywords are keywords which levels allow.
+var compatibilityKeywords = map[CompatibilityLevel]map[string]bool{
+	CompatibilityBasic: keywordSet(basicKeywords),
+	CompatibilityStandard: keywordSet(basicKeywords,
+		"$id", "id", "$ref", "$defs", "definitions", "$comment",
+		"default", "examples", "readOnly", "writeOnly", "deprecated",
+		"const", "format", "multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+		"maxLength", "minLength", "pattern", "contentEncoding", "contentMediaType",
+		"maxItems", "minItems", "uniqueItems", "additionalItems",
+		"maxProperties", "minProperties", "patternProperties", "additionalProperties",
+		"allOf", "anyOf", "oneOf", "not"),
+}
+
+var basicKeywords = []string{"$schema", "title", "description", "type", "properties", "required", "items", "enum"}
+
+func keywordSet(base []string, keywords ...string) map[string]bool {
+	s := make(map[string]bool, len(base)+len(keywords))
+	for _, k := range append(base, keywords...) {
+		s[k] = true
+	}
+	return s
+}
+
+// Compatibility restricts generated schemas to the keywords of the level
+// for gateways and legacy validators which implement a part of the specification.
+// Keywords which the level does not allow are downgraded to equivalent or looser ones if possible
+// and dropped otherwise, so generated schemas may accept more documents than Go types do.
+// Extensions such as propertyOrder and x-* keywords are kept.
+// CheckCompatibility reports the changes.
+func Compatibility(level CompatibilityLevel) Option {
+	return configOption(func(c *config) {
+		c.compatibility = level
+	})
+}
+
+// CompatibilityChange is a keyword which is downgraded or dropped for a CompatibilityLevel.
+type CompatibilityChange struct {
+	// Ref is the reference of the object which has the keyword.
+	Ref string
+	// Keyword is the downgraded or dropped keyword such as "if".
+	Keyword string
+	// Change describes how the keyword is changed such as "dropped".
+	Change string
+}
+
+func (c CompatibilityChange) String() string {
+	return fmt.Sprintf("%s: %s is %s", c.Ref, c.Keyword, c.Change)
+}
+
+// CheckCompatibility generates the schema of v for the level and reports keywords
+// which are downgraded or dropped, sorted by their references and keywords.
+// The level overrides Compatibility of the options.
+func CheckCompatibility(v interface{}, level CompatibilityLevel, opts ...Option) ([]CompatibilityChange, error) {
+	opts = append(opts[:len(opts):len(opts)], Compatibility(level))
+	g := gen{cfg: newConfig(opts)}
+	o := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+	if err := g.do(o, reflect.ValueOf(v), opts...); err != nil {
+		return nil, err
+	}
+	if err := g.finish(o); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(g.compatibilityChanges, func(i, j int) bool {
+		a, b := g.compatibilityChanges[i], g.compatibilityChanges[j]
+		if a.Ref != b.Ref {
+			return a.Ref < b.Ref
+		}
+		return a.Keyword < b.Keyword
+	})
+	return g.compatibilityChanges, nil
+}
+
+// applyCompatibility restricts the root schema to the keywords of the compatibility level.
+func (g *gen) applyCompatibility(root *obj) error {
+	allowed, ok := compatibilityKeywords[g.cfg.compatibility]
+	if !ok {
+		return nil
+	}
+	// the root is being inlined as "#"
+	if err := g.restrictKeywords(root.m, root, allowed, []string{"#"}); err != nil {
+		return err
+	}
+
+	// definitions are removed after all references are inlined
+	for _, k := range []string{"$defs", "definitions"} {
+		if _, ok := root.m[k]; ok && !allowed[k] {
+			delete(root.m, k)
+			g.changeKeyword(root.Ref(), k, "dropped after references are inlined")
+		}
+	}
+	return nil
+}
+
+// restrictKeywords restricts the object and its subschemas to the allowed keywords.
+// refs are references which are being inlined.
+func (g *gen) restrictKeywords(root map[string]interface{}, o *obj, allowed map[string]bool, refs []string) error {
+	s := o.m
+	if r, ok := s["$ref"].(string); ok && !allowed["$ref"] {
+		if inRefs(refs, r) {
+			delete(s, "$ref")
+			g.changeKeyword(o.ref, "$ref", fmt.Sprintf("dropped because %s refers to itself", r))
+		} else {
+			refs = append(refs, r)
+			if err := inlineRef(root, s, o.ref); err != nil {
+				return err
+			}
+			g.changeKeyword(o.ref, "$ref", fmt.Sprintf("inlined from %s", r))
+		}
+	}
+
+	if v, ok := s["const"]; ok && !allowed["const"] {
+		delete(s, "const")
+		if _, ok := s["enum"]; !ok {
+			s["enum"] = []interface{}{v}
+			g.changeKeyword(o.ref, "const", "converted to enum")
+		} else {
+			g.changeKeyword(o.ref, "const", "dropped")
+		}
+	}
+
+	if prefix, ok := s["prefixItems"].([]interface{}); ok && !allowed["prefixItems"] && allowed["anyOf"] {
+		// elements of tuples are any of the schemas of their positions
+		anyOf := append([]interface{}{}, prefix...)
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			anyOf = append(anyOf, items)
+		}
+		delete(s, "prefixItems")
+		s["items"] = map[string]interface{}{"anyOf": anyOf}
+		g.changeKeyword(o.ref, "prefixItems", "converted to items of anyOf")
+	}
+
+	for _, k := range sortedKeys(s) {
+		if !isStandardKeyword(k) || allowed[k] || k == "$defs" || k == "definitions" {
+			continue
+		}
+		delete(s, k)
+		g.changeKeyword(o.ref, k, "dropped")
+	}
+	// boolean items such as items:false of tuples
+	if _, ok := s["items"].(bool); ok && !allowed["prefixItems"] && !allowed["additionalItems"] {
+		delete(s, "items")
+		g.changeKeyword(o.ref, "items", "dropped")
+	}
+
+	for _, k := range sortedKeys(s) {
+		// definitions which are not allowed are inlined instead of being restricted
+		if (k == "$defs" || k == "definitions") && !allowed[k] {
+			continue
+		}
+		for _, sub := range subschemas(o.ref, k, s[k]) {
+			if err := g.restrictKeywords(root, sub.(*obj), allowed, refs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// inRefs reports whether the reference is in refs.
+func inRefs(refs []string, ref string) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *gen) changeKeyword(ref, keyword, change string) {
+	g.compatibilityChanges = append(g.compatibilityChanges, CompatibilityChange{Ref: ref, Keyword: keyword, Change: change})
+}
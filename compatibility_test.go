@@ -0,0 +1,163 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type compatibilityNode struct {
+	Name     string               `json:"name" jsonschema:"minLength=1"`
+	Kind     string               `json:"kind"`
+	Point    [2]float64           `json:"point"`
+	Tags     []string             `json:"tags" jsonschema:"containsEnum=a|b"`
+	Parent   *compatibilityParent `json:"parent"`
+	Children []*compatibilityNode `json:"children"`
+}
+
+type compatibilityParent struct {
+	ID string `json:"id"`
+}
+
+func TestCompatibility(t *testing.T) {
+	opts := []Option{
+		SharedTypes(SharedTypesRef),
+		Arrays(ArrayPrefixItems),
+		MapKeywords(KeywordRenames{"propertyOrder": ""}),
+		ByReference("#/properties/kind", func(o Object) (Object, error) {
+			o.Set("const", "node")
+			return o, nil
+		}),
+	}
+	v := compatibilityNode{Tags: []string{}}
+
+	cases := []struct {
+		name    string
+		level   CompatibilityLevel
+		expect  string
+		changes []string
+	}{
+		{"full", CompatibilityFull, `{
+			"$defs": {
+				"compatibilityParent": {
+					"title": "compatibilityParent",
+					"type": "object",
+					"required": ["id"],
+					"properties": {"id": {"type": "string"}}
+				}
+			},
+			"title": "compatibilityNode",
+			"type": "object",
+			"required": ["name", "kind", "point", "tags", "children"],
+			"properties": {
+				"name": {"type": "string", "minLength": 1},
+				"kind": {"type": "string", "const": "node"},
+				"point": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}], "items": false, "minItems": 2},
+				"tags": {"type": "array", "items": {"type": "string"}, "contains": {"enum": ["a", "b"]}},
+				"parent": {"$ref": "#/$defs/compatibilityParent"},
+				"children": {"type": "array", "items": {"$ref": "#"}}
+			}
+		}`, nil},
+		{"standard", CompatibilityStandard, `{
+			"$defs": {
+				"compatibilityParent": {
+					"title": "compatibilityParent",
+					"type": "object",
+					"required": ["id"],
+					"properties": {"id": {"type": "string"}}
+				}
+			},
+			"title": "compatibilityNode",
+			"type": "object",
+			"required": ["name", "kind", "point", "tags", "children"],
+			"properties": {
+				"name": {"type": "string", "minLength": 1},
+				"kind": {"type": "string", "const": "node"},
+				"point": {"type": "array", "items": {"anyOf": [{"type": "number"}, {"type": "number"}]}, "minItems": 2},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"parent": {"$ref": "#/$defs/compatibilityParent"},
+				"children": {"type": "array", "items": {"$ref": "#"}}
+			}
+		}`, []string{
+			"#/properties/point: prefixItems is converted to items of anyOf",
+			"#/properties/tags: contains is dropped",
+		}},
+		{"basic", CompatibilityBasic, `{
+			"title": "compatibilityNode",
+			"type": "object",
+			"required": ["name", "kind", "point", "tags", "children"],
+			"properties": {
+				"name": {"type": "string"},
+				"kind": {"type": "string", "enum": ["node"]},
+				"point": {"type": "array"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"parent": {
+					"title": "compatibilityParent",
+					"type": "object",
+					"required": ["id"],
+					"properties": {"id": {"type": "string"}}
+				},
+				"children": {"type": "array", "items": {}}
+			}
+		}`, []string{
+			"#/: $defs is dropped after references are inlined",
+			"#/properties/children/items: $ref is dropped because # refers to itself",
+			"#/properties/kind: const is converted to enum",
+			"#/properties/name: minLength is dropped",
+			"#/properties/parent: $ref is inlined from #/$defs/compatibilityParent",
+			"#/properties/point: items is dropped",
+			"#/properties/point: minItems is dropped",
+			"#/properties/point: prefixItems is dropped",
+			"#/properties/tags: contains is dropped",
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(v, append(opts, Compatibility(tt.level))...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+
+			changes, err := CheckCompatibility(v, tt.level, opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			var descs []string
+			for _, c := range changes {
+				descs = append(descs, c.String())
+			}
+			if !reflect.DeepEqual(descs, tt.changes) {
+				t.Errorf("want changes %q but got %q", tt.changes, descs)
+			}
+		})
+	}
+}
+
+func TestCompatibility_draft07(t *testing.T) {
+	type T struct {
+		Point [2]string `json:"point" jsonschema:"tuple"`
+	}
+	got, err := GenerateString(T{}, Draft(Draft07), Compatibility(CompatibilityBasic), MapKeywords(KeywordRenames{"propertyOrder": ""}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	// tuples of items are kept but additionalItems is dropped
+	expect := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title": "T",
+		"type": "object",
+		"required": ["point"],
+		"properties": {
+			"point": {"type": "array", "items": [{"type": "string"}, {"type": "string"}]}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
@@ -0,0 +1,409 @@
+package jsonschema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Schema is a compiled JSON Schema which validates JSON documents.
+type Schema struct {
+	schema *gojsonschema.Schema
+	// doc is the decoded root schema.
+	doc interface{}
+	// errMsgKey is the key of the extension which holds custom error messages.
+	errMsgKey string
+	// sl, base and remotes are the schema loader, the URI of the root schema
+	// and loaded remote schemas, which are used to compile subschemas.
+	sl      *gojsonschema.SchemaLoader
+	base    string
+	remotes map[string]interface{}
+	// items is the schema of items which is compiled by Items once
+	// because a schema loader cannot have the same URI twice.
+	items     *Schema
+	itemsOnce sync.Once
+	// err is an error of compilation which is reported by Validate.
+	err error
+	// fast is the compiled validator for valid documents.
+	// It is nil if the schema uses keywords which it does not support.
+	fast *validator
+}
+
+// CompileOption is an option of Compile.
+type CompileOption func(c *compiler)
+
+type compiler struct {
+	ctx       context.Context
+	loader    Loader
+	sl        *gojsonschema.SchemaLoader
+	fsys      fs.FS
+	seen      map[string]bool
+	remotes   map[string]interface{}
+	errMsgKey string
+	policy    *RemotePolicy
+	store     CacheStore
+}
+
+// WithLoader resolves references to remote schemas such as "https://example.com/user.json"
+// by the loader. Without loaders, remote references cannot be compiled.
+func WithLoader(l Loader) CompileOption {
+	return func(c *compiler) {
+		c.loader = l
+	}
+}
+
+// WithCacheStore caches remote schemas which are loaded by the loader of WithLoader in the store,
+// so multi-instance services can share them without loading them from their hosts again.
+// Cached schemas are still checked by the size limit of WithRemotePolicy.
+func WithCacheStore(s CacheStore) CompileOption {
+	return func(c *compiler) {
+		c.store = s
+	}
+}
+
+// Compile compiles a schema file of the name in fsys such as externally authored schemas.
+// Relative references to other files such as {"$ref": "defs.json#/$defs/id"}
+// are resolved in fsys, so multi-file schemas can be compiled.
+func Compile(fsys fs.FS, name string, opts ...CompileOption) (*Schema, error) {
+	return CompileContext(context.Background(), fsys, name, opts...)
+}
+
+// CompileContext is same as Compile but remote schemas are loaded with the context.
+func CompileContext(ctx context.Context, fsys fs.FS, name string, opts ...CompileOption) (*Schema, error) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if !fs.ValidPath(name) {
+		return nil, newError(ErrRefInvalid, name, fmt.Errorf("invalid path"))
+	}
+
+	c := newCompiler(ctx, fsys, opts)
+	uri := "file:///" + name
+	doc, err := c.preloadFile(uri, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := gojsonschema.NewReferenceLoaderFileSystem(uri, http.FS(fsys))
+	s, err := c.sl.Compile(loader)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot compile %s: %w", name, err)
+	}
+
+	return c.newSchema(s, doc, uri), nil
+}
+
+// CompileBytes compiles a schema which does not refer to other files.
+// It can refer to remote schemas with WithLoader.
+func CompileBytes(schema []byte, opts ...CompileOption) (*Schema, error) {
+	c := newCompiler(context.Background(), nil, opts)
+
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot compile schema: %w", err)
+	}
+	if err := c.preload(&url.URL{}, doc, 0); err != nil {
+		return nil, err
+	}
+
+	s, err := c.sl.Compile(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot compile schema: %w", err)
+	}
+	return c.newSchema(s, doc, ""), nil
+}
+
+func (c *compiler) newSchema(s *gojsonschema.Schema, doc interface{}, base string) *Schema {
+	// schemas which the validator does not support are validated by gojsonschema only
+	fast, _ := compileValidator(doc)
+	return &Schema{schema: s, doc: doc, errMsgKey: c.errMsgKey, sl: c.sl, base: base, remotes: c.remotes, fast: fast}
+}
+
+func newCompiler(ctx context.Context, fsys fs.FS, opts []CompileOption) *compiler {
+	c := &compiler{
+		ctx:       ctx,
+		sl:        gojsonschema.NewSchemaLoader(),
+		fsys:      fsys,
+		seen:      map[string]bool{},
+		remotes:   map[string]interface{}{},
+		errMsgKey: ErrorMessageKey,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// preloadFile loads remote schemas which are referred from the file of the URI
+// and returns the decoded file. The depth is the number of remote schemas in the chain of references.
+func (c *compiler) preloadFile(uri string, depth int) (interface{}, error) {
+	c.seen[uri] = true
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, newError(ErrRefInvalid, uri, err)
+	}
+
+	b, err := fs.ReadFile(c.fsys, strings.TrimPrefix(u.Path, "/"))
+	if err != nil {
+		return nil, newError(ErrRefInvalid, uri, err)
+	}
+
+	doc, err := decodeJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot compile %s: %w", uri, err)
+	}
+
+	return doc, c.preload(u, doc, depth)
+}
+
+// preload loads remote schemas which are referred from the doc
+// and adds them to the schema loader in advance,
+// because gojsonschema loads them without any restrictions.
+// The depth is the number of remote schemas in the chain of references to the doc.
+func (c *compiler) preload(base *url.URL, doc interface{}, depth int) error {
+	for _, ref := range collectRefs(doc, nil) {
+		r, err := url.Parse(ref)
+		if err != nil {
+			return newError(ErrRefInvalid, ref, err)
+		}
+
+		u := base.ResolveReference(r)
+		u.Fragment = ""
+		uri := u.String()
+		if uri == "" || c.seen[uri] {
+			continue
+		}
+		c.seen[uri] = true
+
+		switch u.Scheme {
+		case "file":
+			if c.fsys == nil {
+				return newError(ErrRefInvalid, ref, fmt.Errorf("files cannot be referred"))
+			}
+			if _, err := c.preloadFile(uri, depth); err != nil {
+				return err
+			}
+		case "http", "https":
+			if c.loader == nil {
+				return newError(ErrRefInvalid, ref, fmt.Errorf("no loader for remote references"))
+			}
+
+			if err := c.policy.before(u, depth+1, len(c.remotes)); err != nil {
+				return err
+			}
+			b, err := c.load(u)
+			if err != nil {
+				return err
+			}
+
+			var remote interface{}
+			if err := json.Unmarshal(b, &remote); err != nil {
+				return fmt.Errorf("jsonschema: cannot compile %s: %w", uri, err)
+			}
+			if err := c.sl.AddSchema(uri, gojsonschema.NewGoLoader(remote)); err != nil {
+				return fmt.Errorf("jsonschema: cannot compile %s: %w", uri, err)
+			}
+			c.remotes[uri] = remote
+
+			if err := c.preload(u, remote, depth+1); err != nil {
+				return err
+			}
+		default:
+			return newError(ErrRefInvalid, ref, fmt.Errorf("unsupported reference"))
+		}
+	}
+
+	return nil
+}
+
+// load loads the remote schema of the URL by the loader or from the cache store.
+func (c *compiler) load(u *url.URL) ([]byte, error) {
+	uri := u.String()
+	var key string
+	if c.store != nil {
+		key = remoteCacheKey(uri)
+		b, ok, err := c.store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return b, c.policy.after(uri, b)
+		}
+	}
+
+	if err := c.policy.wait(c.ctx, u); err != nil {
+		return nil, err
+	}
+	b, err := c.loader.Load(c.policy.withSizeLimit(c.ctx), uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.policy.after(uri, b); err != nil {
+		return nil, err
+	}
+
+	if c.store != nil {
+		if err := c.store.Set(key, b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// remoteCacheKey returns the fingerprint of the remote schema of the URI in cache stores.
+func remoteCacheKey(uri string) string {
+	h := sha256.New()
+	fmt.Fprintln(h, cacheVersion)
+	fmt.Fprintf(h, "remote:%s\n", uri)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// collectRefs collects values of $ref in the doc.
+func collectRefs(doc interface{}, refs []string) []string {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		if ref, ok := doc["$ref"].(string); ok {
+			refs = append(refs, ref)
+		}
+		for _, k := range sortedKeys(doc) {
+			refs = collectRefs(doc[k], refs)
+		}
+	case []interface{}:
+		for _, v := range doc {
+			refs = collectRefs(v, refs)
+		}
+	}
+	return refs
+}
+
+// Validate validates the JSON document.
+// If the document is not valid, it returns a *ValidationError.
+// Valid documents are checked by the compiled representation of the schema
+// and errors of invalid documents are reported by gojsonschema.
+func (s *Schema) Validate(doc []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.fast != nil {
+		if v, err := decodeJSON(doc); err == nil && s.fast.valid(v) {
+			return nil
+		}
+	}
+
+	r, err := s.schema.Validate(gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot validate: %w", err)
+	}
+
+	if r.Valid() {
+		return nil
+	}
+
+	verr := &ValidationError{
+		Errors: make([]FieldError, len(r.Errors())),
+	}
+	for i, e := range r.Errors() {
+		details := make(map[string]interface{}, len(e.Details()))
+		for k, v := range e.Details() {
+			if k != "field" && k != "context" {
+				details[k] = v
+			}
+		}
+		verr.Errors[i] = FieldError{
+			Field:   e.Field(),
+			Pointer: contextPointer(e.Context()),
+			Keyword: e.Type(),
+			Message: e.Description(),
+			Details: details,
+		}
+		if msg, ok := s.errorMessage(verr.Errors[i]); ok {
+			verr.Errors[i].Message = msg
+		}
+	}
+
+	return verr
+}
+
+// ValidateValue encodes v as JSON and validates it.
+func (s *Schema) ValidateValue(v interface{}) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Validate(doc)
+}
+
+// Validate compiles the schema and validates the JSON document against it.
+// The schema is compiled as CompileBytes, so it is better to use Schema
+// to validate many documents against the same schema.
+// If the document is not valid, it returns a *ValidationError
+// which reports each violation with a JSON Pointer of the field such as "/items/0/name".
+func Validate(schema, doc []byte, opts ...CompileOption) error {
+	s, err := CompileBytes(schema, opts...)
+	if err != nil {
+		return err
+	}
+	return s.Validate(doc)
+}
+
+// ValidationError is an error which reports that a document is not valid against a schema.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i := range e.Errors {
+		msgs[i] = e.Errors[i].String()
+	}
+	return "jsonschema: invalid document: " + strings.Join(msgs, "; ")
+}
+
+// FieldError is an error of a field of a document.
+type FieldError struct {
+	// Field is a path of the field such as "items.0.name".
+	// The root is "(root)".
+	Field string
+	// Pointer is a JSON Pointer of the field such as "/items/0/name".
+	// The root is "". Unlike Field, it distinguishes names which contain ".".
+	Pointer string
+	// Keyword is a kind of the error such as "required".
+	Keyword string
+	// Message describes the error.
+	Message string
+	// Details are parameters of the error such as "min" of minimum.
+	Details map[string]interface{}
+}
+
+// contextSep separates names in a context of gojsonschema.
+// It never appears in decoded names because they are valid UTF-8.
+const contextSep = "\xff"
+
+// contextPointer converts the context of an error such as "(root).items.0.name" to a JSON Pointer.
+func contextPointer(c *gojsonschema.JsonContext) string {
+	if c == nil {
+		return ""
+	}
+	// the first name is "(root)"
+	parts := strings.Split(c.String(contextSep), contextSep)[1:]
+	if len(parts) == 0 {
+		return ""
+	}
+	for i := range parts {
+		parts[i] = escapePointer(parts[i])
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Message
+}
@@ -0,0 +1,146 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestCompile(t *testing.T) {
+	s, err := Compile(os.DirFS("testdata/compile"), "user.json")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		doc    string
+		fields []string
+	}{
+		{`{"id":1,"name":"a"}`, nil},
+		{`{"id":0,"name":"a"}`, []string{"id"}},
+		{`{"name":""}`, []string{"(root)", "name"}},
+	}
+
+	for _, tt := range cases {
+		err := s.Validate([]byte(tt.doc))
+		if len(tt.fields) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.doc, err)
+			}
+			continue
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("%s: want *ValidationError but got %v", tt.doc, err)
+		}
+		fields := map[string]bool{}
+		for _, e := range verr.Errors {
+			fields[e.Field] = true
+		}
+		for _, f := range tt.fields {
+			if !fields[f] {
+				t.Errorf("%s: want an error of %s but got %v", tt.doc, f, verr)
+			}
+		}
+	}
+
+	// schemas which are not found
+	fsys := fstest.MapFS{
+		"a.json": {Data: []byte(`{"$ref": "missing.json"}`)},
+	}
+	if _, err := Compile(fsys, "a.json"); err == nil {
+		t.Error("expected error does not occur")
+	}
+	if _, err := Compile(fsys, "../a.json"); !errors.Is(err, ErrRefInvalid) {
+		t.Errorf("want ErrRefInvalid but got %v", err)
+	}
+}
+
+func TestCompileBytes(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	schema, err := GenerateBytes(T{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	s, err := CompileBytes(schema)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := s.ValidateValue(T{Name: "a"}); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"name":1}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type Item struct {
+		SKU string `json:"sku" jsonschema:"pattern=^[A-Z]+$"`
+	}
+	type Order struct {
+		ID    string `json:"id" jsonschema:"minLength=1"`
+		Count int    `json:"count" jsonschema:"minimum=1,maximum=10"`
+		Items []Item `json:"items"`
+	}
+	schema, err := GenerateBytes(Order{Items: []Item{{}}}, ByReference("#/properties/items/items", func(o Object) (Object, error) {
+		o.Set("additionalProperties", false)
+		return o, nil
+	}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		name   string
+		doc    string
+		expect map[string]string
+	}{
+		{"valid", `{"id": "a", "count": 1, "items": [{"sku": "A"}]}`, nil},
+		{"type", `{"id": 1, "count": 1, "items": []}`, map[string]string{"/id": "invalid_type"}},
+		{"required", `{"id": "a", "items": []}`, map[string]string{"": "required"}},
+		{"maximum", `{"id": "a", "count": 11, "items": []}`, map[string]string{"/count": "number_lte"}},
+		{"items", `{"id": "a", "count": 1, "items": [{"sku": "A"}, {"sku": "a", "x": 1}]}`, map[string]string{
+			"/items/1/sku": "pattern",
+			"/items/1":     "additional_property_not_allowed",
+		}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(schema, []byte(tt.doc))
+			var verr *ValidationError
+			switch {
+			case tt.expect == nil && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.expect == nil:
+				return
+			case !errors.As(err, &verr):
+				t.Fatalf("want *ValidationError but got %v", err)
+			}
+
+			got := map[string]string{}
+			for _, fe := range verr.Errors {
+				got[fe.Pointer] = fe.Keyword
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("want %v but got %v", tt.expect, got)
+			}
+		})
+	}
+
+	if err := Validate([]byte(`{`), []byte(`{}`)); err == nil || errors.As(err, new(*ValidationError)) {
+		t.Errorf("want a compile error but got %v", err)
+	}
+}
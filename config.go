@@ -0,0 +1,214 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/minio/pkg/wildcard"
+)
+
+// config is a special Object which holds settings of a generator.
+// Before generation, options which are created by configOption are applied to a config once.
+// Other options are applied only to schema objects.
+type config struct {
+	promotedRequired EmbedRequired
+	strictNames      bool
+	namePatterns     []*regexp.Regexp
+	timeFormat       string
+	hoistAnonymous   bool
+	hashDefNames     bool
+	sortedKeys       bool
+	propertiesOrder  PropertiesOrder
+	annotations      map[string]map[string]interface{}
+	nullablePointers bool
+	compatTags       bool
+	typeOverrides    []typeOverride
+	propertyTitle    func(fieldName string) string
+	hal              map[reflect.Type][]string
+	integrity        bool
+	cacheKeys        []string
+	mapIntKeyStyle   MapIntKeyStyle
+	arrayStyle       ArrayStyle
+	strictObjects    bool
+	bytesAsArrays    bool
+	meter            Meter
+	directives       map[string]map[string]interface{}
+	docs             map[string]string
+	sources          *sourceIndex
+	naming           *namingPolicy
+	isoDurations     bool
+	compatibility    CompatibilityLevel
+	validateOutput   bool
+	flavor           SchemaFlavor
+	closedMaps       bool
+	patternProps     bool
+	requiredOrder    RequiredOrder
+	defaults         bool
+	defaultPolicy    DefaultPolicy
+	errMsgKey        string
+	maxNodes         int
+	typeTitle        func(t reflect.Type) string
+	enums            map[reflect.Type][]EnumValue
+	typeSchemas      map[reflect.Type]map[string]interface{}
+	typeMappings     map[reflect.Type]func(o Object) error
+	marshalerHints   map[reflect.Type]*Schema
+	keywordMappers   []KeywordMapper
+	refBuilder       RefBuilder
+	typeOptions      map[reflect.Type][]Option
+	forTypes         map[reflect.Type][]Option
+	shapes           []shape
+	implementations  map[reflect.Type]*implementations
+	sharedTypes      SharedTypePolicy
+	draft            SchemaDraft
+	nestEmbedded     bool
+	id               string
+	dynamicRefs      bool
+}
+
+// typeOverride overrides the type of objects whose Go type or reference matches.
+type typeOverride struct {
+	goType  reflect.Type
+	pattern string
+	typ     string
+}
+
+func newConfig(options []Option) *config {
+	var c config
+	for _, opt := range options {
+		if isConfigOption(opt) {
+			// options of the generator do not return errors
+			_, _ = opt(&c)
+		}
+	}
+	return &c
+}
+
+func (c *config) Set(key string, value interface{}) {}
+
+func (c *config) Get(key string) (interface{}, bool) {
+	return nil, false
+}
+
+func (c *config) Delete(key string) {}
+
+func (c *config) Merge(fragment map[string]interface{}) {}
+
+func (c *config) Walk(f func(o Object) error) error {
+	return nil
+}
+
+func (c *config) Ref() string {
+	return ""
+}
+
+// configOption creates an Option which configures the generator.
+// It does nothing for schema objects.
+func configOption(f func(c *config)) Option {
+	return configFunc(f).option
+}
+
+// configFunc configures the generator.
+// Options which configOption creates are method values of option,
+// so they are told from other options by their code without calling them.
+type configFunc func(c *config)
+
+func (f configFunc) option(o Object) (Object, error) {
+	if c, ok := o.(*config); ok {
+		f(c)
+	}
+	return o, nil
+}
+
+// configOptionCode is the code of options which configOption creates.
+var configOptionCode = reflect.ValueOf(configOption(nil)).Pointer()
+
+// isConfigOption reports whether the option is created by configOption.
+func isConfigOption(opt Option) bool {
+	return opt != nil && reflect.ValueOf(opt).Pointer() == configOptionCode
+}
+
+// validateName validates a property name when strict name validation is enabled.
+func (c *config) validateName(name string) error {
+	if !c.strictNames {
+		return nil
+	}
+
+	switch {
+	case name == "":
+		return fmt.Errorf("empty name")
+	case !utf8.ValidString(name):
+		return fmt.Errorf("invalid UTF-8")
+	}
+
+	// same rule as encoding/json
+	for _, r := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			return fmt.Errorf("invalid character %q", r)
+		}
+	}
+
+	for _, p := range c.namePatterns {
+		if !p.MatchString(name) {
+			return fmt.Errorf("not match %s", p)
+		}
+	}
+
+	return nil
+}
+
+// overrideType returns the overridden type for an object.
+// Later overrides have priority.
+func (c *config) overrideType(ref string, t reflect.Type) (string, bool) {
+	for i := len(c.typeOverrides) - 1; i >= 0; i-- {
+		o := c.typeOverrides[i]
+		switch {
+		case o.goType != nil && (o.goType == t || t.Kind() == reflect.Ptr && o.goType == t.Elem()):
+			return o.typ, true
+		case o.goType == nil && wildcard.MatchSimple(o.pattern, ref):
+			return o.typ, true
+		}
+	}
+	return "", false
+}
+
+// titleOf returns a title of the struct type. An empty title means no title.
+// Titles of instantiations of generic types do not have package paths such as "Page[User]".
+func (c *config) titleOf(t reflect.Type) string {
+	if t.Name() == "" || c.typeTitle == nil {
+		return shortTypeName(t)
+	}
+	return c.typeTitle(t)
+}
+
+// errorMessageKey returns the key of the extension which holds custom error messages.
+func (c *config) errorMessageKey() string {
+	if c.errMsgKey == "" {
+		return ErrorMessageKey
+	}
+	return c.errMsgKey
+}
+
+// orderRequired removes duplicated names from required and orders them.
+func (c *config) orderRequired(required []string) []string {
+	seen := make(map[string]bool, len(required))
+	names := make([]string, 0, len(required))
+	for _, name := range required {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	if c.requiredOrder == RequiredAlphabetical {
+		sort.Strings(names)
+	}
+
+	return names
+}
@@ -0,0 +1,182 @@
+// Package configcmd provides a command which generates schemas of config structs
+// and validates configuration files against them, so ops teams get early feedback on bad configs.
+//
+// Go types cannot be looked up by their names at run time,
+// so a program gives its config structs with default settings to Main:
+//
+//	func main() {
+//		configcmd.Main(config.Default())
+//	}
+//
+// Then the program has the following subcommands:
+//
+//	mytool schema [--type Config]
+//	mytool validate-config [--type Config] config.yaml [more.json ...]
+//
+// The type can be omitted if the program has only one config struct.
+// YAML files are chosen by extensions such as ".yaml" and ".yml" and others are JSON.
+package configcmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tenntenn/jsonschema"
+)
+
+// Exit codes of Run.
+const (
+	ExitOK      = 0
+	ExitInvalid = 1
+	ExitUsage   = 2
+)
+
+// Command is a command of config structs.
+type Command struct {
+	// Configs are config structs with default settings.
+	// Each config is identified by the name of its type.
+	Configs []interface{}
+	// Options are given to jsonschema.ConfigSchema.
+	Options []jsonschema.Option
+}
+
+// Main runs the command of the configs with arguments of the program and exits.
+func Main(configs ...interface{}) {
+	c := &Command{Configs: configs}
+	os.Exit(c.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Run runs a subcommand of the args and returns an exit code.
+// Invalid files are reported to stderr with JSON Pointers of their fields
+// and the exit code is ExitInvalid.
+func (c *Command) Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		c.usage(stderr)
+		return ExitUsage
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typ := fs.String("type", "", "name of the type of the config struct")
+	if err := fs.Parse(args[1:]); err != nil {
+		return ExitUsage
+	}
+
+	v, err := c.config(*typ)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+
+	schema, err := jsonschema.ConfigSchema(v, c.Options...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "schema":
+		if _, err := stdout.Write(schema); err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitInvalid
+		}
+		return ExitOK
+	case "validate-config":
+		if fs.NArg() == 0 {
+			fmt.Fprintln(stderr, "no config files")
+			return ExitUsage
+		}
+		return validate(schema, fs.Args(), stderr)
+	}
+
+	c.usage(stderr)
+	return ExitUsage
+}
+
+// validate validates the files against the schema.
+func validate(schema []byte, files []string, stderr io.Writer) int {
+	s, err := jsonschema.CompileBytes(schema)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+
+	code := ExitOK
+	for _, name := range files {
+		doc, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = ExitInvalid
+			continue
+		}
+
+		err = s.ValidateFile(name, doc)
+		var verr *jsonschema.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			for _, fe := range verr.Errors {
+				ptr := fe.Pointer
+				if ptr == "" {
+					ptr = "(root)"
+				}
+				fmt.Fprintf(stderr, "%s: %s: %s\n", name, ptr, fe.Message)
+			}
+			code = ExitInvalid
+		case err != nil:
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			code = ExitInvalid
+		}
+	}
+	return code
+}
+
+// config returns the config struct whose type has the name.
+func (c *Command) config(name string) (interface{}, error) {
+	if name == "" {
+		if len(c.Configs) != 1 {
+			return nil, fmt.Errorf("--type must be one of %s", strings.Join(c.names(), ", "))
+		}
+		return c.Configs[0], nil
+	}
+
+	for _, v := range c.Configs {
+		if typeName(v) == name {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown type %q: --type must be one of %s", name, strings.Join(c.names(), ", "))
+}
+
+func (c *Command) names() []string {
+	names := make([]string, len(c.Configs))
+	for i, v := range c.Configs {
+		names[i] = typeName(v)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Command) usage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	fmt.Fprintln(w, "\tschema [--type name]")
+	fmt.Fprintln(w, "\tvalidate-config [--type name] file...")
+	fmt.Fprintf(w, "types: %s\n", strings.Join(c.names(), ", "))
+}
+
+// typeName returns the name of the type of v such as "Config" for *Config.
+func typeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
@@ -0,0 +1,70 @@
+package configcmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tenntenn/jsonschema/configcmd"
+)
+
+type Config struct {
+	Port int `json:"port" jsonschema:"minimum=1"`
+}
+
+type Other struct {
+	Name string `json:"name"`
+}
+
+func TestCommand_Run(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		return p
+	}
+	valid := write("valid.yaml", "port: 8080\n")
+	invalid := write("invalid.yml", "port: 0\n")
+	malformed := write("malformed.json", "{")
+
+	cases := []struct {
+		name    string
+		configs []interface{}
+		args    []string
+		code    int
+		stdout  string
+		stderr  string
+	}{
+		{"schema", []interface{}{Config{Port: 80}}, []string{"schema"}, configcmd.ExitOK, `"default":80`, ""},
+		{"valid", []interface{}{Config{}}, []string{"validate-config", valid}, configcmd.ExitOK, "", ""},
+		{"invalid", []interface{}{Config{}}, []string{"validate-config", valid, invalid}, configcmd.ExitInvalid, "", invalid + ": /port: "},
+		{"malformed", []interface{}{Config{}}, []string{"validate-config", malformed}, configcmd.ExitInvalid, "", malformed + ": "},
+		{"type", []interface{}{Config{}, &Other{}}, []string{"validate-config", "--type", "Config", valid}, configcmd.ExitOK, "", ""},
+		{"no type", []interface{}{Config{}, Other{}}, []string{"validate-config", valid}, configcmd.ExitUsage, "", "--type must be one of Config, Other"},
+		{"unknown type", []interface{}{Config{}}, []string{"schema", "--type", "Other"}, configcmd.ExitUsage, "", `unknown type "Other"`},
+		{"no files", []interface{}{Config{}}, []string{"validate-config"}, configcmd.ExitUsage, "", "no config files"},
+		{"unknown command", []interface{}{Config{}}, []string{"run"}, configcmd.ExitUsage, "", "usage:"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			c := &configcmd.Command{Configs: tt.configs}
+			if code := c.Run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("want exit code %d but got %d: %s", tt.code, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.stdout) {
+				t.Errorf("want stdout which contains %q but got %q", tt.stdout, stdout.String())
+			}
+			if tt.stderr == "" && stderr.Len() != 0 || !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("want stderr which contains %q but got %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
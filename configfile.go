@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigSchema generates a schema of the config struct v for configuration files.
+// Values of fields of v are emitted as defaults by Defaults(DefaultOmitNil),
+// so v is usually a config which has default settings.
+// Descriptions are given by tags and directives as same as Generate.
+// The options are applied after Defaults, so they can change the policy.
+func ConfigSchema(v interface{}, opts ...Option) ([]byte, error) {
+	return GenerateBytes(v, append([]Option{Defaults(DefaultOmitNil)}, opts...)...)
+}
+
+// ValidateYAML converts the YAML document into JSON and validates it as same as Validate.
+// Keys of mappings are converted into strings.
+func (s *Schema) ValidateYAML(doc []byte) error {
+	b, err := yamlToJSON(doc)
+	if err != nil {
+		return err
+	}
+	return s.Validate(b)
+}
+
+// ValidateFile validates the content of a file of the name by Validate or ValidateYAML
+// which is chosen by the extension of the name such as ".yaml" and ".yml".
+func (s *Schema) ValidateFile(name string, doc []byte) error {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return s.ValidateYAML(doc)
+	}
+	return s.Validate(doc)
+}
+
+// yamlToJSON converts the YAML document into JSON.
+func yamlToJSON(doc []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(doc, &v); err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot decode YAML: %w", err)
+	}
+
+	b, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot convert YAML into JSON: %w", err)
+	}
+	return b, nil
+}
+
+// jsonValue converts mappings of YAML into objects of JSON recursively.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonValue(v[i])
+		}
+	}
+	return v
+}
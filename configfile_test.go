@@ -0,0 +1,93 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestConfigSchema(t *testing.T) {
+	type Server struct {
+		Host string `json:"host" jsonschema:"description=host name"`
+		Port int    `json:"port" jsonschema:"minimum=1,maximum=65535"`
+	}
+	type Config struct {
+		Server Server   `json:"server"`
+		Tags   []string `json:"tags,omitempty"`
+	}
+
+	schema, err := ConfigSchema(Config{Server: Server{Host: "localhost", Port: 8080}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "Config",
+		"required": ["server"],
+		"properties": {
+			"server": {
+				"type": "object",
+				"title": "Server",
+				"propertyOrder": 0,
+				"required": ["host", "port"],
+				"properties": {
+					"host": {"type": "string", "description": "host name", "default": "localhost", "propertyOrder": 0},
+					"port": {"type": "number", "minimum": 1, "maximum": 65535, "default": 8080, "propertyOrder": 1}
+				}
+			},
+			"tags": {}
+		}
+	}`
+	if diff := jsonDiff(t, string(schema), expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	s, err := CompileBytes(schema)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		name    string
+		file    string
+		doc     string
+		invalid []string
+		isErr   bool
+	}{
+		{"yaml", "config.yaml", "server:\n  host: example.com\n  port: 80\n", nil, false},
+		{"invalid yaml", "config.yml", "server:\n  host: example.com\n  port: 0\n", []string{"/server/port"}, false},
+		{"json", "config.json", `{"server": {"host": "example.com"}}`, []string{"/server"}, false},
+		{"malformed yaml", "config.yaml", "server: [", nil, true},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateFile(tt.file, []byte(tt.doc))
+			var verr *ValidationError
+			switch {
+			case tt.isErr && (err == nil || errors.As(err, &verr)):
+				t.Fatalf("want a decoding error but got %v", err)
+			case tt.isErr:
+				return
+			case len(tt.invalid) == 0 && err != nil:
+				t.Fatal("unexpected error:", err)
+			case len(tt.invalid) == 0:
+				return
+			case !errors.As(err, &verr):
+				t.Fatalf("want *ValidationError but got %v", err)
+			}
+
+			var got []string
+			for _, fe := range verr.Errors {
+				got = append(got, fe.Pointer)
+			}
+			if !reflect.DeepEqual(got, tt.invalid) {
+				t.Errorf("want errors of %v but got %v", tt.invalid, got)
+			}
+		})
+	}
+}
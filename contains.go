@@ -0,0 +1,56 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contains creates an Option which requires arrays to contain elements which match the schema
+// by contains, such as lists of roles which must include "admin".
+// The number of matching elements is limited by minContains of min and maxContains of max.
+// minContains is omitted if min is 1, which is the default of contains,
+// and maxContains is omitted if max is zero.
+// It is ignored by objects which are not arrays, so it is usually used with ByReference.
+// A field can also give them by tags such as `jsonschema:"containsEnum=admin|owner,maxContains=1"`.
+// Drafts before 2019-09 do not have minContains and maxContains and drafts before draft-06 do not have contains.
+func Contains(schema map[string]interface{}, min, max int) Option {
+	return func(o Object) (Object, error) {
+		if typ, _ := o.Get("type"); typ != "array" {
+			return o, nil
+		}
+		if min < 0 || max < 0 || max != 0 && max < min {
+			return nil, fmt.Errorf("jsonschema: %s: invalid range of contains: %d..%d", o.Ref(), min, max)
+		}
+
+		o.Set("contains", copyValue(schema))
+		if min != 1 {
+			o.Set("minContains", min)
+		}
+		if max != 0 {
+			o.Set("maxContains", max)
+		}
+
+		return o, nil
+	}
+}
+
+// setTagContains sets contains of values which are separated by "|" such as `jsonschema:"containsEnum=admin|owner"`.
+// Values are converted according to the type of items.
+// Objects of nil slices which have no types also have contains.
+func setTagContains(o Object, values string) error {
+	if typ, ok := o.Get("type"); ok && typ != "array" {
+		return fmt.Errorf("containsEnum is only for arrays")
+	}
+	_, typ := enumTarget(o)
+	items := strings.Split(values, "|")
+	enum := make([]interface{}, len(items))
+	for i, s := range items {
+		v, err := compatValue(typ, s)
+		if err != nil {
+			return err
+		}
+		enum[i] = v
+	}
+	o.Set("contains", map[string]interface{}{"enum": enum})
+	return nil
+}
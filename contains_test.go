@@ -0,0 +1,182 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type containsMember struct {
+	Roles   []string `json:"roles" jsonschema:"containsEnum=admin|owner,maxContains=1"`
+	Scores  []int    `json:"scores" jsonschema:"containsEnum=100,minContains=2"`
+	Aliases []string `json:"aliases"`
+}
+
+func TestContains(t *testing.T) {
+	noOrder := MapKeywords(KeywordRenames{"propertyOrder": ""})
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "tags",
+			expect: `{
+				"title": "containsMember",
+				"type": "object",
+				"required": ["roles", "scores", "aliases"],
+				"properties": {
+					"roles": {
+						"type": "array",
+						"items": {"type": "string"},
+						"contains": {"enum": ["admin", "owner"]},
+						"maxContains": 1
+					},
+					"scores": {
+						"type": "array",
+						"items": {"type": "number"},
+						"contains": {"enum": [100]},
+						"minContains": 2
+					},
+					"aliases": {"type": "array", "items": {"type": "string"}}
+				}
+			}`,
+		},
+		{
+			name: "option",
+			opts: []Option{
+				ByReference("#/properties/aliases", Contains(map[string]interface{}{"pattern": "^@"}, 0, 3)),
+				// objects which are not arrays are ignored
+				ByReference("#", Contains(map[string]interface{}{"const": 1}, 1, 0)),
+			},
+			expect: `{
+				"title": "containsMember",
+				"type": "object",
+				"required": ["roles", "scores", "aliases"],
+				"properties": {
+					"roles": {
+						"type": "array",
+						"items": {"type": "string"},
+						"contains": {"enum": ["admin", "owner"]},
+						"maxContains": 1
+					},
+					"scores": {
+						"type": "array",
+						"items": {"type": "number"},
+						"contains": {"enum": [100]},
+						"minContains": 2
+					},
+					"aliases": {
+						"type": "array",
+						"items": {"type": "string"},
+						"contains": {"pattern": "^@"},
+						"minContains": 0,
+						"maxContains": 3
+					}
+				}
+			}`,
+		},
+		{
+			name: "draft-07",
+			opts: []Option{Draft(Draft07)},
+			expect: `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"title": "containsMember",
+				"type": "object",
+				"required": ["roles", "scores", "aliases"],
+				"properties": {
+					"roles": {"type": "array", "items": {"type": "string"}, "contains": {"enum": ["admin", "owner"]}},
+					"scores": {"type": "array", "items": {"type": "number"}, "contains": {"enum": [100]}},
+					"aliases": {"type": "array", "items": {"type": "string"}}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v := containsMember{Roles: []string{}, Scores: []int{}, Aliases: []string{}}
+			got, err := GenerateString(v, append(tt.opts, noOrder)...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestContains_validate(t *testing.T) {
+	v := struct {
+		Roles []string `json:"roles" jsonschema:"containsEnum=admin"`
+	}{Roles: []string{}}
+	b, err := GenerateBytes(v)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	s, err := CompileBytes(b)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"roles": ["viewer", "admin"]}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"roles": ["viewer"]}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestContains_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+		opts []Option
+		kind error
+	}{
+		{
+			name: "not array",
+			v: struct {
+				Role string `json:"role" jsonschema:"containsEnum=admin"`
+			}{},
+			kind: ErrTagSyntax,
+		},
+		{
+			name: "invalid value",
+			v: struct {
+				Scores []int `json:"scores" jsonschema:"containsEnum=high"`
+			}{Scores: []int{}},
+			kind: ErrTagSyntax,
+		},
+		{
+			name: "invalid count",
+			v: struct {
+				Roles []string `json:"roles" jsonschema:"containsEnum=admin,minContains=one"`
+			}{Roles: []string{}},
+			kind: ErrTagSyntax,
+		},
+		{
+			name: "invalid range",
+			v: struct {
+				Roles []string `json:"roles"`
+			}{Roles: []string{}},
+			opts: []Option{ByReference("#/properties/roles", Contains(map[string]interface{}{"const": "admin"}, 2, 1))},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateString(tt.v, tt.opts...)
+			if err == nil {
+				t.Fatal("expected error does not occur")
+			}
+			if tt.kind != nil && !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+		})
+	}
+}
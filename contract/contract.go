@@ -0,0 +1,196 @@
+// Package contract validates recorded HTTP traffic against schemas which are generated
+// from Go types of handlers, so drift between Go models and actual traffic is caught in CI.
+//
+// Traffic is read from HAR files, which browsers and proxies record,
+// or from simple JSON fixtures by ReadHAR and ReadFixtures:
+//
+//	h, err := contract.New([]contract.Route{{
+//		Method:    http.MethodPost,
+//		Path:      "/users/{id}",
+//		Request:   UpdateUser{},
+//		Responses: map[int]interface{}{http.StatusOK: User{}},
+//	}})
+//	...
+//	for _, m := range h.Check(exchanges) {
+//		t.Error(m)
+//	}
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tenntenn/jsonschema"
+)
+
+// Route describes an endpoint of handlers and Go types of its bodies.
+type Route struct {
+	// Method is an HTTP method such as "GET". An empty method matches any methods.
+	Method string
+	// Path is a path of the endpoint such as "/users/{id}".
+	// A segment in braces matches any segment.
+	Path string
+	// Request is a value of the type of request bodies. If it is nil, request bodies are not checked.
+	Request interface{}
+	// Responses are values of types of response bodies by status codes.
+	// The status code 0 matches statuses which are not in Responses.
+	// If it is nil, response bodies are not checked.
+	Responses map[int]interface{}
+}
+
+// Exchange is a recorded pair of a request and a response.
+type Exchange struct {
+	Method string
+	URL    string
+	Status int
+	// RequestBody and ResponseBody are JSON documents. Empty bodies are not checked.
+	RequestBody  []byte
+	ResponseBody []byte
+}
+
+// Mismatch is an exchange which does not match the contract.
+type Mismatch struct {
+	Exchange Exchange
+	// Part is "route", "request" or "response".
+	Part string
+	// Err describes the mismatch. It is a *jsonschema.ValidationError
+	// if the body is invalid against the schema.
+	Err error
+}
+
+func (m Mismatch) String() string {
+	var verr *jsonschema.ValidationError
+	if !errors.As(m.Err, &verr) {
+		return fmt.Sprintf("%s %s (%d): %s: %v", m.Exchange.Method, m.Exchange.URL, m.Exchange.Status, m.Part, m.Err)
+	}
+
+	msgs := make([]string, len(verr.Errors))
+	for i, fe := range verr.Errors {
+		ptr := fe.Pointer
+		if ptr == "" {
+			ptr = "(root)"
+		}
+		msgs[i] = ptr + ": " + fe.Message
+	}
+	return fmt.Sprintf("%s %s (%d): %s: %s", m.Exchange.Method, m.Exchange.URL, m.Exchange.Status, m.Part, strings.Join(msgs, "; "))
+}
+
+// Harness checks exchanges against schemas of routes.
+type Harness struct {
+	routes []*route
+}
+
+type route struct {
+	Route
+	segments  []string
+	request   *jsonschema.Schema
+	responses map[int]*jsonschema.Schema
+}
+
+// New generates and compiles schemas of the routes with the options.
+func New(routes []Route, opts ...jsonschema.Option) (*Harness, error) {
+	h := &Harness{routes: make([]*route, len(routes))}
+	for i, r := range routes {
+		cr := &route{
+			Route:    r,
+			segments: strings.Split(strings.Trim(r.Path, "/"), "/"),
+		}
+
+		var err error
+		if r.Request != nil {
+			if cr.request, err = compile(r.Request, opts); err != nil {
+				return nil, fmt.Errorf("contract: request of %s %s: %w", r.Method, r.Path, err)
+			}
+		}
+		if r.Responses != nil {
+			cr.responses = make(map[int]*jsonschema.Schema, len(r.Responses))
+			for status, v := range r.Responses {
+				if cr.responses[status], err = compile(v, opts); err != nil {
+					return nil, fmt.Errorf("contract: response %d of %s %s: %w", status, r.Method, r.Path, err)
+				}
+			}
+		}
+		h.routes[i] = cr
+	}
+	return h, nil
+}
+
+func compile(v interface{}, opts []jsonschema.Option) (*jsonschema.Schema, error) {
+	schema, err := jsonschema.GenerateBytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.CompileBytes(schema)
+}
+
+// Check checks the exchanges in order and returns their mismatches.
+// Exchanges which do not match any routes are reported as mismatches of "route".
+func (h *Harness) Check(exchanges []Exchange) []Mismatch {
+	var mismatches []Mismatch
+	for _, e := range exchanges {
+		r, err := h.route(e)
+		if err != nil {
+			mismatches = append(mismatches, Mismatch{Exchange: e, Part: "route", Err: err})
+			continue
+		}
+
+		if r.request != nil && len(e.RequestBody) != 0 {
+			if err := r.request.Validate(e.RequestBody); err != nil {
+				mismatches = append(mismatches, Mismatch{Exchange: e, Part: "request", Err: err})
+			}
+		}
+
+		if r.responses == nil {
+			continue
+		}
+		s, ok := r.responses[e.Status]
+		if !ok {
+			s, ok = r.responses[0]
+		}
+		switch {
+		case !ok:
+			mismatches = append(mismatches, Mismatch{Exchange: e, Part: "response", Err: fmt.Errorf("unexpected status %d", e.Status)})
+		case len(e.ResponseBody) != 0:
+			if err := s.Validate(e.ResponseBody); err != nil {
+				mismatches = append(mismatches, Mismatch{Exchange: e, Part: "response", Err: err})
+			}
+		}
+	}
+	return mismatches
+}
+
+// route returns the first route which matches the exchange.
+func (h *Harness) route(e Exchange) (*route, error) {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	for _, r := range h.routes {
+		if r.Method != "" && !strings.EqualFold(r.Method, e.Method) {
+			continue
+		}
+		if matchSegments(r.segments, segments) {
+			return r, nil
+		}
+	}
+	return nil, errors.New("no routes match")
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			continue
+		}
+		if p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
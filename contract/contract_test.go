@@ -0,0 +1,106 @@
+package contract_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tenntenn/jsonschema/contract"
+)
+
+type User struct {
+	ID   int    `json:"id" jsonschema:"minimum=1"`
+	Name string `json:"name"`
+}
+
+type UpdateUser struct {
+	Name string `json:"name" jsonschema:"minLength=1"`
+}
+
+type Problem struct {
+	Title string `json:"title"`
+}
+
+func newHarness(t *testing.T) *contract.Harness {
+	t.Helper()
+	h, err := contract.New([]contract.Route{
+		{
+			Method:    http.MethodGet,
+			Path:      "/users/{id}",
+			Responses: map[int]interface{}{http.StatusOK: User{}, 0: Problem{}},
+		},
+		{
+			Method:    http.MethodPut,
+			Path:      "/users/{id}",
+			Request:   UpdateUser{},
+			Responses: map[int]interface{}{http.StatusOK: User{}},
+		},
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	return h
+}
+
+func TestHarness_Check(t *testing.T) {
+	fixtures := `[
+		{"method": "GET", "url": "/users/1", "status": 200, "response": {"id": 1, "name": "gopher"}},
+		{"method": "GET", "url": "https://example.com/users/2?x=1", "status": 200, "response": {"id": "2", "name": "gopher"}},
+		{"method": "GET", "url": "/users/3", "status": 404, "response": {"title": "not found"}},
+		{"method": "PUT", "url": "/users/1", "status": 200, "request": {"name": ""}, "response": {"id": 1, "name": "gopher"}},
+		{"method": "PUT", "url": "/users/1", "status": 500},
+		{"method": "DELETE", "url": "/users/1", "status": 204}
+	]`
+	exchanges, err := contract.ReadFixtures(strings.NewReader(fixtures))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := []string{
+		"GET https://example.com/users/2?x=1 (200): response: /id: Invalid type. Expected: number, given: string",
+		"PUT /users/1 (200): request: /name: String length must be greater than or equal to 1",
+		"PUT /users/1 (500): response: unexpected status 500",
+		"DELETE /users/1 (204): route: no routes match",
+	}
+	mismatches := newHarness(t).Check(exchanges)
+	if len(mismatches) != len(expect) {
+		t.Fatalf("want %d mismatches but got %v", len(expect), mismatches)
+	}
+	for i, m := range mismatches {
+		if got := m.String(); got != expect[i] {
+			t.Errorf("want %q but got %q", expect[i], got)
+		}
+	}
+}
+
+func TestReadHAR(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(`{"id": 0, "name": "gopher"}`))
+	har := `{"log": {"entries": [
+		{
+			"request": {"method": "PUT", "url": "https://example.com/users/1", "postData": {"mimeType": "application/json", "text": "{\"name\": \"gopher\"}"}},
+			"response": {"status": 200, "content": {"mimeType": "application/json; charset=utf-8", "text": "` + encoded + `", "encoding": "base64"}}
+		},
+		{
+			"request": {"method": "GET", "url": "https://example.com/users/1"},
+			"response": {"status": 200, "content": {"mimeType": "text/html", "text": "<html></html>"}}
+		}
+	]}}`
+
+	exchanges, err := contract.ReadHAR(strings.NewReader(har))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(exchanges) != 2 {
+		t.Fatalf("want 2 exchanges but got %d", len(exchanges))
+	}
+	if len(exchanges[1].ResponseBody) != 0 {
+		t.Errorf("a body which is not JSON is read: %s", exchanges[1].ResponseBody)
+	}
+
+	mismatches := newHarness(t).Check(exchanges)
+	expect := "PUT https://example.com/users/1 (200): response: /id: Must be greater than or equal to 1"
+	if len(mismatches) != 1 || mismatches[0].String() != expect {
+		t.Errorf("want %q but got %v", expect, mismatches)
+	}
+}
@@ -0,0 +1,101 @@
+package contract
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadHAR reads exchanges from a HAR file.
+// Bodies whose MIME types are not JSON are ignored.
+func ReadHAR(r io.Reader) ([]Exchange, error) {
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method   string `json:"method"`
+					URL      string `json:"url"`
+					PostData *struct {
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+				Response struct {
+					Status  int `json:"status"`
+					Content struct {
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+						Encoding string `json:"encoding"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("contract: cannot decode HAR: %w", err)
+	}
+
+	exchanges := make([]Exchange, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		e := Exchange{
+			Method: entry.Request.Method,
+			URL:    entry.Request.URL,
+			Status: entry.Response.Status,
+		}
+		if pd := entry.Request.PostData; pd != nil && isJSON(pd.MimeType) {
+			e.RequestBody = []byte(pd.Text)
+		}
+
+		content := entry.Response.Content
+		if isJSON(content.MimeType) {
+			e.ResponseBody = []byte(content.Text)
+			if content.Encoding == "base64" {
+				b, err := base64.StdEncoding.DecodeString(content.Text)
+				if err != nil {
+					return nil, fmt.Errorf("contract: cannot decode the response of %s %s: %w", e.Method, e.URL, err)
+				}
+				e.ResponseBody = b
+			}
+		}
+		exchanges[i] = e
+	}
+	return exchanges, nil
+}
+
+// isJSON reports whether the MIME type is JSON such as "application/problem+json; charset=utf-8".
+func isJSON(mimeType string) bool {
+	mt := strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0])
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// ReadFixtures reads exchanges from a JSON array of simple fixtures such as
+//
+//	[{"method": "GET", "url": "/users/1", "status": 200, "response": {"id": 1}}]
+//
+// Bodies are JSON values of request and response.
+func ReadFixtures(r io.Reader) ([]Exchange, error) {
+	var fixtures []struct {
+		Method   string          `json:"method"`
+		URL      string          `json:"url"`
+		Status   int             `json:"status"`
+		Request  json.RawMessage `json:"request"`
+		Response json.RawMessage `json:"response"`
+	}
+	if err := json.NewDecoder(r).Decode(&fixtures); err != nil {
+		return nil, fmt.Errorf("contract: cannot decode fixtures: %w", err)
+	}
+
+	exchanges := make([]Exchange, len(fixtures))
+	for i, f := range fixtures {
+		exchanges[i] = Exchange{
+			Method:       f.Method,
+			URL:          f.URL,
+			Status:       f.Status,
+			RequestBody:  f.Request,
+			ResponseBody: f.Response,
+		}
+	}
+	return exchanges, nil
+}
@@ -0,0 +1,156 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Extensions of structural schemas of Kubernetes.
+const (
+	crdPreserveUnknownFields = "x-kubernetes-preserve-unknown-fields"
+	crdIntOrString           = "x-kubernetes-int-or-string"
+)
+
+// crdDropped are keywords of OpenAPI 3.0 which structural schemas do not have.
+var crdDropped = []string{"discriminator", "readOnly", "writeOnly", "xml", "deprecated"}
+
+// crdJunctorDisallowed are keywords which structural schemas disallow in allOf, anyOf, oneOf and not.
+var crdJunctorDisallowed = []string{"type", "description", "default", "additionalProperties", "nullable"}
+
+// isIntOrString reports whether t is intstr.IntOrString of Kubernetes,
+// which is generated without importing it.
+func isIntOrString(t reflect.Type) bool {
+	return t.PkgPath() == "k8s.io/apimachinery/pkg/util/intstr" && t.Name() == "IntOrString"
+}
+
+// applyCRD converts the root schema into a structural schema of Kubernetes.
+func applyCRD(root map[string]interface{}) error {
+	// the root is being inlined as "#"
+	if err := inlineRefs(root, &obj{m: root, ref: RefRoot}, []string{"#"}); err != nil {
+		return err
+	}
+	delete(root, "$defs")
+	delete(root, "definitions")
+
+	walkSubschemas(root, openAPI3Schema)
+	walkSubschemas(root, crdSchema)
+	return NewObject(RefRoot, root).Walk(checkCRDSchema)
+}
+
+// inlineRefs replaces references of the object and its subschemas with copies of their targets.
+// refs are references which are being inlined.
+func inlineRefs(root map[string]interface{}, o *obj, refs []string) error {
+	if r, ok := o.m["$ref"].(string); ok {
+		for _, ref := range refs {
+			if ref == r {
+				return newError(ErrCycle, o.ref, fmt.Errorf("%s refers to itself and cannot be inlined", r))
+			}
+		}
+		refs = append(refs, r)
+		if err := inlineRef(root, o.m, o.ref); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range sortedKeys(o.m) {
+		if k == "$defs" || k == "definitions" {
+			continue
+		}
+		for _, sub := range subschemas(o.ref, k, o.m[k]) {
+			if err := inlineRefs(root, sub.(*obj), refs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// crdSchema converts the schema object of OpenAPI 3.0 into a structural schema.
+func crdSchema(s map[string]interface{}) {
+	for _, k := range crdDropped {
+		delete(s, k)
+	}
+
+	// a nullable reference becomes allOf of the inlined schema
+	if allOf, ok := s["allOf"].([]interface{}); ok && len(allOf) == 1 {
+		if m, ok := allOf[0].(map[string]interface{}); ok && s["type"] == nil {
+			delete(s, "allOf")
+			for k, v := range m {
+				if _, ok := s[k]; !ok {
+					s[k] = v
+				}
+			}
+		}
+	}
+
+	// an int or a string cannot have a type
+	if s[crdIntOrString] == true {
+		delete(s, "type")
+		return
+	}
+	if _, ok := s["type"]; ok || s[crdPreserveUnknownFields] == true {
+		return
+	}
+	for _, k := range []string{"allOf", "anyOf", "oneOf", "not"} {
+		if _, ok := s[k]; ok {
+			return
+		}
+	}
+	s[crdPreserveUnknownFields] = true
+}
+
+// checkCRDSchema reports constructs of the object which structural schemas disallow.
+func checkCRDSchema(o Object) error {
+	disallow := func(format string, args ...interface{}) error {
+		return newError(ErrInvalidOutput, o.Ref(), fmt.Errorf("structural schema: "+format, args...))
+	}
+
+	if v, _ := o.Get("additionalProperties"); v == false {
+		return disallow("additionalProperties must not be false")
+	}
+	if _, ok := o.Get("additionalProperties"); ok {
+		if _, ok := o.Get("properties"); ok {
+			return disallow("additionalProperties and properties must not be used together")
+		}
+	}
+	if v, _ := o.Get("uniqueItems"); v == true {
+		return disallow("uniqueItems must not be true")
+	}
+	if items, _ := o.Get("items"); isArray(items) {
+		return disallow("items must be a schema")
+	}
+
+	if isInJunctor(o.Ref()) {
+		for _, k := range crdJunctorDisallowed {
+			if _, ok := o.Get(k); ok {
+				return disallow("%s must not be in allOf, anyOf, oneOf or not", k)
+			}
+		}
+		return nil
+	}
+
+	_, typed := o.Get("type")
+	intOrString, _ := o.Get(crdIntOrString)
+	preserve, _ := o.Get(crdPreserveUnknownFields)
+	if !typed && intOrString != true && preserve != true {
+		return disallow("type must be given")
+	}
+	return nil
+}
+
+// isInJunctor reports whether the reference is in allOf, anyOf, oneOf or not.
+// Properties of the names such as "#/properties/not" are not.
+func isInJunctor(ref string) bool {
+	tokens := strings.Split(ref, "/")
+	for i, token := range tokens {
+		if i > 0 && (tokens[i-1] == "properties" || tokens[i-1] == "patternProperties") {
+			continue
+		}
+		switch token {
+		case "allOf", "anyOf", "oneOf", "not":
+			return true
+		}
+	}
+	return false
+}
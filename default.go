@@ -0,0 +1,152 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DefaultPolicy is a policy to extract defaults of properties from values of struct fields.
+type DefaultPolicy int
+
+const (
+	// DefaultOmitNil emits values of fields as defaults including zero values
+	// such as default:0, but nil pointers, maps, slices and interfaces emit no defaults.
+	DefaultOmitNil DefaultPolicy = iota
+	// DefaultOmitZero emits only values of fields which are not zero values as defaults.
+	// Empty maps and slices are also omitted but non-nil pointers to zero values are not.
+	DefaultOmitZero
+)
+
+// Defaults extracts defaults of properties from values of struct fields by the policy.
+// Values are encoded as JSON, so a default of a time.Time or a type which implements
+// json.Marshaler is same as its JSON representation.
+// Fields of structs are not emitted as defaults but their fields are.
+// Defaults are set before other options, so options can override them.
+func Defaults(policy DefaultPolicy) Option {
+	return configOption(func(c *config) {
+		c.defaults = true
+		c.defaultPolicy = policy
+	})
+}
+
+// defaultOf returns a default of the value by the policy.
+func (c *config) defaultOf(v reflect.Value) (interface{}, bool, error) {
+	if !c.defaults || !v.IsValid() {
+		return nil, false, nil
+	}
+
+	// a non-nil pointer to a zero value is present
+	present := false
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		v, present = v.Elem(), true
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		if c.defaultPolicy == DefaultOmitZero && v.Len() == 0 {
+			return nil, false, nil
+		}
+	case reflect.Struct:
+		if v.Type() != timeType && !v.Type().Implements(jsonMarshalerType) {
+			return nil, false, nil
+		}
+	}
+
+	if c.defaultPolicy == DefaultOmitZero && !present && v.IsZero() {
+		return nil, false, nil
+	}
+
+	d, err := encodedValue(v.Interface())
+	if err != nil {
+		return nil, false, err
+	}
+	return d, true, nil
+}
+
+// encodedValue returns the JSON representation of v such as float64 and map[string]interface{}.
+func encodedValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var d interface{}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Defaulter is implemented by types which give defaults of their objects
+// such as a default port of a Port type. JSONSchemaDefault is called with a zero value of the type.
+type Defaulter interface {
+	JSONSchemaDefault() interface{}
+}
+
+// Exampler is implemented by types which give examples of their objects.
+// JSONSchemaExamples is called with a zero value of the type.
+type Exampler interface {
+	JSONSchemaExamples() []interface{}
+}
+
+var (
+	defaulterType = reflect.TypeOf((*Defaulter)(nil)).Elem()
+	examplerType  = reflect.TypeOf((*Exampler)(nil)).Elem()
+)
+
+// zeroOf returns a zero value of t which implements the interface type it
+// or a pointer to it if only the pointer implements it.
+func zeroOf(t, it reflect.Type) (interface{}, bool) {
+	switch {
+	case t.Implements(it):
+		return reflect.Zero(t).Interface(), true
+	case reflect.PtrTo(t).Implements(it):
+		return reflect.New(t).Interface(), true
+	}
+	return nil, false
+}
+
+// setTypeDefaults sets the default and examples which the type t gives by Defaulter and Exampler.
+// Values are encoded as JSON and defaults of fields and tags override them.
+func setTypeDefaults(o Object, t reflect.Type) error {
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	if v, ok := zeroOf(t, defaulterType); ok {
+		d, err := encodedValue(v.(Defaulter).JSONSchemaDefault())
+		if err != nil {
+			return newError(ErrUnsupportedType, o.Ref(), fmt.Errorf("invalid default of %s: %w", t, err))
+		}
+		o.Set("default", d)
+	}
+
+	if v, ok := zeroOf(t, examplerType); ok {
+		examples, err := encodedValue(v.(Exampler).JSONSchemaExamples())
+		if err != nil {
+			return newError(ErrUnsupportedType, o.Ref(), fmt.Errorf("invalid examples of %s: %w", t, err))
+		}
+		if examples, ok := examples.([]interface{}); ok && len(examples) != 0 {
+			o.Set("examples", examples)
+		}
+	}
+
+	return nil
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// defaultOption creates an Option which sets the default.
+func defaultOption(d interface{}) Option {
+	return func(o Object) (Object, error) {
+		o.Set("default", d)
+		return o, nil
+	}
+}
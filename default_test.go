@@ -0,0 +1,204 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+// defaultPort gives its default and examples by Defaulter and Exampler.
+type defaultPort int
+
+func (defaultPort) JSONSchemaDefault() interface{} {
+	return 8080
+}
+
+func (*defaultPort) JSONSchemaExamples() []interface{} {
+	return []interface{}{80, 443}
+}
+
+func TestDefaults(t *testing.T) {
+	type Server struct {
+		Port int `json:"port"`
+	}
+	type Config struct {
+		Name    string            `json:"name"`
+		Retries int               `json:"retries"`
+		Timeout *int              `json:"timeout"`
+		Debug   *bool             `json:"debug"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]string `json:"labels"`
+		Since   time.Time         `json:"since"`
+		Server  Server            `json:"server"`
+	}
+
+	f := false
+	v := Config{
+		Name:   "app",
+		Debug:  &f,
+		Tags:   []string{},
+		Labels: map[string]string{"env": "dev"},
+		Since:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Server: Server{Port: 0},
+	}
+
+	props := func(defaults map[string]string) string {
+		// properties which are same regardless of policies
+		base := map[string]string{
+			"name":    `"type": "string", "propertyOrder": 0`,
+			"retries": `"type": "number", "propertyOrder": 1`,
+			"timeout": ``, // a nil pointer generates an empty schema
+			"debug":   `"type": "boolean", "propertyOrder": 3`,
+			"tags":    `"type": "array", "items": {"type": "string"}, "propertyOrder": 4`,
+			"labels":  `"type": "object", "additionalProperties": {"type": "string"}, "propertyOrder": 5`,
+			"since":   `"type": "string", "format": "date-time", "propertyOrder": 6`,
+		}
+		s := `{
+			"type": "object",
+			"title": "Config",
+			"required": ["name", "retries", "tags", "labels", "since", "server"],
+			"properties": {`
+		for _, name := range []string{"name", "retries", "timeout", "debug", "tags", "labels", "since"} {
+			s += `"` + name + `": {` + base[name]
+			if d, ok := defaults[name]; ok {
+				s += `, "default": ` + d
+			}
+			s += `},`
+		}
+		port := `"type": "number", "propertyOrder": 0`
+		if d, ok := defaults["port"]; ok {
+			port += `, "default": ` + d
+		}
+		s += `"server": {
+					"type": "object",
+					"title": "Server",
+					"required": ["port"],
+					"properties": {"port": {` + port + `}},
+					"propertyOrder": 7
+				}
+			}
+		}`
+		return s
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name:   "no defaults",
+			expect: props(nil),
+		},
+		{
+			name: "omit nil",
+			opts: []Option{Defaults(DefaultOmitNil)},
+			expect: props(map[string]string{
+				"name":    `"app"`,
+				"retries": `0`,
+				"debug":   `false`,
+				"tags":    `[]`,
+				"labels":  `{"env": "dev"}`,
+				"since":   `"2020-01-02T03:04:05Z"`,
+				"port":    `0`,
+			}),
+		},
+		{
+			name: "omit zero",
+			opts: []Option{Defaults(DefaultOmitZero)},
+			expect: props(map[string]string{
+				"name":   `"app"`,
+				"debug":  `false`,
+				"labels": `{"env": "dev"}`,
+				"since":  `"2020-01-02T03:04:05Z"`,
+			}),
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultTags(t *testing.T) {
+	type T struct {
+		Retries int               `json:"retries" jsonschema:"default=10,example=42"`
+		Ratio   float64           `json:"ratio" jsonschema:"default=0.5,examples=0.1|0.9"`
+		Name    string            `json:"name" jsonschema:"default=10,examples=a|b,example=c"`
+		Debug   bool              `json:"debug" jsonschema:"default=true"`
+		Count   int               `json:"count,string" jsonschema:"default=3"`
+		Tags    []string          `json:"tags" jsonschema:"default=[\"a\"\\,\"b\"]"`
+		Labels  map[string]string `json:"labels" jsonschema:"default={\"env\":\"dev\"}"`
+		Port    defaultPort       `json:"port"`
+		Custom  defaultPort       `json:"custom" jsonschema:"default=3000,example=3001"`
+		Ports   []defaultPort     `json:"ports"`
+	}
+
+	expect := `{
+		"title": "T",
+		"type": "object",
+		"required": ["retries", "ratio", "name", "debug", "count", "tags", "labels", "port", "custom", "ports"],
+		"properties": {
+			"retries": {"type": "number", "default": 10, "examples": [42], "propertyOrder": 0},
+			"ratio": {"type": "number", "default": 0.5, "examples": [0.1, 0.9], "propertyOrder": 1},
+			"name": {"type": "string", "default": "10", "examples": ["a", "b", "c"], "propertyOrder": 2},
+			"debug": {"type": "boolean", "default": true, "propertyOrder": 3},
+			"count": {"type": "string", "default": "3", "propertyOrder": 4},
+			"tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"], "propertyOrder": 5},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}, "default": {"env": "dev"}, "propertyOrder": 6},
+			"port": {"type": "number", "default": 8080, "examples": [80, 443], "propertyOrder": 7},
+			"custom": {"type": "number", "default": 3000, "examples": [3001], "propertyOrder": 8},
+			"ports": {
+				"type": "array",
+				"items": {"type": "number", "default": 8080, "examples": [80, 443]},
+				"propertyOrder": 9
+			}
+		}
+	}`
+
+	got, err := GenerateString(T{Tags: []string{}, Labels: map[string]string{}, Ports: []defaultPort{0}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestDefaultTags_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+	}{
+		{"default", struct {
+			N int `jsonschema:"default=x"`
+		}{}},
+		{"example", struct {
+			B bool `jsonschema:"example=yes"`
+		}{}},
+		{"examples", struct {
+			N float64 `jsonschema:"examples=1|x"`
+		}{}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateString(tt.v)
+			if !errors.Is(err, ErrTagSyntax) {
+				t.Errorf("want %v but got %v", ErrTagSyntax, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package jsonschema
+
+import "sort"
+
+// keywordRange is the range of drafts which define a keyword.
+// until is the first draft which does not define it or DraftUnspecified if it is still defined.
+type keywordRange struct {
+	since SchemaDraft
+	until SchemaDraft
+}
+
+// standardKeywords are keywords of the core, validation and meta-data vocabularies of drafts.
+var standardKeywords = map[string]keywordRange{
+	"$schema":          {Draft04, DraftUnspecified},
+	"id":               {Draft04, Draft06},
+	"$id":              {Draft06, DraftUnspecified},
+	"$ref":             {Draft04, DraftUnspecified},
+	"$comment":         {Draft07, DraftUnspecified},
+	"definitions":      {Draft04, Draft201909},
+	"$defs":            {Draft201909, DraftUnspecified},
+	"$anchor":          {Draft201909, DraftUnspecified},
+	"$vocabulary":      {Draft201909, DraftUnspecified},
+	"$recursiveRef":    {Draft201909, Draft202012},
+	"$recursiveAnchor": {Draft201909, Draft202012},
+	"$dynamicRef":      {Draft202012, DraftUnspecified},
+	"$dynamicAnchor":   {Draft202012, DraftUnspecified},
+
+	"title":       {Draft04, DraftUnspecified},
+	"description": {Draft04, DraftUnspecified},
+	"default":     {Draft04, DraftUnspecified},
+	"examples":    {Draft06, DraftUnspecified},
+	"readOnly":    {Draft07, DraftUnspecified},
+	"writeOnly":   {Draft07, DraftUnspecified},
+	"deprecated":  {Draft201909, DraftUnspecified},
+
+	"type":             {Draft04, DraftUnspecified},
+	"enum":             {Draft04, DraftUnspecified},
+	"const":            {Draft06, DraftUnspecified},
+	"format":           {Draft04, DraftUnspecified},
+	"multipleOf":       {Draft04, DraftUnspecified},
+	"maximum":          {Draft04, DraftUnspecified},
+	"exclusiveMaximum": {Draft04, DraftUnspecified},
+	"minimum":          {Draft04, DraftUnspecified},
+	"exclusiveMinimum": {Draft04, DraftUnspecified},
+	"maxLength":        {Draft04, DraftUnspecified},
+	"minLength":        {Draft04, DraftUnspecified},
+	"pattern":          {Draft04, DraftUnspecified},
+	"contentEncoding":  {Draft07, DraftUnspecified},
+	"contentMediaType": {Draft07, DraftUnspecified},
+	"contentSchema":    {Draft201909, DraftUnspecified},
+
+	"items":            {Draft04, DraftUnspecified},
+	"additionalItems":  {Draft04, Draft202012},
+	"prefixItems":      {Draft202012, DraftUnspecified},
+	"maxItems":         {Draft04, DraftUnspecified},
+	"minItems":         {Draft04, DraftUnspecified},
+	"uniqueItems":      {Draft04, DraftUnspecified},
+	"contains":         {Draft06, DraftUnspecified},
+	"maxContains":      {Draft201909, DraftUnspecified},
+	"minContains":      {Draft201909, DraftUnspecified},
+	"unevaluatedItems": {Draft201909, DraftUnspecified},
+
+	"maxProperties":         {Draft04, DraftUnspecified},
+	"minProperties":         {Draft04, DraftUnspecified},
+	"required":              {Draft04, DraftUnspecified},
+	"properties":            {Draft04, DraftUnspecified},
+	"patternProperties":     {Draft04, DraftUnspecified},
+	"additionalProperties":  {Draft04, DraftUnspecified},
+	"dependencies":          {Draft04, Draft201909},
+	"dependentRequired":     {Draft201909, DraftUnspecified},
+	"dependentSchemas":      {Draft201909, DraftUnspecified},
+	"propertyNames":         {Draft06, DraftUnspecified},
+	"unevaluatedProperties": {Draft201909, DraftUnspecified},
+
+	"allOf": {Draft04, DraftUnspecified},
+	"anyOf": {Draft04, DraftUnspecified},
+	"oneOf": {Draft04, DraftUnspecified},
+	"not":   {Draft04, DraftUnspecified},
+	"if":    {Draft07, DraftUnspecified},
+	"then":  {Draft07, DraftUnspecified},
+	"else":  {Draft07, DraftUnspecified},
+}
+
+// Supports reports whether the draft defines the keyword.
+// DraftUnspecified has keywords of draft 2020-12.
+// Extensions such as propertyOrder and x-* keywords are not defined by any drafts.
+func (d SchemaDraft) Supports(keyword string) bool {
+	if d == DraftUnspecified {
+		d = Draft202012
+	}
+	r, ok := standardKeywords[keyword]
+	return ok && r.since <= d && (r.until == DraftUnspecified || d < r.until)
+}
+
+// Keywords returns keywords which the draft defines in alphabetical order.
+func (d SchemaDraft) Keywords() []string {
+	var keywords []string
+	for k := range standardKeywords {
+		if d.Supports(k) {
+			keywords = append(keywords, k)
+		}
+	}
+	sort.Strings(keywords)
+	return keywords
+}
+
+// DraftOf returns the draft which the options target, so custom options can adapt
+// to it, e.g. by emitting dependencies instead of dependentRequired before draft 2019-09.
+// Keywords which are defined by other drafts but not by the target draft
+// are converted or removed from generated schemas.
+func DraftOf(opts ...Option) SchemaDraft {
+	return newConfig(opts).draft
+}
+
+// isStandardKeyword reports whether some draft defines the keyword.
+func isStandardKeyword(keyword string) bool {
+	_, ok := standardKeywords[keyword]
+	return ok
+}
+
+// downgradeKeywords converts keywords of the schema which the draft does not define
+// into equivalent keywords of the draft if possible.
+func downgradeKeywords(s map[string]interface{}, d SchemaDraft) {
+	if !d.Supports("dependentRequired") {
+		deps, _ := s["dependencies"].(map[string]interface{})
+		for _, k := range []string{"dependentRequired", "dependentSchemas"} {
+			m, ok := s[k].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if deps == nil {
+				deps = map[string]interface{}{}
+			}
+			for name, dep := range m {
+				deps[name] = dep
+			}
+			delete(s, k)
+		}
+		if deps != nil {
+			s["dependencies"] = deps
+		}
+	}
+
+	if !d.Supports("prefixItems") {
+		if prefix, ok := s["prefixItems"]; ok {
+			if items, ok := s["items"]; ok {
+				s["additionalItems"] = items
+			}
+			s["items"] = prefix
+			delete(s, "prefixItems")
+		}
+	}
+}
+
+// dropUnsupported removes keywords of the schema which other drafts define but the draft does not.
+func dropUnsupported(s map[string]interface{}, d SchemaDraft) {
+	for k := range s {
+		if isStandardKeyword(k) && !d.Supports(k) {
+			delete(s, k)
+		}
+	}
+}
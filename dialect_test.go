@@ -0,0 +1,127 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestSchemaDraft_Supports(t *testing.T) {
+	cases := []struct {
+		draft   SchemaDraft
+		keyword string
+		expect  bool
+	}{
+		{Draft04, "id", true},
+		{Draft04, "$id", false},
+		{Draft04, "const", false},
+		{Draft06, "const", true},
+		{Draft07, "if", true},
+		{Draft07, "$defs", false},
+		{Draft07, "definitions", true},
+		{Draft201909, "dependentRequired", true},
+		{Draft201909, "dependencies", false},
+		{Draft201909, "prefixItems", false},
+		{Draft202012, "prefixItems", true},
+		{Draft202012, "additionalItems", false},
+		{DraftUnspecified, "$defs", true},
+		{DraftUnspecified, "propertyOrder", false},
+	}
+
+	for _, tt := range cases {
+		if got := tt.draft.Supports(tt.keyword); got != tt.expect {
+			t.Errorf("draft %d: want Supports(%q)=%t but got %t", tt.draft, tt.keyword, tt.expect, got)
+		}
+	}
+}
+
+func TestSchemaDraft_Keywords(t *testing.T) {
+	keywords := Draft04.Keywords()
+	for i, k := range keywords {
+		if !Draft04.Supports(k) {
+			t.Errorf("draft-04 does not support %q", k)
+		}
+		if i > 0 && keywords[i-1] >= k {
+			t.Errorf("keywords are not sorted: %q and %q", keywords[i-1], k)
+		}
+	}
+	if len(keywords) == 0 || len(keywords) >= len(Draft202012.Keywords()) {
+		t.Errorf("unexpected number of keywords of draft-04: %d", len(keywords))
+	}
+}
+
+func TestDraftOf(t *testing.T) {
+	if got := DraftOf(PropertyOrder(1), Draft(Draft07)); got != Draft07 {
+		t.Errorf("want draft-07 but got %d", got)
+	}
+	if got := DraftOf(); got != DraftUnspecified {
+		t.Errorf("want DraftUnspecified but got %d", got)
+	}
+}
+
+func TestDraft_keywords(t *testing.T) {
+	type Pair []string
+	type T struct {
+		Pair  Pair   `json:"pair"`
+		Card  string `json:"card"`
+		Extra string `json:"extra"`
+	}
+
+	// newer keywords such as custom options emit
+	keywords := []Option{
+		ByReference("#/properties/pair", func(o Object) (Object, error) {
+			o.Set("prefixItems", []interface{}{map[string]interface{}{"type": "string"}})
+			o.Set("deprecated", true)
+			return o, nil
+		}),
+		ByReference("#/", func(o Object) (Object, error) {
+			o.Set("dependentRequired", map[string]interface{}{"card": []interface{}{"extra"}})
+			return o, nil
+		}),
+	}
+
+	cases := []struct {
+		name   string
+		draft  SchemaDraft
+		expect string
+	}{
+		{"draft 2020-12", Draft202012, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"title": "T",
+			"required": ["pair", "card", "extra"],
+			"dependentRequired": {"card": ["extra"]},
+			"properties": {
+				"pair": {"type": "array", "items": {"type": "string"}, "prefixItems": [{"type": "string"}], "deprecated": true, "propertyOrder": 0},
+				"card": {"type": "string", "propertyOrder": 1},
+				"extra": {"type": "string", "propertyOrder": 2}
+			}
+		}`},
+		{"draft-07", Draft07, `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			"title": "T",
+			"required": ["pair", "card", "extra"],
+			"dependencies": {"card": ["extra"]},
+			"properties": {
+				"pair": {"type": "array", "items": [{"type": "string"}], "additionalItems": {"type": "string"}, "propertyOrder": 0},
+				"card": {"type": "string", "propertyOrder": 1},
+				"extra": {"type": "string", "propertyOrder": 2}
+			}
+		}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{Draft(tt.draft)}, keywords...)
+			got, err := GenerateString(T{Pair: Pair{""}}, opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
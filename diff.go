@@ -0,0 +1,413 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Changes is a structural difference between an old schema and a new one.
+type Changes struct {
+	// Items are changes sorted by their pointers.
+	Items []Change
+}
+
+// Change is a difference of a keyword between schemas.
+type Change struct {
+	// Pointer is a JSON Pointer of the changed subschema in the old schema such as "/properties/name".
+	// The root is "".
+	Pointer string
+	// Keyword is the changed keyword such as "required".
+	// It is empty if a boolean schema false is changed or a schema is changed into it.
+	Keyword string
+	// Breaking reports whether the change breaks backward compatibility.
+	// A breaking change such as a removed property or a new required property
+	// can reject documents which the old schema accepts or break clients which rely on them.
+	Breaking bool
+	// Message describes the change such as `property "age" is removed`.
+	Message string
+}
+
+// Breaking returns the breaking changes.
+func (c *Changes) Breaking() []Change {
+	var changes []Change
+	for _, ch := range c.Items {
+		if ch.Breaking {
+			changes = append(changes, ch)
+		}
+	}
+	return changes
+}
+
+// Compatible reports whether the new schema is backward-compatible with the old one.
+func (c *Changes) Compatible() bool {
+	return len(c.Breaking()) == 0
+}
+
+// String returns the report such as:
+//
+//	/properties/age: breaking: property "age" is removed (properties)
+//	/properties/name: compatible: maxLength is raised from 10 to 20 (maxLength)
+func (c *Changes) String() string {
+	if len(c.Items) == 0 {
+		return "the schemas are equivalent"
+	}
+
+	var b strings.Builder
+	for _, ch := range c.Items {
+		p := ch.Pointer
+		if p == "" {
+			p = "(root)"
+		}
+		kind := "compatible"
+		if ch.Breaking {
+			kind = "breaking"
+		}
+		fmt.Fprintf(&b, "%s: %s: %s", p, kind, ch.Message)
+		if ch.Keyword != "" {
+			fmt.Fprintf(&b, " (%s)", ch.Keyword)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Diff compares the old schema with the new one and classifies their changes into
+// backward-compatible ones such as a new optional property or a widened enum
+// and breaking ones such as a removed property, a narrowed type or a new required property.
+// Annotations such as title and description are ignored as Equivalent does
+// and local references are followed in each schema.
+// Changes of keywords which cannot be classified such as anyOf are breaking
+// unless the keywords are removed.
+func Diff(old, new io.Reader) (*Changes, error) {
+	x, err := readSchema(old)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot decode old schema: %w", err)
+	}
+	y, err := readSchema(new)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot decode new schema: %w", err)
+	}
+
+	d := &differ{
+		eq:      &equivalence{a: x, b: y, assumed: map[[2]string]bool{}},
+		visited: map[[2]string]bool{},
+	}
+	d.schema("", x, y)
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Pointer < d.changes[j].Pointer
+	})
+	return &Changes{Items: d.changes}, nil
+}
+
+func readSchema(r io.Reader) (interface{}, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(b)
+}
+
+// lowerBounds and upperBounds are keywords which restrict values by their numbers.
+// Raising a lower bound and lowering an upper bound are breaking.
+var (
+	lowerBounds = map[string]bool{
+		"minimum": true, "exclusiveMinimum": true, "minLength": true,
+		"minItems": true, "minProperties": true, "minContains": true,
+	}
+	upperBounds = map[string]bool{
+		"maximum": true, "exclusiveMaximum": true, "maxLength": true,
+		"maxItems": true, "maxProperties": true, "maxContains": true,
+	}
+)
+
+// differ compares subschemas of the old and new root schemas.
+type differ struct {
+	eq *equivalence
+	// visited are pairs of referred locations which are already compared
+	// to compare recursive schemas.
+	visited map[[2]string]bool
+	changes []Change
+}
+
+func (d *differ) add(ptr, keyword string, breaking bool, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Pointer:  ptr,
+		Keyword:  keyword,
+		Breaking: breaking,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) schema(ptr string, x, y interface{}) {
+	x, xref := resolveRef(d.eq.a, x)
+	y, yref := resolveRef(d.eq.b, y)
+	if xref != "" && yref != "" {
+		key := [2]string{xref, yref}
+		if d.visited[key] {
+			return
+		}
+		d.visited[key] = true
+	}
+
+	xm, xok := validationKeywords(x)
+	ym, yok := validationKeywords(y)
+	switch {
+	case !xok && !yok:
+		return
+	case !xok:
+		d.add(ptr, "", false, "schema accepts values")
+		return
+	case !yok:
+		d.add(ptr, "", true, "schema rejects all values")
+		return
+	}
+
+	keywords := make([]string, 0, len(xm)+len(ym))
+	for k := range xm {
+		keywords = append(keywords, k)
+	}
+	for k := range ym {
+		if _, ok := xm[k]; !ok {
+			keywords = append(keywords, k)
+		}
+	}
+	sort.Strings(keywords)
+
+	for _, k := range keywords {
+		xv, xok := xm[k]
+		yv, yok := ym[k]
+		d.keyword(ptr, k, xv, xok, yv, yok)
+	}
+}
+
+// keyword compares the keyword of the schemas. xok and yok report whether the schemas have it.
+func (d *differ) keyword(ptr, k string, x interface{}, xok bool, y interface{}, yok bool) {
+	switch {
+	case k == "properties" || k == "patternProperties":
+		d.properties(ptr, k, x, y)
+		return
+	case k == "required":
+		d.required(ptr, x, y)
+		return
+	case k == "type":
+		d.types(ptr, x, xok, y, yok)
+		return
+	case k == "enum":
+		d.enum(ptr, x, xok, y, yok)
+		return
+	case lowerBounds[k] || upperBounds[k]:
+		if d.bound(ptr, k, x, xok, y, yok) {
+			return
+		}
+	case k == "items" || k == "additionalItems" || k == "additionalProperties" ||
+		k == "propertyNames" || k == "unevaluatedItems" || k == "unevaluatedProperties":
+		// missing subschemas accept any values
+		if !xok {
+			x = true
+		}
+		if !yok {
+			y = true
+		}
+		if d.subschemas(ptr, k, x, y) {
+			return
+		}
+	case k == "prefixItems":
+		if xok && yok && d.subschemas(ptr, k, x, y) {
+			return
+		}
+	}
+
+	switch {
+	case !xok:
+		d.add(ptr, k, true, "%s is added", k)
+	case !yok:
+		d.add(ptr, k, false, "%s is removed", k)
+	case !d.eq.keyword(k, x, y):
+		d.add(ptr, k, true, "%s is changed", k)
+	}
+}
+
+func (d *differ) properties(ptr, k string, x, y interface{}) {
+	xm, _ := x.(map[string]interface{})
+	ym, _ := y.(map[string]interface{})
+	what := "property"
+	if k == "patternProperties" {
+		what = "pattern property"
+	}
+
+	for _, name := range sortedKeys(xm) {
+		ys, ok := ym[name]
+		if !ok {
+			d.add(ptr, k, true, "%s %q is removed", what, name)
+			continue
+		}
+		d.schema(ptr+"/"+k+"/"+escapePointer(name), xm[name], ys)
+	}
+	for _, name := range sortedKeys(ym) {
+		if _, ok := xm[name]; !ok {
+			// properties are optional unless required says so,
+			// but new patterns restrict existing properties which match them
+			d.add(ptr, k, k == "patternProperties", "%s %q is added", what, name)
+		}
+	}
+}
+
+func (d *differ) required(ptr string, x, y interface{}) {
+	xs := stringSet(x)
+	ys := stringSet(y)
+	for _, name := range sortedSet(ys) {
+		if !xs[name] {
+			d.add(ptr, "required", true, "property %q is required", name)
+		}
+	}
+	for _, name := range sortedSet(xs) {
+		if !ys[name] {
+			d.add(ptr, "required", false, "property %q is optional", name)
+		}
+	}
+}
+
+func (d *differ) types(ptr string, x interface{}, xok bool, y interface{}, yok bool) {
+	switch {
+	case !xok:
+		d.add(ptr, "type", true, "type is restricted to %s", strings.Join(sortedSet(stringSet(y)), ", "))
+		return
+	case !yok:
+		d.add(ptr, "type", false, "type is unrestricted")
+		return
+	}
+
+	xs := stringSet(x)
+	ys := stringSet(y)
+	// integers are numbers
+	accepts := func(types map[string]bool, t string) bool {
+		return types[t] || t == "integer" && types["number"]
+	}
+	for _, t := range sortedSet(xs) {
+		if !accepts(ys, t) {
+			d.add(ptr, "type", true, "type %s is removed", t)
+		}
+	}
+	for _, t := range sortedSet(ys) {
+		if !accepts(xs, t) {
+			d.add(ptr, "type", false, "type %s is added", t)
+		}
+	}
+}
+
+func (d *differ) enum(ptr string, x interface{}, xok bool, y interface{}, yok bool) {
+	switch {
+	case !xok:
+		d.add(ptr, "enum", true, "enum is added")
+		return
+	case !yok:
+		d.add(ptr, "enum", false, "enum is removed")
+		return
+	}
+
+	xs := jsonSet(x)
+	ys := jsonSet(y)
+	for _, v := range sortedSet(xs) {
+		if !ys[v] {
+			d.add(ptr, "enum", true, "value %s is removed from enum", v)
+		}
+	}
+	for _, v := range sortedSet(ys) {
+		if !xs[v] {
+			d.add(ptr, "enum", false, "value %s is added to enum", v)
+		}
+	}
+}
+
+// bound compares the numeric keyword. It reports false if the values are not numbers
+// such as exclusiveMinimum of draft-04.
+func (d *differ) bound(ptr, k string, x interface{}, xok bool, y interface{}, yok bool) bool {
+	lower := lowerBounds[k]
+	switch {
+	case !xok:
+		if _, err := ratOf(y); err != nil {
+			return false
+		}
+		d.add(ptr, k, true, "%s %v is added", k, y)
+		return true
+	case !yok:
+		if _, err := ratOf(x); err != nil {
+			return false
+		}
+		d.add(ptr, k, false, "%s %v is removed", k, x)
+		return true
+	}
+
+	xr, xerr := ratOf(x)
+	yr, yerr := ratOf(y)
+	if xerr != nil || yerr != nil {
+		return false
+	}
+	switch c := yr.Cmp(xr); {
+	case c > 0:
+		d.add(ptr, k, lower, "%s is raised from %v to %v", k, x, y)
+	case c < 0:
+		d.add(ptr, k, !lower, "%s is lowered from %v to %v", k, x, y)
+	}
+	return true
+}
+
+// subschemas compares the keyword whose value is a subschema or a list of subschemas.
+// It reports false if they cannot be compared one by one.
+func (d *differ) subschemas(ptr, k string, x, y interface{}) bool {
+	xa, xok := x.([]interface{})
+	ya, yok := y.([]interface{})
+	switch {
+	case !xok && !yok:
+		d.schema(ptr+"/"+k, x, y)
+		return true
+	case xok && yok && len(xa) == len(ya):
+		for i := range xa {
+			d.schema(ptr+"/"+k+"/"+strconv.Itoa(i), xa[i], ya[i])
+		}
+		return true
+	}
+	return false
+}
+
+// stringSet returns the set of a string or strings such as type and required.
+func stringSet(v interface{}) map[string]bool {
+	set := map[string]bool{}
+	switch v := v.(type) {
+	case string:
+		set[v] = true
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				set[s] = true
+			}
+		}
+	}
+	return set
+}
+
+// jsonSet returns the set of normalized JSON texts of the values.
+func jsonSet(v interface{}) map[string]bool {
+	set := map[string]bool{}
+	vs, _ := v.([]interface{})
+	for _, e := range vs {
+		if s, err := normalizeJSON(e); err == nil {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+// sortedSet returns the elements of the set in order.
+func sortedSet(set map[string]bool) []string {
+	elems := make([]string, 0, len(set))
+	for e := range set {
+		elems = append(elems, e)
+	}
+	sort.Strings(elems)
+	return elems
+}
@@ -0,0 +1,166 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name       string
+		old, new   string
+		expect     []Change
+		compatible bool
+	}{
+		{
+			name:       "equivalent",
+			old:        `{"title": "T", "type": "object", "properties": {"name": {"type": "string"}}}`,
+			new:        `{"type": "object", "properties": {"name": {"type": "string", "description": "name"}}}`,
+			compatible: true,
+		},
+		{
+			name: "new optional property",
+			old:  `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			new:  `{"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}`,
+			expect: []Change{
+				{Pointer: "", Keyword: "properties", Message: `property "age" is added`},
+			},
+			compatible: true,
+		},
+		{
+			name: "new required property",
+			old:  `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			new:  `{"type": "object", "required": ["age"], "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}`,
+			expect: []Change{
+				{Pointer: "", Keyword: "properties", Message: `property "age" is added`},
+				{Pointer: "", Keyword: "required", Breaking: true, Message: `property "age" is required`},
+			},
+		},
+		{
+			name: "removed property",
+			old:  `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`,
+			new:  `{"type": "object", "properties": {}}`,
+			expect: []Change{
+				{Pointer: "", Keyword: "properties", Breaking: true, Message: `property "name" is removed`},
+				{Pointer: "", Keyword: "required", Message: `property "name" is optional`},
+			},
+		},
+		{
+			name: "enum",
+			old:  `{"properties": {"level": {"enum": ["low", "high"]}, "mode": {"enum": ["a", "b"]}}}`,
+			new:  `{"properties": {"level": {"enum": ["low", "middle", "high"]}, "mode": {"enum": ["a"]}}}`,
+			expect: []Change{
+				{Pointer: "/properties/level", Keyword: "enum", Message: `value "middle" is added to enum`},
+				{Pointer: "/properties/mode", Keyword: "enum", Breaking: true, Message: `value "b" is removed from enum`},
+			},
+		},
+		{
+			name: "type",
+			old:  `{"properties": {"a": {"type": "integer"}, "b": {"type": ["number", "string"]}, "c": {}}}`,
+			new:  `{"properties": {"a": {"type": "number"}, "b": {"type": "integer"}, "c": {"type": "string"}}}`,
+			expect: []Change{
+				{Pointer: "/properties/a", Keyword: "type", Message: "type number is added"},
+				{Pointer: "/properties/b", Keyword: "type", Breaking: true, Message: "type number is removed"},
+				{Pointer: "/properties/b", Keyword: "type", Breaking: true, Message: "type string is removed"},
+				{Pointer: "/properties/c", Keyword: "type", Breaking: true, Message: "type is restricted to string"},
+			},
+		},
+		{
+			name: "bounds",
+			old:  `{"type": "string", "minLength": 1, "maxLength": 10}`,
+			new:  `{"type": "string", "minLength": 2, "maxLength": 20, "pattern": "^a"}`,
+			expect: []Change{
+				{Pointer: "", Keyword: "maxLength", Message: "maxLength is raised from 10 to 20"},
+				{Pointer: "", Keyword: "minLength", Breaking: true, Message: "minLength is raised from 1 to 2"},
+				{Pointer: "", Keyword: "pattern", Breaking: true, Message: "pattern is added"},
+			},
+		},
+		{
+			name: "references",
+			old: `{
+				"type": "object",
+				"properties": {"address": {"$ref": "#/$defs/Address"}},
+				"$defs": {"Address": {"type": "object", "properties": {"city": {"type": "string"}}}}
+			}`,
+			new: `{
+				"type": "object",
+				"properties": {"address": {"$ref": "#/definitions/Addr"}},
+				"definitions": {"Addr": {"type": "object", "additionalProperties": false, "properties": {"city": {"type": "string"}}}}
+			}`,
+			expect: []Change{
+				{Pointer: "/properties/address/additionalProperties", Breaking: true, Message: "schema rejects all values"},
+			},
+		},
+		{
+			name: "recursive",
+			old: `{
+				"$ref": "#/$defs/Node",
+				"$defs": {"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}}}}
+			}`,
+			new: `{
+				"$ref": "#/$defs/Node",
+				"$defs": {"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}, "name": {"type": "string"}}}}
+			}`,
+			expect: []Change{
+				{Pointer: "", Keyword: "properties", Message: `property "name" is added`},
+			},
+			compatible: true,
+		},
+		{
+			name: "unclassified",
+			old:  `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			new:  `{"anyOf": [{"type": "string"}]}`,
+			expect: []Change{
+				{Pointer: "", Keyword: "anyOf", Breaking: true, Message: "anyOf is changed"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(strings.NewReader(tt.old), strings.NewReader(tt.new))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if len(changes.Items) != len(tt.expect) {
+				t.Fatalf("changes are %v, but want %v", changes.Items, tt.expect)
+			}
+			for i := range tt.expect {
+				if changes.Items[i] != tt.expect[i] {
+					t.Errorf("change %d is %+v, but want %+v", i, changes.Items[i], tt.expect[i])
+				}
+			}
+			if got := changes.Compatible(); got != tt.compatible {
+				t.Errorf("Compatible() = %v, but want %v", got, tt.compatible)
+			}
+		})
+	}
+}
+
+func TestChanges_String(t *testing.T) {
+	changes, err := Diff(
+		strings.NewReader(`{"properties": {"age": {}, "name": {"maxLength": 10}}}`),
+		strings.NewReader(`{"properties": {"name": {"maxLength": 20}}}`),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `(root): breaking: property "age" is removed (properties)
+/properties/name: compatible: maxLength is raised from 10 to 20 (maxLength)
+`
+	if got := changes.String(); got != expect {
+		t.Errorf("report is %q, but want %q", got, expect)
+	}
+}
+
+func TestDiff_error(t *testing.T) {
+	if _, err := Diff(strings.NewReader(`{`), strings.NewReader(`{}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+	if _, err := Diff(strings.NewReader(`{}`), strings.NewReader(`{} {}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
@@ -0,0 +1,414 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// directivePrefix is a prefix of directive comments.
+const directivePrefix = "//jsonschema:"
+
+// Directives parses Go source files and returns an Option which applies
+// directive comments of struct fields as an alternative to long struct tags:
+//
+//	type User struct {
+//		//jsonschema: minLength=3 pattern=^[a-z]+$
+//		//jsonschema: description="name of the user"
+//		Name string `json:"name"`
+//	}
+//
+// A directive is a space separated list of keyword=value pairs.
+// A value is decoded as JSON such as numbers and arrays, otherwise it is a string.
+// A value which contains spaces can be quoted as a Go string literal.
+// Directives are applied after struct tags and before other options.
+// It returns an error for malformed directives.
+//
+// Fields are identified by import paths of packages which are given by go.mod files
+// in parent directories of the files. If there is no go.mod file, package names are used instead.
+func Directives(filenames ...string) (Option, error) {
+	fset, pkgs, files, err := parseFiles(filenames)
+	if err != nil {
+		return nil, err
+	}
+	return parseDirectives(fset, pkgs, files)
+}
+
+// DirectivesFS is same as Directives but it reads Go source files from fsys.
+// Names can be patterns of fs.Glob such as "*.go".
+func DirectivesFS(fsys fs.FS, patterns ...string) (Option, error) {
+	fset, files, err := parseFS(fsys, patterns)
+	if err != nil {
+		return nil, err
+	}
+	return parseDirectives(fset, nil, files)
+}
+
+// parseFiles parses Go source files with comments
+// and returns import paths of the files which are given by go.mod files.
+func parseFiles(filenames []string) (*token.FileSet, map[*ast.File]string, []*ast.File, error) {
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(filenames))
+	pkgs := map[*ast.File]string{}
+	for _, name := range filenames {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		files = append(files, f)
+		path, err := importPathOf(filepath.Dir(name))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if path != "" {
+			pkgs[f] = testPackagePath(path, f)
+		}
+	}
+	return fset, pkgs, files, nil
+}
+
+// parseFS parses Go source files of fsys which match the patterns with comments.
+func parseFS(fsys fs.FS, patterns []string) (*token.FileSet, []*ast.File, error) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range names {
+			src, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, f)
+		}
+	}
+	return fset, files, nil
+}
+
+// DirectivesBuild is same as Directives but it reads Go source files of the package in dir
+// which match build constraints of ctxt such as GOOS, GOARCH and BuildTags,
+// so directives of fields which exist only on some platforms follow the build configuration.
+// If ctxt is nil, build.Default is used. Test files are not read.
+//
+// Fields are still given by reflection, so ctxt should match the build configuration
+// of the running program; otherwise directives of the other configuration are applied
+// to fields whose types and names are the same in both configurations.
+func DirectivesBuild(ctxt *build.Context, dir string) (Option, error) {
+	filenames, err := buildFiles(ctxt, dir)
+	if err != nil {
+		return nil, err
+	}
+	return Directives(filenames...)
+}
+
+// buildFiles returns names of Go source files of the package in dir which match ctxt.
+func buildFiles(ctxt *build.Context, dir string) ([]string, error) {
+	if ctxt == nil {
+		ctxt = &build.Default
+	}
+	pkg, err := ctxt.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	names := append(append([]string{}, pkg.GoFiles...), pkg.CgoFiles...)
+	sort.Strings(names)
+	filenames := make([]string, len(names))
+	for i := range names {
+		filenames[i] = filepath.Join(pkg.Dir, names[i])
+	}
+	return filenames, nil
+}
+
+// ParseDirectives is same as Directives but it accepts parsed files.
+// The files must be parsed with parser.ParseComments.
+// Fields are identified by package names because import paths of the files are unknown.
+func ParseDirectives(fset *token.FileSet, files ...*ast.File) (Option, error) {
+	return parseDirectives(fset, nil, files)
+}
+
+// parseDirectives parses directives of the files whose import paths are given by pkgs.
+// Files which are not in pkgs are identified by their package names.
+func parseDirectives(fset *token.FileSet, pkgs map[*ast.File]string, files []*ast.File) (Option, error) {
+	directives := map[string]map[string]interface{}{}
+	for _, f := range files {
+		pkg, ok := pkgs[f]
+		if !ok {
+			pkg = f.Name.Name
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					kw, err := parseDirectiveComments(fset, field.Doc)
+					if err != nil {
+						return nil, err
+					}
+					if len(kw) == 0 {
+						continue
+					}
+					for _, name := range fieldNames(field) {
+						directives[directiveKey(pkg, ts.Name.Name, name)] = kw
+					}
+				}
+			}
+		}
+	}
+
+	return configOption(func(c *config) {
+		if c.directives == nil {
+			c.directives = map[string]map[string]interface{}{}
+		}
+		for k, v := range directives {
+			c.directives[k] = v
+		}
+	}), nil
+}
+
+// directiveKey returns a key of directives of the field.
+// The package is an import path or a package name.
+func directiveKey(pkg, typeName, field string) string {
+	return pkg + "." + typeName + "." + field
+}
+
+// directivesOf returns directives of the field of the struct type t.
+// Keys by the import path have priority over keys by the package name.
+func (c *config) directivesOf(t reflect.Type, field string) (map[string]interface{}, bool) {
+	if len(c.directives) == 0 || t.Name() == "" {
+		return nil, false
+	}
+	for _, key := range sourceKeys(t, field) {
+		if kw, ok := c.directives[key]; ok {
+			return kw, true
+		}
+	}
+	return nil, false
+}
+
+// sourceKeys returns keys of the field of the named type t in the order of priority,
+// which are a key by the import path and a key by the package name.
+func sourceKeys(t reflect.Type, field string) []string {
+	// type parameters of generic types are not written in declarations
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+
+	pkg := t.String()
+	if i := strings.IndexByte(pkg, '['); i >= 0 {
+		pkg = pkg[:i]
+	}
+	pkg = pkg[:strings.LastIndexByte(pkg, '.')+1]
+	return []string{
+		directiveKey(t.PkgPath(), name, field),
+		directiveKey(strings.TrimSuffix(pkg, "."), name, field),
+	}
+}
+
+// importPathOf returns the import path of the package in dir by go.mod in its parent directories.
+// It returns an empty string if there is no go.mod.
+func importPathOf(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var rel []string
+	for {
+		b, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		switch {
+		case err == nil:
+			mod := modulePath(b)
+			if mod == "" {
+				return "", nil
+			}
+			for i := len(rel) - 1; i >= 0; i-- {
+				mod += "/" + rel[i]
+			}
+			return mod, nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		rel = append(rel, filepath.Base(dir))
+		dir = parent
+	}
+}
+
+// modulePath returns the module path of the go.mod file.
+func modulePath(mod []byte) string {
+	for _, line := range strings.Split(string(mod), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
+
+// testPackagePath returns the import path of the external test package if the file is in it.
+func testPackagePath(path string, f *ast.File) string {
+	if strings.HasSuffix(f.Name.Name, "_test") && !strings.HasSuffix(path, "_test") {
+		return path + "_test"
+	}
+	return path
+}
+
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) != 0 {
+		names := make([]string, len(field.Names))
+		for i := range field.Names {
+			names[i] = field.Names[i].Name
+		}
+		return names
+	}
+
+	// embedded field
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch typ := typ.(type) {
+	case *ast.Ident:
+		return []string{typ.Name}
+	case *ast.SelectorExpr:
+		return []string{typ.Sel.Name}
+	}
+	return nil
+}
+
+func parseDirectiveComments(fset *token.FileSet, doc *ast.CommentGroup) (map[string]interface{}, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	var kw map[string]interface{}
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+
+		if kw == nil {
+			kw = map[string]interface{}{}
+		}
+		if err := parseDirective(strings.TrimPrefix(c.Text, directivePrefix), kw); err != nil {
+			return nil, fmt.Errorf("%s: %w", fset.Position(c.Pos()), newError(ErrTagSyntax, "", fmt.Errorf("malformed directive: %w", err)))
+		}
+	}
+
+	return kw, nil
+}
+
+// parseDirective parses keyword=value pairs of a directive into kw.
+func parseDirective(s string, kw map[string]interface{}) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return fmt.Errorf("no keywords")
+	}
+
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return fmt.Errorf("%q does not have a value", strings.Fields(s)[0])
+		}
+		key := s[:eq]
+		if key == "" || strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+			return fmt.Errorf("invalid keyword %q", key)
+		}
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			quoted := quotedPrefix(s)
+			var err error
+			value, err = strconv.Unquote(quoted)
+			if err != nil {
+				return fmt.Errorf("invalid quoted value of %q: %w", key, err)
+			}
+			kw[key] = value
+			s = s[len(quoted):]
+		} else {
+			end := strings.IndexFunc(s, unicode.IsSpace)
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+			if value == "" {
+				return fmt.Errorf("%q does not have a value", key)
+			}
+			kw[key] = directiveValue(value)
+		}
+
+		if s != "" && !unicode.IsSpace(rune(s[0])) {
+			return fmt.Errorf("value of %q is followed by %q", key, s)
+		}
+		s = strings.TrimSpace(s)
+	}
+
+	return nil
+}
+
+// quotedPrefix returns the prefix of s which is a double quoted string.
+// If the string is not terminated, it returns s.
+func quotedPrefix(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return s[:i+1]
+		}
+	}
+	return s
+}
+
+func directiveValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		return v
+	}
+	return s
+}
+
+// directiveOption creates an Option which sets keywords of a directive.
+func directiveOption(kw map[string]interface{}) Option {
+	return func(o Object) (Object, error) {
+		for k, v := range kw {
+			o.Set(k, copyValue(v))
+		}
+		return o, nil
+	}
+}
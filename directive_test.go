@@ -0,0 +1,193 @@
+package jsonschema_test
+
+import (
+	"go/build"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/tenntenn/jsonschema/internal/directivetest"
+	other "github.com/tenntenn/jsonschema/internal/directivetest/other"
+)
+
+type directiveUser struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Email string   `json:"email" jsonschema:"format=email"`
+}
+
+func TestParseDirectives(t *testing.T) {
+	const src = `package jsonschema_test
+
+type directiveUser struct {
+	// Name is a name.
+	//jsonschema: minLength=3 pattern=^[a-z]+$
+	//jsonschema: description="name of the user"
+	Name  string   ` + "`json:\"name\"`" + `
+	//jsonschema: enum=["a","b"] maxItems=2
+	Tags  []string ` + "`json:\"tags\"`" + `
+	//jsonschema: format=idn-email
+	Email string   ` + "`json:\"email\" jsonschema:\"format=email\"`" + `
+}
+`
+
+	cases := []struct {
+		name   string
+		src    string
+		expect string
+		err    string
+	}{
+		{
+			name: "directives",
+			src:  src,
+			expect: `{
+				"type": "object",
+				"title": "directiveUser",
+				"required": ["name", "tags", "email"],
+				"properties": {
+					"name": {"type": "string", "minLength": 3, "pattern": "^[a-z]+$", "description": "name of the user", "propertyOrder": 0},
+					"tags": {"type": "array", "items": {"type": "string"}, "enum": ["a", "b"], "maxItems": 2, "propertyOrder": 1},
+					"email": {"type": "string", "format": "idn-email", "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "no value",
+			src:  "package p\ntype T struct {\n//jsonschema: minLength\nName string\n}\n",
+			err:  `src.go:3:1: jsonschema: malformed directive: "minLength" does not have a value`,
+		},
+		{
+			name: "empty",
+			src:  "package p\ntype T struct {\n//jsonschema:\nName string\n}\n",
+			err:  "src.go:3:1: jsonschema: malformed directive: no keywords",
+		},
+		{
+			name: "unterminated quote",
+			src:  "package p\ntype T struct {\n//jsonschema: description=\"name\nName string\n}\n",
+			err:  `src.go:3:1: jsonschema: malformed directive: invalid quoted value of "description"`,
+		},
+		{
+			name: "empty value",
+			src:  "package p\ntype T struct {\n//jsonschema: minLength= maxLength=3\nName string\n}\n",
+			err:  `src.go:3:1: jsonschema: malformed directive: "minLength" does not have a value`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "src.go", tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			opt, err := ParseDirectives(fset, f)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Fatalf("want error %q but got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			got, err := GenerateString(directiveUser{Tags: []string{"a"}}, opt)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestDirectivesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.go":  {Data: []byte("package jsonschema_test\ntype directiveUser struct {\n//jsonschema: minLength=1\nName string\n}\n")},
+		"b.go":  {Data: []byte("package jsonschema_test\ntype other struct {\n//jsonschema: minimum=1\nN int\n}\n")},
+		"c.txt": {Data: []byte("not go")},
+	}
+
+	opt, err := DirectivesFS(fsys, "*.go")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	got, err := GenerateString(directiveUser{Tags: []string{"a"}}, opt)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !strings.Contains(got, `"minLength":1`) {
+		t.Errorf("directive is not applied: %s", got)
+	}
+}
+
+func TestDirectivesBuild(t *testing.T) {
+	cases := []struct {
+		name   string
+		goos   string
+		tags   []string
+		v      interface{}
+		expect string
+	}{
+		{"linux", "linux", nil, directivetest.Platform{}, `"description":"path of the socket"`},
+		{"windows", "windows", nil, directivetest.Platform{}, `"description":"name of the pipe"`},
+		{"without tag", "linux", nil, directivetest.Debug{}, `"trace":{"propertyOrder":0,"type":"string"}`},
+		{"with tag", "linux", []string{"debug"}, directivetest.Debug{}, `"description":"debug only"`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctxt := build.Default
+			ctxt.GOOS, ctxt.BuildTags = tt.goos, tt.tags
+			opt, err := DirectivesBuild(&ctxt, "internal/directivetest")
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			got, err := GenerateString(tt.v, opt)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if !strings.Contains(got, tt.expect) {
+				t.Errorf("want %s in %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestDirectivesBuild_samePackageName(t *testing.T) {
+	var opts []Option
+	for _, dir := range []string{"internal/directivetest", "internal/directivetest/other"} {
+		opt, err := DirectivesBuild(nil, dir)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		opts = append(opts, opt)
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		expect string
+	}{
+		{"parent", directivetest.Config{}, `"minLength":1`},
+		{"other", other.Config{}, `"minLength":5`},
+	}
+	for _, tt := range cases {
+		got, err := GenerateString(tt.v, opts...)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if !strings.Contains(got, tt.expect) {
+			t.Errorf("%s: want %s in %s", tt.name, tt.expect, got)
+		}
+	}
+}
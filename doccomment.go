@@ -0,0 +1,150 @@
+package jsonschema
+
+import (
+	"go/ast"
+	"go/build"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strings"
+)
+
+// DocComments parses Go source files and returns an Option which sets doc comments
+// of struct types and their fields as descriptions, which runtime reflection cannot see:
+//
+//	// User is a user of the service.
+//	type User struct {
+//		// Name is the display name.
+//		Name string `json:"name"`
+//	}
+//
+// A field without a doc comment uses its line comment.
+// Directive comments are not parts of descriptions.
+// Descriptions which are given by tags, directives and other options have priority,
+// and a doc comment of a field has priority over the doc comment of its type.
+//
+// Types and fields are identified in the same way as Directives.
+func DocComments(filenames ...string) (Option, error) {
+	_, pkgs, files, err := parseFiles(filenames)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocComments(pkgs, files), nil
+}
+
+// DocCommentsFS is same as DocComments but it reads Go source files from fsys.
+// Names can be patterns of fs.Glob such as "*.go".
+func DocCommentsFS(fsys fs.FS, patterns ...string) (Option, error) {
+	_, files, err := parseFS(fsys, patterns)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocComments(nil, files), nil
+}
+
+// DocCommentsBuild is same as DocComments but it reads Go source files of the package in dir
+// which match build constraints of ctxt as DirectivesBuild.
+func DocCommentsBuild(ctxt *build.Context, dir string) (Option, error) {
+	filenames, err := buildFiles(ctxt, dir)
+	if err != nil {
+		return nil, err
+	}
+	return DocComments(filenames...)
+}
+
+// ParseDocComments is same as DocComments but it accepts parsed files.
+// The files must be parsed with parser.ParseComments.
+func ParseDocComments(files ...*ast.File) Option {
+	return parseDocComments(nil, files)
+}
+
+// parseDocComments collects doc comments of the files whose import paths are given by pkgs.
+// Doc comments of types are keyed by empty field names.
+func parseDocComments(pkgs map[*ast.File]string, files []*ast.File) Option {
+	docs := map[string]string{}
+	for _, f := range files {
+		pkg, ok := pkgs[f]
+		if !ok {
+			pkg = f.Name.Name
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+
+				doc := ts.Doc
+				if doc == nil && !gd.Lparen.IsValid() {
+					doc = gd.Doc
+				}
+				if text := docText(doc); text != "" {
+					docs[directiveKey(pkg, ts.Name.Name, "")] = text
+				}
+
+				for _, field := range st.Fields.List {
+					text := docText(field.Doc)
+					if text == "" {
+						text = docText(field.Comment)
+					}
+					if text == "" {
+						continue
+					}
+					for _, name := range fieldNames(field) {
+						docs[directiveKey(pkg, ts.Name.Name, name)] = text
+					}
+				}
+			}
+		}
+	}
+
+	return configOption(func(c *config) {
+		if c.docs == nil {
+			c.docs = map[string]string{}
+		}
+		for k, v := range docs {
+			c.docs[k] = v
+		}
+	})
+}
+
+// docText returns the text of the comment group without directive comments.
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	var cg ast.CommentGroup
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			cg.List = append(cg.List, c)
+		}
+	}
+	return strings.TrimSpace(cg.Text())
+}
+
+// docOf returns the doc comment of the field of the struct type t
+// or the doc comment of t if the field is empty.
+func (c *config) docOf(t reflect.Type, field string) (string, bool) {
+	if len(c.docs) == 0 || t.Name() == "" {
+		return "", false
+	}
+	for _, key := range sourceKeys(t, field) {
+		if doc, ok := c.docs[key]; ok {
+			return doc, true
+		}
+	}
+	return "", false
+}
+
+// docOption creates an Option which sets the doc comment as the description.
+func docOption(doc string) Option {
+	return func(o Object) (Object, error) {
+		o.Set("description", doc)
+		return o, nil
+	}
+}
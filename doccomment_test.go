@@ -0,0 +1,102 @@
+package jsonschema_test
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/tenntenn/jsonschema/internal/directivetest"
+)
+
+type docAddress struct {
+	City string `json:"city"`
+}
+
+type docUser struct {
+	Name    string     `json:"name"`
+	Email   string     `json:"email" jsonschema:"description=address by tag"`
+	Age     int        `json:"age"`
+	Home    docAddress `json:"home"`
+	Office  docAddress `json:"office"`
+	Comment string     `json:"comment"`
+}
+
+func TestParseDocComments(t *testing.T) {
+	const src = `package jsonschema_test
+
+// docAddress is a postal address.
+type docAddress struct {
+	City string
+}
+
+// docUser is a user.
+//
+// It has two paragraphs.
+type docUser struct {
+	// Name is the display name.
+	//jsonschema: minLength=1
+	Name string
+	// Email is the address of the user.
+	Email string
+	Age int // Age in years.
+	Home docAddress
+	// Office is the address of the office.
+	Office docAddress
+	//jsonschema: maxLength=10
+	Comment string
+}
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "src.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	directives, err := ParseDirectives(fset, f)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	got, err := GenerateString(docUser{}, ParseDocComments(f), directives)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	address := `"type": "object", "title": "docAddress", "required": ["city"],
+		"properties": {"city": {"type": "string", "propertyOrder": 0}}`
+	expect := `{
+		"type": "object",
+		"title": "docUser",
+		"description": "docUser is a user.\n\nIt has two paragraphs.",
+		"required": ["name", "email", "age", "home", "office", "comment"],
+		"properties": {
+			"name": {"type": "string", "description": "Name is the display name.", "minLength": 1, "propertyOrder": 0},
+			"email": {"type": "string", "description": "address by tag", "propertyOrder": 1},
+			"age": {"type": "number", "description": "Age in years.", "propertyOrder": 2},
+			"home": {` + address + `, "description": "docAddress is a postal address.", "propertyOrder": 3},
+			"office": {` + address + `, "description": "Office is the address of the office.", "propertyOrder": 4},
+			"comment": {"type": "string", "maxLength": 10, "propertyOrder": 5}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestDocCommentsBuild(t *testing.T) {
+	opt, err := DocCommentsBuild(nil, "internal/directivetest")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	got, err := GenerateString(directivetest.Config{}, opt)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `"description":"Config is a type which is also declared in the other package with the same name."`
+	if !strings.Contains(got, expect) {
+		t.Errorf("want %s in %s", expect, got)
+	}
+}
@@ -0,0 +1,168 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// SchemaDraft is a draft of JSON Schema which generated schemas target.
+type SchemaDraft int
+
+const (
+	// DraftUnspecified emits no $schema and uses keywords of draft 2020-12. It is the default.
+	DraftUnspecified SchemaDraft = iota
+	// Draft04 is draft-04. Definitions are in definitions, $id is id
+	// and exclusiveMinimum and exclusiveMaximum are booleans beside minimum and maximum.
+	Draft04
+	// Draft06 is draft-06. Definitions are in definitions.
+	Draft06
+	// Draft07 is draft-07. Definitions are in definitions.
+	Draft07
+	// Draft201909 is draft 2019-09.
+	Draft201909
+	// Draft202012 is draft 2020-12.
+	Draft202012
+)
+
+// draftURIs are values of $schema of drafts.
+var draftURIs = map[SchemaDraft]string{
+	Draft04:     "http://json-schema.org/draft-04/schema#",
+	Draft06:     "http://json-schema.org/draft-06/schema#",
+	Draft07:     "http://json-schema.org/draft-07/schema#",
+	Draft201909: "https://json-schema.org/draft/2019-09/schema",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// Draft sets the draft of generated schemas.
+// The root object has $schema of the draft and keywords which differ between drafts
+// such as definitions and $defs are emitted for the draft.
+func Draft(d SchemaDraft) Option {
+	return configOption(func(c *config) {
+		c.draft = d
+	})
+}
+
+// ID sets $id of the root object, which is id in draft-04.
+func ID(uri string) Option {
+	return configOption(func(c *config) {
+		c.id = uri
+	})
+}
+
+// applyDraft converts the root schema for the draft and sets $schema and $id.
+// Keywords which the draft does not define are converted or removed.
+func (c *config) applyDraft(root map[string]interface{}) {
+	if c.draft == Draft04 || c.draft == Draft06 || c.draft == Draft07 {
+		if defs, ok := root["$defs"]; ok {
+			delete(root, "$defs")
+			root["definitions"] = defs
+		}
+		rewriteRefs(root, func(ref string) string {
+			if strings.HasPrefix(ref, "#/$defs/") {
+				return "#/definitions/" + strings.TrimPrefix(ref, "#/$defs/")
+			}
+			return ref
+		})
+	}
+
+	if c.draft == Draft04 {
+		walkSubschemas(root, func(s map[string]interface{}) {
+			draft04Exclusive(s, "exclusiveMinimum", "minimum", 1)
+			draft04Exclusive(s, "exclusiveMaximum", "maximum", -1)
+		})
+	}
+
+	if c.draft != DraftUnspecified {
+		walkSubschemas(root, func(s map[string]interface{}) {
+			downgradeKeywords(s, c.draft)
+			dropUnsupported(s, c.draft)
+		})
+	}
+
+	idKey := "$id"
+	if c.draft == Draft04 {
+		idKey = "id"
+	}
+	if c.id != "" {
+		root[idKey] = c.id
+	}
+	if uri, ok := draftURIs[c.draft]; ok {
+		root["$schema"] = uri
+	}
+}
+
+// draft04Exclusive converts the numeric exclusive keyword into the boolean one of draft-04.
+// If the inclusive keyword is stricter, the exclusive keyword is removed.
+// sign is 1 for minimum and -1 for maximum.
+func draft04Exclusive(s map[string]interface{}, exclusive, inclusive string, sign int) {
+	ex, ok := s[exclusive]
+	if !ok {
+		return
+	}
+	exr, ok := numberRat(ex)
+	if !ok {
+		// already a boolean
+		return
+	}
+
+	if in, ok := s[inclusive]; ok {
+		if inr, ok := numberRat(in); ok && inr.Cmp(exr)*sign > 0 {
+			delete(s, exclusive)
+			return
+		}
+	}
+	s[inclusive] = ex
+	s[exclusive] = true
+}
+
+// numberRat returns the number of a generated schema.
+func numberRat(v interface{}) (*big.Rat, bool) {
+	switch v.(type) {
+	case bool, string, nil:
+		return nil, false
+	}
+	return new(big.Rat).SetString(fmt.Sprint(v))
+}
+
+// walkSubschemas calls f with the schema and all its subschemas.
+func walkSubschemas(s map[string]interface{}, f func(s map[string]interface{})) {
+	f(s)
+	for k, v := range s {
+		switch k {
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas", "dependencies":
+			if m, ok := v.(map[string]interface{}); ok {
+				for _, e := range m {
+					if sm, ok := e.(map[string]interface{}); ok {
+						walkSubschemas(sm, f)
+					}
+				}
+			}
+		case "items", "additionalItems", "additionalProperties", "contains", "propertyNames",
+			"not", "if", "then", "else", "unevaluatedItems", "unevaluatedProperties":
+			if sm, ok := v.(map[string]interface{}); ok {
+				walkSubschemas(sm, f)
+			}
+			if ss, ok := v.([]interface{}); ok {
+				for _, e := range ss {
+					if sm, ok := e.(map[string]interface{}); ok {
+						walkSubschemas(sm, f)
+					}
+				}
+			}
+		case "allOf", "anyOf", "oneOf", "prefixItems":
+			switch ss := v.(type) {
+			case []interface{}:
+				for _, e := range ss {
+					if sm, ok := e.(map[string]interface{}); ok {
+						walkSubschemas(sm, f)
+					}
+				}
+			case []map[string]interface{}:
+				for _, sm := range ss {
+					walkSubschemas(sm, f)
+				}
+			}
+		}
+	}
+}
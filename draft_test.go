@@ -0,0 +1,122 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type draftNode struct {
+	Value    int          `json:"value" jsonschema:"exclusiveMinimum=0,maximum=10,exclusiveMaximum=20"`
+	Children []*draftNode `json:"children"`
+}
+
+func TestDraft(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{"unspecified", nil, `{
+			"type": "object",
+			"title": "draftNode",
+			"required": ["value", "children"],
+			"properties": {
+				"value": {"type": "number", "exclusiveMinimum": 0, "maximum": 10, "exclusiveMaximum": 20, "propertyOrder": 0},
+				"children": {"type": "array", "items": {"$ref": "#"}, "propertyOrder": 1}
+			}
+		}`},
+		{"draft-04", []Option{Draft(Draft04), ID("https://example.com/node.json")}, `{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"id": "https://example.com/node.json",
+			"type": "object",
+			"title": "draftNode",
+			"required": ["value", "children"],
+			"properties": {
+				"value": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 10, "propertyOrder": 0},
+				"children": {"type": "array", "items": {"$ref": "#"}, "propertyOrder": 1}
+			}
+		}`},
+		{"draft-07", []Option{Draft(Draft07), ID("https://example.com/node.json")}, `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"$id": "https://example.com/node.json",
+			"type": "object",
+			"title": "draftNode",
+			"required": ["value", "children"],
+			"properties": {
+				"value": {"type": "number", "exclusiveMinimum": 0, "maximum": 10, "exclusiveMaximum": 20, "propertyOrder": 0},
+				"children": {"type": "array", "items": {"$ref": "#"}, "propertyOrder": 1}
+			}
+		}`},
+		{"draft 2020-12", []Option{Draft(Draft202012)}, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"title": "draftNode",
+			"required": ["value", "children"],
+			"properties": {
+				"value": {"type": "number", "exclusiveMinimum": 0, "maximum": 10, "exclusiveMaximum": 20, "propertyOrder": 0},
+				"children": {"type": "array", "items": {"$ref": "#"}, "propertyOrder": 1}
+			}
+		}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{SharedTypes(SharedTypesRecursive)}, tt.opts...)
+			got, err := GenerateString(draftNode{}, opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestDraft_definitions(t *testing.T) {
+	type Leaf struct {
+		Name string `json:"name"`
+	}
+	type Root struct {
+		Leaf Leaf `json:"leaf"`
+	}
+
+	drafts := map[SchemaDraft]string{
+		Draft04: "http://json-schema.org/draft-04/schema#",
+		Draft06: "http://json-schema.org/draft-06/schema#",
+		Draft07: "http://json-schema.org/draft-07/schema#",
+	}
+	for d, uri := range drafts {
+		got, err := GenerateString(Root{}, SharedTypes(SharedTypesRef), Draft(d))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		expect := `{
+			"$schema": "` + uri + `",
+			"type": "object",
+			"title": "Root",
+			"required": ["leaf"],
+			"properties": {"leaf": {"$ref": "#/definitions/Leaf", "propertyOrder": 0}},
+			"definitions": {"Leaf": {
+				"type": "object",
+				"title": "Leaf",
+				"required": ["name"],
+				"properties": {"name": {"type": "string", "propertyOrder": 0}}
+			}}
+		}`
+		if diff := jsonDiff(t, got, expect); diff != "" {
+			t.Errorf("draft %d: generated JSON Schema does not match to expected one: %v", d, diff)
+		}
+
+		s, err := CompileBytes([]byte(got))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if err := s.Validate([]byte(`{"leaf": {"name": 1}}`)); err == nil {
+			t.Errorf("draft %d: invalid document is accepted", d)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func init() {
+	// validation of schemas supports the formats of this package
+	gojsonschema.FormatCheckers.Add("duration", durationChecker{})
+	gojsonschema.FormatCheckers.Add("time-zone", timeZoneChecker{})
+}
+
+// ISODurations generates time.Duration values as strings of the format "duration"
+// which are ISO 8601 durations such as "PT1H30M" instead of numbers of nanoseconds.
+// encoding/json encodes time.Duration as numbers, so documents must be encoded
+// by FormatDuration or fields must be Duration.
+func ISODurations() Option {
+	return configOption(func(c *config) {
+		c.isoDurations = true
+	})
+}
+
+// Duration is a time.Duration which is encoded in JSON as an ISO 8601 duration such as "PT1H30M".
+// Fields of the type are generated as strings of the format "duration".
+// Negative durations cannot be encoded.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if d < 0 {
+		return nil, fmt.Errorf("jsonschema: negative duration %s cannot be an ISO 8601 duration", time.Duration(d))
+	}
+	return json.Marshal(FormatDuration(time.Duration(d)))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// FormatDuration formats the duration as an ISO 8601 duration of hours, minutes and seconds
+// such as "PT36H0.5S". The zero duration is "PT0S".
+// Negative durations have a leading "-" such as "-PT1M", which the format "duration" does not accept.
+func FormatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+	}
+	// the absolute value of math.MinInt64 overflows int64
+	u := uint64(d)
+	if d < 0 {
+		u = -u
+	}
+	b.WriteString("PT")
+
+	if h := u / uint64(time.Hour); h != 0 {
+		b.WriteString(strconv.FormatUint(h, 10) + "H")
+	}
+	if m := u % uint64(time.Hour) / uint64(time.Minute); m != 0 {
+		b.WriteString(strconv.FormatUint(m, 10) + "M")
+	}
+	if ns := u % uint64(time.Minute); ns != 0 || u == 0 {
+		s := strconv.FormatUint(ns/uint64(time.Second), 10)
+		if frac := ns % uint64(time.Second); frac != 0 {
+			s += strings.TrimRight(fmt.Sprintf(".%09d", frac), "0")
+		}
+		b.WriteString(s + "S")
+	}
+	return b.String()
+}
+
+// durationPattern matches ISO 8601 durations of RFC 3339 Appendix A
+// whose smallest components can have fractions.
+var durationPattern = regexp.MustCompile(`^(-)?P(?:([0-9]+)W|(?:([0-9]+)Y)?(?:([0-9]+)M)?(?:([0-9]+)D)?(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+(?:[.,][0-9]+)?)S)?)?)$`)
+
+// ParseDuration parses an ISO 8601 duration such as "P1DT12H" or "PT0.5S".
+// Years and months are errors because their lengths vary, and a day is 24 hours.
+// A leading "-" negates the duration as FormatDuration formats.
+func ParseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil || !hasDurationComponent(s) {
+		return 0, fmt.Errorf("jsonschema: invalid ISO 8601 duration %q", s)
+	}
+	if m[3] != "" || m[4] != "" {
+		return 0, fmt.Errorf("jsonschema: ISO 8601 duration %q has years or months which are not fixed lengths", s)
+	}
+
+	// weeks and days are converted to hours which time.ParseDuration accepts
+	const maxHours = math.MaxInt64 / int64(time.Hour)
+	var hours int64
+	for _, c := range []struct {
+		value string
+		hours int64
+	}{{m[2], 24 * 7}, {m[5], 24}, {m[6], 1}} {
+		if c.value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(c.value, 10, 64)
+		if err != nil || n > maxHours/c.hours {
+			return 0, fmt.Errorf("jsonschema: ISO 8601 duration %q is out of range", s)
+		}
+		hours += n * c.hours
+	}
+
+	goDuration := m[1] + strconv.FormatInt(hours, 10) + "h"
+	if m[7] != "" {
+		goDuration += m[7] + "m"
+	}
+	if m[8] != "" {
+		goDuration += strings.Replace(m[8], ",", ".", 1) + "s"
+	}
+	d, err := time.ParseDuration(goDuration)
+	if err != nil {
+		return 0, fmt.Errorf("jsonschema: ISO 8601 duration %q is out of range", s)
+	}
+	return d, nil
+}
+
+// hasDurationComponent reports whether the duration which matches durationPattern has components
+// and its T is followed by a component, e.g. "P" and "P1DT" are invalid.
+func hasDurationComponent(s string) bool {
+	return !strings.HasSuffix(s, "P") && !strings.HasSuffix(s, "T")
+}
+
+// durationChecker checks the format "duration" which is an ISO 8601 duration without a sign.
+type durationChecker struct{}
+
+func (durationChecker) IsFormat(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	return !strings.HasPrefix(s, "-") && durationPattern.MatchString(s) && hasDurationComponent(s)
+}
@@ -0,0 +1,175 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestFormatDuration(t *testing.T) {
+	cases := []struct {
+		d      time.Duration
+		expect string
+	}{
+		{0, "PT0S"},
+		{90 * time.Minute, "PT1H30M"},
+		{36*time.Hour + 500*time.Millisecond, "PT36H0.5S"},
+		{time.Nanosecond, "PT0.000000001S"},
+		{-time.Minute, "-PT1M"},
+	}
+
+	for _, tt := range cases {
+		got := FormatDuration(tt.d)
+		if got != tt.expect {
+			t.Errorf("FormatDuration(%v): want %q but got %q", tt.d, tt.expect, got)
+		}
+		if d, err := ParseDuration(got); err != nil || d != tt.d {
+			t.Errorf("ParseDuration(%q): want %v but got %v, %v", got, tt.d, d, err)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		s      string
+		expect time.Duration
+		err    bool
+	}{
+		{s: "P1DT12H", expect: 36 * time.Hour},
+		{s: "P2W", expect: 14 * 24 * time.Hour},
+		{s: "PT1,5S", expect: 1500 * time.Millisecond},
+		{s: "PT90M", expect: 90 * time.Minute},
+		{s: "P1Y", err: true},
+		{s: "P1M", err: true},
+		{s: "P", err: true},
+		{s: "P1DT", err: true},
+		{s: "PT1.5M", err: true},
+		{s: "1h", err: true},
+		{s: "P99999999999W", err: true},
+	}
+
+	for _, tt := range cases {
+		got, err := ParseDuration(tt.s)
+		switch {
+		case tt.err && err == nil:
+			t.Errorf("ParseDuration(%q): want an error but got %v", tt.s, got)
+		case !tt.err && err != nil:
+			t.Errorf("ParseDuration(%q): unexpected error: %v", tt.s, err)
+		case got != tt.expect:
+			t.Errorf("ParseDuration(%q): want %v but got %v", tt.s, tt.expect, got)
+		}
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	b, err := json.Marshal(Duration(time.Hour))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if string(b) != `"PT1H"` {
+		t.Errorf(`want "PT1H" but got %s`, b)
+	}
+
+	var d Duration
+	if err := json.Unmarshal([]byte(`"P1DT30M"`), &d); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if time.Duration(d) != 24*time.Hour+30*time.Minute {
+		t.Errorf("unexpected duration %v", time.Duration(d))
+	}
+
+	if _, err := json.Marshal(Duration(-time.Hour)); err == nil {
+		t.Error("want an error of a negative duration")
+	}
+	if err := json.Unmarshal([]byte(`3600`), &d); err == nil {
+		t.Error("want an error of a number")
+	}
+}
+
+func TestGenerate_durationAndTimeZone(t *testing.T) {
+	type Job struct {
+		Timeout  time.Duration `json:"timeout"`
+		Interval Duration      `json:"interval"`
+		Zone     TimeZone      `json:"zone"`
+	}
+
+	cases := []struct {
+		name    string
+		opts    []Option
+		timeout string
+	}{
+		{"default", nil, `{"type": "number"}`},
+		{"ISODurations", []Option{ISODurations()}, `{"type": "string", "format": "duration"}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(Job{}, append(tt.opts, MapKeywords(KeywordRenames{"propertyOrder": ""}))...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			expect := `{
+				"title": "Job",
+				"type": "object",
+				"required": ["timeout", "interval", "zone"],
+				"properties": {
+					"timeout": ` + tt.timeout + `,
+					"interval": {"type": "string", "format": "duration"},
+					"zone": {"type": "string", "format": "time-zone", "pattern": "^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9][A-Za-z0-9_+-]*)*$"}
+				}
+			}`
+			if diff := jsonDiff(t, got, expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidate_durationAndTimeZone(t *testing.T) {
+	type Job struct {
+		Interval Duration `json:"interval"`
+		Zone     TimeZone `json:"zone"`
+	}
+	schema, err := GenerateBytes(Job{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	s, err := CompileBytes(schema)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		doc   string
+		valid bool
+	}{
+		{`{"interval": "PT1H30M", "zone": "Asia/Tokyo"}`, true},
+		{`{"interval": "P1Y2M", "zone": "UTC"}`, true},
+		{`{"interval": "P1W", "zone": "America/Port-au-Prince"}`, true},
+		{`{"interval": "1h30m", "zone": "Asia/Tokyo"}`, false},
+		{`{"interval": "-PT1H", "zone": "Asia/Tokyo"}`, false},
+		{`{"interval": "PT", "zone": "Asia/Tokyo"}`, false},
+		{`{"interval": "PT1H", "zone": "Asia/Nowhere"}`, false},
+		{`{"interval": "PT1H", "zone": "Local"}`, false},
+		{`{"interval": "PT1H", "zone": "../etc/passwd"}`, false},
+	}
+
+	for _, tt := range cases {
+		err := s.Validate([]byte(tt.doc))
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.doc, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: want an error", tt.doc)
+		}
+	}
+
+	loc, err := TimeZone("Asia/Tokyo").Location()
+	if err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("unexpected location %v, %v", loc, err)
+	}
+}
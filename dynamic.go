@@ -0,0 +1,305 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tenntenn/jsonschema/pointer"
+)
+
+// DynamicRefs generates a single definition of a generic type for its instantiations
+// by $dynamicRef and $dynamicAnchor of draft 2020-12, which keeps schemas of
+// generic recursive containers such as trees and linked nodes compact.
+// Because type parameters cannot be seen by reflection, fields of type parameters
+// are tagged by `jsonschema:"typeParam=T"` and elements of slices and values of maps
+// of type parameters such as []T and map[string]T are tagged by `jsonschema:"typeParam=[]T"`
+// and `jsonschema:"typeParam=map[string]T"`:
+//
+//	type Tree[T any] struct {
+//		Value    T          `json:"value" jsonschema:"typeParam=T"`
+//		Children []*Tree[T] `json:"children"`
+//	}
+//
+// The generic definition such as "#/$defs/Tree" has the relative $id "Tree"
+// and refers to its type parameters by {"$dynamicRef": "#T"},
+// and each instantiation such as "#/$defs/Tree_User" is
+// {"$id": "Tree_User", "$ref": "Tree", "$defs": {"T": {"$dynamicAnchor": "T", ...}}}.
+// Definitions are only generated with SharedTypes and the root type is not specialized.
+// ID is required to resolve $id of definitions and references from them.
+// Instantiations must not differ besides their type parameters
+// and type arguments must not refer to other instantiations of the same generic type,
+// because the outermost $dynamicAnchor wins in dynamic scopes of $dynamicRef.
+// Drafts besides 2020-12 and flavors keep a definition for each instantiation.
+func DynamicRefs() Option {
+	return configOption(func(c *config) {
+		c.dynamicRefs = true
+	})
+}
+
+// dynamicSite is an object of a type parameter in a definition of an instantiation.
+type dynamicSite struct {
+	param string
+	node  map[string]interface{}
+}
+
+// typeParamSite records the object of the field which is tagged by `jsonschema:"typeParam=T"`.
+func (g *gen) typeParamSite(o *obj, typeParam string) error {
+	if !g.cfg.dynamicRefs || g.hoisted == nil {
+		return nil
+	}
+
+	node, param := o.m, strings.TrimSpace(typeParam)
+	for {
+		var key string
+		switch {
+		case strings.HasPrefix(param, "*"):
+			param = param[len("*"):]
+			continue
+		case strings.HasPrefix(param, "[]"):
+			param, key = param[len("[]"):], "items"
+		case strings.HasPrefix(param, "map[string]"):
+			param, key = param[len("map[string]"):], "additionalProperties"
+		}
+		if key == "" {
+			break
+		}
+		elem, ok := node[key].(map[string]interface{})
+		if !ok {
+			return newError(ErrTagSyntax, o.Ref(), fmt.Errorf("typeParam %q does not match the type of the field", typeParam))
+		}
+		node = elem
+	}
+	if !isTypeParamName(param) {
+		return newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid typeParam %q", typeParam))
+	}
+
+	if g.dynamicSites == nil {
+		g.dynamicSites = map[string][]dynamicSite{}
+	}
+	ref := g.hoisted.Ref()
+	g.dynamicSites[ref] = append(g.dynamicSites[ref], dynamicSite{param: param, node: node})
+	return nil
+}
+
+func isTypeParamName(s string) bool {
+	for i, r := range s {
+		if r != '_' && !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || i > 0 && '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// instantiation is a definition of an instantiation of a generic type which is specialized by DynamicRefs.
+type instantiation struct {
+	name   string
+	base   string
+	typ    reflect.Type
+	sites  []dynamicSite
+	params map[string]interface{}
+}
+
+// specializeGenerics replaces definitions of instantiations which have type parameters
+// with specializations of their generic definitions before defs are put into the root.
+func (g *gen) specializeGenerics(root map[string]interface{}) error {
+	if !g.cfg.dynamicRefs || len(g.dynamicSites) == 0 ||
+		g.cfg.draft != DraftUnspecified && g.cfg.draft != Draft202012 || g.cfg.flavor != JSONSchemaFlavor {
+		return nil
+	}
+	refs := g.cfg.refs()
+
+	var insts []*instantiation
+	byName := map[string]*instantiation{}
+	for _, name := range sortedKeys(g.defs) {
+		t := g.defTypes[name]
+		sites := g.dynamicSites[refs.Join(refs.Root(), "$defs", name)]
+		if t == nil || !isGenericType(t) || len(sites) == 0 {
+			continue
+		}
+		inst := &instantiation{
+			name:  name,
+			base:  t.Name()[:strings.IndexByte(t.Name(), '[')],
+			typ:   t,
+			sites: sites,
+		}
+		insts = append(insts, inst)
+		byName[name] = inst
+	}
+	if len(insts) == 0 {
+		return nil
+	}
+	if g.cfg.id == "" {
+		return newError(ErrRefInvalid, RefRoot, fmt.Errorf("DynamicRefs needs ID to identify definitions of generic types"))
+	}
+	id := g.cfg.id
+	if i := strings.IndexByte(id, '#'); i >= 0 {
+		id = id[:i]
+	}
+
+	// type arguments are checked before they are moved
+	for _, inst := range insts {
+		for _, s := range inst.sites {
+			if other := g.refersToGeneric(s.node, inst.base, byName); other != "" {
+				return newError(ErrUnsupportedType, RefRoot+"$defs/"+escapePointer(inst.name),
+					fmt.Errorf("type argument of %s refers to %s of the same generic type", inst.typ, other))
+			}
+		}
+	}
+
+	for _, inst := range insts {
+		inst.params = map[string]interface{}{}
+		for _, s := range inst.sites {
+			p := map[string]interface{}{"$dynamicAnchor": s.param}
+			for k, v := range s.node {
+				if k != "propertyOrder" {
+					p[k] = v
+					delete(s.node, k)
+				}
+			}
+			s.node["$dynamicRef"] = "#" + s.param
+			if prev, ok := inst.params[s.param]; ok && !reflect.DeepEqual(prev, p) {
+				return newError(ErrUnsupportedType, RefRoot+"$defs/"+escapePointer(inst.name),
+					fmt.Errorf("type parameter %s of %s has different schemas", s.param, inst.typ))
+			}
+			inst.params[s.param] = p
+		}
+	}
+
+	// local references are resolved against $id of definitions,
+	// so they refer to the root by the ID or to specializations by their $id
+	rewrite := func(doc interface{}, self string, inResource bool) {
+		rewriteRefs(doc, func(ref string) string {
+			if !strings.HasPrefix(ref, "#") {
+				return ref
+			}
+			if !strings.HasPrefix(ref, "#/$defs/") {
+				if inResource {
+					return id + ref
+				}
+				return ref
+			}
+			name, rest := ref[len("#/$defs/"):], ""
+			if i := strings.IndexByte(name, '/'); i >= 0 {
+				name, rest = name[:i], name[i:]
+			}
+			name = pointer.Unescape(name)
+			switch _, ok := byName[name]; {
+			case self != "" && name == self:
+				return "#" + rest
+			case ok:
+				if rest != "" {
+					return url.PathEscape(name) + "#" + rest
+				}
+				return url.PathEscape(name)
+			case inResource:
+				return id + ref
+			}
+			return ref
+		})
+	}
+
+	generics := map[string]*instantiation{}
+	for _, inst := range insts {
+		def := g.defs[inst.name].(map[string]interface{})
+		title, hasTitle := def["title"]
+		rewrite(def, inst.name, true)
+		for _, p := range inst.params {
+			rewrite(p, "", true)
+		}
+		generic := inst.generic(def)
+
+		first, ok := generics[inst.base]
+		switch {
+		case !ok:
+			if _, ok := g.defs[inst.base]; ok {
+				return newError(ErrNameCollision, RefRoot+"$defs/"+escapePointer(inst.base),
+					fmt.Errorf("generic definition of %s has the same name as another definition", inst.typ))
+			}
+			generics[inst.base] = inst
+			g.defs[inst.base] = generic
+		case first.typ.PkgPath() != inst.typ.PkgPath():
+			return newError(ErrNameCollision, RefRoot+"$defs/"+escapePointer(inst.base),
+				fmt.Errorf("generic types of %s and %s have the same name %q", first.typ, inst.typ, inst.base))
+		case !reflect.DeepEqual(g.defs[inst.base], generic):
+			return newError(ErrUnsupportedType, RefRoot+"$defs/"+escapePointer(inst.name),
+				fmt.Errorf("%s and %s differ besides type parameters which are tagged by typeParam", first.typ, inst.typ))
+		}
+
+		specialized := map[string]interface{}{
+			"$id":  url.PathEscape(inst.name),
+			"$ref": url.PathEscape(inst.base),
+		}
+		if hasTitle {
+			specialized["title"] = title
+		}
+		if len(inst.params) != 0 {
+			specialized["$defs"] = inst.params
+		}
+		g.defs[inst.name] = specialized
+	}
+
+	for _, name := range sortedKeys(g.defs) {
+		if _, ok := byName[name]; !ok && generics[name] == nil {
+			rewrite(g.defs[name], "", false)
+		}
+	}
+	rewrite(root, "", false)
+
+	return nil
+}
+
+// generic returns the generic definition whose body is the definition of the instantiation
+// and whose type parameters are anchors which accept any values unless they are specialized.
+func (inst *instantiation) generic(def map[string]interface{}) map[string]interface{} {
+	generic := copyValue(def).(map[string]interface{})
+	generic["$id"] = url.PathEscape(inst.base)
+	if _, ok := generic["title"]; ok {
+		generic["title"] = inst.base
+	}
+	anchors := make(map[string]interface{}, len(inst.params))
+	for param := range inst.params {
+		anchors[param] = map[string]interface{}{"$dynamicAnchor": param}
+	}
+	generic["$defs"] = anchors
+	return generic
+}
+
+// refersToGeneric returns the name of an instantiation of the generic type of the base
+// which the schema refers directly or indirectly.
+func (g *gen) refersToGeneric(s interface{}, base string, insts map[string]*instantiation) string {
+	var refs []string
+	rewriteRefs(s, func(ref string) string {
+		refs = append(refs, ref)
+		return ref
+	})
+	sort.Strings(refs)
+
+	visited := map[string]bool{}
+	for len(refs) != 0 {
+		ref := refs[0]
+		refs = refs[1:]
+		if !strings.HasPrefix(ref, "#/$defs/") {
+			continue
+		}
+		name := ref[len("#/$defs/"):]
+		if i := strings.IndexByte(name, '/'); i >= 0 {
+			name = name[:i]
+		}
+		name = pointer.Unescape(name)
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+		if inst, ok := insts[name]; ok && inst.base == base {
+			return name
+		}
+		rewriteRefs(g.defs[name], func(ref string) string {
+			refs = append(refs, ref)
+			return ref
+		})
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+//go:build go1.18
+
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type dynamicTree[T any] struct {
+	Value    T                 `json:"value" jsonschema:"typeParam=T"`
+	Children []*dynamicTree[T] `json:"children"`
+}
+
+type dynamicList[T any] struct {
+	Values []T             `json:"values" jsonschema:"typeParam=[]T"`
+	Next   *dynamicList[T] `json:"next"`
+}
+
+type dynamicMismatch[T any] struct {
+	Value T                   `json:"value" jsonschema:"typeParam=[]T"`
+	Next  *dynamicMismatch[T] `json:"next"`
+}
+
+func TestDynamicRefs(t *testing.T) {
+	opts := []Option{
+		DynamicRefs(),
+		ID("https://example.com/tree.json"),
+		SharedTypes(SharedTypesRecursive),
+		MapKeywords(KeywordRenames{"propertyOrder": ""}),
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "tree",
+			v: struct {
+				Numbers dynamicTree[int]         `json:"numbers"`
+				Users   dynamicTree[genericUser] `json:"users"`
+			}{},
+			expect: `{
+				"$id": "https://example.com/tree.json",
+				"type": "object",
+				"required": ["numbers", "users"],
+				"properties": {
+					"numbers": {"$ref": "dynamicTree_int"},
+					"users": {"$ref": "dynamicTree_genericUser"}
+				},
+				"$defs": {
+					"dynamicTree": {
+						"$id": "dynamicTree",
+						"title": "dynamicTree",
+						"type": "object",
+						"required": ["value", "children"],
+						"properties": {
+							"value": {"$dynamicRef": "#T"},
+							"children": {"type": "array", "items": {"$ref": "#"}}
+						},
+						"$defs": {"T": {"$dynamicAnchor": "T"}}
+					},
+					"dynamicTree_int": {
+						"$id": "dynamicTree_int",
+						"title": "dynamicTree[int]",
+						"$ref": "dynamicTree",
+						"$defs": {"T": {"$dynamicAnchor": "T", "type": "number"}}
+					},
+					"dynamicTree_genericUser": {
+						"$id": "dynamicTree_genericUser",
+						"title": "dynamicTree[genericUser]",
+						"$ref": "dynamicTree",
+						"$defs": {
+							"T": {
+								"$dynamicAnchor": "T",
+								"title": "genericUser",
+								"type": "object",
+								"required": ["name"],
+								"properties": {"name": {"type": "string"}}
+							}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "elements of slices",
+			v: struct {
+				Strings dynamicList[string] `json:"strings"`
+			}{},
+			expect: `{
+				"$id": "https://example.com/tree.json",
+				"type": "object",
+				"required": ["strings"],
+				"properties": {
+					"strings": {"$ref": "dynamicList_string"}
+				},
+				"$defs": {
+					"dynamicList": {
+						"$id": "dynamicList",
+						"title": "dynamicList",
+						"type": "object",
+						"required": ["values"],
+						"properties": {
+							"values": {"type": "array", "items": {"$dynamicRef": "#T"}},
+							"next": {"$ref": "#"}
+						},
+						"$defs": {"T": {"$dynamicAnchor": "T"}}
+					},
+					"dynamicList_string": {
+						"$id": "dynamicList_string",
+						"title": "dynamicList[string]",
+						"$ref": "dynamicList",
+						"$defs": {"T": {"$dynamicAnchor": "T", "type": "string"}}
+					}
+				}
+			}`,
+		},
+		{
+			name: "draft-07",
+			v: struct {
+				Numbers dynamicTree[int] `json:"numbers"`
+			}{},
+			opts: []Option{Draft(Draft07)},
+			expect: `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"$id": "https://example.com/tree.json",
+				"type": "object",
+				"required": ["numbers"],
+				"properties": {
+					"numbers": {"$ref": "#/definitions/dynamicTree_int"}
+				},
+				"definitions": {
+					"dynamicTree_int": {
+						"title": "dynamicTree[int]",
+						"type": "object",
+						"required": ["value", "children"],
+						"properties": {
+							"value": {"type": "number"},
+							"children": {"type": "array", "items": {"$ref": "#/definitions/dynamicTree_int"}}
+						}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, append(opts, tt.opts...)...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestDynamicRefs_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+		opts []Option
+		kind error
+	}{
+		{
+			name: "no ID",
+			v: struct {
+				Numbers dynamicTree[int] `json:"numbers"`
+			}{},
+			kind: ErrRefInvalid,
+		},
+		{
+			name: "nested instantiations",
+			v: struct {
+				Trees dynamicTree[dynamicTree[int]] `json:"trees"`
+			}{},
+			opts: []Option{ID("https://example.com/tree.json")},
+			kind: ErrUnsupportedType,
+		},
+		{
+			name: "mismatched typeParam",
+			v: struct {
+				Numbers dynamicMismatch[int] `json:"numbers"`
+			}{},
+			opts: []Option{ID("https://example.com/tree.json")},
+			kind: ErrTagSyntax,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{DynamicRefs(), SharedTypes(SharedTypesRecursive)}, tt.opts...)
+			_, err := GenerateString(tt.v, opts...)
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// schemaCatalogSchema is $schema of catalogs of SchemaStore.
+const schemaCatalogSchema = "https://json.schemastore.org/schema-catalog.json"
+
+// EditorSchema associates a generated schema with files which editors validate and complete by it.
+type EditorSchema struct {
+	// Name and Description describe the schema in catalogs of SchemaStore.
+	Name        string
+	Description string
+	// FileMatch are glob patterns of file names such as ".myapp.yaml" and "**/myapp/*.json".
+	FileMatch []string
+	// URL is a location of the schema such as "./schemas/myapp.json" or a https URL.
+	URL string
+	// Schema is the generated schema which is embedded into settings if URL is empty.
+	// YAML files cannot use embedded schemas.
+	Schema []byte
+}
+
+// isYAMLPattern reports whether the pattern matches YAML files.
+func isYAMLPattern(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// WriteVSCodeSettings writes settings of Visual Studio Code which associate schemas with files
+// such as {"json.schemas": [...], "yaml.schemas": {...}} into w.
+// Patterns of YAML files are put into yaml.schemas of the YAML extension
+// and others are put into json.schemas, so the settings can be merged into .vscode/settings.json.
+func WriteVSCodeSettings(w io.Writer, schemas ...EditorSchema) error {
+	jsonSchemas := []interface{}{}
+	yamlSchemas := map[string]interface{}{}
+	for _, s := range schemas {
+		var jsonMatch, yamlMatch []string
+		for _, p := range s.FileMatch {
+			if isYAMLPattern(p) {
+				yamlMatch = append(yamlMatch, p)
+			} else {
+				jsonMatch = append(jsonMatch, p)
+			}
+		}
+
+		if len(yamlMatch) != 0 {
+			if s.URL == "" {
+				return fmt.Errorf("jsonschema: schema for %s must have a URL", strings.Join(yamlMatch, ", "))
+			}
+			cur, _ := yamlSchemas[s.URL].([]string)
+			yamlSchemas[s.URL] = append(cur, yamlMatch...)
+		}
+
+		if len(jsonMatch) != 0 {
+			entry := map[string]interface{}{"fileMatch": jsonMatch}
+			if s.URL != "" {
+				entry["url"] = s.URL
+			} else {
+				var schema interface{}
+				if err := json.Unmarshal(s.Schema, &schema); err != nil {
+					return fmt.Errorf("jsonschema: cannot decode schema for %s: %w", strings.Join(jsonMatch, ", "), err)
+				}
+				entry["schema"] = schema
+			}
+			jsonSchemas = append(jsonSchemas, entry)
+		}
+	}
+
+	settings := map[string]interface{}{}
+	if len(jsonSchemas) != 0 {
+		settings["json.schemas"] = jsonSchemas
+	}
+	if len(yamlSchemas) != 0 {
+		settings["yaml.schemas"] = yamlSchemas
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(settings)
+}
+
+// WriteSchemaCatalog writes a catalog of SchemaStore of the schemas into w,
+// which is the format of submissions to SchemaStore and of catalogs of editors.
+// Each schema must have a name and a URL.
+func WriteSchemaCatalog(w io.Writer, schemas ...EditorSchema) error {
+	entries := make([]interface{}, len(schemas))
+	for i, s := range schemas {
+		if s.Name == "" || s.URL == "" {
+			return fmt.Errorf("jsonschema: schema of catalogs must have a name and a URL: %q", s.Name)
+		}
+		entry := map[string]interface{}{
+			"name": s.Name,
+			"url":  s.URL,
+		}
+		if s.Description != "" {
+			entry["description"] = s.Description
+		}
+		if len(s.FileMatch) != 0 {
+			entry["fileMatch"] = s.FileMatch
+		}
+		entries[i] = entry
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{
+		"$schema": schemaCatalogSchema,
+		"version": 1,
+		"schemas": entries,
+	})
+}
@@ -0,0 +1,76 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestWriteVSCodeSettings(t *testing.T) {
+	type Config struct {
+		Port int `json:"port"`
+	}
+	schema, err := GenerateBytes(Config{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteVSCodeSettings(&buf,
+		EditorSchema{FileMatch: []string{".myapp.yaml", ".myapp.json", "**/myapp/*.yml"}, URL: "./schemas/myapp.json"},
+		EditorSchema{FileMatch: []string{"other.json"}, Schema: schema},
+		EditorSchema{FileMatch: []string{"another.yaml"}, URL: "./schemas/myapp.json"},
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"json.schemas": [
+			{"fileMatch": [".myapp.json"], "url": "./schemas/myapp.json"},
+			{"fileMatch": ["other.json"], "schema": ` + string(schema) + `}
+		],
+		"yaml.schemas": {
+			"./schemas/myapp.json": [".myapp.yaml", "**/myapp/*.yml", "another.yaml"]
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("settings do not match to expected one: %v", diff)
+	}
+
+	if err := WriteVSCodeSettings(&buf, EditorSchema{FileMatch: []string{"a.yaml"}, Schema: schema}); err == nil {
+		t.Error("expected error does not occur for YAML files without URL")
+	}
+}
+
+func TestWriteSchemaCatalog(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteSchemaCatalog(&buf, EditorSchema{
+		Name:        "myapp",
+		Description: "Config of myapp",
+		FileMatch:   []string{".myapp.yaml"},
+		URL:         "https://example.com/myapp.json",
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"$schema": "https://json.schemastore.org/schema-catalog.json",
+		"version": 1,
+		"schemas": [{
+			"name": "myapp",
+			"description": "Config of myapp",
+			"fileMatch": [".myapp.yaml"],
+			"url": "https://example.com/myapp.json"
+		}]
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("catalog does not match to expected one: %v", diff)
+	}
+
+	if err := WriteSchemaCatalog(&buf, EditorSchema{Name: "myapp"}); err == nil {
+		t.Error("expected error does not occur for a schema without URL")
+	}
+}
@@ -0,0 +1,181 @@
+package jsonschema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// Enumer is implemented by types which have a fixed set of values.
+// Objects of the type have enum, so items of a slice of the type also have it.
+// A map whose key type implements Enumer has propertyNames with the enum
+// so that only valid keys pass validation.
+type Enumer interface {
+	JSONSchemaEnum() []interface{}
+}
+
+var (
+	enumerType        = reflect.TypeOf((*Enumer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// enumOf returns values of the Enumer which is implemented by t or *t.
+func enumOf(t reflect.Type) ([]interface{}, bool) {
+	switch {
+	case t.Implements(enumerType):
+		return reflect.Zero(t).Interface().(Enumer).JSONSchemaEnum(), true
+	case reflect.PtrTo(t).Implements(enumerType):
+		return reflect.New(t).Interface().(Enumer).JSONSchemaEnum(), true
+	}
+	return nil, false
+}
+
+// isTextMarshaler reports whether t can be a key of a map in JSON by encoding.TextMarshaler.
+func isTextMarshaler(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+// keyName returns a name of a map key as same as encoding/json.
+func keyName(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+
+	if tm, ok := v.(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	return fmt.Sprint(v), nil
+}
+
+// propertyNamesEnum returns names of keys when the key type of a map implements Enumer.
+func propertyNamesEnum(kt reflect.Type) ([]interface{}, error) {
+	values, ok := enumOf(kt)
+	if !ok {
+		return nil, nil
+	}
+
+	names := make([]interface{}, len(values))
+	for i, v := range values {
+		name, err := keyName(v)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = name
+	}
+
+	return names, nil
+}
+
+// ItemsEnum creates an Option which restricts items of arrays to the values,
+// i.e. an array whose items are drawn from a fixed set.
+// It is ignored by objects which are not arrays, so it is usually used with ByReference.
+// If unique is true, each value can appear once by uniqueItems.
+func ItemsEnum(unique bool, values ...interface{}) Option {
+	return func(o Object) (Object, error) {
+		if typ, _ := o.Get("type"); typ != "array" {
+			return o, nil
+		}
+
+		items, _ := o.Get("items")
+		m, ok := items.(map[string]interface{})
+		if !ok {
+			m = map[string]interface{}{}
+		}
+		m["enum"] = copyValue(values)
+		o.Set("items", m)
+
+		if unique {
+			o.Set("uniqueItems", true)
+		}
+
+		return o, nil
+	}
+}
+
+// Keys of enum metadata for code generators.
+const (
+	// EnumVarNamesKey is a key of names of enum values.
+	EnumVarNamesKey = "x-enum-varnames"
+	// EnumDescriptionsKey is a key of descriptions of enum values.
+	EnumDescriptionsKey = "x-enum-descriptions"
+)
+
+// EnumValue is a value of an enum with its name and description.
+type EnumValue struct {
+	Value interface{}
+	// Name is a name of the constant such as "StatusActive".
+	Name string
+	// Description describes the value.
+	Description string
+}
+
+// EnumDescriber is implemented by types whose enum values have names and descriptions.
+// Objects of the type have enum with x-enum-varnames and x-enum-descriptions,
+// which are paired with the enum by their indexes as many OpenAPI code generators expect:
+//
+//	"enum": ["active", "deleted"],
+//	"x-enum-varnames": ["StatusActive", "StatusDeleted"],
+//	"x-enum-descriptions": ["in use", "removed by the owner"]
+//
+// Empty names or descriptions of all values are omitted.
+type EnumDescriber interface {
+	JSONSchemaEnumValues() []EnumValue
+}
+
+var enumDescriberType = reflect.TypeOf((*EnumDescriber)(nil)).Elem()
+
+// RegisterEnum registers enum values of the type of v
+// for types which cannot implement EnumDescriber such as types of other packages.
+// Registered values have priority over EnumDescriber and Enumer.
+func RegisterEnum(v interface{}, values ...EnumValue) Option {
+	t := reflect.TypeOf(v)
+	return configOption(func(c *config) {
+		if c.enums == nil {
+			c.enums = map[reflect.Type][]EnumValue{}
+		}
+		c.enums[t] = values
+	})
+}
+
+// enumValuesOf returns registered enum values of t or values of the EnumDescriber
+// which is implemented by t or *t.
+func (c *config) enumValuesOf(t reflect.Type) ([]EnumValue, bool) {
+	if values, ok := c.enums[t]; ok {
+		return values, true
+	}
+
+	switch {
+	case t.Implements(enumDescriberType):
+		return reflect.Zero(t).Interface().(EnumDescriber).JSONSchemaEnumValues(), true
+	case reflect.PtrTo(t).Implements(enumDescriberType):
+		return reflect.New(t).Interface().(EnumDescriber).JSONSchemaEnumValues(), true
+	}
+	return nil, false
+}
+
+// setEnumValues sets enum and its metadata to the object.
+func setEnumValues(o Object, values []EnumValue) {
+	enum := make([]interface{}, len(values))
+	names := make([]interface{}, len(values))
+	descs := make([]interface{}, len(values))
+	var hasName, hasDesc bool
+	for i, v := range values {
+		enum[i], names[i], descs[i] = v.Value, v.Name, v.Description
+		hasName = hasName || v.Name != ""
+		hasDesc = hasDesc || v.Description != ""
+	}
+
+	o.Set("enum", enum)
+	if hasName {
+		o.Set(EnumVarNamesKey, names)
+	}
+	if hasDesc {
+		o.Set(EnumDescriptionsKey, descs)
+	}
+}
@@ -0,0 +1,244 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// annotations are keywords which do not affect validation.
+var annotations = map[string]bool{
+	"$schema":       true,
+	"$id":           true,
+	"$comment":      true,
+	"$defs":         true,
+	"definitions":   true,
+	"title":         true,
+	"description":   true,
+	"default":       true,
+	"examples":      true,
+	"readOnly":      true,
+	"writeOnly":     true,
+	"deprecated":    true,
+	"propertyOrder": true,
+}
+
+// Equivalent reports whether the schemas are semantically equal.
+// It ignores order of keys, names of definitions which are referred by local $ref
+// and annotation keywords such as title, description and extensions whose keys begin with "x-".
+// Orders of required, type, enum and subschemas of allOf, anyOf and oneOf are also ignored.
+func Equivalent(a, b *Schema) bool {
+	e := &equivalence{a: a.doc, b: b.doc, assumed: map[[2]string]bool{}}
+	return e.schema(a.doc, b.doc)
+}
+
+// equivalence compares subschemas of the root schemas a and b.
+type equivalence struct {
+	a, b interface{}
+	// assumed are pairs of referred locations which are assumed to be equivalent
+	// to compare recursive schemas.
+	assumed map[[2]string]bool
+}
+
+func (e *equivalence) schema(x, y interface{}) bool {
+	x, xref := resolveRef(e.a, x)
+	y, yref := resolveRef(e.b, y)
+	// only pairs of references can be recursive
+	if xref != "" && yref != "" {
+		key := [2]string{xref, yref}
+		if e.assumed[key] {
+			return true
+		}
+		e.assumed[key] = true
+		if !e.compare(x, y) {
+			// the assumption is wrong, so it must not be used by other comparisons
+			delete(e.assumed, key)
+			return false
+		}
+		return true
+	}
+
+	return e.compare(x, y)
+}
+
+// compare compares keywords of the schemas.
+func (e *equivalence) compare(x, y interface{}) bool {
+	xm, xok := validationKeywords(x)
+	ym, yok := validationKeywords(y)
+	if !xok || !yok {
+		return xok == yok && equalJSON(x, y)
+	}
+
+	if len(xm) != len(ym) {
+		return false
+	}
+	for k, xv := range xm {
+		yv, ok := ym[k]
+		if !ok || !e.keyword(k, xv, yv) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *equivalence) keyword(k string, x, y interface{}) bool {
+	switch k {
+	case "properties", "patternProperties", "dependencies":
+		xm, xok := x.(map[string]interface{})
+		ym, yok := y.(map[string]interface{})
+		if !xok || !yok || len(xm) != len(ym) {
+			return false
+		}
+		for name, xs := range xm {
+			ys, ok := ym[name]
+			if !ok {
+				return false
+			}
+			// dependencies can be arrays of property names
+			if xa, ok := xs.([]interface{}); ok && k == "dependencies" {
+				if !equalSet(xa, ys) {
+					return false
+				}
+				continue
+			}
+			if !e.schema(xs, ys) {
+				return false
+			}
+		}
+		return true
+	case "items", "additionalItems", "additionalProperties", "propertyNames", "contains", "not", "if", "then", "else":
+		xa, xok := x.([]interface{})
+		ya, yok := y.([]interface{})
+		if xok || yok {
+			return xok && yok && e.schemas(xa, ya, false)
+		}
+		return e.schema(x, y)
+	case "allOf", "anyOf", "oneOf":
+		xa, xok := x.([]interface{})
+		ya, yok := y.([]interface{})
+		return xok && yok && e.schemas(xa, ya, true)
+	case "required", "type", "enum":
+		xa, xok := x.([]interface{})
+		if !xok {
+			return equalJSON(x, y)
+		}
+		return equalSet(xa, y)
+	}
+	return equalJSON(x, y)
+}
+
+// schemas compares lists of subschemas. If unordered is true, orders of them are ignored.
+func (e *equivalence) schemas(xs, ys []interface{}, unordered bool) bool {
+	if len(xs) != len(ys) {
+		return false
+	}
+
+	if !unordered {
+		for i := range xs {
+			if !e.schema(xs[i], ys[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	used := make([]bool, len(ys))
+	for _, x := range xs {
+		found := false
+		for j, y := range ys {
+			if !used[j] && e.schema(x, y) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveRef follows local references of the schema in the root
+// and returns the referred schema and the last reference.
+// The reference is empty if the schema does not refer to others.
+func resolveRef(root, s interface{}) (interface{}, string) {
+	var last string
+	for i := 0; i < 32; i++ {
+		m, ok := s.(map[string]interface{})
+		if !ok {
+			break
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			break
+		}
+		target := lookupPointer(root, strings.TrimPrefix(ref, "#"))
+		if target == nil {
+			break
+		}
+		s, last = target, ref
+	}
+	return s, last
+}
+
+// validationKeywords returns keywords of the schema without annotations.
+// A boolean schema true is same as an empty schema.
+func validationKeywords(s interface{}) (map[string]interface{}, bool) {
+	switch s := s.(type) {
+	case bool:
+		if s {
+			return map[string]interface{}{}, true
+		}
+		return nil, false
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(s))
+		for k, v := range s {
+			if !annotations[k] && !strings.HasPrefix(k, "x-") {
+				m[k] = v
+			}
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// equalSet reports whether the arrays have the same values regardless of their orders.
+func equalSet(xs []interface{}, y interface{}) bool {
+	ys, ok := y.([]interface{})
+	if !ok || len(xs) != len(ys) {
+		return false
+	}
+
+	counts := map[string]int{}
+	for _, x := range xs {
+		s, err := normalizeJSON(x)
+		if err != nil {
+			return false
+		}
+		counts[s]++
+	}
+	for _, y := range ys {
+		s, err := normalizeJSON(y)
+		if err != nil || counts[s] == 0 {
+			return false
+		}
+		counts[s]--
+	}
+	return true
+}
+
+// equalJSON reports whether the values are same as JSON.
+// Numbers are compared by their values such as 1 and 1.0.
+func equalJSON(x, y interface{}) bool {
+	xn, xok := x.(json.Number)
+	yn, yok := y.(json.Number)
+	if xok && yok {
+		xr, xok := new(big.Rat).SetString(string(xn))
+		yr, yok := new(big.Rat).SetString(string(yn))
+		return xok && yok && xr.Cmp(yr) == 0
+	}
+
+	xs, xerr := normalizeJSON(x)
+	ys, yerr := normalizeJSON(y)
+	return xerr == nil && yerr == nil && xs == ys
+}
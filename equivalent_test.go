@@ -0,0 +1,88 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestEquivalent(t *testing.T) {
+	cases := []struct {
+		name   string
+		a, b   string
+		expect bool
+	}{
+		{"same", `{"type": "string"}`, `{"type": "string"}`, true},
+		{"annotations", `{"type": "string", "title": "A", "x-order": 1}`, `{"description": "B", "type": "string"}`, true},
+		{"numbers", `{"minimum": 1}`, `{"minimum": 1.0}`, true},
+		{"unordered sets", `{"type": ["string", "null"], "required": ["a", "b"], "enum": [1, 2]}`, `{"type": ["null", "string"], "required": ["b", "a"], "enum": [2, 1]}`, true},
+		{"unordered subschemas", `{"anyOf": [{"type": "string"}, {"minimum": 0}]}`, `{"anyOf": [{"minimum": 0}, {"type": "string"}]}`, true},
+		{"ordered items", `{"items": [{"type": "string"}, {"minimum": 0}]}`, `{"items": [{"minimum": 0}, {"type": "string"}]}`, false},
+		{"defs names", `{"$defs": {"A": {"type": "string"}}, "properties": {"a": {"$ref": "#/$defs/A"}}}`, `{"definitions": {"B": {"type": "string"}}, "properties": {"a": {"$ref": "#/definitions/B"}}}`, true},
+		{"inlined", `{"$defs": {"A": {"type": "string"}}, "items": {"$ref": "#/$defs/A"}}`, `{"items": {"type": "string"}}`, true},
+		{"recursive", `{"$defs": {"N": {"properties": {"next": {"$ref": "#/$defs/N"}}}}, "$ref": "#/$defs/N"}`, `{"properties": {"next": {"$ref": "#"}}}`, true},
+		{"recursive different", `{"$defs": {"N": {"properties": {"next": {"$ref": "#/$defs/N"}}}}, "$ref": "#/$defs/N"}`, `{"properties": {"next": {"$ref": "#"}, "v": {}}}`, false},
+		{"boolean", `{"properties": {"a": true}}`, `{"properties": {"a": {}}}`, true},
+		{"different", `{"type": "string"}`, `{"type": "string", "minLength": 1}`, false},
+		{"different properties", `{"properties": {"a": {"type": "string"}}}`, `{"properties": {"b": {"type": "string"}}}`, false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := CompileBytes([]byte(tt.a))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			b, err := CompileBytes([]byte(tt.b))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := Equivalent(a, b); got != tt.expect {
+				t.Errorf("Equivalent(a, b) = %v, want %v", got, tt.expect)
+			}
+			if got := Equivalent(b, a); got != tt.expect {
+				t.Errorf("Equivalent(b, a) = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+// TestEquivalent_roundTrip checks that options which only restructure schemas keep their meanings.
+func TestEquivalent_roundTrip(t *testing.T) {
+	type T struct {
+		Name  string `json:"name"`
+		Inner struct {
+			Tags []string `json:"tags"`
+		} `json:"inner"`
+	}
+	v := T{}
+	v.Inner.Tags = []string{}
+
+	compile := func(opts ...Option) *Schema {
+		t.Helper()
+		b, err := GenerateBytes(v, opts...)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		s, err := CompileBytes(b)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		return s
+	}
+
+	base := compile()
+	cases := map[string]*Schema{
+		"hoisted":      compile(HoistAnonymousStructs()),
+		"titles":       compile(PropertyTitles()),
+		"integrity":    compile(Integrity()),
+		"alphabetical": compile(OrderRequired(RequiredAlphabetical)),
+	}
+	for name, s := range cases {
+		if !Equivalent(base, s) {
+			t.Errorf("%s: schemas are not equivalent", name)
+		}
+	}
+}
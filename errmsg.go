@@ -0,0 +1,105 @@
+package jsonschema
+
+import "strings"
+
+// ErrorMessageKey is the default key of the extension which holds custom error messages.
+const ErrorMessageKey = "x-errorMessage"
+
+// errMsgPrefix is a prefix of tag keys which give custom error messages of keywords.
+const errMsgPrefix = "errMsg:"
+
+// ErrorMessageExtension changes the key of the extension which holds custom error messages
+// from ErrorMessageKey.
+//
+// Custom error messages are given by tags such as
+// `jsonschema:"minLength=3,errMsg:minLength=Name is too short"`
+// and emitted as follows:
+//
+//	"x-errorMessage": {"minLength": "Name is too short"}
+//
+// Messages are language agnostic hints, so they can also be keys of translations.
+// Schema honors them when it reports validation errors.
+func ErrorMessageExtension(key string) Option {
+	return configOption(func(c *config) {
+		c.errMsgKey = key
+	})
+}
+
+// WithErrorMessageKey changes the key of the extension which holds custom error messages
+// from ErrorMessageKey. It should be same as the key given to ErrorMessageExtension.
+func WithErrorMessageKey(key string) CompileOption {
+	return func(c *compiler) {
+		c.errMsgKey = key
+	}
+}
+
+// errorMessages returns custom error messages of keywords which are given by errMsg tags.
+func (t schemaTag) errorMessages() map[string]interface{} {
+	var msgs map[string]interface{}
+	for k, v := range t {
+		if !strings.HasPrefix(k, errMsgPrefix) || k == errMsgPrefix {
+			continue
+		}
+		if msgs == nil {
+			msgs = map[string]interface{}{}
+		}
+		msgs[strings.TrimPrefix(k, errMsgPrefix)] = v
+	}
+	return msgs
+}
+
+// errorMessageOption creates an Option which adds custom error messages to the object.
+func errorMessageOption(key string, msgs map[string]interface{}) Option {
+	return func(o Object) (Object, error) {
+		merged := map[string]interface{}{}
+		if v, ok := o.Get(key); ok {
+			if m, ok := v.(map[string]interface{}); ok {
+				for k, msg := range m {
+					merged[k] = msg
+				}
+			}
+		}
+		for k, msg := range msgs {
+			merged[k] = msg
+		}
+		o.Set(key, merged)
+		return o, nil
+	}
+}
+
+// errorMessage returns a custom error message of the error from the subschema
+// which is applied to the field.
+// Messages of required are given by the subschema of the missing property.
+func (s *Schema) errorMessage(fe FieldError) (string, bool) {
+	keyword := fe.Keyword
+	if ex, ok := explanations[fe.Keyword]; ok {
+		keyword = ex.keyword
+	}
+
+	iloc := fe.Pointer
+	if keyword == "required" {
+		name, ok := fe.Details["property"].(string)
+		if !ok {
+			return "", false
+		}
+		iloc += "/" + escapePointer(name)
+	}
+
+	// messages beside $ref have priority over messages of the referred schema
+	cur := walkSchema(s.doc, iloc, &strings.Builder{})
+	for i := 0; cur != nil && i < 32; i++ {
+		if msgs, ok := cur[s.errMsgKey].(map[string]interface{}); ok {
+			if msg, ok := msgs[keyword].(string); ok {
+				return msg, true
+			}
+		}
+
+		ref, ok := cur["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			break
+		}
+		cur, _ = lookupPointer(s.doc, strings.TrimPrefix(ref, "#")).(map[string]interface{})
+	}
+
+	return "", false
+}
@@ -0,0 +1,97 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestErrorMessageExtension(t *testing.T) {
+	type Address struct {
+		Zip string `json:"zip" jsonschema:"pattern=^[0-9]{7}$,errMsg:pattern=address.zip.pattern"`
+	}
+	type T struct {
+		Name string   `json:"name" jsonschema:"minLength=3,errMsg:minLength=Name is too short,errMsg:required=Name is required"`
+		Home *Address `json:"home"`
+	}
+
+	cases := []struct {
+		name   string
+		key    string
+		doc    string
+		expect map[string]string
+	}{
+		{"valid", "", `{"name": "gopher", "home": {"zip": "1000001"}}`, nil},
+		{"minLength", "", `{"name": "go"}`, map[string]string{"name": "Name is too short"}},
+		{"required", "", `{"home": {"zip": "1000001"}}`, map[string]string{"(root)": "Name is required"}},
+		{"nested", "", `{"name": "gopher", "home": {"zip": "a"}}`, map[string]string{"home.zip": "address.zip.pattern"}},
+		{"custom key", "x-message", `{"name": "go"}`, map[string]string{"name": "Name is too short"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{CompatibleTags()}
+			var copts []CompileOption
+			if tt.key != "" {
+				opts = append(opts, ErrorMessageExtension(tt.key))
+				copts = append(copts, WithErrorMessageKey(tt.key))
+			}
+
+			schema, err := GenerateBytes(T{Home: &Address{}}, opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			s, err := CompileBytes(schema, copts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			err = s.Validate([]byte(tt.doc))
+			if tt.expect == nil {
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("want *ValidationError but got %v", err)
+			}
+			got := map[string]string{}
+			for _, e := range verr.Errors {
+				got[e.Field] = e.Message
+			}
+			for field, msg := range tt.expect {
+				if got[field] != msg {
+					t.Errorf("want %q for %s but got %q", msg, field, got[field])
+				}
+			}
+		})
+	}
+}
+
+func TestErrorMessageExtension_generate(t *testing.T) {
+	type T struct {
+		Name string `json:"name" jsonschema:"errMsg:minLength=too short"`
+	}
+
+	got, err := GenerateString(T{}, ErrorMessageExtension("x-message"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "propertyOrder": 0, "x-message": {"minLength": "too short"}}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+)
+
+// Categories of errors.
+// Errors which are returned by the package can be matched with them by errors.Is.
+var (
+	// ErrUnsupportedType means that a Go type cannot be encoded in JSON Schema.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrCycle means that a value refers to itself.
+	ErrCycle = errors.New("cycle")
+	// ErrTagSyntax means that a struct tag, a directive or its value is malformed.
+	ErrTagSyntax = errors.New("invalid tag syntax")
+	// ErrNameCollision means that different things have the same name.
+	ErrNameCollision = errors.New("name collision")
+	// ErrRefInvalid means that a reference or a pattern of references is invalid.
+	ErrRefInvalid = errors.New("invalid reference")
+	// ErrBudgetExceeded means that a schema is larger than the limit which is given by MaxNodes.
+	ErrBudgetExceeded = errors.New("budget exceeded")
+	// ErrRemotePolicy means that loading of remote schemas violates a RemotePolicy.
+	ErrRemotePolicy = errors.New("remote policy violation")
+	// ErrInvalidOutput means that a generated schema is not valid against the meta-schema of its draft
+	// or rules of its flavor.
+	ErrInvalidOutput = errors.New("invalid output")
+)
+
+// Error is an error with a location where it occurs.
+// It matches its Kind by errors.Is and
+// the underlying error can be obtained by errors.As.
+type Error struct {
+	// Kind is a category of the error such as ErrUnsupportedType.
+	Kind error
+	// Ref is a reference of the object where the error occurs.
+	Ref string
+	// Field is a Go field such as "pkg.T.Name" where the error occurs.
+	Field string
+	// Fields are Go fields from the outermost one to Field
+	// such as ["pkg.T.Config", "pkg.Config.Callback"] if the error occurs in a nested struct.
+	Fields []string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("jsonschema: ")
+	field := e.FieldPath()
+	if e.Ref != "" {
+		b.WriteString(e.Ref)
+		if field != "" {
+			b.WriteString(" (" + field + ")")
+		}
+		b.WriteString(": ")
+	} else if field != "" {
+		b.WriteString(field + ": ")
+	}
+
+	if e.Err != nil {
+		b.WriteString(e.Err.Error())
+	} else {
+		b.WriteString(e.Kind.Error())
+	}
+	return b.String()
+}
+
+// FieldPath returns the chain of Fields as a selector such as "pkg.T.Config.Callback".
+// It returns Field if the error does not have Fields.
+func (e *Error) FieldPath() string {
+	if len(e.Fields) == 0 {
+		return e.Field
+	}
+	path := e.Fields[0]
+	for _, f := range e.Fields[1:] {
+		path += f[strings.LastIndex(f, "."):]
+	}
+	return path
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the category of the error.
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func newError(kind error, ref string, err error) *Error {
+	return &Error{Kind: kind, Ref: ref, Err: err}
+}
+
+// withRef sets the reference to the error if it does not have a reference yet.
+func withRef(err error, ref string) error {
+	var e *Error
+	if errors.As(err, &e) && e.Ref == "" {
+		e.Ref = ref
+	}
+	return err
+}
+
+// withField sets the field to the error if it does not have a field yet,
+// so that the innermost field is reported, and prepends it to the chain of fields.
+func withField(err error, field string) error {
+	var e *Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	switch {
+	case e.Field == "":
+		e.Field = field
+		e.Fields = []string{field}
+	case len(e.Fields) == 0:
+		e.Fields = []string{field, e.Field}
+	default:
+		e.Fields = append([]string{field}, e.Fields...)
+	}
+	return err
+}
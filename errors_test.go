@@ -0,0 +1,160 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestError(t *testing.T) {
+	type Unsupported struct {
+		Ch chan int `json:"ch"`
+	}
+	type Node struct {
+		Next *Node `json:"next"`
+	}
+	cycle := &Node{}
+	cycle.Next = cycle
+	type BadTag struct {
+		N int `json:"n" jsonschema:"minimum=x"`
+	}
+	type BadName struct {
+		N int `json:"N"`
+	}
+	type Collision struct {
+		A struct {
+			B struct {
+				C int `json:"c"`
+			} `json:"b"`
+		} `json:"a"`
+		// hoisted as Collision_A_B same as A.B
+		A_B struct {
+			D int `json:"d"`
+		} `json:"ab"`
+	}
+
+	type Config struct {
+		Callback func() `json:"callback"`
+	}
+	type Nested struct {
+		Configs []Config `json:"configs"`
+	}
+
+	cases := []struct {
+		name  string
+		v     interface{}
+		opts  []Option
+		kind  error
+		field string
+		path  string
+		msg   string
+	}{
+		{
+			name:  "unsupported type",
+			v:     Unsupported{Ch: make(chan int)},
+			kind:  ErrUnsupportedType,
+			field: "jsonschema_test.Unsupported.Ch",
+			msg:   "jsonschema: #/properties/ch (jsonschema_test.Unsupported.Ch): json: unsupported type: chan int",
+		},
+		{
+			name:  "nested",
+			v:     Nested{Configs: []Config{{Callback: func() {}}}},
+			kind:  ErrUnsupportedType,
+			field: "jsonschema_test.Config.Callback",
+			path:  "jsonschema_test.Nested.Configs.Callback",
+			msg:   "jsonschema: #/properties/configs/items/properties/callback (jsonschema_test.Nested.Configs.Callback): json: unsupported type: func()",
+		},
+		{
+			name:  "cycle",
+			v:     cycle,
+			kind:  ErrCycle,
+			field: "jsonschema_test.Node.Next",
+			msg:   "jsonschema: #/properties/next (jsonschema_test.Node.Next): *jsonschema_test.Node refers to itself",
+		},
+		{
+			name:  "tag syntax",
+			v:     BadTag{},
+			opts:  []Option{CompatibleTags()},
+			kind:  ErrTagSyntax,
+			field: "jsonschema_test.BadTag.N",
+		},
+		{
+			name:  "invalid name",
+			v:     BadName{},
+			opts:  []Option{StrictNames(regexp.MustCompile("^[a-z]+$"))},
+			kind:  ErrTagSyntax,
+			field: "jsonschema_test.BadName.N",
+			msg:   `jsonschema: #/ (jsonschema_test.BadName.N): invalid property name "N": not match ^[a-z]+$`,
+		},
+		{
+			name:  "name collision",
+			v:     Collision{},
+			opts:  []Option{HoistAnonymousStructs()},
+			kind:  ErrNameCollision,
+			field: "jsonschema_test.Collision.A_B",
+		},
+		{
+			name: "nil",
+			v:    nil,
+			kind: ErrUnsupportedType,
+			msg:  "jsonschema: #/: untyped nil cannot be encoded in JSON Schema",
+		},
+		{
+			name: "invalid reference",
+			v:    0,
+			opts: []Option{Overlay(strings.NewReader(`{"properties/name": {}}`))},
+			kind: ErrRefInvalid,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateBytes(tt.v, tt.opts...)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("want %v but got %v", tt.kind, err)
+			}
+
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("want *Error but got %T", err)
+			}
+			if e.Field != tt.field {
+				t.Errorf("want field %q but got %q", tt.field, e.Field)
+			}
+			path := tt.path
+			if path == "" {
+				path = tt.field
+			}
+			if e.FieldPath() != path {
+				t.Errorf("want field path %q but got %q", path, e.FieldPath())
+			}
+			if tt.msg != "" && err.Error() != tt.msg {
+				t.Errorf("want message %q but got %q", tt.msg, err.Error())
+			}
+		})
+	}
+
+	// underlying errors are kept
+	_, err := GenerateBytes(Unsupported{Ch: make(chan int)})
+	var ute *json.UnsupportedTypeError
+	if !errors.As(err, &ute) {
+		t.Errorf("want *json.UnsupportedTypeError but got %T", err)
+	}
+
+	if _, err := Plan(nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want ErrUnsupportedType by Plan but got %v", err)
+	}
+
+	r := NewRegistry()
+	if err := r.Register("node", "v1", Node{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := r.Register("node", "v1", Node{}); !errors.Is(err, ErrNameCollision) {
+		t.Errorf("want ErrNameCollision but got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// maxKeywordLength is the maximum length of strings which are mapped to keyword fields.
+// It is same as ignore_above of the default dynamic mapping of Elasticsearch.
+const maxKeywordLength = 256
+
+// esNumberTypes are types of Elasticsearch for kinds of Go numbers.
+var esNumberTypes = map[reflect.Kind]string{
+	reflect.Int:     "long",
+	reflect.Int8:    "byte",
+	reflect.Int16:   "short",
+	reflect.Int32:   "integer",
+	reflect.Int64:   "long",
+	reflect.Uint:    "unsigned_long",
+	reflect.Uint8:   "short",
+	reflect.Uint16:  "integer",
+	reflect.Uint32:  "long",
+	reflect.Uint64:  "unsigned_long",
+	reflect.Uintptr: "unsigned_long",
+	reflect.Float32: "float",
+	reflect.Float64: "double",
+}
+
+// GenerateESMapping generates an index mapping of Elasticsearch and OpenSearch
+// for documents of the struct v into w such as {"mappings": {"properties": {...}}}.
+// It can be a body of a request which creates an index.
+//
+// Each field is generated in the same way as Generate with struct tags and options,
+// then it is converted into a field mapping.
+// Strings which have enum, const, format or maxLength up to 256 are keyword fields
+// and other strings are text fields. Date-time and date formats such as time.Time are date fields
+// and byte slices are binary fields. Numbers are mapped by their Go types such as long and double.
+// Structs and groups are objects and slices of structs are nested fields.
+// Maps are flattened fields.
+//
+// An es tag overrides the type of a field such as `es:"keyword"` and `es:"-"` excludes the field.
+// Other fields which cannot be mapped such as interfaces and references of shared struct types
+// cause an error which matches ErrUnsupportedType.
+func GenerateESMapping(w io.Writer, v interface{}, opts ...Option) error {
+	rv, err := structValue(v, "document of Elasticsearch")
+	if err != nil {
+		return err
+	}
+
+	g := gen{cfg: newConfig(opts)}
+	root := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+
+	props, err := g.esProperties(root, rv, opts)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"mappings": map[string]interface{}{"properties": props},
+	})
+}
+
+// esProperties returns mappings of fields of the struct v.
+func (g *gen) esProperties(parent Object, v reflect.Value, options []Option) (map[string]interface{}, error) {
+	props := map[string]interface{}{}
+	groups := map[string]*obj{}
+	fields, err := g.fields(v)
+	if err != nil {
+		return nil, withRef(err, parent.Ref())
+	}
+	for i, f := range fields {
+		typ := f.rawTag.Get("es")
+		if typ == "-" {
+			continue
+		}
+
+		p, dst := parent, props
+		if f.group != "" {
+			if groups[f.group] == nil {
+				groups[f.group] = &obj{
+					m:   map[string]interface{}{},
+					ref: g.cfg.refs().Join(parent.Ref(), "properties", f.group),
+				}
+				props[f.group] = map[string]interface{}{"properties": groups[f.group].m}
+			}
+			p, dst = groups[f.group], groups[f.group].m
+		}
+
+		// types of fields are needed even if they are nil
+		if isNil(f.value) && f.value.Kind() != reflect.Interface {
+			f.value = empty(f.value.Type())
+		}
+
+		o, err := g.propertyGen(p, f, i, v.Type().Name(), options)
+		if err != nil {
+			return nil, err
+		}
+		m, err := g.esMapping(o, reflect.Indirect(f.value), typ, options)
+		if err != nil {
+			return nil, withField(err, f.goName)
+		}
+		dst[f.name] = m
+	}
+	return props, nil
+}
+
+// esMapping returns a mapping of the value v whose schema is s.
+// typ is a type which is given by the es tag.
+func (g *gen) esMapping(s Object, v reflect.Value, typ string, options []Option) (map[string]interface{}, error) {
+	// encoding/json encodes bytes as base64 strings
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && typ == "" {
+		return map[string]interface{}{"type": "binary"}, nil
+	}
+
+	// arrays are not distinguished from their elements except nested objects
+	if t, _ := s.Get("type"); t == "array" && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+		items, ok := s.Get("items")
+		if m, isMap := items.(map[string]interface{}); ok && isMap {
+			elem := reflect.Indirect(empty(v.Type().Elem()))
+			m, err := g.esMapping(&obj{m: m, ref: g.cfg.refs().Join(s.Ref(), "items")}, elem, typ, options)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := m["properties"]; ok && typ == "" {
+				m["type"] = "nested"
+			}
+			return m, nil
+		}
+	}
+
+	if typ != "" {
+		return map[string]interface{}{"type": typ}, nil
+	}
+
+	if ref, ok := s.Get("$ref"); ok {
+		return nil, &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("reference %v cannot be mapped to Elasticsearch", ref)}
+	}
+
+	t, _ := s.Get("type")
+	switch t {
+	case "string":
+		return map[string]interface{}{"type": esStringType(s)}, nil
+	case "integer", "number":
+		if et, ok := esNumberTypes[v.Kind()]; ok {
+			return map[string]interface{}{"type": et}, nil
+		}
+		if t == "integer" {
+			return map[string]interface{}{"type": "long"}, nil
+		}
+		return map[string]interface{}{"type": "double"}, nil
+	case "boolean":
+		return map[string]interface{}{"type": "boolean"}, nil
+	case "object":
+		if _, ok := s.Get("properties"); ok && v.Kind() == reflect.Struct {
+			props, err := g.esProperties(s, v, options)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"properties": props}, nil
+		}
+		return map[string]interface{}{"type": "flattened"}, nil
+	case nil:
+		return nil, &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("schema without type cannot be mapped to Elasticsearch")}
+	}
+	return nil, &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("type %v cannot be mapped to Elasticsearch", t)}
+}
+
+// esStringType returns a type of Elasticsearch for the string schema.
+func esStringType(s Object) string {
+	format, _ := s.Get("format")
+	switch format {
+	case "date-time", "date":
+		return "date"
+	}
+	if _, ok := s.Get("contentEncoding"); ok {
+		return "binary"
+	}
+
+	for _, k := range []string{"enum", "const", "format"} {
+		if _, ok := s.Get(k); ok {
+			return "keyword"
+		}
+	}
+
+	maxLength, _ := s.Get("maxLength")
+	var n float64
+	switch l := maxLength.(type) {
+	case int:
+		n = float64(l)
+	case float64:
+		n = l
+	case json.Number:
+		n, _ = l.Float64()
+	default:
+		return "text"
+	}
+	if n <= maxKeywordLength {
+		return "keyword"
+	}
+	return "text"
+}
@@ -0,0 +1,92 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateESMapping(t *testing.T) {
+	type Comment struct {
+		Body  string `json:"body"`
+		Stars int8   `json:"stars"`
+	}
+	type Doc struct {
+		ID       string            `json:"id" jsonschema:"format=uuid"`
+		Title    string            `json:"title"`
+		Code     string            `json:"code" jsonschema:"maxLength=16"`
+		Summary  string            `json:"summary" jsonschema:"maxLength=1024"`
+		Name     string            `json:"name" es:"keyword"`
+		Views    int64             `json:"views"`
+		Score    float32           `json:"score"`
+		Count    *uint64           `json:"count,omitempty"`
+		Public   bool              `json:"public"`
+		Created  time.Time         `json:"created"`
+		Born     string            `json:"born" jsonschema:"format=date"`
+		Data     []byte            `json:"data"`
+		Tags     []string          `json:"tags"`
+		Comments []Comment         `json:"comments"`
+		Author   *Comment          `json:"author"`
+		Labels   map[string]string `json:"labels"`
+		City     string            `json:"city" group:"address"`
+		Internal interface{}       `json:"internal" es:"-"`
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateESMapping(&buf, Doc{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{"mappings": {"properties": {
+		"id": {"type": "keyword"},
+		"title": {"type": "text"},
+		"code": {"type": "keyword"},
+		"summary": {"type": "text"},
+		"name": {"type": "keyword"},
+		"views": {"type": "long"},
+		"score": {"type": "float"},
+		"count": {"type": "unsigned_long"},
+		"public": {"type": "boolean"},
+		"created": {"type": "date"},
+		"born": {"type": "date"},
+		"data": {"type": "binary"},
+		"tags": {"type": "text"},
+		"comments": {"type": "nested", "properties": {"body": {"type": "text"}, "stars": {"type": "byte"}}},
+		"author": {"properties": {"body": {"type": "text"}, "stars": {"type": "byte"}}},
+		"labels": {"type": "flattened"},
+		"address": {"properties": {"city": {"type": "text"}}}
+	}}}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated mapping does not match to expected one: %v", diff)
+	}
+}
+
+func TestGenerateESMapping_unsupported(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+	}{
+		{"nil", nil},
+		{"not struct", 1},
+		{"interface", struct {
+			Any interface{} `json:"any"`
+		}{}},
+		{"ref", struct {
+			Meta tableMeta `json:"meta"`
+		}{}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateESMapping(&buf, tt.v, SharedTypes(SharedTypesRef))
+			if !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("want ErrUnsupportedType but got %v", err)
+			}
+		})
+	}
+}
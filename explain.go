@@ -0,0 +1,181 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tenntenn/jsonschema/pointer"
+)
+
+// Explanation is a human-readable report of why a document fails validation.
+type Explanation struct {
+	// Locations are locations of failures in the document sorted by their pointers.
+	Locations []ExplainedLocation
+}
+
+// ExplainedLocation is a location of failures in a document.
+type ExplainedLocation struct {
+	// Pointer is a JSON Pointer of the location such as "/items/0/name".
+	// The root is "".
+	Pointer string
+	// Problems are failures at the location.
+	Problems []Problem
+}
+
+// Problem is a failure of a document.
+type Problem struct {
+	// Message is a readable message for end users such as "must be at least 1 characters".
+	Message string
+	// Constraint quotes the constraint of the schema such as `"minLength": 1`.
+	// It is empty if the keyword is unknown.
+	Constraint string
+}
+
+// Valid reports whether the document is valid.
+func (e *Explanation) Valid() bool {
+	return len(e.Locations) == 0
+}
+
+// String returns the report such as:
+//
+//	/name:
+//	  - must be at least 1 characters ("minLength": 1)
+func (e *Explanation) String() string {
+	if e.Valid() {
+		return "the document is valid"
+	}
+
+	var b strings.Builder
+	for _, loc := range e.Locations {
+		p := loc.Pointer
+		if p == "" {
+			p = "(root)"
+		}
+		fmt.Fprintf(&b, "%s:\n", p)
+		for _, prob := range loc.Problems {
+			fmt.Fprintf(&b, "  - %s", prob.Message)
+			if prob.Constraint != "" {
+				fmt.Fprintf(&b, " (%s)", prob.Constraint)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// Explain validates the document against the schema and explains its failures.
+// Failures are grouped by their locations.
+func Explain(schema, doc []byte) (*Explanation, error) {
+	s, err := CompileBytes(schema)
+	if err != nil {
+		return nil, err
+	}
+	return s.Explain(doc)
+}
+
+// Explain validates the document and explains its failures.
+func (s *Schema) Explain(doc []byte) (*Explanation, error) {
+	var verr *ValidationError
+	if err := s.Validate(doc); err == nil {
+		return &Explanation{}, nil
+	} else if !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	locs := map[string]*ExplainedLocation{}
+	for _, fe := range verr.Errors {
+		ptr := fe.Pointer
+		prob := explain(fe)
+
+		// a missing or an additional property is a failure of the property
+		if p, ok := fe.Details["property"].(string); ok {
+			ptr += "/" + escapePointer(p)
+		}
+
+		loc, ok := locs[ptr]
+		if !ok {
+			loc = &ExplainedLocation{Pointer: ptr}
+			locs[ptr] = loc
+		}
+		loc.Problems = append(loc.Problems, prob)
+	}
+
+	e := &Explanation{
+		Locations: make([]ExplainedLocation, 0, len(locs)),
+	}
+	for _, loc := range locs {
+		e.Locations = append(e.Locations, *loc)
+	}
+	sort.Slice(e.Locations, func(i, j int) bool {
+		return e.Locations[i].Pointer < e.Locations[j].Pointer
+	})
+
+	return e, nil
+}
+
+// explanations are messages and keywords of kinds of failures.
+var explanations = map[string]struct {
+	keyword string
+	detail  string
+	message string
+}{
+	"required":                        {"required", "", "is required"},
+	"invalid_type":                    {"type", "expected", "must be %v"},
+	"const":                           {"const", "allowed", "must be %v"},
+	"enum":                            {"enum", "allowed", "must be one of %v"},
+	"number_gte":                      {"minimum", "min", "must be greater than or equal to %v"},
+	"number_gt":                       {"exclusiveMinimum", "min", "must be greater than %v"},
+	"number_lte":                      {"maximum", "max", "must be less than or equal to %v"},
+	"number_lt":                       {"exclusiveMaximum", "max", "must be less than %v"},
+	"multiple_of":                     {"multipleOf", "multiple", "must be a multiple of %v"},
+	"string_gte":                      {"minLength", "min", "must be at least %v characters"},
+	"string_lte":                      {"maxLength", "max", "must be at most %v characters"},
+	"pattern":                         {"pattern", "pattern", "must match the pattern %v"},
+	"format":                          {"format", "format", "must be a valid %v"},
+	"array_min_items":                 {"minItems", "min", "must have at least %v items"},
+	"array_max_items":                 {"maxItems", "max", "must have at most %v items"},
+	"unique":                          {"uniqueItems", "", "must not have duplicated items"},
+	"contains":                        {"contains", "", "must contain a matching item"},
+	"array_min_properties":            {"minProperties", "min", "must have at least %v properties"},
+	"array_max_properties":            {"maxProperties", "max", "must have at most %v properties"},
+	"additional_property_not_allowed": {"additionalProperties", "", "is not allowed"},
+}
+
+func explain(fe FieldError) Problem {
+	ex, ok := explanations[fe.Keyword]
+	if !ok {
+		return Problem{Message: fe.Message}
+	}
+
+	if ex.detail == "" {
+		var constraint string
+		switch ex.keyword {
+		case "required":
+			constraint = fmt.Sprintf("%q: [%q]", ex.keyword, fe.Details["property"])
+		case "uniqueItems":
+			constraint = fmt.Sprintf("%q: true", ex.keyword)
+		case "additionalProperties":
+			constraint = fmt.Sprintf("%q: false", ex.keyword)
+		}
+		return Problem{Message: ex.message, Constraint: constraint}
+	}
+
+	d := fe.Details[ex.detail]
+	constraint := fmt.Sprintf("%q: %v", ex.keyword, d)
+	switch ex.keyword {
+	case "enum":
+		constraint = fmt.Sprintf("%q: [%v]", ex.keyword, d)
+	case "pattern", "format", "type":
+		constraint = fmt.Sprintf("%q: %q", ex.keyword, d)
+	}
+	return Problem{
+		Message:    fmt.Sprintf(ex.message, d),
+		Constraint: constraint,
+	}
+}
+
+func escapePointer(s string) string {
+	return pointer.Escape(s)
+}
@@ -0,0 +1,71 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestExplain(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["id", "name"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"color": {"enum": ["red", "green"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 1}
+		}
+	}`)
+
+	cases := []struct {
+		name   string
+		doc    string
+		expect string
+	}{
+		{
+			name:   "valid",
+			doc:    `{"id": 1, "name": "ab"}`,
+			expect: "the document is valid",
+		},
+		{
+			name: "failures",
+			doc:  `{"id": 0, "name": "A", "color": "blue", "tags": ["a", 1], "extra": true}`,
+			expect: `/color:
+  - must be one of "red", "green" ("enum": ["red", "green"])
+/extra:
+  - is not allowed ("additionalProperties": false)
+/id:
+  - must be greater than or equal to 1 ("minimum": 1)
+/name:
+  - must be at least 2 characters ("minLength": 2)
+  - must match the pattern ^[a-z]+$ ("pattern": "^[a-z]+$")
+/tags:
+  - must have at most 1 items ("maxItems": 1)
+/tags/1:
+  - must be string ("type": "string")
+`,
+		},
+		{
+			name: "required",
+			doc:  `{"name": "ab"}`,
+			expect: `/id:
+  - is required ("required": ["id"])
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Explain(schema, []byte(tt.doc))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got := e.String(); got != tt.expect {
+				t.Errorf("want\n%s\nbut got\n%s", tt.expect, got)
+			}
+		})
+	}
+}
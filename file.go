@@ -0,0 +1,25 @@
+package jsonschema
+
+import (
+	"fmt"
+	"mime"
+)
+
+// FileRef creates an Option which generates a schema of a reference to a file
+// such as an URL of an uploaded image.
+// The schema is a string with format uri and contentMediaType of the media type.
+// If the media type is empty, contentMediaType is omitted.
+// A field can also be a reference by `jsonschema:"file=image/png"` tag.
+func FileRef(mediaType string) Option {
+	return func(o Object) (Object, error) {
+		if mediaType != "" {
+			if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+				return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid media type %q: %w", mediaType, err))
+			}
+			o.Set("contentMediaType", mediaType)
+		}
+		o.Set("type", "string")
+		o.Set("format", "uri")
+		return o, nil
+	}
+}
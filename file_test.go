@@ -0,0 +1,63 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestFileRef(t *testing.T) {
+	type Asset struct {
+		Icon   string `json:"icon" jsonschema:"file=image/png"`
+		Any    string `json:"any" jsonschema:"file"`
+		Avatar string `json:"avatar"`
+	}
+
+	cases := []struct {
+		name    string
+		v       interface{}
+		opts    []Option
+		expect  string
+		wantErr bool
+	}{
+		{
+			name: "tag and option",
+			v:    Asset{},
+			opts: []Option{ByReference("#/properties/avatar", FileRef("image/jpeg"))},
+			expect: `{
+				"type": "object",
+				"title": "Asset",
+				"required": ["icon", "any", "avatar"],
+				"properties": {
+					"icon": {"type": "string", "format": "uri", "contentMediaType": "image/png", "propertyOrder": 0},
+					"any": {"type": "string", "format": "uri", "propertyOrder": 1},
+					"avatar": {"type": "string", "format": "uri", "contentMediaType": "image/jpeg", "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name:    "invalid media type",
+			v:       "",
+			opts:    []Option{FileRef("image/")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, tt.opts...)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case tt.wantErr:
+				return
+			case err != nil:
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
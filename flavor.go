@@ -0,0 +1,220 @@
+package jsonschema
+
+import (
+	"sort"
+	"strings"
+)
+
+// SchemaFlavor is a flavor of generated schemas, which is a dialect of other specifications
+// whose schema objects differ from JSON Schema.
+type SchemaFlavor int
+
+const (
+	// JSONSchemaFlavor generates JSON Schema of the draft which is given by Draft. It is the default.
+	JSONSchemaFlavor SchemaFlavor = iota
+	// OpenAPI3 generates schema objects of OpenAPI 3.0, which are an extended subset of draft-04.
+	// Type arrays with null become nullable,
+	// defs are put into components.schemas and referred by "#/components/schemas/...",
+	// and $schema and $id are not emitted.
+	// const becomes enum of one value, examples becomes example,
+	// and keywords which OpenAPI 3.0 does not define are removed except extensions of "x-".
+	OpenAPI3
+	// KubernetesCRD generates structural schemas of openAPIV3Schema of CustomResourceDefinitions of Kubernetes,
+	// which are schema objects of OpenAPI3 without references:
+	// defs are inlined and recursive types cause an error which matches ErrCycle.
+	// Schemas without types such as interface{} have x-kubernetes-preserve-unknown-fields
+	// and a field can also preserve unknown fields by `jsonschema:"preserveUnknownFields"` tag.
+	// IntOrString of k8s.io/apimachinery/pkg/util/intstr is x-kubernetes-int-or-string
+	// and other fields such as quantities can also be by `jsonschema:"intOrString"` tag.
+	// Constructs which CRDs disallow such as additionalProperties of false,
+	// additionalProperties with properties, uniqueItems and types in allOf, anyOf, oneOf and not
+	// cause an error which matches ErrInvalidOutput.
+	KubernetesCRD
+)
+
+// openAPI3Keywords are keywords of schema objects of OpenAPI 3.0.
+var openAPI3Keywords = map[string]bool{
+	"title": true, "multipleOf": true, "maximum": true, "exclusiveMaximum": true,
+	"minimum": true, "exclusiveMinimum": true, "maxLength": true, "minLength": true,
+	"pattern": true, "maxItems": true, "minItems": true, "uniqueItems": true,
+	"maxProperties": true, "minProperties": true, "required": true, "enum": true,
+	"type": true, "allOf": true, "oneOf": true, "anyOf": true, "not": true,
+	"items": true, "properties": true, "additionalProperties": true, "description": true,
+	"format": true, "default": true, "nullable": true, "discriminator": true,
+	"readOnly": true, "writeOnly": true, "xml": true, "externalDocs": true,
+	"example": true, "deprecated": true, "$ref": true,
+}
+
+// openAPI3SchemasRef is the prefix of references to components.schemas.
+const openAPI3SchemasRef = "#/components/schemas/"
+
+// Flavor sets the flavor of generated schemas such as OpenAPI3,
+// so the same Go types can be used for both JSON Schema validation and OpenAPI documents.
+func Flavor(f SchemaFlavor) Option {
+	return configOption(func(c *config) {
+		c.flavor = f
+	})
+}
+
+// applyFlavor converts the root schema for the flavor after applyDraft.
+func (c *config) applyFlavor(root map[string]interface{}) error {
+	switch c.flavor {
+	case OpenAPI3:
+		applyOpenAPI3(root)
+	case KubernetesCRD:
+		return applyCRD(root)
+	}
+	return nil
+}
+
+// applyOpenAPI3 converts the root schema into a schema object of OpenAPI 3.0.
+func applyOpenAPI3(root map[string]interface{}) {
+	schemas := map[string]interface{}{}
+	for _, k := range []string{"$defs", "definitions"} {
+		if defs, ok := root[k].(map[string]interface{}); ok {
+			for name, d := range defs {
+				schemas[name] = d
+			}
+			delete(root, k)
+		}
+	}
+	rewriteRefs(root, openAPI3Ref)
+	walkSubschemas(root, openAPI3Schema)
+	for _, s := range schemas {
+		if m, ok := s.(map[string]interface{}); ok {
+			rewriteRefs(m, openAPI3Ref)
+			walkSubschemas(m, openAPI3Schema)
+		}
+	}
+	if len(schemas) != 0 {
+		root["components"] = map[string]interface{}{"schemas": schemas}
+	}
+}
+
+// openAPI3Ref converts a reference to a def into a reference to components.schemas.
+func openAPI3Ref(ref string) string {
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return openAPI3SchemasRef + strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+// openAPI3Schema converts the schema into a schema object of OpenAPI 3.0.
+func openAPI3Schema(s map[string]interface{}) {
+	draft04Exclusive(s, "exclusiveMinimum", "minimum", 1)
+	draft04Exclusive(s, "exclusiveMaximum", "maximum", -1)
+
+	if v, ok := s["const"]; ok {
+		s["enum"] = []interface{}{v}
+	}
+	if examples, ok := s["examples"].([]interface{}); ok && len(examples) != 0 {
+		if _, ok := s["example"]; !ok {
+			s["example"] = examples[0]
+		}
+	}
+	// OpenAPI 3.0 expresses base64 encoded strings by the format
+	if _, ok := s["format"]; !ok && s["contentEncoding"] == "base64" {
+		s["format"] = "byte"
+	}
+
+	openAPI3Type(s)
+	for _, k := range []string{"oneOf", "anyOf"} {
+		openAPI3NullableOf(s, k)
+	}
+
+	for k := range s {
+		if !openAPI3Keywords[k] && !strings.HasPrefix(k, "x-") {
+			delete(s, k)
+		}
+	}
+}
+
+// openAPI3Type converts a type array into a type and nullable.
+// Multiple types other than null become anyOf of the types.
+func openAPI3Type(s map[string]interface{}) {
+	var types []string
+	switch typ := s["type"].(type) {
+	case string:
+		if typ == "null" {
+			delete(s, "type")
+			s["nullable"] = true
+		}
+		return
+	case []interface{}:
+		for _, t := range typ {
+			if t, ok := t.(string); ok {
+				types = append(types, t)
+			}
+		}
+	case []string:
+		types = typ
+	default:
+		return
+	}
+
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			s["nullable"] = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+
+	delete(s, "type")
+	switch len(nonNull) {
+	case 0:
+	case 1:
+		s["type"] = nonNull[0]
+	default:
+		sort.Strings(nonNull)
+		anyOf := make([]interface{}, len(nonNull))
+		for i, t := range nonNull {
+			anyOf[i] = map[string]interface{}{"type": t}
+		}
+		if _, ok := s["anyOf"]; ok {
+			appendAllOf(s, map[string]interface{}{"anyOf": anyOf})
+		} else {
+			s["anyOf"] = anyOf
+		}
+	}
+}
+
+// openAPI3NullableOf removes null schemas from oneOf or anyOf of the key and sets nullable.
+// A remaining schema such as a reference is wrapped by allOf
+// because siblings of $ref are ignored in OpenAPI 3.0.
+func openAPI3NullableOf(s map[string]interface{}, key string) {
+	schemas, ok := s[key].([]interface{})
+	if !ok {
+		return
+	}
+
+	var rest []interface{}
+	for _, e := range schemas {
+		if m, ok := e.(map[string]interface{}); ok && len(m) == 1 && m["type"] == "null" {
+			s["nullable"] = true
+			continue
+		}
+		rest = append(rest, e)
+	}
+	if len(rest) == len(schemas) {
+		return
+	}
+
+	delete(s, key)
+	switch len(rest) {
+	case 0:
+	case 1:
+		appendAllOf(s, rest[0])
+	default:
+		s[key] = rest
+	}
+}
+
+// appendAllOf appends the schema to allOf of s.
+func appendAllOf(s map[string]interface{}, schema interface{}) {
+	allOf, _ := s["allOf"].([]interface{})
+	s["allOf"] = append(allOf, schema)
+}
@@ -0,0 +1,285 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type flavorPoint struct {
+	X int `json:"x"`
+}
+
+func TestFlavor(t *testing.T) {
+	type T struct {
+		Name    *string `json:"name"`
+		Age     int     `json:"age" jsonschema:"exclusiveMinimum=0"`
+		Address *struct {
+			City string `json:"city"`
+		} `json:"address"`
+	}
+	name := "gopher"
+	v := T{Name: &name, Address: &struct {
+		City string `json:"city"`
+	}{}}
+
+	examples := ByReference("#/properties/name", func(o Object) (Object, error) {
+		o.Set("examples", []interface{}{"gopher"})
+		return o, nil
+	})
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "JSON Schema",
+			opts: []Option{examples, Draft(Draft202012), NullablePointers(), HoistAnonymousStructs()},
+			expect: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"title": "T",
+				"required": ["age"],
+				"properties": {
+					"name": {"type": ["string", "null"], "examples": ["gopher"], "propertyOrder": 0},
+					"age": {"type": "number", "exclusiveMinimum": 0, "propertyOrder": 1},
+					"address": {"$ref": "#/$defs/T_Address", "propertyOrder": 2}
+				},
+				"$defs": {
+					"T_Address": {
+						"type": ["object", "null"],
+						"required": ["city"],
+						"properties": {"city": {"type": "string", "propertyOrder": 0}}
+					}
+				}
+			}`,
+		},
+		{
+			name: "OpenAPI 3.0",
+			opts: []Option{examples, Draft(Draft202012), NullablePointers(), HoistAnonymousStructs(), Flavor(OpenAPI3)},
+			expect: `{
+				"type": "object",
+				"title": "T",
+				"required": ["age"],
+				"properties": {
+					"name": {"type": "string", "nullable": true, "example": "gopher"},
+					"age": {"type": "number", "minimum": 0, "exclusiveMinimum": true},
+					"address": {"$ref": "#/components/schemas/T_Address"}
+				},
+				"components": {
+					"schemas": {
+						"T_Address": {
+							"type": "object",
+							"nullable": true,
+							"required": ["city"],
+							"properties": {"city": {"type": "string"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "OpenAPI 3.0 of draft-07",
+			opts: []Option{examples, Draft(Draft07), HoistAnonymousStructs(), Flavor(OpenAPI3)},
+			expect: `{
+				"type": "object",
+				"title": "T",
+				"required": ["age"],
+				"properties": {
+					"name": {"type": "string", "example": "gopher"},
+					"age": {"type": "number", "minimum": 0, "exclusiveMinimum": true},
+					"address": {"$ref": "#/components/schemas/T_Address"}
+				},
+				"components": {
+					"schemas": {
+						"T_Address": {
+							"type": "object",
+							"required": ["city"],
+							"properties": {"city": {"type": "string"}}
+						}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestFlavor_nullableRef(t *testing.T) {
+	type T struct {
+		A *flavorPoint `json:"a"`
+		B flavorPoint  `json:"b"`
+	}
+
+	got, err := GenerateString(T{A: &flavorPoint{}}, SharedTypes(SharedTypesRef), NullablePointers(), Flavor(OpenAPI3))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["b"],
+		"properties": {
+			"a": {"allOf": [{"$ref": "#/components/schemas/flavorPoint"}], "nullable": true},
+			"b": {"$ref": "#/components/schemas/flavorPoint"}
+		},
+		"components": {
+			"schemas": {
+				"flavorPoint": {
+					"type": "object",
+					"title": "flavorPoint",
+					"required": ["x"],
+					"properties": {"x": {"type": "number"}}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+type crdSpec struct {
+	Replicas *int                   `json:"replicas,omitempty" jsonschema:"minimum=0"`
+	Port     string                 `json:"port" jsonschema:"intOrString"`
+	Template map[string]interface{} `json:"template" jsonschema:"preserveUnknownFields"`
+	Extra    interface{}            `json:"extra,omitempty"`
+	Labels   map[string]string      `json:"labels"`
+	Selector *flavorPoint           `json:"selector,omitempty"`
+	Points   []flavorPoint          `json:"points"`
+}
+
+type crdResource struct {
+	Spec crdSpec `json:"spec"`
+}
+
+type crdNode struct {
+	Children []crdNode `json:"children"`
+}
+
+func TestFlavor_kubernetesCRD(t *testing.T) {
+	replicas := 1
+	v := crdResource{Spec: crdSpec{
+		Replicas: &replicas,
+		Template: map[string]interface{}{},
+		Labels:   map[string]string{},
+		Selector: &flavorPoint{},
+		Points:   []flavorPoint{{}},
+	}}
+	opts := []Option{Flavor(KubernetesCRD), SharedTypes(SharedTypesRef), NullablePointers(), Draft(Draft202012), ValidateOutput()}
+
+	got, err := GenerateString(v, opts...)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	point := `{
+		"type": "object",
+		"title": "flavorPoint",
+		"required": ["x"],
+		"properties": {"x": {"type": "number"}}
+	}`
+	expect := `{
+		"type": "object",
+		"title": "crdResource",
+		"required": ["spec"],
+		"properties": {
+			"spec": {
+				"type": "object",
+				"title": "crdSpec",
+				"required": ["port", "template", "labels", "points"],
+				"properties": {
+					"replicas": {"type": "number", "minimum": 0, "nullable": true},
+					"port": {"x-kubernetes-int-or-string": true},
+					"template": {"type": "object", "additionalProperties": {"x-kubernetes-preserve-unknown-fields": true}, "x-kubernetes-preserve-unknown-fields": true},
+					"extra": {"x-kubernetes-preserve-unknown-fields": true},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+					"selector": {
+						"type": "object",
+						"title": "flavorPoint",
+						"nullable": true,
+						"required": ["x"],
+						"properties": {"x": {"type": "number"}}
+					},
+					"points": {"type": "array", "items": ` + point + `}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestFlavor_kubernetesCRDError(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+		opts []Option
+		kind error
+		ref  string
+	}{
+		{
+			name: "recursive",
+			v:    crdNode{Children: []crdNode{}},
+			opts: []Option{SharedTypes(SharedTypesRecursive)},
+			kind: ErrCycle,
+			ref:  "#/properties/children/items",
+		},
+		{
+			name: "closed object",
+			v:    flavorPoint{},
+			opts: []Option{StrictObjects()},
+			kind: ErrInvalidOutput,
+			ref:  "#/",
+		},
+		{
+			name: "unique items",
+			v: struct {
+				Tags []string `json:"tags" jsonschema:"uniqueItems"`
+			}{Tags: []string{""}},
+			kind: ErrInvalidOutput,
+			ref:  "#/properties/tags",
+		},
+		{
+			name: "type in anyOf",
+			v: struct {
+				N json.Number `json:"n"`
+			}{},
+			opts: []Option{ByReference("#/properties/n", func(o Object) (Object, error) {
+				o.Set("anyOf", []interface{}{map[string]interface{}{"type": "string"}, map[string]interface{}{"pattern": "^[0-9]+$"}})
+				return o, nil
+			})},
+			kind: ErrInvalidOutput,
+			ref:  "#/properties/n/anyOf/0",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateString(tt.v, append([]Option{Flavor(KubernetesCRD)}, tt.opts...)...)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("want %v but got %v", tt.kind, err)
+			}
+			var e *Error
+			if !errors.As(err, &e) || e.Ref != tt.ref {
+				t.Errorf("want the error at %s but got %v", tt.ref, err)
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package jsonschema
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
-	"path"
 	"reflect"
+	"strconv"
+	"time"
 )
 
 const (
@@ -21,38 +23,207 @@ type Generator interface {
 // Generate generates JSON Schema from a Go type.
 // Channel, complex, and function values cannot be encoded in JSON Schema.
 // Attempting to generate such a type causes Generate to return
-// an UnsupportedTypeError.
-func Generate(w io.Writer, v interface{}, opts ...Option) error {
+// an error which matches ErrUnsupportedType and wraps an UnsupportedTypeError.
+// Errors are *Error which report the reference of the object and the chain of Go fields
+// where they occur, such as "#/properties/config/properties/callback" and "pkg.T.Config.Callback".
+//
+// Any type can be a root such as a struct, a slice, a map or a scalar value.
+// Options are applied to every object in a same way regardless of its depth.
+// Each object has a reference which begins with RefRoot:
+// the root object is "#/", items of an array are "#/items",
+// values of a map are "#/additionalProperties" and
+// properties of a struct are "#/properties/name".
+// References are nested such as "#/items/properties/name"
+// and ByReference can apply an option to specific objects by them.
+// WithRefBuilder changes how references are built.
+//
+// Fields of a struct are required unless they are pointers or their json tags have omitempty.
+// A required tag such as `required:"true"` or `required:"false"` overrides it.
+//
+// Fields of a struct which have the same group tag such as `group:"address"`
+// are collected into a nested object of the property "address",
+// e.g. "#/properties/address/properties/city".
+// The nested object is required if one of its fields is required.
+func Generate(w io.Writer, v interface{}, opts ...Option) (rerr error) {
+	g := gen{cfg: newConfig(opts)}
+	defer func(start time.Time) {
+		g.measure(start, v, rerr)
+	}(time.Now())
 
-	if g, ok := v.(Generator); ok {
-		return g.JSONSchema(w, opts...)
+	if sg, ok := v.(Generator); ok {
+		validates := g.cfg.validatesOutput()
+		if !g.cfg.sortedKeys && g.cfg.propertiesOrder == PropertiesAlphabetical && !validates {
+			return sg.JSONSchema(w, opts...)
+		}
+		var buf bytes.Buffer
+		if err := sg.JSONSchema(&buf, opts...); err != nil {
+			return err
+		}
+		if validates {
+			if err := g.cfg.validateOutputOf(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		if !g.cfg.sortedKeys && g.cfg.propertiesOrder == PropertiesAlphabetical {
+			_, err := buf.WriteTo(w)
+			return err
+		}
+		return g.cfg.reencode(w, buf.Bytes())
 	}
 
-	var g gen
 	o := &obj{
-		m:   map[string]interface{}{},
-		ref: RefRoot,
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
 	}
 
 	if err := g.do(o, reflect.ValueOf(v), opts...); err != nil {
 		return err
 	}
-	return json.NewEncoder(w).Encode(o.m)
+	if err := g.finish(o); err != nil {
+		return err
+	}
+
+	cw := &countWriter{w: w}
+	if !g.cfg.validatesOutput() {
+		err := g.cfg.encode(cw, o.m)
+		g.size = cw.n
+		return err
+	}
+
+	// invalid schemas are not written
+	var buf bytes.Buffer
+	if err := g.cfg.encode(&buf, o.m); err != nil {
+		return err
+	}
+	if err := g.cfg.validateOutputOf(buf.Bytes()); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(cw)
+	g.size = cw.n
+	return err
 }
 
-type gen struct{}
+// GenerateBytes generates JSON Schema from a Go type and returns it as a byte slice.
+// It accepts same options with Generate.
+func GenerateBytes(v interface{}, opts ...Option) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Generate(&buf, v, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateString generates JSON Schema from a Go type and returns it as a string.
+// It accepts same options with Generate.
+func GenerateString(v interface{}, opts ...Option) (string, error) {
+	b, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+type gen struct {
+	cfg *config
+	// defs are schemas which are referred from other schemas.
+	defs map[string]interface{}
+	// hoisting is a name of the def which is being generated.
+	hoisting string
+	// hoisted is the object of the def which is being generated.
+	hoisted Object
+	// plan records generation if it is not nil.
+	plan *PlanReport
+	// nodes is the number of generated objects.
+	nodes int
+	// size is the number of bytes of the encoded schema.
+	size int64
+	// visiting are pointers which are being generated.
+	visiting map[visitKey]bool
+	// defTypes are Go types of defs.
+	defTypes map[string]reflect.Type
+	// rootType is a shared struct type of the root.
+	rootType reflect.Type
+	// recursive caches whether struct types refer to themselves.
+	recursive map[reflect.Type]bool
+	// expanding are types of nil values which are being generated from their types.
+	expanding map[reflect.Type]bool
+	// dynamicSites are objects of type parameters by references of defs of instantiations.
+	dynamicSites map[string][]dynamicSite
+	// compatibilityChanges are keywords which are changed for the compatibility level.
+	compatibilityChanges []CompatibilityChange
+	// violations are names which violate the naming convention of EnforceNaming with NamingWarn.
+	violations []NamingViolation
+}
+
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
 
 func (g *gen) do(o Object, v reflect.Value, options ...Option) error {
+	g.nodes++
+	// non-nil pointers are not counted because their elements are generated as the same object
+	if max := g.cfg.maxNodes; max > 0 && g.nodes > max && (v.Kind() != reflect.Ptr || v.IsNil()) {
+		return newError(ErrBudgetExceeded, o.Ref(), fmt.Errorf("more than %d objects are generated", max))
+	}
 
-	switch v.Kind() {
-	case reflect.Interface, reflect.Chan, reflect.Func,
-		reflect.Ptr, reflect.Map, reflect.Slice:
-		if v.IsNil() {
-			return nil
+	// untyped nil such as Generate(w, nil) does not have any types
+	if !v.IsValid() {
+		return newError(ErrUnsupportedType, o.Ref(), fmt.Errorf("untyped nil cannot be encoded in JSON Schema"))
+	}
+
+	if g.plan != nil {
+		g.plan.visit(o.Ref(), v.Type())
+	}
+
+	if typ, ok := g.cfg.overrideType(o.Ref(), v.Type()); ok {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "type is overridden to %s", typ)
+		}
+		o.Set("type", typ)
+		return g.applyOptions(o, g.cfg.withTypeOptions(v.Type(), options))
+	}
+
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		if ok, err := g.placeholder(o, options); ok || err != nil {
+			return err
+		}
+	}
+
+	if v.Kind() == reflect.Interface {
+		if ok, err := g.implementationsGen(o, v.Type(), options); ok || err != nil {
+			return err
 		}
 	}
 
+	if isNil(v) && g.canExpand(v) {
+		if g.expanding == nil {
+			g.expanding = map[reflect.Type]bool{}
+		}
+		g.expanding[v.Type()] = true
+		defer delete(g.expanding, v.Type())
+
+		// the empty value is generated as the same object
+		g.nodes--
+		return g.do(o, empty(v.Type()), options...)
+	}
+
+	if isNil(v) {
+		if g.plan != nil {
+			g.plan.warn("%s is nil value of %s and an empty schema is generated", o.Ref(), v.Type())
+		}
+		return nil
+	}
+
+	if isSecret(v) {
+		defer scrubSecret(o)
+	}
+
 	if g1, ok := v.Interface().(Generator); ok {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "generated by Generator %s", v.Type())
+		}
 
 		var buf bytes.Buffer
 		if err := g1.JSONSchema(&buf, options...); err != nil {
@@ -68,39 +239,126 @@ func (g *gen) do(o Object, v reflect.Value, options ...Option) error {
 			o.Set(k, v)
 		}
 
+		if opts := g.cfg.forTypes[v.Type()]; len(opts) != 0 {
+			return g.applyOptions(o, opts)
+		}
 		return nil
 	}
 
+	if ok, err := g.mappedGen(o, v.Type()); err != nil {
+		return err
+	} else if ok {
+		g.enumGen(o, v.Type())
+		if err := setTypeDefaults(o, v.Type()); err != nil {
+			return err
+		}
+		return g.applyOptions(o, g.cfg.withTypeOptions(v.Type(), options))
+	}
+
 	switch v.Kind() {
 	// unsupported types
 	case reflect.Complex64, reflect.Complex128, reflect.Interface,
 		reflect.Chan, reflect.Func, reflect.Invalid, reflect.UnsafePointer:
-		return &json.UnsupportedTypeError{v.Type()}
+		return newError(ErrUnsupportedType, o.Ref(), &json.UnsupportedTypeError{Type: v.Type()})
 	case reflect.Ptr:
-		return g.do(o, v.Elem(), options...)
+		key := visitKey{v.Type(), v.Pointer()}
+		// shared struct types are referred instead of generating them again
+		if g.visiting[key] && !g.isShared(v.Type().Elem()) {
+			return newError(ErrCycle, o.Ref(), fmt.Errorf("%s refers to itself", v.Type()))
+		}
+		if g.visiting == nil {
+			g.visiting = map[visitKey]bool{}
+		}
+		g.visiting[key] = true
+		defer delete(g.visiting, key)
+
+		// the element is generated as the same object
+		g.nodes--
+		if err := g.do(o, v.Elem(), options...); err != nil {
+			return err
+		}
+		if g.cfg.nullablePointers {
+			setNullable(o)
+		}
+		if opts := g.cfg.forTypes[v.Type()]; len(opts) != 0 {
+			return g.applyOptions(o, opts)
+		}
+		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Uintptr, reflect.Float32, reflect.Float64:
+		if v.Type() == durationType && g.cfg.isoDurations {
+			o.Set("type", "string")
+			o.Set("format", "duration")
+			break
+		}
 		o.Set("type", "number")
 	case reflect.Bool:
 		o.Set("type", "boolean")
 	case reflect.String:
 		o.Set("type", "string")
 	case reflect.Map:
-		if v.Type().Key().Kind() != reflect.String {
-			return &json.UnsupportedTypeError{v.Type()}
+		kt := v.Type().Key()
+		switch {
+		case isIntKey(kt) && g.cfg.mapIntKeyStyle == MapIntKeyEntries:
+			if err := g.mapEntriesGen(o, v, options...); err != nil {
+				return err
+			}
+		case kt.Kind() == reflect.String || isTextMarshaler(kt) || isIntKey(kt):
+			if err := g.mapGen(o, v, options...); err != nil {
+				return err
+			}
+			if err := g.shapeGen(o, options); err != nil {
+				return err
+			}
+		default:
+			return newError(ErrUnsupportedType, o.Ref(), &json.UnsupportedTypeError{Type: v.Type()})
 		}
-		o.Set("type", "object")
 	case reflect.Array, reflect.Slice:
+		if isBytes(v.Type()) && !g.cfg.bytesAsArrays {
+			o.Set("type", "string")
+			o.Set("contentEncoding", "base64")
+			break
+		}
 		if err := g.arrayGen(o, v, options...); err != nil {
 			return err
 		}
 	case reflect.Struct:
+		if v.Type() == timeType {
+			g.timeGen(o)
+			break
+		}
+		if ok, err := g.sharedRef(o, v, options); ok || err != nil {
+			return err
+		}
 		if err := g.structGen(o, v, options...); err != nil {
 			return err
 		}
 	}
 
+	g.enumGen(o, v.Type())
+	if err := setTypeDefaults(o, v.Type()); err != nil {
+		return err
+	}
+
+	return g.applyOptions(o, g.cfg.withTypeOptions(v.Type(), options))
+}
+
+// enumGen sets enum of the type t to the object.
+func (g *gen) enumGen(o Object, t reflect.Type) {
+	if values, ok := g.cfg.enumValuesOf(t); ok {
+		setEnumValues(o, values)
+	} else if enum, ok := enumOf(t); ok {
+		o.Set("enum", enum)
+	}
+}
+
+func (g *gen) applyOptions(o Object, options []Option) error {
+	if g.plan != nil {
+		snapshot := g.plan.beforeOptions(o)
+		defer g.plan.afterOptions(o, snapshot)
+	}
+
 	for _, opt := range options {
 		var err error
 		o, err = opt(o)
@@ -108,18 +366,21 @@ func (g *gen) do(o Object, v reflect.Value, options ...Option) error {
 			return err
 		}
 	}
-
 	return nil
 }
 
 func (g *gen) arrayGen(parent Object, v reflect.Value, options ...Option) error {
+	if v.Kind() == reflect.Array && g.cfg.arrayStyle != ArrayItems {
+		return g.tupleGen(parent, v, g.cfg.arrayStyle == ArrayTuples, options...)
+	}
+
 	o := &obj{
 		m:   map[string]interface{}{},
-		ref: path.Join(parent.Ref(), "items"),
+		ref: g.cfg.refs().Join(parent.Ref(), "items"),
 	}
 
-	elm := reflect.Zero(v.Type().Elem())
-	if v.Len() != 0 {
+	elm := empty(v.Type().Elem())
+	if v.Len() != 0 && !isNil(v.Index(0)) {
 		elm = v.Index(0)
 	}
 	if err := g.do(o, elm, options...); err != nil {
@@ -128,50 +389,646 @@ func (g *gen) arrayGen(parent Object, v reflect.Value, options ...Option) error
 
 	parent.Set("type", "array")
 	parent.Set("items", o.m)
+	if v.Kind() == reflect.Array {
+		// the length of an array is fixed
+		parent.Set("minItems", v.Len())
+		parent.Set("maxItems", v.Len())
+	}
 
 	return nil
 }
 
-func (g *gen) structGen(parent Object, v reflect.Value, options ...Option) error {
-	required := make([]string, v.NumField())
-	properties := make(map[string]interface{}, v.NumField())
+func (g *gen) mapGen(parent Object, v reflect.Value, options ...Option) error {
+	if g.cfg.closedMaps && v.Len() != 0 {
+		return g.closedMapGen(parent, v, options...)
+	}
+
+	enum, err := propertyNamesEnum(v.Type().Key())
+	if err != nil {
+		return err
+	}
+	var pattern string
+	switch {
+	case g.cfg.patternProps && len(enum) != 0:
+		pattern = enumPattern(enum)
+	case g.cfg.patternProps && isIntKey(v.Type().Key()):
+		pattern = intKeyPatternOf(v.Type().Key())
+	}
+
+	o := &obj{
+		m:   map[string]interface{}{},
+		ref: g.cfg.refs().Join(parent.Ref(), "additionalProperties"),
+	}
+	if pattern != "" {
+		o.ref = g.cfg.refs().Join(parent.Ref(), "patternProperties", pattern)
+	}
+
+	elm := empty(v.Type().Elem())
+	if keys := v.MapKeys(); len(keys) != 0 {
+		names := make([]string, len(keys))
+		for i := range keys {
+			name, err := keyName(keys[i].Interface())
+			if err != nil {
+				return err
+			}
+			names[i] = name
+		}
 
+		// use the first key for stable results
+		intKey := isIntKey(v.Type().Key())
+		first := 0
+		for i := range names {
+			if intKey && intKeyLess(keys[i], keys[first]) ||
+				!intKey && names[i] < names[first] {
+				first = i
+			}
+		}
+		if e := v.MapIndex(keys[first]); !isNil(e) {
+			elm = e
+		}
+	}
+	if err := g.do(o, elm, options...); err != nil {
+		return err
+	}
+
+	parent.Set("type", "object")
+	if pattern != "" {
+		parent.Set("patternProperties", map[string]interface{}{pattern: o.m})
+		parent.Set("additionalProperties", false)
+		return nil
+	}
+	parent.Set("additionalProperties", o.m)
+
+	switch {
+	case len(enum) != 0:
+		parent.Set("propertyNames", map[string]interface{}{
+			"enum": enum,
+		})
+	case isIntKey(v.Type().Key()):
+		parent.Set("propertyNames", map[string]interface{}{
+			"pattern": intKeyPatternOf(v.Type().Key()),
+		})
+	}
+
+	return nil
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Chan, reflect.Func,
+		reflect.Ptr, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// empty returns an empty value of the type t.
+// Unlike reflect.Zero, pointers, maps and slices are not nil
+// so that schemas of elements of containers can be generated from their types.
+func empty(t reflect.Type) reflect.Value {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return reflect.New(t.Elem())
+	case reflect.Map:
+		return reflect.MakeMap(t)
+	case reflect.Slice:
+		return reflect.MakeSlice(t, 0, 0)
+	}
+	return reflect.Zero(t)
+}
+
+// field is a struct field which is generated as a property.
+type field struct {
+	name    string
+	goField string
+	goName  string
+	// owner is a struct type which declares the field.
+	owner    reflect.Type
+	value    reflect.Value
+	optional bool
+	depth    int
+	tag      schemaTag
+	rawTag   reflect.StructTag
+	// overrides are given by tags of embedding structs.
+	overrides schemaTag
+	// group is a name of a nested object which has the field.
+	group string
+	// quoted is true if the value is encoded as a JSON string by the string option of the json tag.
+	quoted bool
+	// tagged is true if the json tag gives the name.
+	tagged bool
+}
+
+// fields returns fields of the struct v.
+// Fields of embedded structs whose json tags do not give names are promoted.
+// If a name is duplicated, the shallower field has priority as encoding/json.
+// Fields of the same depth are resolved by the one whose json tag gives the name
+// and ambiguous fields are ignored.
+func (g *gen) fields(v reflect.Value) ([]field, error) {
+	all := g.collectFields(v, 0, false)
+	for i := range all {
+		if err := all[i].applyRequiredTag(); err != nil {
+			return nil, &Error{Kind: ErrTagSyntax, Field: all[i].goName, Err: err}
+		}
+	}
+
+	type dominant struct {
+		depth  int
+		index  int
+		tagged int
+		count  int
+	}
+	dominants := make(map[string]*dominant, len(all))
+	for i, f := range all {
+		d, ok := dominants[f.name]
+		switch {
+		case !ok || f.depth < d.depth:
+			d = &dominant{depth: f.depth, index: i}
+			if f.tagged {
+				d.tagged = 1
+			}
+			d.count = 1
+			dominants[f.name] = d
+		case f.depth == d.depth:
+			d.count++
+			if f.tagged {
+				if d.tagged == 0 {
+					d.index = i
+				}
+				d.tagged++
+			}
+		}
+	}
+
+	fields := make([]field, 0, len(all))
+	for i, f := range all {
+		d := dominants[f.name]
+		switch {
+		case d.index == i && (d.count == 1 || d.tagged == 1):
+			fields = append(fields, f)
+		case g.plan == nil:
+		case d.depth == f.depth && d.count != 1 && d.tagged != 1:
+			g.plan.skip(f.goName, "ambiguous with another field named %q", f.name)
+		default:
+			g.plan.skip(f.goName, "shadowed by another field named %q", f.name)
+		}
+	}
+
+	return fields, nil
+}
+
+// applyRequiredTag overrides whether the field is optional by the required tag
+// such as `required:"false"`.
+func (f *field) applyRequiredTag() error {
+	r, ok := f.rawTag.Lookup("required")
+	if !ok {
+		return nil
+	}
+	required, err := strconv.ParseBool(r)
+	if err != nil {
+		return fmt.Errorf("invalid required tag %q: %w", r, err)
+	}
+	f.optional = !required
+	return nil
+}
+
+// collectFields collects fields of v and promoted fields in declaration order.
+// If viaPtr is true, v is embedded through a pointer.
+func (g *gen) collectFields(v reflect.Value, depth int, viaPtr bool) []field {
+	var fields []field
 	for i := 0; i < v.NumField(); i++ {
 		f, ft := v.Field(i), v.Type().Field(i)
 		name := ft.Name
 
-		if ft.Anonymous {
-			name = ft.Type.Name()
+		// blank fields are markers of object keywords
+		if name == "_" {
+			continue
+		}
+
+		if g.cfg.compatTags && compatIgnored(ft.Tag) {
+			if g.plan != nil {
+				g.plan.skip(v.Type().String()+"."+ft.Name, "ignored by jsonschema tag")
+			}
+			continue
 		}
 
-		if tag, ok := ft.Tag.Lookup("json"); ok {
-			name = tag
+		tag := parseJSONTag(ft.Tag.Get("json"))
+		if tag.ignored {
+			if g.plan != nil {
+				g.plan.skip(v.Type().String()+"."+ft.Name, "ignored by json tag")
+			}
+			continue
 		}
 
-		required[i] = name
+		// unexported fields are ignored as encoding/json but fields of embedded structs are promoted
+		promotable := ft.Anonymous && tag.name == "" && !g.cfg.nestEmbedded
+		if ft.PkgPath != "" && !(promotable && indirectType(ft.Type).Kind() == reflect.Struct) {
+			continue
+		}
+
+		// embedded structs are promoted unless the json tag gives a name as encoding/json
+		if promotable {
+			typ, isPtr := ft.Type, false
+			if typ.Kind() == reflect.Ptr {
+				typ, isPtr = typ.Elem(), true
+				if f.IsNil() {
+					f = reflect.Zero(typ)
+				} else {
+					f = f.Elem()
+				}
+			}
+
+			if typ.Kind() == reflect.Struct {
+				promoted := g.collectFields(f, depth+1, viaPtr || isPtr)
+				// the embedding struct overrides tags of promoted fields
+				// and an outer embedding is applied after inner ones
+				overrides := parseSchemaTag(ft.Tag.Get("jsonschema")).overrides()
+				for i := range promoted {
+					if o, ok := overrides[promoted[i].name]; ok {
+						promoted[i].overrides = promoted[i].overrides.merge(o)
+					}
+				}
+				fields = append(fields, promoted...)
+				continue
+			}
+		}
+
+		if tag.name != "" {
+			name = tag.name
+		}
+
+		optional := tag.omitEmpty || ft.Type.Kind() == reflect.Ptr || viaPtr && g.cfg.promotedRequired == EmbedRequiredInherit
+		if g.cfg.compatTags && compatRequired(ft.Tag) {
+			optional = false
+		}
+		fields = append(fields, field{
+			name:     name,
+			goField:  ft.Name,
+			goName:   v.Type().String() + "." + ft.Name,
+			owner:    v.Type(),
+			value:    f,
+			optional: optional,
+			depth:    depth,
+			tag:      parseSchemaTag(ft.Tag.Get("jsonschema")),
+			rawTag:   ft.Tag,
+			group:    ft.Tag.Get("group"),
+			quoted:   tag.quoted && isQuotable(ft.Type),
+			tagged:   tag.name != "",
+		})
+	}
+	return fields
+}
+
+// fieldGroup is a nested object of fields which have the same group tag.
+type fieldGroup struct {
+	o          *obj
+	order      int
+	required   []string
+	properties map[string]interface{}
+}
+
+func (g *gen) structGen(parent Object, v reflect.Value, options ...Option) error {
+	fields, err := g.fields(v)
+	if err != nil {
+		return withRef(err, parent.Ref())
+	}
+	if fields, err = g.applyNaming(parent, v.Type(), fields); err != nil {
+		return err
+	}
+	required := make([]string, 0, len(fields))
+	properties := make(map[string]interface{}, len(fields))
+
+	parentName := typeName(v.Type())
+	if parentName == "" {
+		parentName = g.hoisting
+	}
 
-		o := &obj{
-			m:   map[string]interface{}{},
-			ref: path.Join(parent.Ref(), "properties", name),
+	// fields which have group tags are collected into nested objects
+	groups := map[string]*fieldGroup{}
+	var order []*fieldGroup
+	for _, f := range fields {
+		if f.group == "" {
+			o, err := g.propertyGen(parent, f, len(properties), parentName, options)
+			if err != nil {
+				return err
+			}
+			if _, ok := properties[f.name]; ok {
+				return &Error{Kind: ErrNameCollision, Ref: o.Ref(), Field: f.goName, Err: fmt.Errorf("property %q is also a group", f.name)}
+			}
+			if !f.optional {
+				required = append(required, f.name)
+			}
+			properties[f.name] = o.m
+			continue
 		}
 
-		opts := make([]Option, len(options)+1)
-		copy(opts, options)
-		opts[len(opts)-1] = ByReference(o.Ref(), PropertyOrder(i))
+		gr, ok := groups[f.group]
+		if !ok {
+			if err := g.cfg.validateName(f.group); err != nil {
+				return &Error{
+					Kind:  ErrTagSyntax,
+					Ref:   parent.Ref(),
+					Field: f.goName,
+					Err:   fmt.Errorf("invalid group name %q: %w", f.group, err),
+				}
+			}
+			if _, ok := properties[f.group]; ok {
+				return &Error{Kind: ErrNameCollision, Ref: parent.Ref(), Field: f.goName, Err: fmt.Errorf("group %q is also a property", f.group)}
+			}
+			gr = &fieldGroup{
+				o: &obj{
+					m:   map[string]interface{}{},
+					ref: g.cfg.refs().Join(parent.Ref(), "properties", f.group),
+				},
+				order:      len(properties),
+				properties: map[string]interface{}{},
+			}
+			groups[f.group] = gr
+			order = append(order, gr)
+			properties[f.group] = gr.o.m
+		}
 
-		if err := g.do(o, f, opts...); err != nil {
+		o, err := g.propertyGen(gr.o, f, len(gr.properties), parentName, options)
+		if err != nil {
 			return err
 		}
+		// the group is required if one of its fields is required
+		if !f.optional {
+			if len(gr.required) == 0 {
+				required = append(required, f.group)
+			}
+			gr.required = append(gr.required, f.name)
+		}
+		gr.properties[f.name] = o.m
+	}
 
-		properties[name] = o.m
+	for _, gr := range order {
+		gr.o.Set("type", "object")
+		gr.o.Set("required", g.cfg.orderRequired(gr.required))
+		gr.o.Set("properties", gr.properties)
+		if g.cfg.strictObjects {
+			gr.o.Set("additionalProperties", false)
+		}
+		opts := append(append([]Option{}, options...), ByReference(gr.o.Ref(), PropertyOrder(gr.order)))
+		if err := g.applyOptions(gr.o, opts); err != nil {
+			return err
+		}
+	}
+
+	if rels, ok := g.cfg.halRels(v.Type()); ok {
+		properties["_links"] = halLinks(rels)
+		properties["_embedded"] = halEmbedded()
 	}
 
 	parent.Set("type", "object")
-	if title := v.Type().Name(); title != "" {
+	if title := g.cfg.titleOf(v.Type()); title != "" {
 		parent.Set("title", title)
 	}
-	parent.Set("required", required)
+	if doc, ok := g.cfg.docOf(v.Type(), ""); ok {
+		parent.Set("description", doc)
+	}
+	if loc, ok := g.cfg.sourceOf(v.Type(), ""); ok {
+		parent.Set(SourceLocationKey, loc)
+	}
+	parent.Set("required", g.cfg.orderRequired(required))
 	parent.Set("properties", properties)
+	if g.cfg.strictObjects {
+		parent.Set("additionalProperties", false)
+	}
+	g.cfg.annotate(parent, v.Type())
+
+	return setObjectKeywords(parent, v)
+}
+
+// propertyGen generates a schema of the field which is a property of the parent.
+// The order is a value of propertyOrder of the property.
+func (g *gen) propertyGen(parent Object, f field, order int, parentName string, options []Option) (*obj, error) {
+	tag := f.tag.merge(f.overrides)
+
+	if err := g.cfg.validateName(f.name); err != nil {
+		return nil, &Error{
+			Kind:  ErrTagSyntax,
+			Ref:   parent.Ref(),
+			Field: f.goName,
+			Err:   fmt.Errorf("invalid property name %q: %w", f.name, err),
+		}
+	}
+
+	o := &obj{
+		m:   map[string]interface{}{},
+		ref: g.cfg.refs().Join(parent.Ref(), "properties", f.name),
+	}
+
+	// options from the tag are applied before given options
+	opts := make([]Option, 0, len(options)+3)
+	if g.cfg.propertyTitle != nil {
+		opts = append(opts, ByReference(o.Ref(), titleOption(g.cfg.propertyTitle(f.goField))))
+	}
+	if doc, ok := g.cfg.docOf(f.owner, f.goField); ok {
+		opts = append(opts, ByReference(o.Ref(), docOption(doc)))
+	}
+	if loc, ok := g.cfg.sourceOf(f.owner, f.goField); ok {
+		opts = append(opts, ByReference(o.Ref(), sourceOption(loc)))
+	}
+	if g.cfg.compatTags {
+		// native keys such as file and layout are also available with compatible tags
+		// but enum and example are repeated keys of compatible tags
+		opts = append(opts, ByReference(o.Ref(), f.tag.without("enum", "default", "example").option()))
+		opts = append(opts, ByReference(o.Ref(), compatOption(f.rawTag)))
+		if len(f.overrides) != 0 {
+			opts = append(opts, ByReference(o.Ref(), f.overrides.option()))
+		}
+	} else {
+		opts = append(opts, ByReference(o.Ref(), tag.option()))
+	}
+	if msgs := tag.errorMessages(); len(msgs) != 0 {
+		opts = append(opts, ByReference(o.Ref(), errorMessageOption(g.cfg.errorMessageKey(), msgs)))
+	}
+	d, ok, err := g.cfg.defaultOf(f.value)
+	if err != nil {
+		return nil, &Error{Kind: ErrUnsupportedType, Ref: o.Ref(), Field: f.goName, Err: err}
+	}
+	if ok {
+		opts = append(opts, ByReference(o.Ref(), defaultOption(d)))
+	}
+	if kw, ok := g.cfg.directivesOf(f.owner, f.goField); ok {
+		opts = append(opts, ByReference(o.Ref(), directiveOption(kw)))
+	}
+	opts = append(opts, options...)
+	opts = append(opts, ByReference(o.Ref(), PropertyOrder(order)))
+
+	typ, ok := tag["type"]
+	if !ok && f.quoted {
+		typ, ok = "string", true
+	}
+	if ok {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "type is overridden to %s by tag", typ)
+		}
+		o.Set("type", typ)
+		if err := g.applyOptions(o, opts); err != nil {
+			return nil, withField(err, f.goName)
+		}
+		if g.cfg.nullablePointers && f.value.Kind() == reflect.Ptr {
+			setNullable(o)
+		}
+	} else if tag.has("tuple") {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "generated as a tuple by tag")
+		}
+		if err := g.tuplePropertyGen(o, f.value, opts); err != nil {
+			return nil, withField(err, f.goName)
+		}
+	} else if g.cfg.hoistAnonymous && isAnonymousStruct(f.value.Type()) {
+		name := f.goField
+		if parentName != "" {
+			name = parentName + "_" + f.goField
+		}
+		ref, err := g.hoist(name, f.value, options)
+		if err != nil {
+			return nil, withField(err, f.goName)
+		}
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "hoisted to %s", ref)
+		}
+		o.Set("$ref", ref)
+		if err := g.applyOptions(o, opts); err != nil {
+			return nil, withField(err, f.goName)
+		}
+	} else if err := g.do(o, f.value, opts...); err != nil {
+		return nil, withField(err, f.goName)
+	}
+
+	if typeParam, ok := tag["typeParam"]; ok {
+		if err := g.typeParamSite(o, typeParam); err != nil {
+			return nil, withField(err, f.goName)
+		}
+	}
+
+	if tag.has("secret") {
+		scrubSecret(o)
+	}
+
+	return o, nil
+}
+
+// hoist generates a schema of v into defs with the name and returns a reference to it.
+func (g *gen) hoist(name string, v reflect.Value, options []Option) (string, error) {
+	// names of nested definitions are synthesized from the name without the hash
+	parentName := name
+	if g.cfg.hashDefNames {
+		if t, ok := g.defTypes[name]; isAnonymousStruct(v.Type()) || ok && t != v.Type() {
+			name += "_" + shortTypeHash(v.Type())
+		}
+	}
+
+	// $ref is always a JSON Pointer regardless of references of objects
+	ref := RefRoot + "$defs/" + escapePointer(name)
+	objRef := g.cfg.refs().Join(g.cfg.refs().Root(), "$defs", name)
+	if _, ok := g.defs[name]; ok {
+		if t := g.defTypes[name]; t != v.Type() {
+			return "", newError(ErrNameCollision, objRef, fmt.Errorf("%s and %s have the same name %q", t, v.Type(), name))
+		}
+		return ref, nil
+	}
+	if g.defTypes == nil {
+		g.defTypes = map[string]reflect.Type{}
+	}
+	g.defTypes[name] = v.Type()
+
+	o := &obj{
+		m:   map[string]interface{}{},
+		ref: objRef,
+	}
+	if g.defs == nil {
+		g.defs = map[string]interface{}{}
+	}
+	g.defs[name] = o.m
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		v = empty(v.Type())
+	}
+
+	hoisting, hoisted := g.hoisting, g.hoisted
+	g.hoisting, g.hoisted = parentName, o
+	defer func() { g.hoisting, g.hoisted = hoisting, hoisted }()
+
+	if err := g.do(o, v, options...); err != nil {
+		return "", err
+	}
+
+	return ref, nil
+}
+
+// finish completes the root object after generation.
+func (g *gen) finish(root *obj) error {
+	if err := g.specializeGenerics(root.m); err != nil {
+		return err
+	}
+	if len(g.defs) != 0 {
+		root.Set("$defs", g.defs)
+	}
+	g.cfg.applyDraft(root.m)
+	if err := g.cfg.applyFlavor(root.m); err != nil {
+		return err
+	}
+	if err := g.applyCompatibility(root); err != nil {
+		return err
+	}
+	g.cfg.applyKeywordMappers(root.m)
+
+	if g.cfg.integrity {
+		if err := setIntegrity(root.m); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
+
+// indirectType returns the element type if t is a pointer.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// isQuotable reports whether the string option of the json tag applies to values of t
+// as encoding/json, which are strings, numbers, booleans and pointers to them.
+func isQuotable(t reflect.Type) bool {
+	switch indirectType(t).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isBytes reports whether values of t are encoded as base64 strings by encoding/json,
+// which are slices of bytes whose elements do not implement json.Marshaler or encoding.TextMarshaler.
+// Arrays of bytes are encoded as arrays.
+func isBytes(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+	pt := reflect.PtrTo(t.Elem())
+	return !pt.Implements(jsonMarshalerType) && !pt.Implements(textMarshalerType)
+}
+
+// BytesAsArrays generates slices of bytes as arrays of numbers instead of base64 encoded strings,
+// for types whose custom marshalers encode bytes as arrays.
+func BytesAsArrays() Option {
+	return configOption(func(c *config) {
+		c.bytesAsArrays = true
+	})
+}
+
+func isAnonymousStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t.Name() == ""
+}
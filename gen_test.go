@@ -3,9 +3,13 @@ package jsonschema_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	jd "github.com/josephburnett/jd/lib"
 	"github.com/tenntenn/jsonschema"
@@ -50,6 +54,64 @@ func jsonDiff(t *testing.T, a, b string) string {
 	return jsonA.Diff(jsonB).Render()
 }
 
+// stringDuration is a duration which is marshaled as a string such as "5s".
+type stringDuration time.Duration
+
+func (d stringDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// version is a struct which is marshaled as a string such as "1.2".
+type version struct {
+	Major, Minor int
+}
+
+func (v version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%d", v.Major, v.Minor))
+}
+
+// color is a string enum.
+type color string
+
+func (color) JSONSchemaEnum() []interface{} {
+	return []interface{}{color("red"), color("green")}
+}
+
+type status int
+
+func (status) JSONSchemaEnumValues() []EnumValue {
+	return []EnumValue{
+		{Value: 0, Name: "StatusActive", Description: "in use"},
+		{Value: 1, Name: "StatusDeleted"},
+	}
+}
+
+// level is an integer enum which is marshaled as a text.
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("low"), nil
+	case 1:
+		return []byte("high"), nil
+	}
+	return nil, fmt.Errorf("invalid level %d", int(l))
+}
+
+func (level) JSONSchemaEnum() []interface{} {
+	return []interface{}{level(0), level(1)}
+}
+
+// point is a struct which is marshaled as a text.
+type point struct {
+	X, Y int
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil
+}
+
 type generator struct {
 	json   string
 	schema string
@@ -64,6 +126,19 @@ func (g *generator) MarshalJSON() ([]byte, error) {
 	return []byte(g.json), nil
 }
 
+// describeRef is an option which sets the reference of each object as its description.
+func describeRef(o Object) (Object, error) {
+	o.Set("description", o.Ref())
+	return o, nil
+}
+
+// setDefault is an option which sets a default value to each object.
+func setDefault(o Object) (Object, error) {
+	o.Set("default", "secret")
+	o.Set("examples", []string{"secret"})
+	return o, nil
+}
+
 func TestGenerate(t *testing.T) {
 
 	type T struct {
@@ -75,9 +150,27 @@ func TestGenerate(t *testing.T) {
 		T T
 	}
 
+	type ET struct {
+		T
+		B bool
+	}
+
+	type PET struct {
+		*T
+		B bool
+	}
+
+	type AT struct {
+		Addr struct {
+			Zip string
+			Geo struct{ Lat, Lng float64 }
+		}
+	}
+
 	cases := []struct {
 		name   string
 		v      interface{}
+		opts   []Option
 		expect string
 		isErr  bool
 	}{
@@ -112,6 +205,175 @@ func TestGenerate(t *testing.T) {
 			v:      []int(nil),
 			expect: `{}`,
 		},
+		{
+			name: "nested array",
+			v:    [][]int{},
+			opts: []Option{describeRef},
+			expect: `{
+				"type":"array",
+				"description": "#/",
+				"items": {
+					"type": "array",
+					"description": "#/items",
+					"items": {"type": "number", "description": "#/items/items"}
+				}
+			}`,
+		},
+		{
+			name: "root array of structs",
+			v:    []*T{{N: 1}},
+			opts: []Option{
+				ByReference(RefRoot, func(o Object) (Object, error) {
+					o.Set("minItems", 1)
+					return o, nil
+				}),
+				ByReference("#/items/properties/N", func(o Object) (Object, error) {
+					o.Set("minimum", 1)
+					return o, nil
+				}),
+				ByReference("#/items", describeRef),
+			},
+			expect: `{
+				"type":"array",
+				"minItems": 1,
+				"items": {
+					"title": "T",
+					"type":"object",
+					"description": "#/items",
+					"required": ["N", "S"],
+					"properties":{
+						"N":{
+							"type":"number",
+							"minimum": 1,
+							"propertyOrder": 0
+						},
+						"S":{
+							"type":"string",
+							"propertyOrder": 1
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:   "root scalar with options",
+			v:      "example",
+			opts:   []Option{ByReference(RefRoot, describeRef), ByReference("#/items", setDefault)},
+			expect: `{"type":"string","description":"#/"}`,
+		},
+		{
+			name: "string map",
+			v:    map[string]int{"a": 1},
+			expect: `{
+				"type":"object",
+				"additionalProperties": {"type": "number"}
+			}`,
+		},
+		{
+			name: "enum keyed map",
+			v:    map[color]int{"red": 1},
+			expect: `{
+				"type":"object",
+				"additionalProperties": {"type": "number"},
+				"propertyNames": {"enum": ["red", "green"]}
+			}`,
+		},
+		{
+			name: "text marshaler enum keyed map",
+			v:    map[level]bool{1: true, 0: false},
+			expect: `{
+				"type":"object",
+				"additionalProperties": {"type": "boolean"},
+				"propertyNames": {"enum": ["low", "high"]}
+			}`,
+		},
+		{
+			name: "text marshaler keyed map",
+			v:    map[point]string{{X: 1, Y: 2}: "a"},
+			expect: `{
+				"type":"object",
+				"additionalProperties": {"type": "string"}
+			}`,
+		},
+		{
+			name: "map of slice",
+			v:    map[string][]string{},
+			opts: []Option{describeRef},
+			expect: `{
+				"type":"object",
+				"description": "#/",
+				"additionalProperties": {
+					"type": "array",
+					"description": "#/additionalProperties",
+					"items": {"type": "string", "description": "#/additionalProperties/items"}
+				}
+			}`,
+		},
+		{
+			name: "deeply nested containers",
+			v: map[string][]map[string]*T{
+				"a": {{"b": &T{N: 1}}},
+			},
+			opts: []Option{describeRef},
+			expect: `{
+				"type":"object",
+				"description": "#/",
+				"additionalProperties": {
+					"type": "array",
+					"description": "#/additionalProperties",
+					"items": {
+						"type": "object",
+						"description": "#/additionalProperties/items",
+						"additionalProperties": {
+							"title": "T",
+							"type":"object",
+							"description": "#/additionalProperties/items/additionalProperties",
+							"required": ["N", "S"],
+							"properties":{
+								"N":{
+									"type":"number",
+									"propertyOrder": 0,
+									"description": "#/additionalProperties/items/additionalProperties/properties/N"
+								},
+								"S":{
+									"type":"string",
+									"propertyOrder": 1,
+									"description": "#/additionalProperties/items/additionalProperties/properties/S"
+								}
+							}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "empty nested containers",
+			v:    map[string][]map[string]*T{},
+			expect: `{
+				"type":"object",
+				"additionalProperties": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"additionalProperties": {
+							"title": "T",
+							"type":"object",
+							"required": ["N", "S"],
+							"properties":{
+								"N":{
+									"type":"number",
+									"propertyOrder": 0
+								},
+								"S":{
+									"type":"string",
+									"propertyOrder": 1
+								}
+							}
+						}
+					}
+				}
+			}`,
+		},
 		{
 			name: "struct",
 			v:    T{N: 100, S: ""},
@@ -158,6 +420,229 @@ func TestGenerate(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "embedded struct",
+			v:    ET{T: T{N: 100}, B: true},
+			expect: `{
+				"title": "ET",
+				"type":"object",
+				"required": ["N", "S", "B"],
+				"properties":{
+					"N":{
+						"type":"number",
+						"propertyOrder": 0
+					},
+					"S":{
+						"type":"string",
+						"propertyOrder": 1
+					},
+					"B":{
+						"type":"boolean",
+						"propertyOrder": 2
+					}
+				}
+			}`,
+		},
+		{
+			name: "pointer embedded struct",
+			v:    PET{T: &T{N: 100}, B: true},
+			expect: `{
+				"title": "PET",
+				"type":"object",
+				"required": ["N", "S", "B"],
+				"properties":{
+					"N":{
+						"type":"number",
+						"propertyOrder": 0
+					},
+					"S":{
+						"type":"string",
+						"propertyOrder": 1
+					},
+					"B":{
+						"type":"boolean",
+						"propertyOrder": 2
+					}
+				}
+			}`,
+		},
+		{
+			name: "pointer embedded struct with inherited optionality",
+			v:    PET{B: true},
+			opts: []Option{PromotedRequired(EmbedRequiredInherit)},
+			expect: `{
+				"title": "PET",
+				"type":"object",
+				"required": ["B"],
+				"properties":{
+					"N":{
+						"type":"number",
+						"propertyOrder": 0
+					},
+					"S":{
+						"type":"string",
+						"propertyOrder": 1
+					},
+					"B":{
+						"type":"boolean",
+						"propertyOrder": 2
+					}
+				}
+			}`,
+		},
+		{
+			name: "shadowed promoted field",
+			v: struct {
+				T
+				N string
+			}{N: "outer"},
+			expect: `{
+				"type":"object",
+				"required": ["S", "N"],
+				"properties":{
+					"S":{
+						"type":"string",
+						"propertyOrder": 0
+					},
+					"N":{
+						"type":"string",
+						"propertyOrder": 1
+					}
+				}
+			}`,
+		},
+		{
+			name: "secrets",
+			v: struct {
+				User     string
+				Password Password
+				Token    string `jsonschema:"secret"`
+			}{},
+			opts: []Option{setDefault},
+			expect: `{
+				"type":"object",
+				"default": "secret",
+				"examples": ["secret"],
+				"required": ["User", "Password", "Token"],
+				"properties":{
+					"User":{
+						"type":"string",
+						"propertyOrder": 0,
+						"default": "secret",
+						"examples": ["secret"]
+					},
+					"Password":{
+						"type":"string",
+						"propertyOrder": 1,
+						"writeOnly": true
+					},
+					"Token":{
+						"type":"string",
+						"propertyOrder": 2,
+						"writeOnly": true
+					}
+				}
+			}`,
+		},
+		{
+			name: "anonymous struct field",
+			v:    AT{},
+			expect: `{
+				"title": "AT",
+				"type":"object",
+				"required": ["Addr"],
+				"properties":{
+					"Addr":{
+						"type":"object",
+						"propertyOrder": 0,
+						"required": ["Zip", "Geo"],
+						"properties":{
+							"Zip": {"type": "string", "propertyOrder": 0},
+							"Geo": {
+								"type":"object",
+								"propertyOrder": 1,
+								"required": ["Lat", "Lng"],
+								"properties":{
+									"Lat": {"type": "number", "propertyOrder": 0},
+									"Lng": {"type": "number", "propertyOrder": 1}
+								}
+							}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "hoisted anonymous struct field",
+			v:    AT{},
+			opts: []Option{HoistAnonymousStructs()},
+			expect: `{
+				"title": "AT",
+				"type":"object",
+				"required": ["Addr"],
+				"properties":{
+					"Addr":{
+						"$ref": "#/$defs/AT_Addr",
+						"propertyOrder": 0
+					}
+				},
+				"$defs": {
+					"AT_Addr": {
+						"type":"object",
+						"required": ["Zip", "Geo"],
+						"properties":{
+							"Zip": {"type": "string", "propertyOrder": 0},
+							"Geo": {"$ref": "#/$defs/AT_Addr_Geo", "propertyOrder": 1}
+						}
+					},
+					"AT_Addr_Geo": {
+						"type":"object",
+						"required": ["Lat", "Lng"],
+						"properties":{
+							"Lat": {"type": "number", "propertyOrder": 0},
+							"Lng": {"type": "number", "propertyOrder": 1}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "type override",
+			v: struct {
+				Timeout  stringDuration
+				Interval *stringDuration
+				Count    int
+				Level    int `json:"level" jsonschema:"type=integer"`
+			}{Interval: new(stringDuration)},
+			opts: []Option{
+				GoTypeOverride(reflect.TypeOf(stringDuration(0)), "string"),
+				TypeOverride("#/properties/Count", "integer"),
+			},
+			expect: `{
+				"type":"object",
+				"required": ["Timeout", "Count", "level"],
+				"properties":{
+					"Timeout": {"type": "string", "propertyOrder": 0},
+					"Interval": {"type": "string", "propertyOrder": 1},
+					"Count": {"type": "integer", "propertyOrder": 2},
+					"level": {"type": "integer", "propertyOrder": 3}
+				}
+			}`,
+		},
+		{
+			name: "struct type override",
+			v: struct {
+				V version
+			}{},
+			opts: []Option{GoTypeOverride(reflect.TypeOf(version{}), "string")},
+			expect: `{
+				"type":"object",
+				"required": ["V"],
+				"properties": {
+					"V": {"type": "string", "propertyOrder": 0}
+				}
+			}`,
+		},
 		{
 			name: "generator",
 			v: &generator{
@@ -178,7 +663,7 @@ func TestGenerate(t *testing.T) {
 			},
 			expect: `{
 				"type":"object",
-				"required": ["v"],
+				"required": [],
 				"properties": {
 					"v": {
 						"type": "number"
@@ -205,7 +690,7 @@ func TestGenerate(t *testing.T) {
 				}
 			}()
 			var buf bytes.Buffer
-			errCheck(Generate(&buf, tt.v))
+			errCheck(Generate(&buf, tt.v, tt.opts...))
 			got := buf.String()
 
 			if diff := jsonDiff(t, got, tt.expect); diff != "" {
@@ -229,3 +714,863 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateBytes(t *testing.T) {
+	var buf bytes.Buffer
+	errCheck(Generate(&buf, []string{}))
+
+	b, err := GenerateBytes([]string{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if got, want := string(b), buf.String(); got != want {
+		t.Errorf("GenerateBytes() = %q, want %q", got, want)
+	}
+
+	if _, err := GenerateBytes(make(chan int)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestGenerateString(t *testing.T) {
+	got, err := GenerateString(100, describeRef)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if diff := jsonDiff(t, got, `{"type":"number","description":"#/"}`); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestStrictNames(t *testing.T) {
+	cases := []struct {
+		name     string
+		v        interface{}
+		patterns []*regexp.Regexp
+		isErr    bool
+	}{
+		{
+			name: "valid",
+			v: struct {
+				Name string `json:"user_name"`
+				Age  int
+			}{},
+		},
+		{
+			// the field name is used as encoding/json
+			name: "empty",
+			v: struct {
+				Name string `json:""`
+			}{},
+		},
+		{
+			name: "comma",
+			v: struct {
+				Name string `json:",omitempty"`
+			}{},
+		},
+		{
+			name: "invalid UTF-8",
+			v: struct {
+				Name string `json:"\xff"`
+			}{},
+			isErr: true,
+		},
+		{
+			name: "nested",
+			v: struct {
+				Nested struct {
+					Name string `json:"a\"b"`
+				}
+			}{},
+			isErr: true,
+		},
+		{
+			name: "match pattern",
+			v: struct {
+				Name string `json:"user_name"`
+			}{},
+			patterns: []*regexp.Regexp{regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)},
+		},
+		{
+			name: "not match pattern",
+			v: struct {
+				Name string `json:"user-name"`
+			}{},
+			patterns: []*regexp.Regexp{regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)},
+			isErr:    true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateBytes(tt.v, StrictNames(tt.patterns...))
+			switch {
+			case tt.isErr && err == nil:
+				t.Errorf("expected error does not occur")
+			case !tt.isErr && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// names are not validated without StrictNames
+	if _, err := GenerateBytes(struct {
+		Name string `json:","`
+	}{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGenerate_nilElement(t *testing.T) {
+	// null elements are not valid for the generated schema
+	// but schemas of items are generated from their types
+	got, err := GenerateString([][]int{nil, {1}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type":"array",
+		"items": {
+			"type":"array",
+			"items": {"type": "number"}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestGenerate_overrideEmbedded(t *testing.T) {
+	type Base struct {
+		ID   string `json:"id" jsonschema:"format=uuid,description=identifier"`
+		Name string `json:"name" jsonschema:"description=name"`
+	}
+	type Middle struct {
+		Base `jsonschema:"name.description=name of the middle,id.title=ID"`
+	}
+	type Child struct {
+		Middle `jsonschema:"name.description=name of the child"`
+		Age    int `json:"age" jsonschema:"description=age"`
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "inner embedding",
+			v:    Middle{},
+			expect: `{
+				"type": "object",
+				"title": "Middle",
+				"required": ["id", "name"],
+				"properties": {
+					"id": {"type": "string", "format": "uuid", "title": "ID", "description": "identifier", "propertyOrder": 0},
+					"name": {"type": "string", "description": "name of the middle", "propertyOrder": 1}
+				}
+			}`,
+		},
+		{
+			name: "outer embedding wins",
+			v:    Child{},
+			expect: `{
+				"type": "object",
+				"title": "Child",
+				"required": ["id", "name", "age"],
+				"properties": {
+					"id": {"type": "string", "format": "uuid", "title": "ID", "description": "identifier", "propertyOrder": 0},
+					"name": {"type": "string", "description": "name of the child", "propertyOrder": 1},
+					"age": {"type": "number", "description": "age", "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "options win",
+			v:    Child{},
+			opts: []Option{ByReference("#/properties/name", func(o Object) (Object, error) {
+				o.Set("description", "by option")
+				return o, nil
+			})},
+			expect: `{
+				"type": "object",
+				"title": "Child",
+				"required": ["id", "name", "age"],
+				"properties": {
+					"id": {"type": "string", "format": "uuid", "title": "ID", "description": "identifier", "propertyOrder": 0},
+					"name": {"type": "string", "description": "by option", "propertyOrder": 1},
+					"age": {"type": "number", "description": "age", "propertyOrder": 2}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestGenerate_required(t *testing.T) {
+	type T struct {
+		Name     string  `json:"name"`
+		Nickname string  `json:"nickname,omitempty"`
+		Age      *int    `json:"age"`
+		Email    *string `json:"email" required:"true"`
+		Note     string  `json:"note" required:"false"`
+		Group    string  `json:"group,omitempty" required:"true" group:"g"`
+	}
+
+	age, email := 20, "a@example.com"
+	got, err := GenerateString(T{Age: &age, Email: &email})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["name", "email", "g"],
+		"properties": {
+			"name": {"type": "string", "propertyOrder": 0},
+			"nickname": {"type": "string", "propertyOrder": 1},
+			"age": {"type": "number", "propertyOrder": 2},
+			"email": {"type": "string", "propertyOrder": 3},
+			"note": {"type": "string", "propertyOrder": 4},
+			"g": {
+				"type": "object",
+				"propertyOrder": 5,
+				"required": ["group"],
+				"properties": {
+					"group": {"type": "string", "propertyOrder": 0}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	type Invalid struct {
+		Name string `required:"yes"`
+	}
+	if _, err := GenerateString(Invalid{}); !errors.Is(err, ErrTagSyntax) {
+		t.Errorf("want ErrTagSyntax but got %v", err)
+	}
+}
+
+func TestNullablePointers(t *testing.T) {
+	type Node struct {
+		Name string `json:"name"`
+		Next *Node  `json:"next"`
+	}
+	type T struct {
+		Name  *string  `json:"name"`
+		Color *color   `json:"color"`
+		Count *int     `json:"count" jsonschema:"type=integer"`
+		Tags  []string `json:"tags"`
+		Node  *Node    `json:"node"`
+		Plain string   `json:"plain"`
+	}
+
+	name, c, n := "", color("red"), 0
+	v := T{Name: &name, Color: &c, Count: &n, Tags: []string{""}, Node: &Node{}}
+	got, err := GenerateString(v, NullablePointers(), SharedTypes(SharedTypesRecursive))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["tags", "plain"],
+		"$defs": {
+			"Node": {
+				"type": "object",
+				"title": "Node",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string", "propertyOrder": 0},
+					"next": {"oneOf": [{"$ref": "#/$defs/Node"}, {"type": "null"}], "propertyOrder": 1}
+				}
+			}
+		},
+		"properties": {
+			"name": {"type": ["string", "null"], "propertyOrder": 0},
+			"color": {"type": ["string", "null"], "enum": ["red", "green", null], "propertyOrder": 1},
+			"count": {"type": ["integer", "null"], "propertyOrder": 2},
+			"tags": {"type": "array", "items": {"type": "string"}, "propertyOrder": 3},
+			"node": {"oneOf": [{"$ref": "#/$defs/Node"}, {"type": "null"}], "propertyOrder": 4},
+			"plain": {"type": "string", "propertyOrder": 5}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	r, err := s.Validate(gojsonschema.NewStringLoader(`{"name": null, "color": null, "count": null, "tags": [], "node": {"name": "a", "next": null}, "plain": ""}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !r.Valid() {
+		t.Errorf("null values are rejected: %v", r.Errors())
+	}
+}
+
+func TestGenerate_embeddedPromotion(t *testing.T) {
+	type A struct {
+		Name string
+		ID   string
+	}
+	type B struct {
+		Name string
+		ID   string `json:"ID"`
+	}
+	type T struct {
+		A
+		B
+		Age int
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "ambiguous and tagged fields",
+			v:    T{},
+			expect: `{
+				"type": "object",
+				"title": "T",
+				"required": ["ID", "Age"],
+				"properties": {
+					"ID": {"type": "string", "propertyOrder": 0},
+					"Age": {"type": "number", "propertyOrder": 1}
+				}
+			}`,
+		},
+		{
+			name: "nested",
+			v:    T{},
+			opts: []Option{NestEmbeddedStructs()},
+			expect: `{
+				"type": "object",
+				"title": "T",
+				"required": ["A", "B", "Age"],
+				"properties": {
+					"A": {
+						"type": "object",
+						"title": "A",
+						"propertyOrder": 0,
+						"required": ["Name", "ID"],
+						"properties": {
+							"Name": {"type": "string", "propertyOrder": 0},
+							"ID": {"type": "string", "propertyOrder": 1}
+						}
+					},
+					"B": {
+						"type": "object",
+						"title": "B",
+						"propertyOrder": 1,
+						"required": ["Name", "ID"],
+						"properties": {
+							"Name": {"type": "string", "propertyOrder": 0},
+							"ID": {"type": "string", "propertyOrder": 1}
+						}
+					},
+					"Age": {"type": "number", "propertyOrder": 2}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestOrderRequired(t *testing.T) {
+	type Base struct {
+		Name string `json:"name"`
+		ID   string `json:"id"`
+	}
+	type T struct {
+		Zip string `json:"zip"`
+		Base
+		Age  int    `json:"age"`
+		Name string `json:"name"`
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect []string
+	}{
+		{"default", nil, []string{"zip", "id", "age", "name"}},
+		{"declaration", []Option{OrderRequired(RequiredDeclaration)}, []string{"zip", "id", "age", "name"}},
+		{"alphabetical", []Option{OrderRequired(RequiredAlphabetical)}, []string{"age", "id", "name", "zip"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := GenerateBytes(T{}, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			var got struct {
+				Required []string `json:"required"`
+			}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if !reflect.DeepEqual(got.Required, tt.expect) {
+				t.Errorf("want %v but got %v", tt.expect, got.Required)
+			}
+		})
+	}
+}
+
+func TestGenerate_enum(t *testing.T) {
+	type Colors []color
+	type T struct {
+		Color  color    `json:"color"`
+		Colors Colors   `json:"colors"`
+		Sizes  []string `json:"sizes"`
+	}
+
+	v := T{Colors: Colors{"red"}, Sizes: []string{"S"}}
+	got, err := GenerateString(v, ByReference("#/properties/sizes", ItemsEnum(true, "S", "M", "L")))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["color", "colors", "sizes"],
+		"properties": {
+			"color": {"type": "string", "enum": ["red", "green"], "propertyOrder": 0},
+			"colors": {"type": "array", "items": {"type": "string", "enum": ["red", "green"]}, "propertyOrder": 1},
+			"sizes": {"type": "array", "items": {"type": "string", "enum": ["S", "M", "L"]}, "uniqueItems": true, "propertyOrder": 2}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	docs := map[string]bool{
+		`{"color":"red","colors":["green"],"sizes":["S","L"]}`: true,
+		`{"color":"blue","colors":[],"sizes":[]}`:              false,
+		`{"color":"red","colors":["blue"],"sizes":[]}`:         false,
+		`{"color":"red","colors":[],"sizes":["XL"]}`:           false,
+		`{"color":"red","colors":[],"sizes":["S","S"]}`:        false,
+	}
+	for doc, valid := range docs {
+		r, err := s.Validate(gojsonschema.NewStringLoader(doc))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if r.Valid() != valid {
+			t.Errorf("%s: valid = %v, want %v", doc, r.Valid(), valid)
+		}
+	}
+}
+
+func TestGenerate_enumTag(t *testing.T) {
+	type T struct {
+		Size   string   `json:"size" jsonschema:"enum=S|M|L"`
+		Level  int      `json:"level" jsonschema:"enum=1|2|3"`
+		Tags   []string `json:"tags" jsonschema:"enum=a|b"`
+		Color  color    `json:"color" jsonschema:"enum=red"`
+		Status status   `json:"status" jsonschema:"type=integer,enum=0"`
+	}
+
+	got, err := GenerateString(T{Tags: []string{"a"}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["size", "level", "tags", "color", "status"],
+		"properties": {
+			"size": {"type": "string", "enum": ["S", "M", "L"], "propertyOrder": 0},
+			"level": {"type": "number", "enum": [1, 2, 3], "propertyOrder": 1},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}, "propertyOrder": 2},
+			"color": {"type": "string", "enum": ["red"], "propertyOrder": 3},
+			"status": {"type": "integer", "enum": [0], "propertyOrder": 4}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	type Invalid struct {
+		N int `jsonschema:"enum=1|x"`
+	}
+	if _, err := GenerateString(Invalid{}); !errors.Is(err, ErrTagSyntax) {
+		t.Errorf("want ErrTagSyntax but got %v", err)
+	}
+}
+
+func TestGenerate_enumValues(t *testing.T) {
+	type T struct {
+		Status status `json:"status"`
+		Color  color  `json:"color"`
+	}
+
+	got, err := GenerateString(T{}, RegisterEnum(color(""),
+		EnumValue{Value: "red", Name: "ColorRed"},
+		EnumValue{Value: "green", Name: "ColorGreen"},
+	))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["status", "color"],
+		"properties": {
+			"status": {
+				"type": "number",
+				"enum": [0, 1],
+				"x-enum-varnames": ["StatusActive", "StatusDeleted"],
+				"x-enum-descriptions": ["in use", ""],
+				"propertyOrder": 0
+			},
+			"color": {
+				"type": "string",
+				"enum": ["red", "green"],
+				"x-enum-varnames": ["ColorRed", "ColorGreen"],
+				"propertyOrder": 1
+			}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestGenerate_group(t *testing.T) {
+	type T struct {
+		Name   string `json:"name"`
+		Street string `json:"street" group:"address"`
+		City   string `json:"city" group:"address"`
+		Age    int    `json:"age"`
+	}
+
+	got, err := GenerateString(T{}, ByReference("#/properties/address", func(o Object) (Object, error) {
+		o.Set("description", "home address")
+		return o, nil
+	}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["name", "address", "age"],
+		"properties": {
+			"name": {"type": "string", "propertyOrder": 0},
+			"address": {
+				"type": "object",
+				"description": "home address",
+				"required": ["street", "city"],
+				"properties": {
+					"street": {"type": "string", "propertyOrder": 0},
+					"city": {"type": "string", "propertyOrder": 1}
+				},
+				"propertyOrder": 1
+			},
+			"age": {"type": "number", "propertyOrder": 2}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	type Collision struct {
+		Address string `json:"address"`
+		City    string `json:"city" group:"address"`
+	}
+	if _, err := GenerateString(Collision{}); !errors.Is(err, ErrNameCollision) {
+		t.Errorf("want ErrNameCollision but got %v", err)
+	}
+}
+
+func TestGenerate_jsonTag(t *testing.T) {
+	type Base struct {
+		ID string `json:"id"`
+	}
+	type T struct {
+		Base    `json:",omitempty"`
+		Name    string `json:"name,omitempty"`
+		Nick    string `json:",omitempty"`
+		Secret  string `json:"-"`
+		Dash    string `json:"-,"`
+		Comment string `json:"comment,string"`
+		Count   int    `json:"count,string"`
+		hidden  string
+	}
+
+	got, err := GenerateString(T{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["id", "-", "comment", "count"],
+		"properties": {
+			"id": {"type": "string", "propertyOrder": 0},
+			"name": {"type": "string", "propertyOrder": 1},
+			"Nick": {"type": "string", "propertyOrder": 2},
+			"-": {"type": "string", "propertyOrder": 3},
+			"comment": {"type": "string", "propertyOrder": 4},
+			"count": {"type": "string", "propertyOrder": 5}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestGenerate_constraints(t *testing.T) {
+	type Base struct {
+		Code string `json:"code"`
+	}
+	type T struct {
+		Base `jsonschema:"code.pattern=^[A-Z]{2\\,3}$"`
+		ID   string   `json:"id" jsonschema:"minLength=1,maxLength=64,pattern=^[a-z]+$,description=user id"`
+		Age  int      `json:"age" jsonschema:"minimum=0,exclusiveMaximum=150"`
+		Tags []string `json:"tags" jsonschema:"minItems=1,uniqueItems"`
+		Nick string   `json:"nick" jsonschema:"minLength = 2 , maxLength= 3"`
+	}
+
+	got, err := GenerateString(T{Tags: []string{""}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["code", "id", "age", "tags", "nick"],
+		"properties": {
+			"code": {"type": "string", "pattern": "^[A-Z]{2,3}$", "propertyOrder": 0},
+			"id": {"type": "string", "minLength": 1, "maxLength": 64, "pattern": "^[a-z]+$", "description": "user id", "propertyOrder": 1},
+			"age": {"type": "number", "minimum": 0, "exclusiveMaximum": 150, "propertyOrder": 2},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "uniqueItems": true, "propertyOrder": 3},
+			"nick": {"type": "string", "minLength": 2, "maxLength": 3, "propertyOrder": 4}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	// native keys are also available with compatible tags
+	type Compat struct {
+		Born time.Time `json:"born" jsonschema:"layout=2006-01-02,description=birthday"`
+	}
+	compat, err := GenerateString(Compat{}, CompatibleTags())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	native, err := GenerateString(Compat{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if diff := jsonDiff(t, compat, native); diff != "" {
+		t.Errorf("compatible tags change native tags: %v", diff)
+	}
+
+	type Invalid struct {
+		Name string `json:"name" jsonschema:"minLength=short"`
+	}
+	if _, err := GenerateString(Invalid{}); !errors.Is(err, ErrTagSyntax) {
+		t.Errorf("want ErrTagSyntax but got %v", err)
+	}
+}
+
+type jsonTagBase struct {
+	Label string `json:"label"`
+	inner int
+}
+
+func TestGenerate_unexported(t *testing.T) {
+	type T struct {
+		jsonTagBase
+		Name  string `json:"name"`
+		name  string
+		count *int
+	}
+
+	got, err := GenerateString(T{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["label", "name"],
+		"properties": {
+			"label": {"type": "string", "propertyOrder": 0},
+			"name": {"type": "string", "propertyOrder": 1}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+// hexByte is a byte which is marshaled as a hex string, so slices of it are arrays.
+type hexByte byte
+
+func (b hexByte) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%02x", byte(b))), nil
+}
+
+func TestGenerate_bytes(t *testing.T) {
+	type Blob []byte
+	type T struct {
+		Data  []byte    `json:"data"`
+		Blob  Blob      `json:"blob"`
+		Hash  [4]byte   `json:"hash"`
+		Hexes []hexByte `json:"hexes"`
+	}
+	hash := `{"type": "array", "items": {"type": "number"}, "minItems": 4, "maxItems": 4, "propertyOrder": 2}`
+	hexes := `{"type": "array", "items": {"type": "string"}, "propertyOrder": 3}`
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "base64",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["data", "blob", "hash", "hexes"],
+				"properties": {
+					"data": {"type": "string", "contentEncoding": "base64", "propertyOrder": 0},
+					"blob": {"type": "string", "contentEncoding": "base64", "propertyOrder": 1},
+					"hash": ` + hash + `,
+					"hexes": ` + hexes + `
+				}
+			}`,
+		},
+		{
+			name: "arrays",
+			opts: []Option{BytesAsArrays()},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["data", "blob", "hash", "hexes"],
+				"properties": {
+					"data": {"type": "array", "items": {"type": "number"}, "propertyOrder": 0},
+					"blob": {"type": "array", "items": {"type": "number"}, "propertyOrder": 1},
+					"hash": ` + hash + `,
+					"hexes": ` + hexes + `
+				}
+			}`,
+		},
+		{
+			name: "draft-06",
+			opts: []Option{Draft(Draft06)},
+			expect: `{
+				"$schema": "http://json-schema.org/draft-06/schema#",
+				"title": "T",
+				"type": "object",
+				"required": ["data", "blob", "hash", "hexes"],
+				"properties": {
+					"data": {"type": "string", "propertyOrder": 0},
+					"blob": {"type": "string", "propertyOrder": 1},
+					"hash": ` + hash + `,
+					"hexes": ` + hexes + `
+				}
+			}`,
+		},
+		{
+			name: "openapi",
+			opts: []Option{Flavor(OpenAPI3)},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["data", "blob", "hash", "hexes"],
+				"properties": {
+					"data": {"type": "string", "format": "byte"},
+					"blob": {"type": "string", "format": "byte"},
+					"hash": {"type": "array", "items": {"type": "number"}, "minItems": 4, "maxItems": 4},
+					"hexes": {"type": "array", "items": {"type": "string"}}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v := T{Data: []byte{}, Blob: Blob{}, Hexes: []hexByte{}}
+			got, err := GenerateString(v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+
+			// the schema matches the encoding of encoding/json
+			if len(tt.opts) != 0 {
+				return
+			}
+			b, err := json.Marshal(T{Data: []byte("a"), Blob: Blob("b"), Hexes: []hexByte{1}})
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			s, err := CompileBytes([]byte(got))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if err := s.Validate(b); err != nil {
+				t.Errorf("%s is not valid: %v", b, err)
+			}
+		})
+	}
+}
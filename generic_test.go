@@ -0,0 +1,285 @@
+//go:build go1.18
+
+package jsonschema_test
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type genericUser struct {
+	Name string `json:"name"`
+}
+
+type genericOrder struct {
+	ID int `json:"id"`
+}
+
+type genericPage[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next,omitempty"`
+}
+
+type genericResult[T, E any] struct {
+	Value *T `json:"value,omitempty"`
+	Error *E `json:"error,omitempty"`
+}
+
+type genericTree[T any] struct {
+	Value    T                 `json:"value"`
+	Children []*genericTree[T] `json:"children"`
+}
+
+// PublicKey has the same name as rsa.PublicKey.
+type PublicKey struct {
+	PEM string `json:"pem"`
+}
+
+type genericResponse struct {
+	genericPage[genericUser]
+	Total int `json:"total"`
+}
+
+func TestGenerate_generics(t *testing.T) {
+	noOrder := MapKeywords(KeywordRenames{"propertyOrder": ""})
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "title",
+			v:    genericPage[genericUser]{Items: []genericUser{{}}},
+			expect: `{
+				"title": "genericPage[genericUser]",
+				"type": "object",
+				"required": ["items"],
+				"properties": {
+					"items": {
+						"type": "array",
+						"items": {
+							"title": "genericUser",
+							"type": "object",
+							"required": ["name"],
+							"properties": {"name": {"type": "string"}}
+						}
+					},
+					"next": {"type": "string"}
+				}
+			}`,
+		},
+		{
+			name: "definitions of instantiations",
+			v: struct {
+				Users  genericPage[genericUser]                      `json:"users"`
+				Orders genericPage[genericOrder]                     `json:"orders"`
+				Result genericResult[genericUser, map[string]string] `json:"result"`
+			}{},
+			opts: []Option{SharedTypes(SharedTypesRef)},
+			expect: `{
+				"type": "object",
+				"required": ["users", "orders", "result"],
+				"properties": {
+					"users": {"$ref": "#/$defs/genericPage_genericUser"},
+					"orders": {"$ref": "#/$defs/genericPage_genericOrder"},
+					"result": {"$ref": "#/$defs/genericResult_genericUser_map_string_string"}
+				},
+				"$defs": {
+					"genericPage_genericUser": {
+						"title": "genericPage[genericUser]",
+						"type": "object",
+						"required": ["items"],
+						"properties": {
+							"items": {"type": "array", "items": {"$ref": "#/$defs/genericUser"}},
+							"next": {"type": "string"}
+						}
+					},
+					"genericPage_genericOrder": {
+						"title": "genericPage[genericOrder]",
+						"type": "object",
+						"required": ["items"],
+						"properties": {
+							"items": {"type": "array", "items": {"$ref": "#/$defs/genericOrder"}},
+							"next": {"type": "string"}
+						}
+					},
+					"genericResult_genericUser_map_string_string": {
+						"title": "genericResult[genericUser,map[string]string]",
+						"type": "object",
+						"required": [],
+						"properties": {
+							"value": {"$ref": "#/$defs/genericUser"},
+							"error": {"type": "object", "additionalProperties": {"type": "string"}}
+						}
+					},
+					"genericUser": {
+						"title": "genericUser",
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string"}}
+					},
+					"genericOrder": {
+						"title": "genericOrder",
+						"type": "object",
+						"required": ["id"],
+						"properties": {"id": {"type": "number"}}
+					}
+				}
+			}`,
+		},
+		{
+			name: "recursive",
+			v: struct {
+				Tree genericTree[genericUser] `json:"tree"`
+			}{},
+			opts: []Option{SharedTypes(SharedTypesRecursive)},
+			expect: `{
+				"type": "object",
+				"required": ["tree"],
+				"properties": {
+					"tree": {"$ref": "#/$defs/genericTree_genericUser"}
+				},
+				"$defs": {
+					"genericTree_genericUser": {
+						"title": "genericTree[genericUser]",
+						"type": "object",
+						"required": ["value", "children"],
+						"properties": {
+							"value": {
+								"title": "genericUser",
+								"type": "object",
+								"required": ["name"],
+								"properties": {"name": {"type": "string"}}
+							},
+							"children": {"type": "array", "items": {"$ref": "#/$defs/genericTree_genericUser"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "instantiations by types of the same name",
+			v: struct {
+				Keys    genericTree[PublicKey]     `json:"keys"`
+				RSAKeys genericTree[rsa.PublicKey] `json:"rsa_keys"`
+				Backup  genericTree[PublicKey]     `json:"backup"`
+			}{},
+			opts: []Option{SharedTypes(SharedTypesRecursive)},
+			expect: `{
+				"type": "object",
+				"required": ["keys", "rsa_keys", "backup"],
+				"properties": {
+					"keys": {"$ref": "#/$defs/genericTree_PublicKey"},
+					"rsa_keys": {"$ref": "#/$defs/genericTree_rsa_PublicKey"},
+					"backup": {"$ref": "#/$defs/genericTree_PublicKey"}
+				},
+				"$defs": {
+					"genericTree_PublicKey": {
+						"title": "genericTree[PublicKey]",
+						"type": "object",
+						"required": ["value", "children"],
+						"properties": {
+							"value": {
+								"title": "PublicKey",
+								"type": "object",
+								"required": ["pem"],
+								"properties": {"pem": {"type": "string"}}
+							},
+							"children": {"type": "array", "items": {"$ref": "#/$defs/genericTree_PublicKey"}}
+						}
+					},
+					"genericTree_rsa_PublicKey": {
+						"title": "genericTree[PublicKey]",
+						"type": "object",
+						"required": ["value", "children"],
+						"properties": {
+							"value": {
+								"title": "PublicKey",
+								"type": "object",
+								"required": ["E"],
+								"properties": {"N": {"type": "number"}, "E": {"type": "number"}}
+							},
+							"children": {"type": "array", "items": {"$ref": "#/$defs/genericTree_rsa_PublicKey"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "embedded",
+			v:    genericResponse{genericPage: genericPage[genericUser]{Items: []genericUser{{}}}},
+			opts: []Option{SharedTypes(SharedTypesRef)},
+			expect: `{
+				"title": "genericResponse",
+				"type": "object",
+				"required": ["items", "total"],
+				"properties": {
+					"items": {"type": "array", "items": {"$ref": "#/$defs/genericUser"}},
+					"next": {"type": "string"},
+					"total": {"type": "number"}
+				},
+				"$defs": {
+					"genericUser": {
+						"title": "genericUser",
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string"}}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, append([]Option{noOrder}, tt.opts...)...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateAll_generics(t *testing.T) {
+	var buf bytes.Buffer
+	values := []interface{}{
+		genericPage[genericUser]{Items: []genericUser{}},
+		genericPage[genericOrder]{Items: []genericOrder{}},
+	}
+	if err := GenerateAll(&buf, values, OmitGenericTitles(), MapKeywords(KeywordRenames{"propertyOrder": ""})); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"$defs": {
+			"genericPage_genericUser": {
+				"type": "object",
+				"required": ["items"],
+				"properties": {
+					"items": {"type": "array", "items": {"title": "genericUser", "type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}},
+					"next": {"type": "string"}
+				}
+			},
+			"genericPage_genericOrder": {
+				"type": "object",
+				"required": ["items"],
+				"properties": {
+					"items": {"type": "array", "items": {"title": "genericOrder", "type": "object", "required": ["id"], "properties": {"id": {"type": "number"}}}},
+					"next": {"type": "string"}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated bundle does not match to expected one: %v", diff)
+	}
+}
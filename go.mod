@@ -6,4 +6,5 @@ require (
 	github.com/josephburnett/jd v1.5.1
 	github.com/minio/pkg v1.1.15
 	github.com/xeipuuv/gojsonschema v1.2.0
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,377 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoOption is an option of GenerateGo.
+type GoOption func(c *goConfig)
+
+type goConfig struct {
+	pkg  string
+	root string
+}
+
+// GoPackage sets the package name of generated Go code. The default is "schema".
+func GoPackage(name string) GoOption {
+	return func(c *goConfig) {
+		c.pkg = name
+	}
+}
+
+// GoTypeName sets the name of the type of the root schema.
+// The default is the title of the schema or "Root".
+func GoTypeName(name string) GoOption {
+	return func(c *goConfig) {
+		c.root = name
+	}
+}
+
+// commonInitialisms are words which are upper cases in Go names such as "UserID".
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "TCP": true, "UI": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// GenerateGo generates Go type declarations from the JSON Schema document into w.
+// It is the reverse of Generate for schema-first workflows.
+//
+// Objects become structs whose fields have json tags and nested objects become named types
+// such as UserAddress. Definitions in $defs and definitions become types of their names
+// and references to them become the types.
+// Properties which are not required become pointers with omitempty
+// except slices, maps and interfaces, and so do types which allow null.
+// Arrays become slices and objects with additionalProperties become maps.
+// Strings of the date-time format become time.Time.
+// Schemas which cannot be described by Go types such as anyOf become interface{}.
+func GenerateGo(w io.Writer, schema io.Reader, opts ...GoOption) error {
+	var c goConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.pkg == "" {
+		c.pkg = "schema"
+	}
+
+	b, err := io.ReadAll(schema)
+	if err != nil {
+		return err
+	}
+	v, err := decodeJSON(b)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot decode schema: %w", err)
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("jsonschema: schema must be an object")
+	}
+
+	g := &goGen{
+		defs:  map[string]string{},
+		names: map[string]bool{},
+	}
+
+	rootName := c.root
+	if rootName == "" {
+		title, _ := root["title"].(string)
+		rootName = goName(title)
+	}
+	if rootName == "" {
+		rootName = "Root"
+	}
+
+	// definitions are named before generation because they can refer to each other
+	var defNames []string
+	defs := map[string]map[string]interface{}{}
+	for _, key := range []string{"$defs", "definitions"} {
+		m, _ := root[key].(map[string]interface{})
+		for _, k := range sortedKeys(m) {
+			d, ok := m[k].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref := "#/" + key + "/" + escapePointer(k)
+			g.defs[ref] = g.newName(goName(k))
+			defNames = append(defNames, ref)
+			defs[ref] = d
+		}
+	}
+	g.defs["#"] = g.newName(rootName)
+
+	if err := g.namedType(g.defs["#"], root); err != nil {
+		return err
+	}
+	for _, ref := range defNames {
+		if err := g.namedType(g.defs[ref], defs[ref]); err != nil {
+			return err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by jsonschema.GenerateGo. DO NOT EDIT.\n\npackage %s\n\n", c.pkg)
+	if g.usesTime {
+		src.WriteString("import \"time\"\n\n")
+	}
+	src.Write(g.out.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot format generated code: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+type goGen struct {
+	out bytes.Buffer
+	// defs are names of types of references.
+	defs     map[string]string
+	names    map[string]bool
+	usesTime bool
+}
+
+// newName returns a unique name of a type.
+func (g *goGen) newName(name string) string {
+	n := name
+	for i := 2; g.names[n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	g.names[n] = true
+	return n
+}
+
+// namedType declares the type of the name for the schema.
+func (g *goGen) namedType(name string, s map[string]interface{}) error {
+	var decl bytes.Buffer
+	writeGoDoc(&decl, name, s)
+
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		fields, err := g.structFields(name, s, props)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&decl, "type %s struct {\n%s}\n\n", name, fields)
+	} else {
+		typ, err := g.goType(name, s)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&decl, "type %s %s\n\n", name, typ)
+	}
+
+	// nested types are declared before
+	g.out.Write(decl.Bytes())
+	return nil
+}
+
+// structFields returns fields of the struct of the properties.
+func (g *goGen) structFields(typeName string, s, props map[string]interface{}) (string, error) {
+	required := map[string]bool{}
+	if rs, ok := s["required"].([]interface{}); ok {
+		for _, r := range rs {
+			if r, ok := r.(string); ok {
+				required[r] = true
+			}
+		}
+	}
+
+	names := sortedKeys(props)
+	order := func(k string) float64 {
+		p, _ := props[k].(map[string]interface{})
+		if n, ok := p["propertyOrder"].(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return f
+			}
+		}
+		return float64(len(names))
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return order(names[i]) < order(names[j])
+	})
+
+	var b strings.Builder
+	used := map[string]bool{}
+	for _, k := range names {
+		p, ok := props[k].(map[string]interface{})
+		if !ok {
+			p = map[string]interface{}{}
+		}
+
+		field := goName(k)
+		if field == "" {
+			field = "Field"
+		}
+		for i := 2; used[field]; i++ {
+			field = goName(k) + strconv.Itoa(i)
+		}
+		used[field] = true
+
+		typ, err := g.goType(typeName+field, p)
+		if err != nil {
+			return "", err
+		}
+		tag := k
+		if !required[k] || allowsNull(p) {
+			if !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "interface{}" && !strings.HasPrefix(typ, "*") {
+				typ = "*" + typ
+			}
+			tag += ",omitempty"
+		}
+
+		var doc bytes.Buffer
+		writeGoDoc(&doc, "", p)
+		fmt.Fprintf(&b, "%s%s %s `json:%s`\n", doc.String(), field, typ, strconv.Quote(tag))
+	}
+	return b.String(), nil
+}
+
+// goType returns a Go type of the schema.
+// name is used for a nested type.
+func (g *goGen) goType(name string, s map[string]interface{}) (string, error) {
+	if ref, ok := s["$ref"].(string); ok {
+		if t, ok := g.defs[ref]; ok {
+			return t, nil
+		}
+		return "", newError(ErrRefInvalid, ref, fmt.Errorf("reference %s cannot be resolved", ref))
+	}
+
+	switch schemaType(s) {
+	case "string":
+		if s["format"] == "date-time" {
+			g.usesTime = true
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		return "int64", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		items, ok := s["items"].(map[string]interface{})
+		if !ok {
+			return "[]interface{}", nil
+		}
+		elem, err := g.goType(name+"Item", items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object":
+		if _, ok := s["properties"].(map[string]interface{}); ok {
+			n := g.newName(name)
+			if err := g.namedType(n, s); err != nil {
+				return "", err
+			}
+			return n, nil
+		}
+		if ap, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			elem, err := g.goType(name+"Value", ap)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+		return "map[string]interface{}", nil
+	}
+	return "interface{}", nil
+}
+
+// schemaType returns a type of the schema except null.
+// It returns an empty string if the schema has more than one types.
+func schemaType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		var typ string
+		for _, e := range t {
+			if e == "null" {
+				continue
+			}
+			if typ != "" {
+				return ""
+			}
+			typ, _ = e.(string)
+		}
+		return typ
+	}
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// allowsNull reports whether the type of the schema has null.
+func allowsNull(s map[string]interface{}) bool {
+	ts, _ := s["type"].([]interface{})
+	for _, t := range ts {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGoDoc writes a doc comment from the description of the schema.
+func writeGoDoc(w *bytes.Buffer, name string, s map[string]interface{}) {
+	desc, _ := s["description"].(string)
+	if desc == "" {
+		return
+	}
+	for i, line := range strings.Split(strings.TrimSpace(desc), "\n") {
+		if i == 0 && name != "" {
+			line = name + " is " + lowerFirst(line)
+		}
+		fmt.Fprintf(w, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+func lowerFirst(s string) string {
+	rs := []rune(s)
+	if len(rs) > 1 && unicode.IsUpper(rs[1]) {
+		// keep initialisms such as "ID"
+		return s
+	}
+	if len(rs) > 0 {
+		rs[0] = unicode.ToLower(rs[0])
+	}
+	return string(rs)
+}
+
+// goName converts a name of a property such as "user_id" into an exported Go name such as "UserID".
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, w := range words {
+		// split camel cases such as "userId"
+		for _, p := range strings.Fields(Humanize(w)) {
+			if commonInitialisms[strings.ToUpper(p)] {
+				b.WriteString(strings.ToUpper(p))
+				continue
+			}
+			rs := []rune(p)
+			rs[0] = unicode.ToUpper(rs[0])
+			b.WriteString(string(rs))
+		}
+	}
+
+	n := b.String()
+	if n != "" && !unicode.IsLetter([]rune(n)[0]) {
+		n = "X" + n
+	}
+	return n
+}
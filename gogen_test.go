@@ -0,0 +1,89 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateGo(t *testing.T) {
+	schema := `{
+		"title": "user",
+		"description": "A user of the service.",
+		"type": "object",
+		"required": ["id", "name", "address", "tags"],
+		"properties": {
+			"id": {"type": "integer", "propertyOrder": 0},
+			"name": {"type": "string", "description": "Name of the user.", "propertyOrder": 1},
+			"nickname": {"type": ["string", "null"]},
+			"score": {"type": "number"},
+			"active": {"type": "boolean"},
+			"created_at": {"type": "string", "format": "date-time"},
+			"address": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string"}}
+			},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"groups": {"type": "array", "items": {"$ref": "#/$defs/group"}},
+			"manager": {"$ref": "#"},
+			"extra": {"anyOf": [{"type": "string"}, {"type": "number"}]}
+		},
+		"$defs": {
+			"group": {"type": "object", "properties": {"groupId": {"type": "string"}}},
+			"status": {"type": "string", "enum": ["active", "inactive"]}
+		}
+	}`
+
+	var buf bytes.Buffer
+	if err := GenerateGo(&buf, strings.NewReader(schema), GoPackage("model")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := "// Code generated by jsonschema.GenerateGo. DO NOT EDIT.\n\n" +
+		"package model\n\n" +
+		"import \"time\"\n\n" +
+		"type UserAddress struct {\n" +
+		"\tCity string  `json:\"city\"`\n" +
+		"\tZip  *string `json:\"zip,omitempty\"`\n" +
+		"}\n\n" +
+		"// User is a user of the service.\n" +
+		"type User struct {\n" +
+		"\tID int64 `json:\"id\"`\n" +
+		"\t// Name of the user.\n" +
+		"\tName      string            `json:\"name\"`\n" +
+		"\tActive    *bool             `json:\"active,omitempty\"`\n" +
+		"\tAddress   UserAddress       `json:\"address\"`\n" +
+		"\tCreatedAt *time.Time        `json:\"created_at,omitempty\"`\n" +
+		"\tExtra     interface{}       `json:\"extra,omitempty\"`\n" +
+		"\tGroups    []Group           `json:\"groups,omitempty\"`\n" +
+		"\tLabels    map[string]string `json:\"labels,omitempty\"`\n" +
+		"\tManager   *User             `json:\"manager,omitempty\"`\n" +
+		"\tNickname  *string           `json:\"nickname,omitempty\"`\n" +
+		"\tScore     *float64          `json:\"score,omitempty\"`\n" +
+		"\tTags      []string          `json:\"tags\"`\n" +
+		"}\n\n" +
+		"type Group struct {\n" +
+		"\tGroupID *string `json:\"groupId,omitempty\"`\n" +
+		"}\n\n" +
+		"type Status string\n"
+	if got := buf.String(); got != expect {
+		t.Errorf("want\n%s\nbut got\n%s", expect, got)
+	}
+}
+
+func TestGenerateGo_error(t *testing.T) {
+	var buf bytes.Buffer
+	err := GenerateGo(&buf, strings.NewReader(`{"properties": {"a": {"$ref": "other.json"}}}`))
+	if !errors.Is(err, ErrRefInvalid) {
+		t.Errorf("want ErrRefInvalid but got %v", err)
+	}
+
+	if err := GenerateGo(&buf, strings.NewReader(`[]`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
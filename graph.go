@@ -0,0 +1,117 @@
+package jsonschema
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GraphFormat is a format of graphs which are exported by ExportGraph.
+type GraphFormat int
+
+const (
+	// GraphDOT is the DOT language of Graphviz such as
+	//
+	//	digraph schemas {
+	//		"Order" -> "User";
+	//	}
+	GraphDOT GraphFormat = iota
+	// GraphJSON is a JSON object of adjacency lists such as {"Order": ["User"], "User": []}.
+	GraphJSON
+)
+
+// ExportGraph writes a graph of references between schemas of the values into w in the format.
+// Nodes are schemas of the values and their definitions which are generated as Bundle,
+// and an edge from a node to another node means that the schema refers to the other schema by $ref.
+// It is useful to visualize and audit large bundles, e.g. by dot -Tsvg.
+// The values are named by their types as GenerateAll.
+func ExportGraph(w io.Writer, format GraphFormat, values []interface{}, opts ...Option) error {
+	b := NewBundle(opts...)
+	for _, v := range values {
+		t, err := bundleType(v)
+		if err != nil {
+			return err
+		}
+		if err := b.Add(typeName(t), v); err != nil {
+			return err
+		}
+	}
+	return b.ExportGraph(w, format)
+}
+
+// ExportGraph writes a graph of references between schemas of the bundle into w in the format
+// as same as ExportGraph.
+func (b *Bundle) ExportGraph(w io.Writer, format GraphFormat) error {
+	bd, err := b.bundle()
+	if err != nil {
+		return err
+	}
+	return bd.graph().write(w, format)
+}
+
+// schemaGraph is adjacency lists of definitions.
+type schemaGraph map[string][]string
+
+// graph returns the graph of references between definitions of the bundle.
+// References to other documents are not edges.
+func (b bundle) graph() schemaGraph {
+	const prefix = "#/$defs/"
+	g := schemaGraph{}
+	for name, def := range b {
+		seen := map[string]bool{}
+		to := []string{}
+		rewriteRefs(def, func(ref string) string {
+			if !strings.HasPrefix(ref, prefix) {
+				return ref
+			}
+			token := strings.SplitN(strings.TrimPrefix(ref, prefix), "/", 2)[0]
+			target := splitPointer("/" + token)[0]
+			if _, ok := b[target]; ok && !seen[target] {
+				seen[target] = true
+				to = append(to, target)
+			}
+			return ref
+		})
+		sort.Strings(to)
+		g[name] = to
+	}
+	return g
+}
+
+// write writes the graph in the format.
+func (g schemaGraph) write(w io.Writer, format GraphFormat) error {
+	switch format {
+	case GraphDOT:
+		bw := bufio.NewWriter(w)
+		fmt.Fprintln(bw, "digraph schemas {")
+		for _, name := range sortedNames(g) {
+			if len(g[name]) == 0 {
+				fmt.Fprintf(bw, "\t%s;\n", strconv.Quote(name))
+			}
+			for _, to := range g[name] {
+				fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(name), strconv.Quote(to))
+			}
+		}
+		fmt.Fprintln(bw, "}")
+		return bw.Flush()
+	case GraphJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	}
+	return fmt.Errorf("jsonschema: unknown graph format %d", format)
+}
+
+// sortedNames returns names of nodes of the graph in alphabetical order.
+func sortedNames(g schemaGraph) []string {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
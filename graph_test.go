@@ -0,0 +1,97 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type graphNode struct {
+	Children []*graphNode   `json:"children"`
+	Owner    bundleCustomer `json:"owner"`
+}
+
+func TestExportGraph(t *testing.T) {
+	values := []interface{}{bundleOrder{}, &graphNode{}}
+
+	cases := []struct {
+		name   string
+		format GraphFormat
+		expect string
+	}{
+		{
+			name:   "dot",
+			format: GraphDOT,
+			expect: `digraph schemas {
+	"bundleAddress";
+	"bundleCustomer" -> "bundleAddress";
+	"bundleOrder" -> "bundleAddress";
+	"bundleOrder" -> "bundleCustomer";
+	"graphNode" -> "bundleCustomer";
+	"graphNode" -> "graphNode";
+}
+`,
+		},
+		{
+			name:   "json",
+			format: GraphJSON,
+			expect: `{
+  "bundleAddress": [],
+  "bundleCustomer": [
+    "bundleAddress"
+  ],
+  "bundleOrder": [
+    "bundleAddress",
+    "bundleCustomer"
+  ],
+  "graphNode": [
+    "bundleCustomer",
+    "graphNode"
+  ]
+}
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := ExportGraph(&buf, tt.format, values); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got := buf.String(); got != tt.expect {
+				t.Errorf("want graph\n%s\nbut got\n%s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestExportGraph_errors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportGraph(&buf, GraphDOT, []interface{}{nil}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want %v but got %v", ErrUnsupportedType, err)
+	}
+	if err := ExportGraph(&buf, GraphFormat(-1), []interface{}{bundleOrder{}}); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestBundle_ExportGraph(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestBundle(t).ExportGraph(&buf, GraphDOT); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `digraph schemas {
+	"Order" -> "bundleAddress";
+	"Order" -> "bundleCustomer";
+	"bundleAddress";
+	"bundleCustomer" -> "bundleAddress";
+}
+`
+	if got := buf.String(); got != expect {
+		t.Errorf("want graph\n%s\nbut got\n%s", expect, got)
+	}
+}
@@ -0,0 +1,90 @@
+package jsonschema
+
+import "reflect"
+
+// HAL augments schemas of the struct type t with _links and _embedded properties
+// of HAL (JSON Hypertext Application Language).
+// rels are names of link relations such as "self" which _links must have.
+// If t is nil, schemas of all struct types are augmented.
+// Options for a specific type have priority over options for all types.
+func HAL(t reflect.Type, rels ...string) Option {
+	return configOption(func(c *config) {
+		if c.hal == nil {
+			c.hal = map[reflect.Type][]string{}
+		}
+		c.hal[t] = rels
+	})
+}
+
+// halRels returns link relations for the struct type t.
+func (c *config) halRels(t reflect.Type) ([]string, bool) {
+	if rels, ok := c.hal[t]; ok {
+		return rels, true
+	}
+	rels, ok := c.hal[nil]
+	return rels, ok
+}
+
+// halLinks returns a schema of _links whose relations include rels.
+func halLinks(rels []string) map[string]interface{} {
+	link := func() map[string]interface{} {
+		return map[string]interface{}{
+			"type":     "object",
+			"required": []string{"href"},
+			"properties": map[string]interface{}{
+				"href":        map[string]interface{}{"type": "string"},
+				"templated":   map[string]interface{}{"type": "boolean"},
+				"type":        map[string]interface{}{"type": "string"},
+				"deprecation": map[string]interface{}{"type": "string", "format": "uri"},
+				"name":        map[string]interface{}{"type": "string"},
+				"profile":     map[string]interface{}{"type": "string", "format": "uri"},
+				"title":       map[string]interface{}{"type": "string"},
+				"hreflang":    map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+
+	linkOrLinks := func() map[string]interface{} {
+		return map[string]interface{}{
+			"anyOf": []interface{}{
+				link(),
+				map[string]interface{}{
+					"type":  "array",
+					"items": link(),
+				},
+			},
+		}
+	}
+
+	properties := make(map[string]interface{}, len(rels))
+	for _, rel := range rels {
+		properties[rel] = linkOrLinks()
+	}
+
+	links := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": linkOrLinks(),
+	}
+	if len(rels) != 0 {
+		links["required"] = rels
+	}
+
+	return links
+}
+
+// halEmbedded returns a schema of _embedded.
+func halEmbedded() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"additionalProperties": map[string]interface{}{
+			"anyOf": []interface{}{
+				map[string]interface{}{"type": "object"},
+				map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+	}
+}
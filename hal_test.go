@@ -0,0 +1,80 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestHAL(t *testing.T) {
+	type Author struct {
+		Name string `json:"name"`
+	}
+	type Order struct {
+		Total  int    `json:"total"`
+		Author Author `json:"author"`
+	}
+
+	cases := []struct {
+		name  string
+		opts  []Option
+		doc   string
+		valid bool
+	}{
+		{
+			name:  "valid links",
+			opts:  []Option{HAL(reflect.TypeOf(Order{}), "self")},
+			doc:   `{"total":1,"author":{"name":"a"},"_links":{"self":{"href":"/orders/1"},"items":[{"href":"/items/1"}]},"_embedded":{"items":[{"id":1}]}}`,
+			valid: true,
+		},
+		{
+			name:  "missing required relation",
+			opts:  []Option{HAL(reflect.TypeOf(Order{}), "self")},
+			doc:   `{"total":1,"author":{"name":"a"},"_links":{"next":{"href":"/orders/2"}}}`,
+			valid: false,
+		},
+		{
+			name:  "link without href",
+			opts:  []Option{HAL(reflect.TypeOf(Order{}))},
+			doc:   `{"total":1,"author":{"name":"a"},"_links":{"self":{"title":"order"}}}`,
+			valid: false,
+		},
+		{
+			name:  "not augmented type",
+			opts:  []Option{HAL(reflect.TypeOf(Order{}), "self")},
+			doc:   `{"total":1,"author":{"name":"a","_links":1}}`,
+			valid: true,
+		},
+		{
+			name:  "all types",
+			opts:  []Option{HAL(nil, "self"), HAL(reflect.TypeOf(Order{}))},
+			doc:   `{"total":1,"author":{"name":"a","_links":{}}}`,
+			valid: false,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(Order{}, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			r, err := s.Validate(gojsonschema.NewStringLoader(tt.doc))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if r.Valid() != tt.valid {
+				t.Errorf("valid = %v, want %v: %v", r.Valid(), tt.valid, r.Errors())
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// DocTemplate is default template which renders documentation of a JSON Schema.
+// It is executed with a *Doc.
+var DocTemplate = template.Must(template.New("doc").Parse(`
+<html>
+	<head>
+		<title>{{if .Title}}{{.Title}}{{else}}JSON Schema{{end}}</title>
+	</head>
+	<body>
+		<h1>{{if .Title}}{{.Title}}{{else}}JSON Schema{{end}}</h1>
+		{{with .Description}}<p>{{.}}</p>{{end}}
+		{{template "node" .Root}}
+	</body>
+</html>
+{{define "node"}}
+		<table>
+			<tr><th>Name</th><th>Type</th><th>Required</th><th>Description</th></tr>
+			{{range .Properties}}
+			<tr>
+				<td><code>{{.Name}}</code></td>
+				<td>{{.Type}}</td>
+				<td>{{if .Required}}yes{{end}}</td>
+				<td>{{.Description}}{{if .Properties}}{{template "node" .}}{{end}}</td>
+			</tr>
+			{{end}}
+		</table>
+{{end}}`))
+
+// Doc is data for a documentation template.
+type Doc struct {
+	Title       string
+	Description string
+	Root        *DocNode
+	// Schema is the decoded schema.
+	Schema map[string]interface{}
+}
+
+// DocNode is a property of a schema in documentation.
+type DocNode struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+	Properties  []*DocNode
+}
+
+// RenderDoc renders documentation of the schema with the template.
+// If the template is nil, DocTemplate is used.
+func RenderDoc(w io.Writer, schema []byte, tmpl *template.Template) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(schema, &m); err != nil {
+		return err
+	}
+
+	if tmpl == nil {
+		tmpl = DocTemplate
+	}
+
+	doc := &Doc{
+		Title:       str(m["title"]),
+		Description: str(m["description"]),
+		Root:        docNode("", m, false),
+		Schema:      m,
+	}
+
+	return tmpl.Execute(w, doc)
+}
+
+func docNode(name string, m map[string]interface{}, required bool) *DocNode {
+	n := &DocNode{
+		Name:        name,
+		Type:        docType(m),
+		Required:    required,
+		Description: str(m["description"]),
+	}
+
+	// properties of array items are documented as properties of the array
+	target := m
+	if items, ok := m["items"].(map[string]interface{}); ok {
+		target = items
+	}
+
+	requiredSet := map[string]bool{}
+	if rs, ok := target["required"].([]interface{}); ok {
+		for _, r := range rs {
+			requiredSet[str(r)] = true
+		}
+	}
+
+	props, _ := target["properties"].(map[string]interface{})
+	for _, k := range sortedKeys(props) {
+		if p, ok := props[k].(map[string]interface{}); ok {
+			n.Properties = append(n.Properties, docNode(k, p, requiredSet[k]))
+		}
+	}
+
+	return n
+}
+
+func docType(m map[string]interface{}) string {
+	var typ string
+	switch t := m["type"].(type) {
+	case string:
+		typ = t
+	case []interface{}:
+		ts := make([]string, len(t))
+		for i := range t {
+			ts[i] = str(t[i])
+		}
+		typ = strings.Join(ts, " | ")
+	default:
+		if ref, ok := m["$ref"].(string); ok {
+			typ = ref
+		}
+	}
+
+	if items, ok := m["items"].(map[string]interface{}); ok {
+		typ = fmt.Sprintf("%s of %s", typ, docType(items))
+	}
+
+	if format, ok := m["format"].(string); ok {
+		typ = fmt.Sprintf("%s (%s)", typ, format)
+	}
+
+	return typ
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
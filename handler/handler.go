@@ -9,8 +9,9 @@ import (
 )
 
 type Options struct {
-	JSON     string
-	Template *template.Template
+	JSON        string
+	Template    *template.Template
+	DocTemplate *template.Template
 }
 
 type Option func(o *Options) error
@@ -22,6 +23,15 @@ func WithTemplate(tmpl *template.Template) Option {
 	}
 }
 
+// WithDocTemplate sets a template which renders documentation of a schema.
+// The template is executed with a *Doc.
+func WithDocTemplate(tmpl *template.Template) Option {
+	return func(o *Options) error {
+		o.DocTemplate = tmpl
+		return nil
+	}
+}
+
 func WithJSON(r io.Reader) Option {
 	return func(o *Options) error {
 		b, err := ioutil.ReadAll(r)
@@ -35,15 +45,10 @@ func WithJSON(r io.Reader) Option {
 
 // New creates a new http.Handler which provides editor of schema.
 func New(schema io.Reader, options ...Option) (http.Handler, error) {
-	var data struct {
-		Schema string
-		JSON   string
-	}
 	b, err := ioutil.ReadAll(schema)
 	if err != nil {
 		return nil, err
 	}
-	data.Schema = string(b)
 
 	var opts Options
 	for _, o := range options {
@@ -51,6 +56,16 @@ func New(schema io.Reader, options ...Option) (http.Handler, error) {
 			return nil, err
 		}
 	}
+
+	return editor(string(b), &opts), nil
+}
+
+func editor(schema string, opts *Options) http.Handler {
+	var data struct {
+		Schema string
+		JSON   string
+	}
+	data.Schema = schema
 	data.JSON = opts.JSON
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -63,7 +78,7 @@ func New(schema io.Reader, options ...Option) (http.Handler, error) {
 			status := http.StatusInternalServerError
 			http.Error(w, err.Error(), status)
 		}
-	}), nil
+	})
 }
 
 // PostToWriter copies POST request body to the writer w with a new line.
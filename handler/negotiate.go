@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Media types which a schema handler can serve.
+const (
+	MediaTypeSchemaJSON = "application/schema+json"
+	MediaTypeJSON       = "application/json"
+	MediaTypeYAML       = "application/yaml"
+	MediaTypeHTML       = "text/html"
+)
+
+// offers are media types in order of preference.
+var offers = []string{
+	MediaTypeSchemaJSON,
+	MediaTypeJSON,
+	MediaTypeYAML,
+	MediaTypeHTML,
+}
+
+// aliases are other names of offered media types.
+var aliases = map[string]string{
+	"application/x-yaml": MediaTypeYAML,
+	"text/yaml":          MediaTypeYAML,
+	"text/x-yaml":        MediaTypeYAML,
+}
+
+// NewSchema creates a new http.Handler which serves the schema.
+// The handler negotiates a representation by Accept header:
+// application/schema+json (default), application/json, application/yaml and
+// text/html which is documentation rendered by DocTemplate.
+func NewSchema(schema io.Reader, options ...Option) (http.Handler, error) {
+	b, err := ioutil.ReadAll(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts Options
+	for _, o := range options {
+		if err := o(&opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveSchema(w, r, b, &opts)
+	}), nil
+}
+
+// Negotiate returns the most acceptable media type for the Accept header
+// from application/schema+json, application/json, application/yaml and text/html.
+// If no media type is acceptable, it returns an empty string.
+func Negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	type mediaRange struct {
+		typ   string
+		q     float64
+		order int
+	}
+
+	var ranges []mediaRange
+	for i, s := range strings.Split(accept, ",") {
+		typ, params, err := mime.ParseMediaType(strings.TrimSpace(s))
+		if err != nil {
+			continue
+		}
+		if alias, ok := aliases[typ]; ok {
+			typ = alias
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, q: q, order: i})
+	}
+
+	// more specific ranges has priority to decide quality of an offer
+	specificity := func(typ string) int {
+		switch {
+		case typ == "*/*":
+			return 0
+		case strings.HasSuffix(typ, "/*"):
+			return 1
+		}
+		return 2
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, spec := -1.0, -1
+		for _, r := range ranges {
+			if !matchMediaRange(r.typ, offer) {
+				continue
+			}
+			if s := specificity(r.typ); s > spec {
+				q, spec = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+func matchMediaRange(r, typ string) bool {
+	switch {
+	case r == "*/*":
+		return true
+	case strings.HasSuffix(r, "/*"):
+		return strings.HasPrefix(typ, strings.TrimSuffix(r, "*"))
+	}
+	return r == typ
+}
+
+func serveSchema(w http.ResponseWriter, r *http.Request, schema []byte, opts *Options) {
+	w.Header().Add("Vary", "Accept")
+
+	switch typ := Negotiate(r.Header.Get("Accept")); typ {
+	case MediaTypeSchemaJSON, MediaTypeJSON:
+		w.Header().Set("Content-Type", typ)
+		if _, err := w.Write(schema); err != nil {
+			status := http.StatusInternalServerError
+			http.Error(w, err.Error(), status)
+		}
+	case MediaTypeYAML:
+		b, err := toYAML(schema)
+		if err != nil {
+			status := http.StatusInternalServerError
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.Header().Set("Content-Type", typ)
+		if _, err := w.Write(b); err != nil {
+			status := http.StatusInternalServerError
+			http.Error(w, err.Error(), status)
+		}
+	case MediaTypeHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := RenderDoc(w, schema, opts.DocTemplate); err != nil {
+			status := http.StatusInternalServerError
+			http.Error(w, err.Error(), status)
+		}
+	default:
+		status := http.StatusNotAcceptable
+		http.Error(w, http.StatusText(status), status)
+	}
+}
+
+func toYAML(schema []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(schema, &v); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
+// sortedKeys returns keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tenntenn/jsonschema/handler"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", handler.MediaTypeSchemaJSON},
+		{"*/*", handler.MediaTypeSchemaJSON},
+		{"application/json", handler.MediaTypeJSON},
+		{"application/x-yaml", handler.MediaTypeYAML},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", handler.MediaTypeHTML},
+		{"application/yaml;q=0.5, text/html;q=0.4", handler.MediaTypeYAML},
+		{"application/*;q=0.5, application/yaml", handler.MediaTypeYAML},
+		{"text/*, application/*;q=0.1", handler.MediaTypeHTML},
+		{"image/png", ""},
+		{"*/*, application/schema+json;q=0", handler.MediaTypeJSON},
+	}
+
+	for _, tt := range cases {
+		if got := handler.Negotiate(tt.accept); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestNewSchema(t *testing.T) {
+	schema := `{"title":"User","type":"object","required":["name"],"properties":{"name":{"type":"string","description":"name of the user"}}}`
+	h, err := handler.NewSchema(strings.NewReader(schema))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		accept      string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"", 200, handler.MediaTypeSchemaJSON, `"title":"User"`},
+		{"application/yaml", 200, handler.MediaTypeYAML, "title: User"},
+		{"text/html", 200, "text/html; charset=utf-8", "name of the user"},
+		{"image/png", 406, "text/plain; charset=utf-8", ""},
+	}
+
+	for _, tt := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.status {
+			t.Errorf("status for %q = %d, want %d", tt.accept, w.Code, tt.status)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("Content-Type for %q = %q, want %q", tt.accept, got, tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("body for %q does not contain %q: %s", tt.accept, tt.contains, w.Body)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/tenntenn/jsonschema"
+)
+
+// NewRegistry creates a new http.Handler which serves schemas in the registry.
+// GET / returns a list of registered schemas and
+// GET /{name}/{version} returns the schema in a representation which is negotiated
+// in the same manner as NewSchema.
+// If the request has "editor" query parameter, it provides editor of the schema
+// as same as a handler which is created by New.
+// Because the handler reads the registry on each request,
+// regenerated schemas are served immediately.
+func NewRegistry(reg *jsonschema.Registry, options ...Option) (http.Handler, error) {
+	var opts Options
+	for _, o := range options {
+		if err := o(&opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			status := http.StatusMethodNotAllowed
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		p := strings.Trim(r.URL.Path, "/")
+		if p == "" {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(reg.List()); err != nil {
+				status := http.StatusInternalServerError
+				http.Error(w, err.Error(), status)
+			}
+			return
+		}
+
+		name, version := path2key(p)
+		schema, ok := reg.Get(name, version)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		if _, ok := r.URL.Query()["editor"]; ok {
+			editor(string(schema), &opts).ServeHTTP(w, r)
+			return
+		}
+
+		serveSchema(w, r, schema, &opts)
+	}), nil
+}
+
+// path2key splits a path into a name and a version.
+// The last element of the path is the version.
+func path2key(p string) (name, version string) {
+	i := strings.LastIndex(p, "/")
+	if i < 0 {
+		return p, ""
+	}
+	return p[:i], p[i+1:]
+}
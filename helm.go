@@ -0,0 +1,71 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// GenerateHelmSchema generates values.schema.json of a Helm chart from the values struct v into w.
+// v is usually the default values of the chart, whose values are emitted as defaults
+// by Defaults(DefaultOmitZero).
+//
+// The schema targets draft-07, which Helm supports, and keywords which are not in draft-07
+// such as propertyOrder are removed. Helm validates values after merging them with values.yaml
+// of the chart, so properties which have defaults are not required
+// and only properties without defaults remain in required.
+// The options are applied after Defaults and Draft, so they can change the policy.
+func GenerateHelmSchema(w io.Writer, v interface{}, opts ...Option) error {
+	schema, err := GenerateBytes(v, append([]Option{Defaults(DefaultOmitZero), Draft(Draft07)}, opts...)...)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return err
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("jsonschema: values.schema.json must be an object")
+	}
+
+	walkSubschemas(root, func(s map[string]interface{}) {
+		for k := range s {
+			if !Draft07.Supports(k) {
+				delete(s, k)
+			}
+		}
+		helmRequired(s)
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// helmRequired removes properties which have defaults from required of the schema.
+func helmRequired(s map[string]interface{}) {
+	required, ok := s["required"].([]interface{})
+	if !ok {
+		return
+	}
+	props, _ := s["properties"].(map[string]interface{})
+
+	names := []interface{}{}
+	for _, r := range required {
+		name, _ := r.(string)
+		if p, ok := props[name].(map[string]interface{}); ok {
+			if _, ok := p["default"]; ok {
+				continue
+			}
+		}
+		names = append(names, r)
+	}
+
+	if len(names) == 0 {
+		delete(s, "required")
+		return
+	}
+	s["required"] = names
+}
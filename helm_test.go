@@ -0,0 +1,61 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestGenerateHelmSchema(t *testing.T) {
+	type Image struct {
+		Repository string `json:"repository"`
+		Tag        string `json:"tag"`
+	}
+	type Values struct {
+		ReplicaCount int    `json:"replicaCount" jsonschema:"minimum=1"`
+		Image        Image  `json:"image"`
+		Host         string `json:"host"`
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateHelmSchema(&buf, Values{ReplicaCount: 1, Image: Image{Repository: "nginx"}}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"title": "Values",
+		"required": ["image", "host"],
+		"properties": {
+			"replicaCount": {"type": "number", "minimum": 1, "default": 1},
+			"image": {
+				"type": "object",
+				"title": "Image",
+				"required": ["tag"],
+				"properties": {
+					"repository": {"type": "string", "default": "nginx"},
+					"tag": {"type": "string"}
+				}
+			},
+			"host": {"type": "string"}
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated values.schema.json does not match to expected one: %v", diff)
+	}
+
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(buf.Bytes()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	r, err := s.Validate(gojsonschema.NewStringLoader(`{"replicaCount": 2, "image": {"tag": "1.0"}, "host": "a"}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !r.Valid() {
+		t.Errorf("valid values are rejected: %v", r.Errors())
+	}
+}
@@ -0,0 +1,146 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// implementations are registered implementations of an interface type.
+type implementations struct {
+	values []interface{}
+	// discriminator is a name of the property which identifies implementations.
+	discriminator string
+}
+
+// RegisterImplementations registers concrete types of the interface type of iface
+// such as RegisterImplementations((*Shape)(nil), Circle{}, Square{}),
+// so objects of the interface type are generated as oneOf of schemas of the implementations
+// instead of ErrUnsupportedType. iface must be a nil pointer to the interface type
+// and each value must implement it. Subschemas of oneOf are referred by references such as "#/properties/shape/oneOf/0".
+// Registrations for the same interface type are appended.
+func RegisterImplementations(iface interface{}, impls ...interface{}) Option {
+	it, err := interfaceType(iface)
+	if err != nil {
+		return errOption(err)
+	}
+	for _, v := range impls {
+		t := reflect.TypeOf(v)
+		if t == nil || !t.Implements(it) {
+			return errOption(newError(ErrUnsupportedType, "", fmt.Errorf("%v does not implement %v", t, it)))
+		}
+	}
+
+	return configOption(func(c *config) {
+		impl := c.implementation(it)
+		impl.values = append(impl.values, impls...)
+	})
+}
+
+// Discriminator identifies implementations of the interface type of iface by the property,
+// which is a const of the name of each type such as {"kind": {"const": "Circle"}}.
+// The property is required and its schema is merged into a property of the same name
+// if the implementation has it. Schemas of implementations which are referred by $ref
+// are put into allOf with the property.
+func Discriminator(iface interface{}, property string) Option {
+	it, err := interfaceType(iface)
+	if err != nil {
+		return errOption(err)
+	}
+	return configOption(func(c *config) {
+		c.implementation(it).discriminator = property
+	})
+}
+
+// interfaceType returns the interface type of a nil pointer to it.
+func interfaceType(iface interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return nil, newError(ErrUnsupportedType, "", fmt.Errorf("%v is not a pointer to an interface type", t))
+	}
+	return t.Elem(), nil
+}
+
+// implementation returns registered implementations of the interface type and creates them if needed.
+func (c *config) implementation(t reflect.Type) *implementations {
+	if c.implementations == nil {
+		c.implementations = map[reflect.Type]*implementations{}
+	}
+	impl, ok := c.implementations[t]
+	if !ok {
+		impl = &implementations{}
+		c.implementations[t] = impl
+	}
+	return impl
+}
+
+// implementationsHash returns hashable forms of registered implementations.
+func (c *config) implementationsHash() map[reflect.Type][]string {
+	m := make(map[reflect.Type][]string, len(c.implementations))
+	for t, impl := range c.implementations {
+		names := []string{impl.discriminator}
+		for _, v := range impl.values {
+			names = append(names, typeHash(reflect.TypeOf(v)))
+		}
+		m[t] = names
+	}
+	return m
+}
+
+// implementationsGen generates the object of the interface type t as oneOf of its implementations.
+// It reports false if no implementations are registered.
+func (g *gen) implementationsGen(o Object, t reflect.Type, options []Option) (bool, error) {
+	impl, ok := g.cfg.implementations[t]
+	if !ok || len(impl.values) == 0 {
+		return false, nil
+	}
+	if g.plan != nil {
+		g.plan.note(o.Ref(), "generated by %d implementations of %s", len(impl.values), t)
+	}
+
+	oneOf := make([]interface{}, len(impl.values))
+	for i, v := range impl.values {
+		sub := &obj{
+			m:   map[string]interface{}{},
+			ref: g.cfg.refs().Join(o.Ref(), "oneOf", strconv.Itoa(i)),
+		}
+		if err := g.do(sub, reflect.ValueOf(v), options...); err != nil {
+			return true, err
+		}
+		if impl.discriminator != "" {
+			setDiscriminator(sub, impl.discriminator, indirectType(reflect.TypeOf(v)).Name())
+		}
+		oneOf[i] = sub.m
+	}
+	o.Set("oneOf", oneOf)
+
+	return true, g.applyOptions(o, options)
+}
+
+// setDiscriminator sets the required property whose value is the name.
+func setDiscriminator(o *obj, property, name string) {
+	if ref, ok := o.m["$ref"]; ok {
+		delete(o.m, "$ref")
+		appendAllOf(o.m, map[string]interface{}{"$ref": ref})
+	}
+
+	props, _ := o.m["properties"].(map[string]interface{})
+	if props == nil {
+		props = map[string]interface{}{}
+		o.m["properties"] = props
+	}
+	prop, _ := props[property].(map[string]interface{})
+	if prop == nil {
+		prop = map[string]interface{}{}
+		props[property] = prop
+	}
+	prop["const"] = name
+
+	required, _ := o.m["required"].([]string)
+	for _, r := range required {
+		if r == property {
+			return
+		}
+	}
+	o.m["required"] = append(required, property)
+}
@@ -0,0 +1,167 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type implShape interface {
+	Area() float64
+}
+
+type implCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (implCircle) Area() float64 { return 0 }
+
+type implSquare struct {
+	Kind string  `json:"kind" jsonschema:"description=kind of the shape"`
+	Side float64 `json:"side"`
+}
+
+func (*implSquare) Area() float64 { return 0 }
+
+func TestRegisterImplementations(t *testing.T) {
+	type Drawing struct {
+		Shape  implShape   `json:"shape"`
+		Shapes []implShape `json:"shapes"`
+	}
+	shapes := RegisterImplementations((*implShape)(nil), implCircle{}, &implSquare{})
+
+	circle := `{
+		"title": "implCircle",
+		"type": "object",
+		"required": ["radius"],
+		"properties": {"radius": {"type": "number", "propertyOrder": 0}}
+	}`
+	square := `{
+		"title": "implSquare",
+		"type": "object",
+		"required": ["kind", "side"],
+		"properties": {
+			"kind": {"type": "string", "description": "kind of the shape", "propertyOrder": 0},
+			"side": {"type": "number", "propertyOrder": 1}
+		}
+	}`
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+		kind   error
+	}{
+		{
+			name: "oneOf",
+			opts: []Option{shapes},
+			expect: `{
+				"title": "Drawing",
+				"type": "object",
+				"required": ["shape", "shapes"],
+				"properties": {
+					"shape": {"oneOf": [` + circle + `, ` + square + `], "propertyOrder": 0},
+					"shapes": {"type": "array", "items": {"oneOf": [` + circle + `, ` + square + `]}, "propertyOrder": 1}
+				}
+			}`,
+		},
+		{
+			name: "discriminator",
+			opts: []Option{shapes, Discriminator((*implShape)(nil), "kind"), ByReference("#/properties/shapes", func(o Object) (Object, error) {
+				o.Delete("items")
+				return o, nil
+			})},
+			expect: `{
+				"title": "Drawing",
+				"type": "object",
+				"required": ["shape", "shapes"],
+				"properties": {
+					"shape": {
+						"oneOf": [
+							{
+								"title": "implCircle",
+								"type": "object",
+								"required": ["radius", "kind"],
+								"properties": {
+									"radius": {"type": "number", "propertyOrder": 0},
+									"kind": {"const": "implCircle"}
+								}
+							},
+							{
+								"title": "implSquare",
+								"type": "object",
+								"required": ["kind", "side"],
+								"properties": {
+									"kind": {"type": "string", "description": "kind of the shape", "const": "implSquare", "propertyOrder": 0},
+									"side": {"type": "number", "propertyOrder": 1}
+								}
+							}
+						],
+						"propertyOrder": 0
+					},
+					"shapes": {"type": "array", "propertyOrder": 1}
+				}
+			}`,
+		},
+		{
+			name: "shared types",
+			opts: []Option{shapes, Discriminator((*implShape)(nil), "type"), SharedTypes(SharedTypesRef), ByReference("#/properties/shapes", func(o Object) (Object, error) {
+				o.Delete("items")
+				return o, nil
+			})},
+			expect: `{
+				"title": "Drawing",
+				"type": "object",
+				"required": ["shape", "shapes"],
+				"properties": {
+					"shape": {
+						"oneOf": [
+							{"allOf": [{"$ref": "#/$defs/implCircle"}], "required": ["type"], "properties": {"type": {"const": "implCircle"}}},
+							{"allOf": [{"$ref": "#/$defs/implSquare"}], "required": ["type"], "properties": {"type": {"const": "implSquare"}}}
+						],
+						"propertyOrder": 0
+					},
+					"shapes": {"type": "array", "propertyOrder": 1}
+				},
+				"$defs": {
+					"implCircle": ` + circle + `,
+					"implSquare": ` + square + `
+				}
+			}`,
+		},
+		{
+			name: "no implementations",
+			kind: ErrUnsupportedType,
+		},
+		{
+			name: "not implemented",
+			opts: []Option{RegisterImplementations((*implShape)(nil), implSquare{})},
+			kind: ErrUnsupportedType,
+		},
+		{
+			name: "not interface",
+			opts: []Option{RegisterImplementations(implCircle{}, implCircle{})},
+			kind: ErrUnsupportedType,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(Drawing{Shape: implCircle{}, Shapes: []implShape{}}, tt.opts...)
+			switch {
+			case tt.kind != nil:
+				if !errors.Is(err, tt.kind) {
+					t.Fatalf("want %v but got %v", tt.kind, err)
+				}
+				return
+			case err != nil:
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
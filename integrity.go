@@ -0,0 +1,176 @@
+package jsonschema
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IntegrityKey is a key of integrity metadata which is embedded by Integrity option.
+const IntegrityKey = "x-integrity"
+
+// Integrity embeds integrity metadata into the root of the generated schema:
+//
+//	"x-integrity": {"digest": "sha256:..."}
+//
+// The digest is computed from the canonicalized schema without the metadata.
+// It can be verified by VerifyIntegrity.
+func Integrity() Option {
+	return configOption(func(c *config) {
+		c.integrity = true
+	})
+}
+
+// Canonicalize returns the canonical form of a JSON document:
+// keys of objects are sorted and insignificant whitespaces are removed.
+// Numbers are kept as they are written.
+func Canonicalize(doc []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return canonicalize(v)
+}
+
+func canonicalize(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Digest returns a SHA-256 digest of the canonicalized document
+// in the form of "sha256:<hex>".
+func Digest(doc []byte) (string, error) {
+	b, err := Canonicalize(doc)
+	if err != nil {
+		return "", err
+	}
+	return digest(b), nil
+}
+
+func digest(canonical []byte) string {
+	sum := sha256.Sum256(canonical)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ErrIntegrity is returned when integrity of a schema cannot be verified.
+var ErrIntegrity = errors.New("jsonschema: integrity check failed")
+
+// VerifyIntegrity verifies the integrity metadata which is embedded by Integrity option.
+func VerifyIntegrity(schema []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(schema))
+	dec.UseNumber()
+
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+
+	meta, ok := m[IntegrityKey].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: no %s", ErrIntegrity, IntegrityKey)
+	}
+	want, _ := meta["digest"].(string)
+	delete(m, IntegrityKey)
+
+	b, err := canonicalize(m)
+	if err != nil {
+		return err
+	}
+
+	if got := digest(b); got != want {
+		return fmt.Errorf("%w: digest is %s but %s is embedded", ErrIntegrity, got, want)
+	}
+
+	return nil
+}
+
+// setIntegrity embeds integrity metadata into m.
+func setIntegrity(m map[string]interface{}) error {
+	delete(m, IntegrityKey)
+
+	// normalize values which are set by options through JSON
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	canonical, err := Canonicalize(b)
+	if err != nil {
+		return err
+	}
+
+	m[IntegrityKey] = map[string]interface{}{
+		"digest": digest(canonical),
+	}
+	return nil
+}
+
+// Sign creates a detached signature of the document with the signer.
+// The signature is a base64 encoded signature of the SHA-256 digest of the document as it is,
+// which is compatible with signatures of "cosign sign-blob".
+// ECDSA, RSA and Ed25519 keys are supported.
+func Sign(doc []byte, signer crypto.Signer) ([]byte, error) {
+	var (
+		sig []byte
+		err error
+	)
+
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		sig, err = signer.Sign(rand.Reader, doc, crypto.Hash(0))
+	default:
+		sum := sha256.Sum256(doc)
+		sig, err = signer.Sign(rand.Reader, sum[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(sig)), nil
+}
+
+// Verify verifies a detached signature which is created by Sign.
+func Verify(doc, signature []byte, pub crypto.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrIntegrity, err)
+	}
+
+	sum := sha256.Sum256(doc)
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, sum[:], sig) {
+			return fmt.Errorf("%w: invalid signature", ErrIntegrity)
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig); err != nil {
+			return fmt.Errorf("%w: %v", ErrIntegrity, err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, doc, sig) {
+			return fmt.Errorf("%w: invalid signature", ErrIntegrity)
+		}
+	default:
+		return fmt.Errorf("jsonschema: unsupported public key %T", pub)
+	}
+
+	return nil
+}
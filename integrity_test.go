@@ -0,0 +1,146 @@
+package jsonschema_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"sorted keys", `{"b":1, "a":{"d":true,"c":null}}`, `{"a":{"c":null,"d":true},"b":1}`},
+		{"numbers", `[1.50, 1e3]`, `[1.50,1e3]`},
+		{"html", `{"a":"<&>"}`, `{"a":"<&>"}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize([]byte(tt.doc))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("want %s but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIntegrity(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	schema, err := GenerateBytes(T{}, Integrity())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(schema, &m); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	delete(m, IntegrityKey)
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	want, err := Digest(b)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !strings.Contains(string(schema), want) {
+		t.Errorf("digest %s is not embedded: %s", want, schema)
+	}
+
+	if err := VerifyIntegrity(schema); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	tampered := strings.Replace(string(schema), `"name"`, `"names"`, 1)
+	if err := VerifyIntegrity([]byte(tampered)); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("want ErrIntegrity but got %v", err)
+	}
+
+	plain, err := GenerateBytes(T{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := VerifyIntegrity(plain); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("want ErrIntegrity but got %v", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		signer crypto.Signer
+	}{
+		{"ecdsa", ecKey},
+		{"rsa", rsaKey},
+		{"ed25519", edKey},
+	}
+
+	doc := []byte(`{"type":"string"}` + "\n")
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := Sign(doc, tt.signer)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if err := Verify(doc, sig, tt.signer.Public()); err != nil {
+				t.Error("unexpected error:", err)
+			}
+
+			if err := Verify([]byte(`{}`), sig, tt.signer.Public()); !errors.Is(err, ErrIntegrity) {
+				t.Errorf("want ErrIntegrity but got %v", err)
+			}
+		})
+	}
+
+	// signature of cosign sign-blob is an ASN.1 ECDSA signature of SHA-256 digest
+	sig, err := Sign(doc, ecKey)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	raw := make([]byte, len(sig))
+	n, err := base64.StdEncoding.Decode(raw, sig)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	sum := sha256.Sum256(doc)
+	if !ecdsa.VerifyASN1(&ecKey.PublicKey, sum[:], raw[:n]) {
+		t.Error("signature is not compatible with cosign")
+	}
+}
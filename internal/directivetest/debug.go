@@ -0,0 +1,10 @@
+//go:build debug
+// +build debug
+
+package directivetest
+
+// Debug is a type whose directives are given only with the debug tag.
+type Debug struct {
+	//jsonschema: description="debug only"
+	Trace string `json:"trace"`
+}
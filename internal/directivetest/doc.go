@@ -0,0 +1,9 @@
+// Package directivetest has types whose directive comments depend on build constraints.
+// It is used by tests of directives.
+package directivetest
+
+// Config is a type which is also declared in the other package with the same name.
+type Config struct {
+	//jsonschema: minLength=1
+	Name string `json:"name"`
+}
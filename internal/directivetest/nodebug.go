@@ -0,0 +1,9 @@
+//go:build !debug
+// +build !debug
+
+package directivetest
+
+// Debug is a type whose directives are given only with the debug tag.
+type Debug struct {
+	Trace string `json:"trace"`
+}
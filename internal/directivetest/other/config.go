@@ -0,0 +1,8 @@
+// Package directivetest has the same name as its parent package to test directives of them.
+package directivetest
+
+// Config is a type which is also declared in the parent package with the same name.
+type Config struct {
+	//jsonschema: minLength=5
+	Name string `json:"name"`
+}
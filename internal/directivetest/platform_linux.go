@@ -0,0 +1,7 @@
+package directivetest
+
+// Platform is a type which is declared for each platform.
+type Platform struct {
+	//jsonschema: description="path of the socket"
+	Socket string `json:"socket"`
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package directivetest
+
+// Platform is a type which is declared for each platform.
+type Platform struct {
+	//jsonschema: description="name of the pipe"
+	Socket string `json:"socket"`
+}
@@ -0,0 +1,276 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// GenerateJSONAPI generates a schema of a JSON:API document whose primary data is
+// a resource which is represented by the struct v.
+// Fields of the struct are tagged in the same manner as github.com/google/jsonapi:
+//
+//	type Article struct {
+//		ID     string  `jsonapi:"primary,articles"`
+//		Title  string  `jsonapi:"attr,title"`
+//		Author *Person `jsonapi:"relation,author"`
+//	}
+//
+// A primary field becomes the id and gives the type of the resource.
+// If there is no primary field, a field named ID is used and the type is
+// the lower-cased name of the struct.
+// Relation fields become relationships and other fields become attributes.
+// A name of an attribute is given by the jsonapi tag or the json tag
+// and attributes which have omitempty options are not required.
+//
+// References of objects are same as the document such as
+// "#/properties/data/properties/attributes/properties/title".
+func GenerateJSONAPI(w io.Writer, v interface{}, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv = reflect.Zero(rv.Type().Elem())
+			continue
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return newError(ErrUnsupportedType, RefRoot, fmt.Errorf("resource of JSON:API must be a struct: nil"))
+	}
+	if rv.Kind() != reflect.Struct {
+		return newError(ErrUnsupportedType, RefRoot, fmt.Errorf("resource of JSON:API must be a struct: %v", rv.Type()))
+	}
+
+	g := gen{cfg: newConfig(opts)}
+	root := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+
+	data := &obj{
+		m:   map[string]interface{}{},
+		ref: g.cfg.refs().Join(root.ref, "properties", "data"),
+	}
+	if err := g.jsonAPIResource(data, rv, opts); err != nil {
+		return err
+	}
+
+	root.Set("type", "object")
+	root.Set("required", []string{"data"})
+	root.Set("properties", map[string]interface{}{
+		"data":  data.m,
+		"meta":  map[string]interface{}{"type": "object"},
+		"links": map[string]interface{}{"type": "object"},
+	})
+
+	if err := g.applyOptions(root, opts); err != nil {
+		return err
+	}
+	if err := g.finish(root); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(root.m)
+}
+
+func (g *gen) jsonAPIResource(o *obj, v reflect.Value, options []Option) error {
+	attrs := &obj{
+		m:   map[string]interface{}{},
+		ref: g.cfg.refs().Join(o.ref, "properties", "attributes"),
+	}
+	rels := &obj{
+		m:   map[string]interface{}{},
+		ref: g.cfg.refs().Join(o.ref, "properties", "relationships"),
+	}
+
+	attrProps := map[string]interface{}{}
+	attrRequired := []string{}
+	relProps := map[string]interface{}{}
+
+	for i := 0; i < v.NumField(); i++ {
+		f, ft := v.Field(i), v.Type().Field(i)
+		if ft.PkgPath != "" {
+			continue
+		}
+
+		kind, name := jsonAPITag(ft)
+		switch kind {
+		case "primary":
+			continue
+		case "":
+			if ft.Name == "ID" {
+				continue
+			}
+		case "relation":
+			rel := &obj{
+				m:   map[string]interface{}{},
+				ref: g.cfg.refs().Join(rels.ref, "properties", name),
+			}
+			jsonAPIRelationship(rel, ft.Type)
+			if err := g.applyOptions(rel, options); err != nil {
+				return err
+			}
+			relProps[name] = rel.m
+			continue
+		}
+
+		optional := jsonAPIOmitEmpty(ft)
+		if name == "" {
+			name = ft.Name
+			tag := parseJSONTag(ft.Tag.Get("json"))
+			if tag.ignored {
+				continue
+			}
+			if tag.name != "" {
+				name = tag.name
+			}
+			optional = optional || tag.omitEmpty
+		}
+
+		attr := &obj{
+			m:   map[string]interface{}{},
+			ref: g.cfg.refs().Join(attrs.ref, "properties", name),
+		}
+		if isNil(f) {
+			f = empty(f.Type())
+		}
+		if err := g.do(attr, f, options...); err != nil {
+			return err
+		}
+		attrProps[name] = attr.m
+		if !optional {
+			attrRequired = append(attrRequired, name)
+		}
+	}
+
+	attrs.Set("type", "object")
+	attrs.Set("required", attrRequired)
+	attrs.Set("properties", attrProps)
+	if err := g.applyOptions(attrs, options); err != nil {
+		return err
+	}
+
+	rels.Set("type", "object")
+	rels.Set("properties", relProps)
+	if err := g.applyOptions(rels, options); err != nil {
+		return err
+	}
+
+	o.Set("type", "object")
+	if title := g.cfg.titleOf(v.Type()); title != "" {
+		o.Set("title", title)
+	}
+	o.Set("required", []string{"type", "id"})
+	o.Set("properties", map[string]interface{}{
+		"type": map[string]interface{}{
+			"type":  "string",
+			"const": jsonAPIType(v.Type()),
+		},
+		"id": map[string]interface{}{
+			"type": "string",
+		},
+		"attributes":    attrs.m,
+		"relationships": rels.m,
+		"links":         map[string]interface{}{"type": "object"},
+		"meta":          map[string]interface{}{"type": "object"},
+	})
+
+	return g.applyOptions(o, options)
+}
+
+// jsonAPIRelationship sets a relationship object whose resource linkage is
+// a resource identifier of the type t or an array of them.
+func jsonAPIRelationship(o Object, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var linkage map[string]interface{}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		linkage = map[string]interface{}{
+			"type":  "array",
+			"items": jsonAPIIdentifier(t.Elem()),
+		}
+	default:
+		linkage = map[string]interface{}{
+			"anyOf": []interface{}{
+				jsonAPIIdentifier(t),
+				map[string]interface{}{"type": "null"},
+			},
+		}
+	}
+
+	o.Set("type", "object")
+	o.Set("required", []string{"data"})
+	o.Set("properties", map[string]interface{}{
+		"data":  linkage,
+		"links": map[string]interface{}{"type": "object"},
+		"meta":  map[string]interface{}{"type": "object"},
+	})
+}
+
+func jsonAPIIdentifier(t reflect.Type) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"type", "id"},
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{
+				"type":  "string",
+				"const": jsonAPIType(t),
+			},
+			"id": map[string]interface{}{
+				"type": "string",
+			},
+		},
+	}
+}
+
+// jsonAPITag returns the kind and the name in the jsonapi tag of the field.
+func jsonAPITag(f reflect.StructField) (kind, name string) {
+	tag, ok := f.Tag.Lookup("jsonapi")
+	if !ok {
+		return "", ""
+	}
+	items := strings.Split(tag, ",")
+	if len(items) < 2 {
+		return items[0], ""
+	}
+	return items[0], items[1]
+}
+
+// jsonAPIOmitEmpty reports whether the jsonapi tag has the omitempty option
+// such as `jsonapi:"attr,title,omitempty"`.
+func jsonAPIOmitEmpty(f reflect.StructField) bool {
+	items := strings.Split(f.Tag.Get("jsonapi"), ",")
+	if len(items) < 3 {
+		return false
+	}
+	for _, item := range items[2:] {
+		if item == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonAPIType returns the resource type of the struct type t.
+func jsonAPIType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if kind, name := jsonAPITag(t.Field(i)); kind == "primary" && name != "" {
+				return name
+			}
+		}
+	}
+
+	return strings.ToLower(t.Name())
+}
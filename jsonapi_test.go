@@ -0,0 +1,100 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestGenerateJSONAPI(t *testing.T) {
+	type Person struct {
+		ID   string `jsonapi:"primary,people"`
+		Name string `jsonapi:"attr,name"`
+	}
+
+	type Comment struct {
+		ID   string
+		Body string `json:"body"`
+	}
+
+	type Article struct {
+		ID       string     `jsonapi:"primary,articles"`
+		Title    string     `jsonapi:"attr,title"`
+		Views    int        `json:"views"`
+		Summary  string     `jsonapi:"attr,summary,omitempty"`
+		Draft    bool       `json:"draft,omitempty"`
+		Author   *Person    `jsonapi:"relation,author"`
+		Comments []*Comment `jsonapi:"relation,comments"`
+	}
+
+	var buf bytes.Buffer
+	titleRef := ByReference("#/properties/data/properties/attributes/properties/title", describeRef)
+	if err := GenerateJSONAPI(&buf, &Article{}, titleRef); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if !strings.Contains(buf.String(), `"description":"#/properties/data/properties/attributes/properties/title"`) {
+		t.Errorf("option is not applied: %s", buf.String())
+	}
+
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(buf.Bytes()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		doc   string
+		valid bool
+	}{
+		{`{
+			"data": {
+				"type": "articles",
+				"id": "1",
+				"attributes": {"title": "JSON:API", "views": 10},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "9"}},
+					"comments": {"data": [{"type": "comment", "id": "5"}]}
+				}
+			}
+		}`, true},
+		{`{
+			"data": {
+				"type": "articles",
+				"id": "1",
+				"attributes": {"title": "JSON:API", "views": 10},
+				"relationships": {"author": {"data": null}}
+			}
+		}`, true},
+		{`{"data": {"type": "people", "id": "1", "attributes": {"title": "JSON:API", "views": 10}}}`, false},
+		{`{"data": {"type": "articles", "id": "1", "attributes": {"title": 1, "views": 10}}}`, false},
+		{`{"data": {
+			"type": "articles", "id": "1",
+			"attributes": {"title": "JSON:API", "views": 10},
+			"relationships": {"author": {"data": {"type": "comment", "id": "9"}}}
+		}}`, false},
+		{`{"data": {"type": "articles", "id": "1", "attributes": {"title": "JSON:API", "views": 10, "summary": "s", "draft": true}}}`, true},
+		{`{"data": {"type": "articles", "id": "1", "attributes": {"views": 10}}}`, false},
+		{`{}`, false},
+	}
+
+	for _, tt := range cases {
+		r, err := s.Validate(gojsonschema.NewStringLoader(tt.doc))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if r.Valid() != tt.valid {
+			t.Errorf("valid of %s = %v, want %v: %v", tt.doc, r.Valid(), tt.valid, r.Errors())
+		}
+	}
+
+	if err := GenerateJSONAPI(&buf, 100); err == nil {
+		t.Error("expected error does not occur")
+	}
+	if err := GenerateJSONAPI(&buf, nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want ErrUnsupportedType but got %v", err)
+	}
+}
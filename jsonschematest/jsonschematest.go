@@ -0,0 +1,194 @@
+// Package jsonschematest provides utilities for testing generated JSON Schemas.
+//
+// Check verifies that a schema generated with custom options is a valid schema
+// of its draft and accepts the Go value which it is generated from.
+// RunSuite runs test vectors in the format of the official JSON-Schema-Test-Suite
+// (https://github.com/json-schema-org/JSON-Schema-Test-Suite) with a Validator.
+// Golden compares generated schemas with golden files.
+package jsonschematest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// UpdateEnv is a name of an environment variable.
+// If it is set to "1", Golden updates golden files instead of comparing them.
+const UpdateEnv = "JSONSCHEMA_UPDATE_GOLDEN"
+
+// Validator validates a JSON document against a JSON Schema.
+type Validator interface {
+	// Validate reports whether the document is valid.
+	// It returns an error if the schema or the document cannot be used.
+	Validate(schema, doc []byte) (bool, error)
+}
+
+// ValidatorFunc is a function which implements Validator.
+type ValidatorFunc func(schema, doc []byte) (bool, error)
+
+// Validate implements Validator.
+func (f ValidatorFunc) Validate(schema, doc []byte) (bool, error) {
+	return f(schema, doc)
+}
+
+// DefaultValidator is a Validator which uses github.com/xeipuuv/gojsonschema.
+var DefaultValidator Validator = ValidatorFunc(func(schema, doc []byte) (bool, error) {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return false, err
+	}
+	r, err := s.Validate(gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		return false, err
+	}
+	return r.Valid(), nil
+})
+
+// ValidSchema reports an error if the schema is not valid against the meta-schema of its draft.
+func ValidSchema(schema []byte) error {
+	sl := gojsonschema.NewSchemaLoader()
+	sl.Validate = true
+	if _, err := sl.Compile(gojsonschema.NewBytesLoader(schema)); err != nil {
+		return fmt.Errorf("jsonschematest: invalid schema: %w", err)
+	}
+	return nil
+}
+
+// Check generates a schema from v with the options and tests that
+// the schema is valid against the meta-schema and v is valid against the schema.
+// It returns the generated schema.
+func Check(t testing.TB, v interface{}, opts ...jsonschema.Option) []byte {
+	t.Helper()
+
+	schema, err := jsonschema.GenerateBytes(v, opts...)
+	if err != nil {
+		t.Fatal("cannot generate schema:", err)
+	}
+
+	if err := ValidSchema(schema); err != nil {
+		t.Fatalf("%v\n%s", err, schema)
+	}
+
+	doc, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal("cannot marshal value:", err)
+	}
+
+	valid, err := DefaultValidator.Validate(schema, doc)
+	switch {
+	case err != nil:
+		t.Fatal("cannot validate value:", err)
+	case !valid:
+		t.Errorf("%s is not valid against the generated schema %s", doc, schema)
+	}
+
+	return schema
+}
+
+// TestGroup is a group of test cases in the format of JSON-Schema-Test-Suite.
+type TestGroup struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []TestCase      `json:"tests"`
+}
+
+// TestCase is a test case in the format of JSON-Schema-Test-Suite.
+type TestCase struct {
+	Description string          `json:"description"`
+	Data        json.RawMessage `json:"data"`
+	Valid       bool            `json:"valid"`
+}
+
+// RunSuite runs all test vectors in JSON files of fsys with the validator.
+// JSON files must be in the format of JSON-Schema-Test-Suite,
+// such as tests/draft7/*.json of the suite.
+// If the validator is nil, DefaultValidator is used.
+func RunSuite(t *testing.T, fsys fs.FS, v Validator) {
+	t.Helper()
+
+	if v == nil {
+		v = DefaultValidator
+	}
+
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		t.Fatal("cannot find test files:", err)
+	}
+
+	for _, file := range files {
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			t.Fatal("cannot read test file:", err)
+		}
+
+		var groups []TestGroup
+		if err := json.Unmarshal(b, &groups); err != nil {
+			t.Fatalf("cannot decode %s: %v", file, err)
+		}
+
+		t.Run(path.Base(file), func(t *testing.T) {
+			for _, g := range groups {
+				g := g
+				t.Run(g.Description, func(t *testing.T) {
+					for _, tc := range g.Tests {
+						valid, err := v.Validate(g.Schema, tc.Data)
+						switch {
+						case err != nil:
+							t.Errorf("%s: unexpected error: %v", tc.Description, err)
+						case valid != tc.Valid:
+							t.Errorf("%s: valid = %v, want %v", tc.Description, valid, tc.Valid)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+// Golden generates a schema from v with the options and compares it with
+// the golden file testdata/name.golden.json.
+// If the environment variable JSONSCHEMA_UPDATE_GOLDEN is "1",
+// the golden file is updated by the generated schema.
+func Golden(t testing.TB, name string, v interface{}, opts ...jsonschema.Option) {
+	t.Helper()
+
+	schema, err := jsonschema.GenerateBytes(v, opts...)
+	if err != nil {
+		t.Fatal("cannot generate schema:", err)
+	}
+
+	var got bytes.Buffer
+	if err := json.Indent(&got, schema, "", "\t"); err != nil {
+		t.Fatal("cannot indent schema:", err)
+	}
+
+	file := filepath.Join("testdata", name+".golden.json")
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal("cannot create directory:", err)
+		}
+		if err := ioutil.WriteFile(file, got.Bytes(), 0o644); err != nil {
+			t.Fatal("cannot update golden file:", err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("cannot read golden file (set %s=1 to create): %v", UpdateEnv, err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("generated schema does not match to %s\ngot:\n%s\nwant:\n%s", file, got.Bytes(), want)
+	}
+}
@@ -0,0 +1,53 @@
+package jsonschematest_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tenntenn/jsonschema"
+	"github.com/tenntenn/jsonschema/jsonschematest"
+)
+
+type Address struct {
+	Zip  string `json:"zip"`
+	City string `json:"city"`
+}
+
+type User struct {
+	Name      string            `json:"name"`
+	Age       int               `json:"age"`
+	CreatedAt time.Time         `json:"created_at"`
+	Address   Address           `json:"address"`
+	Tags      []string          `json:"tags"`
+	Attrs     map[string]string `json:"attrs"`
+}
+
+var user = User{
+	Name:    "tenntenn",
+	Age:     33,
+	Address: Address{Zip: "000-0000", City: "Tokyo"},
+	Tags:    []string{"gopher"},
+	Attrs:   map[string]string{"lang": "go"},
+}
+
+func TestSuite(t *testing.T) {
+	jsonschematest.RunSuite(t, os.DirFS("testdata/suite"), nil)
+}
+
+func TestCheck(t *testing.T) {
+	jsonschematest.Check(t, user)
+	jsonschematest.Check(t, []User{user}, jsonschema.HoistAnonymousStructs())
+	jsonschematest.Check(t, map[string][]int{"a": {1}})
+}
+
+func TestValidSchema(t *testing.T) {
+	if err := jsonschematest.ValidSchema([]byte(`{"type":"object","required":"name"}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	jsonschematest.Golden(t, "user", user)
+	jsonschematest.Golden(t, "users", []User{user})
+}
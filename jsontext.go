@@ -0,0 +1,24 @@
+//go:build goexperiment.jsonv2
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+)
+
+// GenerateTokens generates JSON Schema from a Go type like Generate and writes it
+// into the token stream of enc as a JSON value.
+// Formatting is controlled by options of the encoder such as jsontext.Multiline,
+// jsontext.WithIndent and jsontext.SpaceAfterColon instead of Generate,
+// and the schema can be written as a part of a larger stream,
+// e.g. after the name of a property which enc has written.
+// The schema is built in memory before it is written as Generate does,
+// so orders of keys by SortedKeys and OrderProperties are kept.
+func GenerateTokens(enc *jsontext.Encoder, v interface{}, opts ...Option) error {
+	var buf bytes.Buffer
+	if err := Generate(&buf, v, opts...); err != nil {
+		return err
+	}
+	return enc.WriteValue(jsontext.Value(bytes.TrimSpace(buf.Bytes())))
+}
@@ -0,0 +1,99 @@
+//go:build goexperiment.jsonv2
+
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateTokens(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	cases := []struct {
+		name    string
+		encOpts []jsontext.Options
+		opts    []Option
+		expect  string
+	}{
+		{
+			name:   "compact",
+			expect: `{"properties":{"age":{"propertyOrder":1,"type":"number"},"name":{"propertyOrder":0,"type":"string"}},"required":["name","age"],"title":"T","type":"object"}` + "\n",
+		},
+		{
+			name:    "indent",
+			encOpts: []jsontext.Options{jsontext.Multiline(true), jsontext.WithIndent("  ")},
+			opts:    []Option{MapKeywords(KeywordRenames{"propertyOrder": "", "required": "", "title": ""})},
+			expect: `{
+  "properties": {
+    "age": {
+      "type": "number"
+    },
+    "name": {
+      "type": "string"
+    }
+  },
+  "type": "object"
+}
+`,
+		},
+		{
+			name:   "declaration order",
+			opts:   []Option{OrderProperties(PropertiesDeclaration), MapKeywords(KeywordRenames{"required": "", "title": ""})},
+			expect: `{"properties":{"name":{"propertyOrder":0,"type":"string"},"age":{"propertyOrder":1,"type":"number"}},"type":"object"}` + "\n",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := jsontext.NewEncoder(&buf, tt.encOpts...)
+			if err := GenerateTokens(enc, T{}, tt.opts...); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got := buf.String(); got != tt.expect {
+				t.Errorf("want %s but got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTokens_stream(t *testing.T) {
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+	for _, tok := range []jsontext.Token{jsontext.BeginObject, jsontext.String("user")} {
+		if err := enc.WriteToken(tok); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+	if err := GenerateTokens(enc, "", MapKeywords(KeywordRenames{"title": ""})); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := enc.WriteToken(jsontext.EndObject); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{"user":{"type":"string"}}` + "\n"
+	if got := buf.String(); got != expect {
+		t.Errorf("want %s but got %s", expect, got)
+	}
+}
+
+func TestGenerateTokens_error(t *testing.T) {
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf)
+	if err := GenerateTokens(enc, nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want ErrUnsupportedType but got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing must be written but got %s", buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+package jsonschema
+
+import "sort"
+
+// KeywordMapper adapts keywords of generated schemas to a dialect such as a schema DSL of a gateway.
+// MapKeyword returns the keyword and the value which replace the keyword of a schema object,
+// or false to drop it.
+type KeywordMapper interface {
+	MapKeyword(keyword string, value interface{}) (string, interface{}, bool)
+}
+
+// KeywordMapperFunc is a function which implements KeywordMapper.
+type KeywordMapperFunc func(keyword string, value interface{}) (string, interface{}, bool)
+
+// MapKeyword calls f(keyword, value).
+func (f KeywordMapperFunc) MapKeyword(keyword string, value interface{}) (string, interface{}, bool) {
+	return f(keyword, value)
+}
+
+// KeywordRenames is a KeywordMapper which renames keywords to the mapped ones.
+// Keywords mapped to empty strings are dropped and other keywords are kept as they are.
+type KeywordRenames map[string]string
+
+// MapKeyword renames the keyword.
+func (r KeywordRenames) MapKeyword(keyword string, value interface{}) (string, interface{}, bool) {
+	name, ok := r[keyword]
+	switch {
+	case !ok:
+		return keyword, value, true
+	case name == "":
+		return "", nil, false
+	}
+	return name, value, true
+}
+
+// MapKeywords applies the mappers in order to keywords of all schema objects
+// after Draft and Flavor convert them, e.g.
+//
+//	MapKeywords(KeywordRenames{"propertyOrder": "x-order", "$comment": ""})
+//
+// emits x-order instead of propertyOrder and drops comments.
+// Names of properties and defs are not keywords and are kept.
+// Keywords are mapped in alphabetical order, so a keyword mapped to an existing one
+// replaces it if it comes later.
+// Generations with mappers are not cached by Cache unless CacheKey gives their keys.
+func MapKeywords(mappers ...KeywordMapper) Option {
+	return configOption(func(c *config) {
+		c.keywordMappers = append(c.keywordMappers, mappers...)
+	})
+}
+
+// applyKeywordMappers maps keywords of the root schema and its subschemas after applyFlavor.
+func (c *config) applyKeywordMappers(root map[string]interface{}) {
+	if len(c.keywordMappers) == 0 {
+		return
+	}
+
+	// subschemas are collected before mapping
+	// because mappers can rename keywords which hold them
+	var schemas []map[string]interface{}
+	collect := func(s map[string]interface{}) {
+		schemas = append(schemas, s)
+	}
+	walkSubschemas(root, collect)
+	if components, ok := root["components"].(map[string]interface{}); ok {
+		defs, _ := components["schemas"].(map[string]interface{})
+		for _, d := range defs {
+			if m, ok := d.(map[string]interface{}); ok {
+				walkSubschemas(m, collect)
+			}
+		}
+	}
+
+	for _, s := range schemas {
+		for _, m := range c.keywordMappers {
+			mapKeywords(s, m)
+		}
+	}
+}
+
+// mapKeywords replaces keywords of the schema with the ones which the mapper returns.
+func mapKeywords(s map[string]interface{}, m KeywordMapper) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	mapped := make(map[string]interface{}, len(s))
+	for _, k := range keys {
+		if name, v, ok := m.MapKeyword(k, s[k]); ok {
+			mapped[name] = v
+		}
+	}
+
+	for k := range s {
+		delete(s, k)
+	}
+	for k, v := range mapped {
+		s[k] = v
+	}
+}
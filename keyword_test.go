@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestMapKeywords(t *testing.T) {
+	type Address struct {
+		City string `json:"city" jsonschema:"minLength=1"`
+	}
+	type T struct {
+		Name    string   `json:"name"`
+		Address *Address `json:"address,omitempty"`
+	}
+
+	// gateway renames x- keywords to gw- keywords and sets minLength to 10.
+	gateway := KeywordMapperFunc(func(keyword string, value interface{}) (string, interface{}, bool) {
+		if strings.HasPrefix(keyword, "x-") {
+			return "gw-" + strings.TrimPrefix(keyword, "x-"), value, true
+		}
+		if keyword == "minLength" {
+			return keyword, 10, true
+		}
+		return keyword, value, true
+	})
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "renames",
+			opts: []Option{MapKeywords(KeywordRenames{"propertyOrder": "x-order", "title": "", "properties": "fields"})},
+			expect: `{
+				"type": "object",
+				"required": ["name"],
+				"fields": {
+					"name": {"type": "string", "x-order": 0},
+					"address": {
+						"type": "object",
+						"required": ["city"],
+						"fields": {"city": {"type": "string", "minLength": 1, "x-order": 0}},
+						"x-order": 1
+					}
+				}
+			}`,
+		},
+		{
+			name: "mappers in order",
+			opts: []Option{MapKeywords(KeywordRenames{"propertyOrder": "x-order"}, gateway)},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string", "gw-order": 0},
+					"address": {
+						"title": "Address",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string", "minLength": 10, "gw-order": 0}},
+						"gw-order": 1
+					}
+				}
+			}`,
+		},
+		{
+			name: "after flavor",
+			opts: []Option{SharedTypes(SharedTypesRef), Flavor(OpenAPI3), MapKeywords(KeywordRenames{"propertyOrder": "", "title": ""})},
+			expect: `{
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"address": {"$ref": "#/components/schemas/Address"}
+				},
+				"components": {
+					"schemas": {
+						"Address": {
+							"type": "object",
+							"required": ["city"],
+							"properties": {"city": {"type": "string", "minLength": 1}}
+						}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{Address: &Address{}}, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/pkg/wildcard"
+)
+
+// Loader loads a schema which is referred by an URI such as "https://example.com/user.json".
+// Users can implement their own loaders such as loaders for object storages or registry APIs.
+type Loader interface {
+	Load(ctx context.Context, uri string) ([]byte, error)
+}
+
+// LoaderFunc is a function which implements Loader.
+type LoaderFunc func(ctx context.Context, uri string) ([]byte, error)
+
+// Load implements Loader.
+func (f LoaderFunc) Load(ctx context.Context, uri string) ([]byte, error) {
+	return f(ctx, uri)
+}
+
+// MapLoader is a Loader which loads schemas from memory by their URIs
+// such as schemas which are generated by GenerateBytes. URIs do not have fragments.
+type MapLoader map[string][]byte
+
+// Load implements Loader.
+func (l MapLoader) Load(_ context.Context, uri string) ([]byte, error) {
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
+	b, ok := l[uri]
+	if !ok {
+		return nil, newError(ErrRefInvalid, uri, fmt.Errorf("schema is not found"))
+	}
+	return b, nil
+}
+
+// FSLoader returns a Loader which loads schemas from files of fsys.
+// URIs are paths in fsys such as "defs/address.json" or file URIs such as "file:///defs/address.json".
+func FSLoader(fsys fs.FS) Loader {
+	return LoaderFunc(func(_ context.Context, uri string) ([]byte, error) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, newError(ErrRefInvalid, uri, err)
+		}
+		if u.Scheme != "" && u.Scheme != "file" {
+			return nil, newError(ErrRefInvalid, uri, fmt.Errorf("scheme %q is not a file", u.Scheme))
+		}
+		name := path.Clean(strings.TrimPrefix(u.Path, "/"))
+		if !fs.ValidPath(name) {
+			return nil, newError(ErrRefInvalid, uri, fmt.Errorf("invalid path"))
+		}
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, newError(ErrRefInvalid, uri, err)
+		}
+		return b, nil
+	})
+}
+
+// HTTPLoader is a Loader which loads schemas over HTTPS.
+// Only hosts which are allowed are requested to prevent SSRF,
+// which are also checked for redirects.
+// Loaded schemas are cached and revalidated by their ETags.
+// It is safe for concurrent use.
+type HTTPLoader struct {
+	// Client is used for requests. If it is nil, http.DefaultClient is used.
+	Client *http.Client
+	// Timeout limits each request. If it is zero, there is no limit but the context's.
+	Timeout time.Duration
+	// AllowedHosts are patterns of hostnames such as "*.example.com".
+	// If it is empty, no hosts are allowed.
+	AllowedHosts []string
+	// MaxSize limits the size of a schema. If it is zero, 10MB is used.
+	MaxSize int64
+
+	mu    sync.Mutex
+	cache map[string]httpCacheEntry
+}
+
+type httpCacheEntry struct {
+	etag string
+	body []byte
+}
+
+const defaultMaxSchemaSize = 10 << 20
+
+// NewHTTPLoader creates an HTTPLoader which allows the hosts.
+func NewHTTPLoader(allowedHosts ...string) *HTTPLoader {
+	return &HTTPLoader{AllowedHosts: allowedHosts}
+}
+
+// Load implements Loader.
+func (l *HTTPLoader) Load(ctx context.Context, uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, newError(ErrRefInvalid, uri, err)
+	}
+	if err := l.check(u); err != nil {
+		return nil, err
+	}
+	u.Fragment = ""
+	uri = u.String()
+
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/schema+json, application/json")
+
+	l.mu.Lock()
+	cached, ok := l.cache[uri]
+	l.mu.Unlock()
+	if ok && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := l.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot load %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		return cached.body, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("jsonschema: cannot load %s: %s", uri, resp.Status)
+	}
+
+	max := l.MaxSize
+	if max <= 0 {
+		max = defaultMaxSchemaSize
+	}
+	limit, byPolicy := sizeLimit(ctx)
+	if byPolicy = byPolicy && limit < max; byPolicy {
+		max = limit
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, max+1))
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot load %s: %w", uri, err)
+	}
+	switch {
+	case int64(len(body)) <= max:
+	case byPolicy:
+		return nil, newError(ErrRemotePolicy, uri, fmt.Errorf("larger than %d bytes", max))
+	default:
+		return nil, fmt.Errorf("jsonschema: cannot load %s: larger than %d bytes", uri, max)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		l.mu.Lock()
+		if l.cache == nil {
+			l.cache = map[string]httpCacheEntry{}
+		}
+		l.cache[uri] = httpCacheEntry{etag: etag, body: body}
+		l.mu.Unlock()
+	}
+
+	return body, nil
+}
+
+// client returns a copy of the client which checks redirected URLs in the same way as the first one.
+func (l *HTTPLoader) client() *http.Client {
+	c := http.DefaultClient
+	if l.Client != nil {
+		c = l.Client
+	}
+	copied := *c
+	checkRedirect := c.CheckRedirect
+	copied.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := l.check(req.URL); err != nil {
+			return err
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		// same as the default policy of http.Client
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &copied
+}
+
+// check reports an error if the URL is not allowed to be requested.
+func (l *HTTPLoader) check(u *url.URL) error {
+	if u.Scheme != "https" {
+		return newError(ErrRefInvalid, u.String(), fmt.Errorf("scheme %q is not allowed", u.Scheme))
+	}
+	if !l.allowed(u.Hostname()) {
+		return newError(ErrRefInvalid, u.String(), fmt.Errorf("host %q is not allowed", u.Hostname()))
+	}
+	return nil
+}
+
+func (l *HTTPLoader) allowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range l.AllowedHosts {
+		if wildcard.MatchSimple(strings.ToLower(pattern), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemotePolicy limits remote schemas which a compilation loads,
+// so compiling untrusted schemas cannot be abused to load many, large or deeply chained schemas
+// or to flood hosts with requests. Zero fields mean no limits.
+// Violations are errors which match ErrRemotePolicy.
+// A policy can be shared by compilations and rate limits of hosts are shared by them.
+// It is safe for concurrent use.
+type RemotePolicy struct {
+	// MaxRefs limits the number of remote schemas which a compilation loads.
+	MaxRefs int
+	// MaxSize limits the size of each remote schema in bytes.
+	// HTTPLoader stops reading a body which is larger than it,
+	// and schemas of other loaders are checked after they are loaded.
+	MaxSize int64
+	// MaxDepth limits the length of chains of remote schemas which refer to other remote schemas.
+	// Remote schemas which are referred from the compiled schema have depth 1.
+	MaxDepth int
+	// HostInterval is the minimum interval between loads from the same host.
+	// Loads wait for the interval and fail if the context is done before it.
+	HostInterval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// WithRemotePolicy limits loading of remote schemas by the policy.
+func WithRemotePolicy(p *RemotePolicy) CompileOption {
+	return func(c *compiler) {
+		c.policy = p
+	}
+}
+
+// before checks the policy before using the URL as the n+1th remote schema of the depth.
+func (p *RemotePolicy) before(u *url.URL, depth, n int) error {
+	if p == nil {
+		return nil
+	}
+	uri := u.String()
+	if p.MaxRefs > 0 && n >= p.MaxRefs {
+		return newError(ErrRemotePolicy, uri, fmt.Errorf("more than %d remote schemas are referred", p.MaxRefs))
+	}
+	if p.MaxDepth > 0 && depth > p.MaxDepth {
+		return newError(ErrRemotePolicy, uri, fmt.Errorf("chain of remote schemas is deeper than %d", p.MaxDepth))
+	}
+	return nil
+}
+
+// wait waits for the interval of the host of the URL before loading it.
+func (p *RemotePolicy) wait(ctx context.Context, u *url.URL) error {
+	if p == nil || p.HostInterval <= 0 {
+		return nil
+	}
+	uri := u.String()
+
+	host := strings.ToLower(u.Host)
+	p.mu.Lock()
+	now := time.Now()
+	at := p.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	if p.next == nil {
+		p.next = map[string]time.Time{}
+	}
+	p.next[host] = at.Add(p.HostInterval)
+	p.mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return newError(ErrRemotePolicy, uri, fmt.Errorf("rate limit of %s: %w", host, ctx.Err()))
+	}
+}
+
+type sizeLimitKey struct{}
+
+// withSizeLimit returns a context of loads which gives MaxSize of the policy to loaders.
+func (p *RemotePolicy) withSizeLimit(ctx context.Context) context.Context {
+	if p == nil || p.MaxSize <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, sizeLimitKey{}, p.MaxSize)
+}
+
+// sizeLimit returns the limit of sizes of schemas which a policy gives to the context.
+func sizeLimit(ctx context.Context) (int64, bool) {
+	n, ok := ctx.Value(sizeLimitKey{}).(int64)
+	return n, ok
+}
+
+// after checks the loaded schema of the URI.
+func (p *RemotePolicy) after(uri string, schema []byte) error {
+	if p != nil && p.MaxSize > 0 && int64(len(schema)) > p.MaxSize {
+		return newError(ErrRemotePolicy, uri, fmt.Errorf("larger than %d bytes", p.MaxSize))
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package jsonschema_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestHTTPLoader(t *testing.T) {
+	var requests, notModified int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/id.json":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"$defs": {"id": {"type": "integer", "minimum": 1}}}`))
+		case "/moved.json":
+			http.Redirect(w, r, "/id.json", http.StatusFound)
+		case "/redirect":
+			http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+		case "/slow.json":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	l := NewHTTPLoader("127.0.0.1")
+	l.Client = srv.Client()
+
+	schema := []byte(`{"type": "object", "properties": {"id": {"$ref": "` + srv.URL + `/id.json#/$defs/id"}}}`)
+	s, err := CompileBytes(schema, WithLoader(l))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"id": 1}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"id": 0}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+
+	// revalidated by ETag
+	if _, err := l.Load(context.Background(), srv.URL+"/id.json"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if got := atomic.LoadInt32(&notModified); got != 1 {
+		t.Errorf("want 1 not modified response but got %d", got)
+	}
+
+	// redirects to allowed hosts are followed
+	if _, err := l.Load(context.Background(), srv.URL+"/moved.json"); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	// remote schemas are also referred from files
+	fsys := fstest.MapFS{
+		"user.json": {Data: schema},
+	}
+	if _, err := Compile(fsys, "user.json", WithLoader(l)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if _, err := Compile(fsys, "user.json"); !errors.Is(err, ErrRefInvalid) {
+		t.Errorf("want ErrRefInvalid without loaders but got %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		loader *HTTPLoader
+		uri    string
+		kind   error
+	}{
+		{"not allowed host", &HTTPLoader{Client: srv.Client(), AllowedHosts: []string{"*.example.com"}}, srv.URL + "/id.json", ErrRefInvalid},
+		{"no allowed hosts", &HTTPLoader{Client: srv.Client()}, srv.URL + "/id.json", ErrRefInvalid},
+		{"http", NewHTTPLoader("127.0.0.1"), "http://127.0.0.1/id.json", ErrRefInvalid},
+		{"not found", l, srv.URL + "/missing.json", nil},
+		{"redirect to not allowed host", l, srv.URL + "/redirect?to=" + url.QueryEscape(strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)+"/id.json"), ErrRefInvalid},
+		{"redirect to http", l, srv.URL + "/redirect?to=" + url.QueryEscape(strings.Replace(srv.URL, "https", "http", 1)+"/id.json"), ErrRefInvalid},
+		{"timeout", &HTTPLoader{Client: srv.Client(), AllowedHosts: []string{"127.0.0.1"}, Timeout: 10 * time.Millisecond}, srv.URL + "/slow.json", context.DeadlineExceeded},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			before := atomic.LoadInt32(&requests)
+			_, err := tt.loader.Load(context.Background(), tt.uri)
+			if err == nil {
+				t.Fatal("expected error does not occur")
+			}
+			if tt.kind != nil && !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+			// only the first URI is requested for redirects
+			var allowed int32
+			if strings.Contains(tt.uri, "/redirect") {
+				allowed = 1
+			}
+			if tt.kind == ErrRefInvalid && atomic.LoadInt32(&requests) != before+allowed {
+				t.Error("denied URI is requested")
+			}
+		})
+	}
+}
+
+func TestRemotePolicy(t *testing.T) {
+	remotes := map[string]string{
+		"https://a.example.com/a.json": `{"$ref": "https://b.example.com/b.json"}`,
+		"https://b.example.com/b.json": `{"$ref": "https://a.example.com/c.json"}`,
+		"https://a.example.com/c.json": `{"type": "integer"}`,
+		"https://a.example.com/d.json": `{"type": "integer", "description": "` + strings.Repeat("x", 100) + `"}`,
+	}
+	var loads int32
+	l := LoaderFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		return []byte(remotes[uri]), nil
+	})
+	chain := []byte(`{"properties": {"n": {"$ref": "https://a.example.com/a.json"}}}`)
+	large := []byte(`{"properties": {"n": {"$ref": "https://a.example.com/d.json"}}}`)
+
+	cases := []struct {
+		name   string
+		schema []byte
+		policy *RemotePolicy
+		loads  int32
+		err    bool
+	}{
+		{"no limits", chain, &RemotePolicy{}, 3, false},
+		{"max refs", chain, &RemotePolicy{MaxRefs: 2}, 2, true},
+		{"enough refs", chain, &RemotePolicy{MaxRefs: 3}, 3, false},
+		{"max depth", chain, &RemotePolicy{MaxDepth: 2}, 2, true},
+		{"enough depth", chain, &RemotePolicy{MaxDepth: 3}, 3, false},
+		{"max size", large, &RemotePolicy{MaxSize: 100}, 1, true},
+		{"enough size", large, &RemotePolicy{MaxSize: 1000}, 1, false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&loads, 0)
+			_, err := CompileBytes(tt.schema, WithLoader(l), WithRemotePolicy(tt.policy))
+			switch {
+			case tt.err && !errors.Is(err, ErrRemotePolicy):
+				t.Errorf("want ErrRemotePolicy but got %v", err)
+			case !tt.err && err != nil:
+				t.Error("unexpected error:", err)
+			}
+			if got := atomic.LoadInt32(&loads); got != tt.loads {
+				t.Errorf("want %d loads but got %d", tt.loads, got)
+			}
+		})
+	}
+
+	t.Run("host interval", func(t *testing.T) {
+		p := &RemotePolicy{HostInterval: 50 * time.Millisecond}
+		start := time.Now()
+		if _, err := CompileBytes(chain, WithLoader(l), WithRemotePolicy(p)); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		// a.json and c.json are loaded from the same host
+		if d := time.Since(start); d < 50*time.Millisecond {
+			t.Errorf("loads from the same host are not throttled: %v", d)
+		}
+
+		// the interval is shared by compilations
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		fsys := fstest.MapFS{"chain.json": {Data: chain}}
+		if _, err := CompileContext(ctx, fsys, "chain.json", WithLoader(l), WithRemotePolicy(p)); !errors.Is(err, ErrRemotePolicy) {
+			t.Errorf("want ErrRemotePolicy but got %v", err)
+		}
+	})
+}
+
+// endlessBody is a body of a response which counts read bytes and never ends.
+type endlessBody struct {
+	n int64
+}
+
+func (b *endlessBody) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	atomic.AddInt64(&b.n, int64(len(p)))
+	return len(p), nil
+}
+
+func (b *endlessBody) Close() error { return nil }
+
+func TestRemotePolicy_maxSize(t *testing.T) {
+	body := &endlessBody{}
+	l := NewHTTPLoader("example.com")
+	l.Client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: body, Header: http.Header{}, Request: r}, nil
+	})}
+
+	schema := []byte(`{"$ref": "https://example.com/large.json"}`)
+	_, err := CompileBytes(schema, WithLoader(l), WithRemotePolicy(&RemotePolicy{MaxSize: 100}))
+	if !errors.Is(err, ErrRemotePolicy) {
+		t.Errorf("want ErrRemotePolicy but got %v", err)
+	}
+	// the body is not read after the limit
+	if n := atomic.LoadInt64(&body.n); n > 101 {
+		t.Errorf("want at most 101 bytes of the body but %d bytes are read", n)
+	}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithCacheStore(t *testing.T) {
+	var loads int32
+	l := LoaderFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		return []byte(`{"type": "integer", "minimum": 1}`), nil
+	})
+	schema := []byte(`{"properties": {"id": {"$ref": "https://example.com/id.json"}}}`)
+
+	store := NewMemoryStore()
+	for i := 0; i < 2; i++ {
+		s, err := CompileBytes(schema, WithLoader(l), WithCacheStore(store))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if err := s.Validate([]byte(`{"id": 0}`)); err == nil {
+			t.Error("expected error does not occur")
+		}
+	}
+	if got := atomic.LoadInt32(&loads); got != 1 {
+		t.Errorf("want 1 load but got %d", got)
+	}
+
+	// cached schemas are checked by policies
+	_, err := CompileBytes(schema, WithLoader(l), WithCacheStore(store), WithRemotePolicy(&RemotePolicy{MaxSize: 10}))
+	if !errors.Is(err, ErrRemotePolicy) {
+		t.Errorf("want ErrRemotePolicy but got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MapIntKeyStyle is a style of schemas of maps which have integer keys.
+type MapIntKeyStyle int
+
+const (
+	// MapIntKeyPropertyNames generates an object whose property names
+	// are restricted to integers by a pattern.
+	// It matches the encoding of encoding/json.
+	MapIntKeyPropertyNames MapIntKeyStyle = iota
+	// MapIntKeyEntries generates an array of entries such as [{"key":1,"value":...}].
+	// It matches custom marshalers which encode maps as entries.
+	MapIntKeyEntries
+)
+
+const (
+	intKeyPattern  = "^(0|-?[1-9][0-9]*)$"
+	uintKeyPattern = "^(0|[1-9][0-9]*)$"
+)
+
+// MapIntKeys sets the style of schemas of maps which have integer keys.
+// The default is MapIntKeyPropertyNames.
+// Keys which implement encoding.TextMarshaler are not affected.
+func MapIntKeys(style MapIntKeyStyle) Option {
+	return configOption(func(c *config) {
+		c.mapIntKeyStyle = style
+	})
+}
+
+// isIntKey reports whether keys of the type t are encoded as integers.
+func isIntKey(t reflect.Type) bool {
+	if isTextMarshaler(t) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+func intKeyPatternOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intKeyPattern
+	}
+	return uintKeyPattern
+}
+
+// mapEntriesGen generates a schema of an array of entries of the map.
+func (g *gen) mapEntriesGen(parent Object, v reflect.Value, options ...Option) error {
+	items := g.cfg.refs().Join(parent.Ref(), "items")
+	o := &obj{
+		m:   map[string]interface{}{},
+		ref: g.cfg.refs().Join(items, "properties", "value"),
+	}
+
+	elm := empty(v.Type().Elem())
+	if keys := v.MapKeys(); len(keys) != 0 {
+		// use the smallest key for stable results
+		first := keys[0]
+		for _, k := range keys[1:] {
+			if intKeyLess(k, first) {
+				first = k
+			}
+		}
+		if e := v.MapIndex(first); !isNil(e) {
+			elm = e
+		}
+	}
+	if err := g.do(o, elm, options...); err != nil {
+		return err
+	}
+
+	key := map[string]interface{}{
+		"type": "integer",
+	}
+	if intKeyPatternOf(v.Type().Key()) == uintKeyPattern {
+		key["minimum"] = 0
+	}
+
+	parent.Set("type", "array")
+	parent.Set("items", map[string]interface{}{
+		"type":     "object",
+		"required": []string{"key", "value"},
+		"properties": map[string]interface{}{
+			"key":   key,
+			"value": o.m,
+		},
+		"additionalProperties": false,
+	})
+
+	return nil
+}
+
+func intKeyLess(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	}
+	return a.Uint() < b.Uint()
+}
+
+// ClosedMaps generates schemas of populated maps as closed objects.
+// Keys of a map become properties which are generated from their values
+// and other properties are not allowed by additionalProperties:false.
+// It is useful for maps whose keys are actually fixed such as configurations.
+// Schemas of empty maps are generated from their types.
+func ClosedMaps() Option {
+	return configOption(func(c *config) {
+		c.closedMaps = true
+	})
+}
+
+// closedMapGen generates a schema of a closed object whose properties are keys of the map.
+func (g *gen) closedMapGen(parent Object, v reflect.Value, options ...Option) error {
+	keys := v.MapKeys()
+	names := make([]string, len(keys))
+	for i := range keys {
+		name, err := keyName(keys[i].Interface())
+		if err != nil {
+			return err
+		}
+		names[i] = name
+	}
+
+	// generate properties in order of their names for stable results
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool {
+		return names[idx[i]] < names[idx[j]]
+	})
+
+	required := make([]string, 0, len(keys))
+	properties := make(map[string]interface{}, len(keys))
+	for _, i := range idx {
+		name := names[i]
+		o := &obj{
+			m:   map[string]interface{}{},
+			ref: g.cfg.refs().Join(parent.Ref(), "properties", name),
+		}
+
+		elm := v.MapIndex(keys[i])
+		if elm.Kind() == reflect.Interface && !elm.IsNil() {
+			// use a dynamic type of the value
+			elm = elm.Elem()
+		}
+		if isNil(elm) {
+			elm = empty(v.Type().Elem())
+		}
+		if err := g.do(o, elm, options...); err != nil {
+			return err
+		}
+
+		required = append(required, name)
+		properties[name] = o.m
+	}
+
+	parent.Set("type", "object")
+	parent.Set("required", g.cfg.orderRequired(required))
+	parent.Set("properties", properties)
+	parent.Set("additionalProperties", false)
+
+	return nil
+}
+
+// PatternProperties generates schemas of maps whose keys are constrained
+// such as integer keys and enum keys by patternProperties instead of propertyNames.
+// Values of the map are described by the schema for the pattern of keys
+// and other properties are not allowed by additionalProperties:false.
+// It is useful for validators and code generators which do not support propertyNames.
+func PatternProperties() Option {
+	return configOption(func(c *config) {
+		c.patternProps = true
+	})
+}
+
+// enumPattern returns a pattern which matches only the names.
+func enumPattern(names []interface{}) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = regexp.QuoteMeta(fmt.Sprint(n))
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
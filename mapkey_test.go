@@ -0,0 +1,337 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestMapIntKeys(t *testing.T) {
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+		docs   map[string]bool
+	}{
+		{
+			name: "property names",
+			v:    map[int]string{},
+			expect: `{
+				"type": "object",
+				"additionalProperties": {"type": "string"},
+				"propertyNames": {"pattern": "^(0|-?[1-9][0-9]*)$"}
+			}`,
+			docs: map[string]bool{
+				`{"1":"a","-20":"b","0":"c"}`: true,
+				`{"a":"a"}`:                   false,
+				`{"01":"a"}`:                  false,
+			},
+		},
+		{
+			name: "unsigned property names",
+			v:    map[uint8]string{},
+			expect: `{
+				"type": "object",
+				"additionalProperties": {"type": "string"},
+				"propertyNames": {"pattern": "^(0|[1-9][0-9]*)$"}
+			}`,
+			docs: map[string]bool{
+				`{"1":"a"}`:  true,
+				`{"-1":"a"}`: false,
+			},
+		},
+		{
+			name: "entries",
+			v:    map[int64]string{2: "b", 1: "a"},
+			opts: []Option{MapIntKeys(MapIntKeyEntries)},
+			expect: `{
+				"type": "array",
+				"items": {
+					"type": "object",
+					"required": ["key", "value"],
+					"properties": {
+						"key": {"type": "integer"},
+						"value": {"type": "string"}
+					},
+					"additionalProperties": false
+				}
+			}`,
+			docs: map[string]bool{
+				`[{"key":1,"value":"a"},{"key":-2,"value":"b"}]`: true,
+				`[{"key":"1","value":"a"}]`:                      false,
+				`[{"key":1}]`:                                    false,
+				`{"1":"a"}`:                                      false,
+			},
+		},
+		{
+			name: "unsigned entries with options",
+			v:    map[uint]int{},
+			opts: []Option{
+				MapIntKeys(MapIntKeyEntries),
+				ByReference("#/items/properties/value", func(o Object) (Object, error) {
+					o.Set("default", 1)
+					return o, nil
+				}),
+			},
+			expect: `{
+				"type": "array",
+				"items": {
+					"type": "object",
+					"required": ["key", "value"],
+					"properties": {
+						"key": {"type": "integer", "minimum": 0},
+						"value": {"type": "number", "default": 1}
+					},
+					"additionalProperties": false
+				}
+			}`,
+			docs: map[string]bool{
+				`[{"key":1,"value":1}]`:  true,
+				`[{"key":-1,"value":1}]`: false,
+			},
+		},
+		{
+			name: "string keys are not affected",
+			v:    map[string]int{},
+			opts: []Option{MapIntKeys(MapIntKeyEntries)},
+			expect: `{
+				"type": "object",
+				"additionalProperties": {"type": "number"}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+
+			s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			for doc, valid := range tt.docs {
+				r, err := s.Validate(gojsonschema.NewStringLoader(doc))
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if r.Valid() != valid {
+					t.Errorf("%s: valid = %v, want %v: %v", doc, r.Valid(), valid, r.Errors())
+				}
+			}
+		})
+	}
+}
+
+func TestPatternProperties(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+		docs   map[string]bool
+	}{
+		{
+			name: "integer keys",
+			v:    map[int]string{},
+			opts: []Option{PatternProperties()},
+			expect: `{
+				"type": "object",
+				"patternProperties": {"^(0|-?[1-9][0-9]*)$": {"type": "string"}},
+				"additionalProperties": false
+			}`,
+			docs: map[string]bool{
+				`{"1":"a","-20":"b"}`: true,
+				`{"1":1}`:             false,
+				`{"a":"a"}`:           false,
+			},
+		},
+		{
+			name: "enum keys with options",
+			v:    map[color][]Item{},
+			opts: []Option{
+				PatternProperties(),
+				ByReference("#/patternProperties/^(red|green)$/items", func(o Object) (Object, error) {
+					o.Set("description", "item")
+					return o, nil
+				}),
+			},
+			expect: `{
+				"type": "object",
+				"patternProperties": {"^(red|green)$": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"description": "item",
+						"title": "Item",
+						"required": ["name"],
+						"properties": {"name": {"type": "string", "propertyOrder": 0}}
+					}
+				}},
+				"additionalProperties": false
+			}`,
+			docs: map[string]bool{
+				`{"red":[{"name":"a"}]}`: true,
+				`{"red":[{}]}`:           false,
+				`{"blue":[]}`:            false,
+			},
+		},
+		{
+			name: "unconstrained keys with nested values",
+			v:    map[string][]Item{},
+			opts: []Option{PatternProperties()},
+			expect: `{
+				"type": "object",
+				"additionalProperties": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"title": "Item",
+						"required": ["name"],
+						"properties": {"name": {"type": "string", "propertyOrder": 0}}
+					}
+				}
+			}`,
+			docs: map[string]bool{
+				`{"a":[{"name":"a"}]}`: true,
+				`{"a":[{"name":1}]}`:   false,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+
+			s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			for doc, valid := range tt.docs {
+				r, err := s.Validate(gojsonschema.NewStringLoader(doc))
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if r.Valid() != valid {
+					t.Errorf("%s: valid = %v, want %v: %v", doc, r.Valid(), valid, r.Errors())
+				}
+			}
+		})
+	}
+}
+
+func TestClosedMaps(t *testing.T) {
+	type Config struct {
+		Servers map[string]map[string]int `json:"servers"`
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		expect string
+		docs   map[string]bool
+	}{
+		{
+			name: "populated",
+			v:    map[string]interface{}{"port": 80, "host": "localhost", "tags": []string{"a"}},
+			expect: `{
+				"type": "object",
+				"required": ["host", "port", "tags"],
+				"properties": {
+					"host": {"type": "string"},
+					"port": {"type": "number"},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				},
+				"additionalProperties": false
+			}`,
+			docs: map[string]bool{
+				`{"host":"a","port":1,"tags":[]}`:          true,
+				`{"host":"a","port":1}`:                    false,
+				`{"host":"a","port":1,"tags":[],"x":true}`: false,
+			},
+		},
+		{
+			name: "nested",
+			v:    Config{Servers: map[string]map[string]int{"web": {"port": 80}, "db": {}}},
+			expect: `{
+				"type": "object",
+				"title": "Config",
+				"required": ["servers"],
+				"properties": {
+					"servers": {
+						"type": "object",
+						"required": ["db", "web"],
+						"properties": {
+							"db": {"type": "object", "additionalProperties": {"type": "number"}},
+							"web": {
+								"type": "object",
+								"required": ["port"],
+								"properties": {"port": {"type": "number"}},
+								"additionalProperties": false
+							}
+						},
+						"additionalProperties": false,
+						"propertyOrder": 0
+					}
+				}
+			}`,
+		},
+		{
+			name: "empty",
+			v:    map[string]int{},
+			expect: `{
+				"type": "object",
+				"additionalProperties": {"type": "number"}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, ClosedMaps())
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+
+			s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(got))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			for doc, valid := range tt.docs {
+				r, err := s.Validate(gojsonschema.NewStringLoader(doc))
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if r.Valid() != valid {
+					t.Errorf("%s: valid = %v, want %v: %v", doc, r.Valid(), valid, r.Errors())
+				}
+			}
+		})
+	}
+}
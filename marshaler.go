@@ -0,0 +1,207 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+)
+
+// builtinTypeSchemas are schemas of well-known types of the standard library
+// whose JSON representations are not strings and semantic types of this package.
+var builtinTypeSchemas = map[reflect.Type]map[string]interface{}{
+	reflect.TypeOf(net.IP{}):          {"type": "string", "format": "ipv4"},
+	reflect.TypeOf(big.Int{}):         {"type": "number"},
+	reflect.TypeOf(json.RawMessage{}): {},
+	reflect.TypeOf(Email("")):         {"type": "string", "format": "email"},
+	reflect.TypeOf(URI("")):           {"type": "string", "format": "uri"},
+	reflect.TypeOf(Currency("")):      {"type": "string", "pattern": "^[A-Z]{3}$"},
+	reflect.TypeOf(TimeZone("")):      {"type": "string", "format": "time-zone", "pattern": timeZonePattern},
+	reflect.TypeOf(Duration(0)):       {"type": "string", "format": "duration"},
+	reflect.TypeOf(AnyValue{}):        {"type": []interface{}{"object", "array", "string", "number", "boolean", "null"}},
+}
+
+// RegisterTypeSchema registers the schema of the type of v
+// for types which cannot implement Generator such as types of other packages.
+// Objects of the type are generated as the schema instead of their structures.
+// Registered schemas have priority over MarshalerHint, json.Marshaler and encoding.TextMarshaler.
+func RegisterTypeSchema(v interface{}, schema map[string]interface{}) Option {
+	t := reflect.TypeOf(v)
+	return configOption(func(c *config) {
+		if c.typeSchemas == nil {
+			c.typeSchemas = map[reflect.Type]map[string]interface{}{}
+		}
+		c.typeSchemas[t] = schema
+	})
+}
+
+// TypeMapping registers the function which generates objects of the Go type t
+// instead of their structures, e.g. decimal.Decimal can be a string of a pattern with
+//
+//	TypeMapping(reflect.TypeOf(decimal.Decimal{}), func(o Object) error {
+//		o.Set("type", "string")
+//		o.Set("pattern", `^-?[0-9]+(\.[0-9]+)?$`)
+//		return nil
+//	})
+//
+// It is useful for types which callers do not own and cannot implement Generator.
+// Mappings have priority over RegisterTypeSchema, json.Marshaler and encoding.TextMarshaler
+// and other options are applied after the function.
+// Generations with mappings are not cached by Cache unless CacheKey gives their keys.
+func TypeMapping(t reflect.Type, f func(o Object) error) Option {
+	return configOption(func(c *config) {
+		if c.typeMappings == nil {
+			c.typeMappings = map[reflect.Type]func(o Object) error{}
+		}
+		c.typeMappings[t] = f
+	})
+}
+
+// MarshalerHint is implemented by json.Marshaler types whose JSON representations depend on their values,
+// such as a type which is encoded as an object when it has options and as a string otherwise.
+// SchemaForMarshaler is called with a zero value of the type and returns the schema of all
+// representations, e.g. {"anyOf": [{"type": "object"}, {"type": "string"}]}.
+// Objects of the type are generated as the schema instead of a string.
+// References of the schema to its own definitions are inlined.
+type MarshalerHint interface {
+	SchemaForMarshaler() *Schema
+}
+
+var marshalerHintType = reflect.TypeOf((*MarshalerHint)(nil)).Elem()
+
+// RegisterMarshalerHint registers the schema of the type of v like MarshalerHint
+// for types which cannot implement it such as types of other packages.
+// MarshalerHint of the type has priority over the registered schema.
+func RegisterMarshalerHint(v interface{}, s *Schema) Option {
+	t := reflect.TypeOf(v)
+	return configOption(func(c *config) {
+		if c.marshalerHints == nil {
+			c.marshalerHints = map[reflect.Type]*Schema{}
+		}
+		c.marshalerHints[t] = s
+	})
+}
+
+// marshalerHintOf returns the schema which t gives by MarshalerHint or RegisterMarshalerHint.
+func (c *config) marshalerHintOf(t reflect.Type) (*Schema, bool) {
+	if v, ok := zeroOf(t, marshalerHintType); ok {
+		return v.(MarshalerHint).SchemaForMarshaler(), true
+	}
+	s, ok := c.marshalerHints[t]
+	return s, ok
+}
+
+// hintGen generates the object as the schema of the hint.
+func hintGen(o Object, t reflect.Type, s *Schema) error {
+	if s == nil {
+		return newError(ErrUnsupportedType, o.Ref(), fmt.Errorf("schema of the marshaler hint of %s is nil", t))
+	}
+	if s.err != nil {
+		return newError(ErrUnsupportedType, o.Ref(), fmt.Errorf("invalid schema of the marshaler hint of %s: %w", t, s.err))
+	}
+
+	m := rootMap(copyValue(s.doc))
+	if err := inlineRefs(m, &obj{m: m, ref: o.Ref()}, nil); err != nil {
+		return err
+	}
+	for _, k := range []string{"$schema", "$id", "id", "$defs", "definitions"} {
+		delete(m, k)
+	}
+	for _, k := range sortedKeys(m) {
+		o.Set(k, m[k])
+	}
+	return nil
+}
+
+// typeSchemaOf returns the registered or built-in schema of t.
+func (c *config) typeSchemaOf(t reflect.Type) (map[string]interface{}, bool) {
+	if s, ok := c.typeSchemas[t]; ok {
+		return s, true
+	}
+	s, ok := builtinTypeSchemas[t]
+	return s, ok
+}
+
+// isMarshaler reports whether t or *t implements json.Marshaler or encoding.TextMarshaler.
+// Values of such types are encoded as their methods return instead of their structures.
+// Pointers are not marshalers because their elements are generated as the same objects.
+func isMarshaler(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface || t == timeType {
+		return false
+	}
+	pt := reflect.PtrTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) || isTextMarshaler(t)
+}
+
+// mappedGen generates the object of a type which has the mapping or the registered schema
+// or implements json.Marshaler or encoding.TextMarshaler.
+// The representation of a json.Marshaler is unknown, so it is a string by default
+// as most of them such as uuid.UUID are. GoTypeOverride and RegisterTypeSchema change it.
+// It reports false if the type is generated from its structure.
+func (g *gen) mappedGen(o Object, t reflect.Type) (bool, error) {
+	if f, ok := g.cfg.typeMappings[t]; ok {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "generated by the mapping of %s", t)
+		}
+		if err := f(o); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if s, ok := g.cfg.typeSchemaOf(t); ok {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "generated by the registered schema of %s", t)
+		}
+		// the schema is shared by generations, so its values are copied
+		for k, v := range s {
+			o.Set(k, copyValue(v))
+		}
+		return true, nil
+	}
+
+	if g.cfg.flavor == KubernetesCRD && isIntOrString(t) {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "%s is an int or a string", t)
+		}
+		o.Set(crdIntOrString, true)
+		return true, nil
+	}
+
+	if s, ok := g.cfg.marshalerHintOf(t); ok {
+		if g.plan != nil {
+			g.plan.note(o.Ref(), "generated by the marshaler hint of %s", t)
+		}
+		if err := hintGen(o, t, s); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if !isMarshaler(t) {
+		return false, nil
+	}
+	if g.plan != nil {
+		g.plan.note(o.Ref(), "%s is marshaled as a string", t)
+		if !isTextMarshaler(t) {
+			g.plan.warn("%s: representation of json.Marshaler %s is assumed to be a string without MarshalerHint", o.Ref(), t)
+		}
+	}
+	o.Set("type", "string")
+	return true, nil
+}
+
+// isMapped reports whether objects of t are generated by mappedGen.
+func (c *config) isMapped(t reflect.Type) bool {
+	if _, ok := c.typeMappings[t]; ok {
+		return true
+	}
+	if _, ok := c.typeSchemaOf(t); ok {
+		return true
+	}
+	if _, ok := c.marshalerHints[t]; ok {
+		return true
+	}
+	return isMarshaler(t) || t.Implements(marshalerHintType) || reflect.PtrTo(t).Implements(marshalerHintType)
+}
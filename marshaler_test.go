@@ -0,0 +1,355 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+// ptrVersion is marshaled by the method of its pointer.
+type ptrVersion struct {
+	Major int
+}
+
+func (v *ptrVersion) MarshalText() ([]byte, error) {
+	return []byte("v1"), nil
+}
+
+func TestGenerate_marshaler(t *testing.T) {
+	type T struct {
+		CreatedAt time.Time
+		Version   version
+		PtrVer    *ptrVersion
+		Point     *point
+		Level     level
+		IP        net.IP
+		Amount    *big.Int
+		Raw       json.RawMessage
+		Points    []point
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "default",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["CreatedAt", "Version", "Level", "IP", "Raw", "Points"],
+				"properties": {
+					"CreatedAt": {"type": "string", "format": "date-time", "propertyOrder": 0},
+					"Version": {"type": "string", "propertyOrder": 1},
+					"PtrVer": {"type": "string", "propertyOrder": 2},
+					"Point": {"type": "string", "propertyOrder": 3},
+					"Level": {"type": "string", "enum": ["low", "high"], "propertyOrder": 4},
+					"IP": {"type": "string", "format": "ipv4", "propertyOrder": 5},
+					"Amount": {"type": "number", "propertyOrder": 6},
+					"Raw": {"propertyOrder": 7},
+					"Points": {"type": "array", "items": {"type": "string"}, "propertyOrder": 8}
+				}
+			}`,
+		},
+		{
+			name: "registered schemas",
+			opts: []Option{
+				RegisterTypeSchema(point{}, map[string]interface{}{
+					"type":    "string",
+					"pattern": "^[0-9]+,[0-9]+$",
+				}),
+				RegisterTypeSchema(net.IP{}, map[string]interface{}{
+					"type":   "string",
+					"format": "ipv6",
+				}),
+			},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["CreatedAt", "Version", "Level", "IP", "Raw", "Points"],
+				"properties": {
+					"CreatedAt": {"type": "string", "format": "date-time", "propertyOrder": 0},
+					"Version": {"type": "string", "propertyOrder": 1},
+					"PtrVer": {"type": "string", "propertyOrder": 2},
+					"Point": {"type": "string", "pattern": "^[0-9]+,[0-9]+$", "propertyOrder": 3},
+					"Level": {"type": "string", "enum": ["low", "high"], "propertyOrder": 4},
+					"IP": {"type": "string", "format": "ipv6", "propertyOrder": 5},
+					"Amount": {"type": "number", "propertyOrder": 6},
+					"Raw": {"propertyOrder": 7},
+					"Points": {"type": "array", "items": {"type": "string", "pattern": "^[0-9]+,[0-9]+$"}, "propertyOrder": 8}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v := T{
+				PtrVer: &ptrVersion{},
+				Point:  &point{},
+				IP:     net.IPv4(127, 0, 0, 1),
+				Amount: big.NewInt(1),
+				Raw:    json.RawMessage(`{}`),
+				Points: []point{{}},
+			}
+			got, err := GenerateString(v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestTypeMapping(t *testing.T) {
+	type decimal struct {
+		value *big.Int
+		exp   int32
+	}
+	type T struct {
+		Price  decimal
+		Prices []decimal
+		Point  point
+	}
+
+	decimalMapping := TypeMapping(reflect.TypeOf(decimal{}), func(o Object) error {
+		o.Set("type", "string")
+		o.Set("pattern", `^-?[0-9]+(\.[0-9]+)?$`)
+		return nil
+	})
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+		isErr  bool
+	}{
+		{
+			name: "mapping",
+			opts: []Option{decimalMapping},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["Price", "Prices", "Point"],
+				"properties": {
+					"Price": {"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]+)?$", "propertyOrder": 0},
+					"Prices": {"type": "array", "items": {"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]+)?$"}, "propertyOrder": 1},
+					"Point": {"type": "string", "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "priority over marshalers",
+			opts: []Option{decimalMapping, TypeMapping(reflect.TypeOf(point{}), func(o Object) error {
+				o.Set("type", "object")
+				return nil
+			})},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["Price", "Prices", "Point"],
+				"properties": {
+					"Price": {"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]+)?$", "propertyOrder": 0},
+					"Prices": {"type": "array", "items": {"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]+)?$"}, "propertyOrder": 1},
+					"Point": {"type": "object", "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "error",
+			opts: []Option{TypeMapping(reflect.TypeOf(decimal{}), func(o Object) error {
+				return errors.New("error")
+			})},
+			isErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{Prices: []decimal{{}}}, tt.opts...)
+			switch {
+			case tt.isErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case !tt.isErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.isErr:
+				return
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+// selector is marshaled as a string of the name or an object with labels.
+type selector struct {
+	Name   string
+	Labels map[string]string
+}
+
+func (s selector) MarshalJSON() ([]byte, error) {
+	if len(s.Labels) == 0 {
+		return json.Marshal(s.Name)
+	}
+	return json.Marshal(map[string]interface{}{"name": s.Name, "labels": s.Labels})
+}
+
+func (selector) SchemaForMarshaler() *Schema {
+	s, err := CompileBytes([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"anyOf": [
+			{"$ref": "#/definitions/name"},
+			{
+				"type": "object",
+				"required": ["name", "labels"],
+				"properties": {
+					"name": {"$ref": "#/definitions/name"},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+				}
+			}
+		],
+		"definitions": {
+			"name": {"type": "string", "minLength": 1}
+		}
+	}`))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// opaque is a json.Marshaler whose representation is an array.
+type opaque struct{}
+
+func (opaque) MarshalJSON() ([]byte, error) {
+	return []byte(`[1, "a"]`), nil
+}
+
+func TestMarshalerHint(t *testing.T) {
+	type T struct {
+		Selector  selector
+		Selectors []selector
+		Opaque    opaque
+	}
+
+	opaqueHint, err := CompileBytes([]byte(`{"type": "array", "items": {"type": ["number", "string"]}}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	selectorSchema := `{
+		"anyOf": [
+			{"type": "string", "minLength": 1},
+			{
+				"type": "object",
+				"required": ["name", "labels"],
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+				}
+			}
+		]
+	}`
+
+	// both representations of selectors are valid
+	s, err := CompileBytes([]byte(selectorSchema))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for _, sel := range []selector{
+		{Name: "app"},
+		{Name: "app", Labels: map[string]string{"env": "prod"}},
+	} {
+		if err := s.ValidateValue(sel); err != nil {
+			t.Errorf("unexpected validation error of %+v: %v", sel, err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+		isErr  bool
+	}{
+		{
+			name: "hint",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["Selector", "Selectors", "Opaque"],
+				"properties": {
+					"Selector": ` + selectorSchema + `,
+					"Selectors": {"type": "array", "items": ` + selectorSchema + `},
+					"Opaque": {"type": "string"}
+				}
+			}`,
+		},
+		{
+			name: "registered hint",
+			opts: []Option{RegisterMarshalerHint(opaque{}, opaqueHint)},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["Selector", "Selectors", "Opaque"],
+				"properties": {
+					"Selector": ` + selectorSchema + `,
+					"Selectors": {"type": "array", "items": ` + selectorSchema + `},
+					"Opaque": {"type": "array", "items": {"type": ["number", "string"]}}
+				}
+			}`,
+		},
+		{
+			name:  "nil hint",
+			opts:  []Option{RegisterMarshalerHint(opaque{}, nil)},
+			isErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{MapKeywords(KeywordRenames{"propertyOrder": ""})}, tt.opts...)
+			got, err := GenerateString(T{Selectors: []selector{{}}}, opts...)
+			switch {
+			case tt.isErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case !tt.isErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.isErr:
+				return
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestMarshalerHint_plan(t *testing.T) {
+	type T struct {
+		Selector selector
+		Opaque   opaque
+		Point    point
+	}
+
+	p, err := Plan(T{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	want := []string{"#/properties/Opaque: representation of json.Marshaler jsonschema_test.opaque is assumed to be a string without MarshalerHint"}
+	if !reflect.DeepEqual(p.Warnings, want) {
+		t.Errorf("want %q but got %q", want, p.Warnings)
+	}
+}
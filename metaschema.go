@@ -0,0 +1,111 @@
+package jsonschema
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidateOutputEnv is a name of an environment variable.
+// If it is set to "1", every generation validates its output as ValidateOutput,
+// which is useful to run tests of custom options with validation always on.
+const ValidateOutputEnv = "JSONSCHEMA_VALIDATE_OUTPUT"
+
+// metaSchemaFS has meta-schemas of drafts and flavors.
+// Vocabularies of drafts 2019-09 and 2020-12 are flattened into schemas of draft-07,
+// so they can be compiled without $recursiveRef and $dynamicRef.
+//
+//go:embed metaschemas/*.json
+var metaSchemaFS embed.FS
+
+// metaSchemaFiles are files of meta-schemas in metaSchemaFS.
+var metaSchemaFiles = map[SchemaDraft]string{
+	Draft04:     "metaschemas/draft-04.json",
+	Draft06:     "metaschemas/draft-06.json",
+	Draft07:     "metaschemas/draft-07.json",
+	Draft201909: "metaschemas/draft-2019-09.json",
+	Draft202012: "metaschemas/draft-2020-12.json",
+}
+
+// openAPI3MetaSchemaFile is the file of the meta-schema of schema objects of OpenAPI 3.0.
+const openAPI3MetaSchemaFile = "metaschemas/openapi-3.0.json"
+
+// metaSchemas are meta-schemas by their files which are compiled at the first use.
+var metaSchemas struct {
+	sync.Mutex
+	m map[string]*gojsonschema.Schema
+}
+
+// ValidateOutput validates generated schemas against the meta-schema of the target draft
+// before returning them, which catches bugs of custom options and Generators early
+// instead of at consumers of the schemas. DraftUnspecified is validated as draft 2020-12
+// and schemas of the OpenAPI3 and KubernetesCRD flavors are validated as schema objects of OpenAPI 3.0.
+// If a schema is invalid, Generate writes nothing and returns an error which matches ErrInvalidOutput.
+// The environment variable JSONSCHEMA_VALIDATE_OUTPUT also turns it on.
+func ValidateOutput() Option {
+	return configOption(func(c *config) {
+		c.validateOutput = true
+	})
+}
+
+// validatesOutput reports whether generated schemas are validated by ValidateOutput or the environment.
+func (c *config) validatesOutput() bool {
+	return c.validateOutput || os.Getenv(ValidateOutputEnv) == "1"
+}
+
+// metaSchema returns the compiled meta-schema of generated schemas.
+func (c *config) metaSchema() (*gojsonschema.Schema, error) {
+	file := openAPI3MetaSchemaFile
+	if c.flavor != OpenAPI3 && c.flavor != KubernetesCRD {
+		d := c.draft
+		if d == DraftUnspecified {
+			d = Draft202012
+		}
+		file = metaSchemaFiles[d]
+	}
+
+	metaSchemas.Lock()
+	defer metaSchemas.Unlock()
+	if s, ok := metaSchemas.m[file]; ok {
+		return s, nil
+	}
+
+	b, err := metaSchemaFS.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(b))
+	if err != nil {
+		return nil, err
+	}
+	if metaSchemas.m == nil {
+		metaSchemas.m = map[string]*gojsonschema.Schema{}
+	}
+	metaSchemas.m[file] = s
+	return s, nil
+}
+
+// validateOutputOf validates the encoded schema against its meta-schema.
+func (c *config) validateOutputOf(schema []byte) error {
+	ms, err := c.metaSchema()
+	if err != nil {
+		return err
+	}
+	r, err := ms.Validate(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return newError(ErrInvalidOutput, "", err)
+	}
+	if r.Valid() {
+		return nil
+	}
+
+	msgs := make([]string, len(r.Errors()))
+	for i, e := range r.Errors() {
+		msgs[i] = e.String()
+	}
+	return newError(ErrInvalidOutput, "", fmt.Errorf("schema is not valid against the meta-schema: %s", strings.Join(msgs, "; ")))
+}
@@ -0,0 +1,72 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+// TestMain validates all generated schemas of tests against meta-schemas.
+func TestMain(m *testing.M) {
+	if os.Getenv(ValidateOutputEnv) == "" {
+		os.Setenv(ValidateOutputEnv, "1")
+	}
+	os.Exit(m.Run())
+}
+
+// rawGenerator generates the schema as it is.
+type rawGenerator string
+
+func (g rawGenerator) JSONSchema(w io.Writer, opts ...Option) error {
+	_, err := io.WriteString(w, string(g))
+	return err
+}
+
+func TestValidateOutput(t *testing.T) {
+	type T struct {
+		N int `json:"n"`
+	}
+
+	set := func(key string, value interface{}) Option {
+		return ByReference("#/properties/n", func(o Object) (Object, error) {
+			o.Set(key, value)
+			return o, nil
+		})
+	}
+
+	cases := []struct {
+		name  string
+		v     interface{}
+		opts  []Option
+		valid bool
+	}{
+		{"valid", T{}, nil, true},
+		{"invalid type", T{}, []Option{set("type", "int")}, false},
+		{"negative minLength", T{}, []Option{set("minLength", -1)}, false},
+		{"boolean exclusiveMinimum of draft-04", T{}, []Option{Draft(Draft04), set("minimum", 0), set("exclusiveMinimum", true)}, true},
+		{"boolean exclusiveMinimum of draft 2020-12", T{}, []Option{Draft(Draft202012), set("exclusiveMinimum", true)}, false},
+		{"prefixItems of draft 2020-12", T{}, []Option{set("prefixItems", []interface{}{})}, false},
+		{"valid generator", rawGenerator(`{"type": "string"}`), nil, true},
+		{"invalid generator", rawGenerator(`{"type": 1}`), nil, false},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Generate(&buf, tt.v, append(tt.opts, ValidateOutput())...)
+			switch {
+			case tt.valid && err != nil:
+				t.Fatal("unexpected error:", err)
+			case !tt.valid && !errors.Is(err, ErrInvalidOutput):
+				t.Fatal("want ErrInvalidOutput but got", err)
+			case !tt.valid && buf.Len() != 0:
+				t.Errorf("invalid schema is written: %s", buf.String())
+			}
+		})
+	}
+}
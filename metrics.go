@@ -0,0 +1,90 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Names of metrics which are reported to a Meter.
+const (
+	// MetricGenerations counts generations of schemas.
+	// It has "type" and "result" ("ok", "error" or "budget_exceeded") attributes.
+	MetricGenerations = "jsonschema.generations"
+	// MetricDuration records durations of generations in seconds.
+	MetricDuration = "jsonschema.generation.duration"
+	// MetricNodes records the number of generated objects in a schema.
+	MetricNodes = "jsonschema.generation.nodes"
+	// MetricSize records the number of bytes of a generated schema.
+	MetricSize = "jsonschema.generation.size"
+	// MetricCacheHits counts schemas which are read from a Cache.
+	MetricCacheHits = "jsonschema.cache.hits"
+	// MetricCacheMisses counts schemas which are not found in a Cache.
+	MetricCacheMisses = "jsonschema.cache.misses"
+)
+
+// Attribute is a key-value pair which is attached to a measurement.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Meter receives measurements of generation.
+// Its methods correspond to counters and histograms of OpenTelemetry,
+// so an adapter can be written as follows:
+//
+//	func (m *otelMeter) Count(name string, incr int64, attrs ...jsonschema.Attribute) {
+//		m.counters[name].Add(context.Background(), incr, metric.WithAttributes(convert(attrs)...))
+//	}
+//
+// A Meter must be safe for concurrent use.
+type Meter interface {
+	// Count adds incr to the counter of the name.
+	Count(name string, incr int64, attrs ...Attribute)
+	// Record records the value to the histogram of the name.
+	Record(name string, value float64, attrs ...Attribute)
+}
+
+// WithMeter reports measurements of generation to the meter.
+func WithMeter(m Meter) Option {
+	return configOption(func(c *config) {
+		c.meter = m
+	})
+}
+
+// measure reports measurements of a generation of v which is started at start.
+func (g *gen) measure(start time.Time, v interface{}, err error) {
+	m := g.cfg.meter
+	if m == nil {
+		return
+	}
+
+	typ := Attribute{Key: "type", Value: fmt.Sprintf("%T", v)}
+	result := Attribute{Key: "result", Value: "ok"}
+	switch {
+	case errors.Is(err, ErrBudgetExceeded):
+		result.Value = "budget_exceeded"
+	case err != nil:
+		result.Value = "error"
+	}
+
+	m.Count(MetricGenerations, 1, typ, result)
+	m.Record(MetricDuration, time.Since(start).Seconds(), typ)
+	m.Record(MetricNodes, float64(g.nodes), typ)
+	if g.size > 0 {
+		m.Record(MetricSize, float64(g.size), typ)
+	}
+}
+
+// countWriter counts bytes which are written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,112 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type recordMeter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	values map[string][]float64
+	attrs  map[string][]Attribute
+}
+
+func newRecordMeter() *recordMeter {
+	return &recordMeter{
+		counts: map[string]int64{},
+		values: map[string][]float64{},
+		attrs:  map[string][]Attribute{},
+	}
+}
+
+func (m *recordMeter) Count(name string, incr int64, attrs ...Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[name] += incr
+	m.attrs[name] = attrs
+}
+
+func (m *recordMeter) Record(name string, value float64, attrs ...Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[name] = append(m.values[name], value)
+	m.attrs[name] = attrs
+}
+
+func TestWithMeter(t *testing.T) {
+	type T struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		Owner *T       `json:"owner"`
+	}
+
+	m := newRecordMeter()
+	if _, err := GenerateBytes(T{Tags: []string{"a"}, Owner: &T{}}, WithMeter(m)); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := m.counts[MetricGenerations]; got != 1 {
+		t.Errorf("want 1 generation but got %d", got)
+	}
+	want := []Attribute{{"type", "jsonschema_test.T"}, {"result", "ok"}}
+	if got := m.attrs[MetricGenerations]; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("want attributes %v but got %v", want, got)
+	}
+	if got := m.values[MetricDuration]; len(got) != 1 || got[0] < 0 {
+		t.Errorf("unexpected durations %v", got)
+	}
+	// root, name, tags, items of tags, owner and name, tags and owner of the owner
+	if got := m.values[MetricNodes]; len(got) != 1 || got[0] != 8 {
+		t.Errorf("want 8 nodes but got %v", got)
+	}
+
+	if _, err := GenerateBytes(make(chan int), WithMeter(m)); err == nil {
+		t.Fatal("expected error does not occur")
+	}
+	if got := m.attrs[MetricGenerations]; len(got) != 2 || got[1].Value != "error" {
+		t.Errorf("want error result but got %v", got)
+	}
+
+	c := NewCache(t.TempDir())
+	for i := 0; i < 2; i++ {
+		if _, err := c.GenerateBytes(T{}, WithMeter(m)); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+	if hits, misses := m.counts[MetricCacheHits], m.counts[MetricCacheMisses]; hits != 1 || misses != 1 {
+		t.Errorf("want 1 hit and 1 miss but got %d and %d", hits, misses)
+	}
+}
+
+func TestMaxNodes(t *testing.T) {
+	type T struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		Owner *T       `json:"owner"`
+	}
+	v := T{Tags: []string{"a"}, Owner: &T{}}
+
+	m := newRecordMeter()
+	b, err := GenerateBytes(v, MaxNodes(8), WithMeter(m))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if got := m.values[MetricSize]; len(got) != 1 || got[0] != float64(len(b)) {
+		t.Errorf("want size %d but got %v", len(b), got)
+	}
+
+	_, err = GenerateBytes(v, MaxNodes(7), WithMeter(m))
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("want ErrBudgetExceeded but got %v", err)
+	}
+	if got := m.attrs[MetricGenerations]; len(got) != 2 || got[1].Value != "budget_exceeded" {
+		t.Errorf("want budget_exceeded result but got %v", got)
+	}
+	if got := m.values[MetricSize]; len(got) != 1 {
+		t.Errorf("size of failed generation is recorded: %v", got)
+	}
+}
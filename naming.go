@@ -0,0 +1,269 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MetricNamingViolations counts property names which violate the convention of EnforceNaming with NamingWarn.
+// It has "type" and "convention" attributes.
+const MetricNamingViolations = "jsonschema.naming.violations"
+
+// NamingConvention is a convention of property names such as SnakeCase.
+type NamingConvention interface {
+	// Convert converts the name to follow the convention.
+	// A name follows the convention if it is not changed.
+	Convert(name string) string
+}
+
+// NamingFunc is a NamingConvention by a function.
+type NamingFunc func(name string) string
+
+// Convert implements NamingConvention.
+func (f NamingFunc) Convert(name string) string {
+	return f(name)
+}
+
+// Predefined naming conventions.
+// Words of names are separated by non-alphanumeric characters and cases such as "UserID" and "HTTPServer",
+// so "UserID" becomes "user_id" in SnakeCase and "userId" in CamelCase.
+// Digits do not begin words, so "address2" follows SnakeCase.
+var (
+	// SnakeCase is a convention of names such as "user_id".
+	SnakeCase NamingConvention = namingCase("snake_case")
+	// CamelCase is a convention of names such as "userId".
+	CamelCase NamingConvention = namingCase("camelCase")
+	// KebabCase is a convention of names such as "user-id".
+	KebabCase NamingConvention = namingCase("kebab-case")
+)
+
+// namingCase is a predefined naming convention which is named after an example of names.
+type namingCase string
+
+func (c namingCase) Convert(name string) string {
+	words := nameWords(name)
+	switch c {
+	case "snake_case":
+		return strings.Join(words, "_")
+	case "kebab-case":
+		return strings.Join(words, "-")
+	}
+	for i := 1; i < len(words); i++ {
+		rs := []rune(words[i])
+		rs[0] = unicode.ToUpper(rs[0])
+		words[i] = string(rs)
+	}
+	return strings.Join(words, "")
+}
+
+func (c namingCase) String() string {
+	return string(c)
+}
+
+// nameWords returns lower case words of the name.
+func nameWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		rs := []rune(part)
+		start := 0
+		for i := 1; i <= len(rs); i++ {
+			if i < len(rs) && !isNameBoundary(rs, i) {
+				continue
+			}
+			words = append(words, strings.ToLower(string(rs[start:i])))
+			start = i
+		}
+	}
+	return words
+}
+
+// isNameBoundary reports whether a new word of a name begins at rs[i].
+// Unlike isWordBoundary, digits continue words.
+func isNameBoundary(rs []rune, i int) bool {
+	prev, cur := rs[i-1], rs[i]
+	switch {
+	case (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(cur):
+		return true
+	case unicode.IsUpper(prev) && unicode.IsUpper(cur):
+		// "HTTPServer" begins "Server" at "S"
+		return i+1 < len(rs) && unicode.IsLower(rs[i+1])
+	}
+	return false
+}
+
+// NamingAction is an action for property names which violate a naming convention.
+type NamingAction int
+
+const (
+	// NamingError makes generation fail with an error which matches ErrTagSyntax.
+	NamingError NamingAction = iota
+	// NamingWarn generates names as they are and reports violations
+	// to the Meter as MetricNamingViolations and to warnings of Plan.
+	NamingWarn
+	// NamingFix converts names to follow the convention in schemas.
+	// Documents must be encoded with the same names such as by an encoder which renames keys,
+	// otherwise the schemas do not match them. Names which collide after conversion are errors
+	// which match ErrNameCollision.
+	NamingFix
+)
+
+// EnforceNaming enforces the naming convention to names of properties and groups
+// with the action for names which violate it.
+// Names of maps such as patternProperties are not checked.
+// LintNames reports violations with suggestions of fixes without generating schemas.
+func EnforceNaming(c NamingConvention, action NamingAction) Option {
+	return configOption(func(cfg *config) {
+		cfg.naming = &namingPolicy{convention: c, action: action}
+	})
+}
+
+// namingPolicy is the setting of EnforceNaming.
+type namingPolicy struct {
+	convention NamingConvention
+	action     NamingAction
+}
+
+// name returns the name of the convention such as "snake_case" or its type.
+func (p *namingPolicy) name() string {
+	if s, ok := p.convention.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", p.convention)
+}
+
+// NamingViolation is a property name which violates a naming convention.
+type NamingViolation struct {
+	// Ref is the reference of the object which has the property.
+	Ref string
+	// Field is a Go field such as "pkg.T.UserID" which the property is generated from.
+	Field string
+	// Name is the name of the property or the group.
+	Name string
+	// Suggestion is the name which follows the convention.
+	Suggestion string
+	// Group reports whether Name is a name of a group tag.
+	Group bool
+}
+
+// Fix returns the struct tag which fixes the violation such as `json:"user_id"`.
+func (v NamingViolation) Fix() string {
+	if v.Group {
+		return fmt.Sprintf("group:%q", v.Suggestion)
+	}
+	return fmt.Sprintf("json:%q", v.Suggestion)
+}
+
+func (v NamingViolation) String() string {
+	return v.Field + ": " + v.problem()
+}
+
+// problem describes the violation without the field.
+func (v NamingViolation) problem() string {
+	kind := "property"
+	if v.Group {
+		kind = "group"
+	}
+	return fmt.Sprintf("%s %q should be %q: use `%s`", kind, v.Name, v.Suggestion, v.Fix())
+}
+
+// LintNames reports names of properties and groups in the schema of v which violate the naming convention.
+// Violations are sorted by their fields and names.
+// Violations do not make generation fail and names are not fixed even if EnforceNaming is given.
+func LintNames(v interface{}, c NamingConvention, opts ...Option) ([]NamingViolation, error) {
+	cfg := newConfig(opts)
+	cfg.naming = &namingPolicy{convention: c, action: NamingWarn}
+	g := gen{cfg: cfg}
+	o := &obj{
+		m:    map[string]interface{}{},
+		ref:  cfg.refs().Root(),
+		root: true,
+	}
+	if err := g.do(o, reflect.ValueOf(v), opts...); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(g.violations, func(i, j int) bool {
+		a, b := g.violations[i], g.violations[j]
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Name < b.Name
+	})
+	return g.violations, nil
+}
+
+// applyNaming checks names of the fields of the struct type t by the naming convention of EnforceNaming
+// and returns the fields whose names are fixed by NamingFix.
+func (g *gen) applyNaming(parent Object, t reflect.Type, fields []field) ([]field, error) {
+	p := g.cfg.naming
+	if p == nil {
+		return fields, nil
+	}
+
+	groups := map[string]string{}
+	names := make(map[string]string, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		// a group is checked once but all of its fields are fixed
+		if f.group != "" {
+			fixed, ok := groups[f.group]
+			if !ok {
+				var err error
+				if fixed, err = g.checkName(parent, t, f.goName, f.group, true); err != nil {
+					return nil, err
+				}
+				groups[f.group] = fixed
+			}
+			f.group = fixed
+		}
+
+		var err error
+		if f.name, err = g.checkName(parent, t, f.goName, f.name, false); err != nil {
+			return nil, err
+		}
+		// names of properties are unique in their groups
+		key := f.group + "\x00" + f.name
+		if other, ok := names[key]; ok {
+			return nil, &Error{Kind: ErrNameCollision, Ref: parent.Ref(), Field: f.goName, Err: fmt.Errorf("property %q is also converted from %s", f.name, other)}
+		}
+		names[key] = f.goName
+	}
+	return fields, nil
+}
+
+// checkName checks the name of the field by the action of EnforceNaming
+// and returns the name which is fixed by NamingFix.
+func (g *gen) checkName(parent Object, t reflect.Type, field, name string, group bool) (string, error) {
+	p := g.cfg.naming
+	fixed := p.convention.Convert(name)
+	if fixed == name {
+		return name, nil
+	}
+
+	v := NamingViolation{Ref: parent.Ref(), Field: field, Name: name, Suggestion: fixed, Group: group}
+	switch p.action {
+	case NamingError:
+		return "", &Error{Kind: ErrTagSyntax, Ref: parent.Ref(), Field: field, Err: fmt.Errorf("naming convention is violated: %s", v.problem())}
+	case NamingFix:
+		if g.plan != nil {
+			g.plan.note(parent.Ref(), "%q of %s is converted to %q", name, field, fixed)
+		}
+		return fixed, nil
+	}
+
+	g.violations = append(g.violations, v)
+	if g.plan != nil {
+		g.plan.warn("%s", v)
+	}
+	if m := g.cfg.meter; m != nil {
+		m.Count(MetricNamingViolations, 1,
+			Attribute{Key: "type", Value: t.String()},
+			Attribute{Key: "convention", Value: p.name()})
+	}
+	return name, nil
+}
@@ -0,0 +1,177 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestNamingConventions(t *testing.T) {
+	cases := []struct {
+		name         string
+		snake, camel string
+		kebab        string
+	}{
+		{"user_id", "user_id", "userId", "user-id"},
+		{"UserID", "user_id", "userId", "user-id"},
+		{"userId", "user_id", "userId", "user-id"},
+		{"HTTPServer", "http_server", "httpServer", "http-server"},
+		{"address2", "address2", "address2", "address2"},
+		{"created-at", "created_at", "createdAt", "created-at"},
+		{"a", "a", "a", "a"},
+	}
+
+	for _, tt := range cases {
+		if got := SnakeCase.Convert(tt.name); got != tt.snake {
+			t.Errorf("SnakeCase(%q): want %q but got %q", tt.name, tt.snake, got)
+		}
+		if got := CamelCase.Convert(tt.name); got != tt.camel {
+			t.Errorf("CamelCase(%q): want %q but got %q", tt.name, tt.camel, got)
+		}
+		if got := KebabCase.Convert(tt.name); got != tt.kebab {
+			t.Errorf("KebabCase(%q): want %q but got %q", tt.name, tt.kebab, got)
+		}
+	}
+}
+
+type namingUser struct {
+	ID        int    `json:"id"`
+	UserName  string `json:"userName"`
+	CreatedAt string
+	Street    string `json:"street" group:"homeAddress"`
+	City      string `json:"city" group:"homeAddress"`
+}
+
+func TestEnforceNaming(t *testing.T) {
+	v := namingUser{}
+	opts := []Option{MapKeywords(KeywordRenames{"propertyOrder": ""})}
+
+	t.Run("fix", func(t *testing.T) {
+		got, err := GenerateBytes(v, append(opts, EnforceNaming(SnakeCase, NamingFix))...)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		expect := `{
+			"title": "namingUser",
+			"type": "object",
+			"required": ["id", "user_name", "created_at", "home_address"],
+			"properties": {
+				"id": {"type": "number"},
+				"user_name": {"type": "string"},
+				"created_at": {"type": "string"},
+				"home_address": {
+					"type": "object",
+					"required": ["street", "city"],
+					"properties": {
+						"street": {"type": "string"},
+						"city": {"type": "string"}
+					}
+				}
+			}
+		}`
+		if diff := jsonDiff(t, string(got), expect); diff != "" {
+			t.Errorf("generated schema does not match to expected one: %v", diff)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := GenerateBytes(v, append(opts, EnforceNaming(SnakeCase, NamingError))...)
+		if !errors.Is(err, ErrTagSyntax) {
+			t.Fatalf("want %v but got %v", ErrTagSyntax, err)
+		}
+		var serr *Error
+		if !errors.As(err, &serr) || serr.Field != "jsonschema_test.namingUser.UserName" {
+			t.Errorf("unexpected error %v", err)
+		}
+		if !strings.Contains(err.Error(), "use `json:\"user_name\"`") {
+			t.Errorf("want a fix in %q", err)
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		m := newRecordMeter()
+		got, err := GenerateBytes(v, EnforceNaming(SnakeCase, NamingWarn), WithMeter(m))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		want, err := GenerateBytes(v)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("want %s but got %s", want, got)
+		}
+		if got := m.counts[MetricNamingViolations]; got != 3 {
+			t.Errorf("want 3 violations but got %d", got)
+		}
+		attrs := []Attribute{{"type", "jsonschema_test.namingUser"}, {"convention", "snake_case"}}
+		if got := m.attrs[MetricNamingViolations]; !reflect.DeepEqual(got, attrs) {
+			t.Errorf("want attributes %v but got %v", attrs, got)
+		}
+
+		p, err := Plan(v, EnforceNaming(SnakeCase, NamingWarn))
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if len(p.Warnings) != 3 || !strings.Contains(p.Warnings[0], `property "userName" should be "user_name"`) {
+			t.Errorf("unexpected warnings %q", p.Warnings)
+		}
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		type T struct {
+			UserID  string `json:"userId"`
+			UserID2 string `json:"user_id"`
+		}
+		_, err := GenerateBytes(T{}, EnforceNaming(SnakeCase, NamingFix))
+		if !errors.Is(err, ErrNameCollision) {
+			t.Errorf("want %v but got %v", ErrNameCollision, err)
+		}
+	})
+
+	t.Run("func", func(t *testing.T) {
+		upper := NamingFunc(strings.ToUpper)
+		got, err := GenerateBytes(struct {
+			Name string `json:"name"`
+		}{}, append(opts, EnforceNaming(upper, NamingFix))...)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		expect := `{"type": "object", "required": ["NAME"], "properties": {"NAME": {"type": "string"}}}`
+		if diff := jsonDiff(t, string(got), expect); diff != "" {
+			t.Errorf("generated schema does not match to expected one: %v", diff)
+		}
+	})
+}
+
+func TestLintNames(t *testing.T) {
+	got, err := LintNames(namingUser{}, CamelCase, EnforceNaming(SnakeCase, NamingError))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := []NamingViolation{
+		{Ref: "#/", Field: "jsonschema_test.namingUser.CreatedAt", Name: "CreatedAt", Suggestion: "createdAt"},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("want %v but got %v", expect, got)
+	}
+	if want := "jsonschema_test.namingUser.CreatedAt: property \"CreatedAt\" should be \"createdAt\": use `json:\"createdAt\"`"; got[0].String() != want {
+		t.Errorf("want %s but got %s", want, got[0])
+	}
+
+	got, err = LintNames(namingUser{}, KebabCase)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	var fixes []string
+	for _, v := range got {
+		fixes = append(fixes, v.Fix())
+	}
+	want := []string{`json:"created-at"`, `group:"home-address"`, `json:"user-name"`}
+	if !reflect.DeepEqual(fixes, want) {
+		t.Errorf("want %q but got %q", want, fixes)
+	}
+}
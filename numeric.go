@@ -0,0 +1,52 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NumericOverride registers the schema of the numeric Go type of v
+// whose JSON representation differs from its kind, such as an int64 of Unix time
+// or a float64 which MarshalJSON encodes as a string.
+// It is same as RegisterTypeSchema but the type must be a number,
+// so UnixTime and UnixDateTime are built on it for common cases.
+// Options of fields such as minimum tags are applied after the schema.
+func NumericOverride(v interface{}, schema map[string]interface{}) Option {
+	t := reflect.TypeOf(v)
+	if !isNumeric(t) {
+		return errOption(newError(ErrUnsupportedType, "", fmt.Errorf("%v is not a numeric type", t)))
+	}
+	return RegisterTypeSchema(v, schema)
+}
+
+// UnixTime generates values of the numeric type of v as integers of seconds
+// since the Unix epoch with the format "unix-time".
+func UnixTime(v interface{}) Option {
+	return NumericOverride(v, map[string]interface{}{
+		"type":   "integer",
+		"format": "unix-time",
+	})
+}
+
+// UnixDateTime generates values of the numeric type of v as date-time strings,
+// which is for Unix time types whose MarshalJSON or MarshalText encodes them in RFC 3339.
+func UnixDateTime(v interface{}) Option {
+	return NumericOverride(v, map[string]interface{}{
+		"type":   "string",
+		"format": "date-time",
+	})
+}
+
+// isNumeric reports whether values of t are numbers in Go.
+func isNumeric(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
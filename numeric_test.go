@@ -0,0 +1,99 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+// unixTime is seconds since the Unix epoch.
+type unixTime int64
+
+// rfcTime is seconds since the Unix epoch which is encoded in RFC 3339.
+type rfcTime int64
+
+func (t rfcTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(time.Unix(int64(t), 0).UTC().Format(time.RFC3339))), nil
+}
+
+// cents is an amount of money which is encoded as a decimal string.
+type cents int64
+
+func TestNumericOverride(t *testing.T) {
+	type T struct {
+		CreatedAt unixTime   `json:"createdAt" jsonschema:"minimum=0"`
+		UpdatedAt rfcTime    `json:"updatedAt"`
+		History   []unixTime `json:"history"`
+		Price     cents      `json:"price"`
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+		isErr  bool
+	}{
+		{
+			name: "default",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["createdAt", "updatedAt", "history", "price"],
+				"properties": {
+					"createdAt": {"type": "number", "minimum": 0, "propertyOrder": 0},
+					"updatedAt": {"type": "string", "propertyOrder": 1},
+					"history": {"type": "array", "items": {"type": "number"}, "propertyOrder": 2},
+					"price": {"type": "number", "propertyOrder": 3}
+				}
+			}`,
+		},
+		{
+			name: "overrides",
+			opts: []Option{
+				UnixTime(unixTime(0)),
+				UnixDateTime(rfcTime(0)),
+				NumericOverride(cents(0), map[string]interface{}{
+					"type":    "string",
+					"pattern": `^-?[0-9]+\.[0-9]{2}$`,
+				}),
+			},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["createdAt", "updatedAt", "history", "price"],
+				"properties": {
+					"createdAt": {"type": "integer", "format": "unix-time", "minimum": 0, "propertyOrder": 0},
+					"updatedAt": {"type": "string", "format": "date-time", "propertyOrder": 1},
+					"history": {"type": "array", "items": {"type": "integer", "format": "unix-time"}, "propertyOrder": 2},
+					"price": {"type": "string", "pattern": "^-?[0-9]+\\.[0-9]{2}$", "propertyOrder": 3}
+				}
+			}`,
+		},
+		{
+			name:  "not numeric",
+			opts:  []Option{UnixTime(time.Time{})},
+			isErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{History: []unixTime{0}}, tt.opts...)
+			switch {
+			case tt.isErr && !errors.Is(err, ErrUnsupportedType):
+				t.Fatal("expected error does not occur:", err)
+			case !tt.isErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.isErr:
+				return
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ObjectKeywords is implemented by structs which set keywords of their own objects
+// rather than their properties, such as additionalProperties and minProperties.
+// JSONSchemaObject is called with a zero value of the type.
+type ObjectKeywords interface {
+	JSONSchemaObject() map[string]interface{}
+}
+
+// setObjectKeywords sets keywords of the object of a struct.
+// Keywords are given by ObjectKeywords or tags of blank fields as follows:
+//
+//	type T struct {
+//		_ struct{} `jsonschema:"additionalProperties=false,minProperties=1,propertyNames=^[a-z]+$"`
+//	}
+//
+// Values of tags are decoded as JSON, otherwise they are strings.
+// A string value of propertyNames is a pattern of property names.
+// ObjectKeywords is applied after tags.
+func setObjectKeywords(o Object, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if ft.Name != "_" {
+			continue
+		}
+
+		for k, s := range parseSchemaTag(ft.Tag.Get("jsonschema")) {
+			if s == "" {
+				return &Error{
+					Kind:  ErrTagSyntax,
+					Ref:   o.Ref(),
+					Field: t.String() + "." + ft.Name,
+					Err:   fmt.Errorf("keyword %q of the object does not have a value", k),
+				}
+			}
+
+			value := directiveValue(s)
+			if pattern, ok := value.(string); ok && k == "propertyNames" {
+				value = map[string]interface{}{"pattern": pattern}
+			}
+			o.Set(k, value)
+		}
+	}
+
+	var kw ObjectKeywords
+	switch {
+	case t.Implements(objectKeywordsType):
+		kw = reflect.Zero(t).Interface().(ObjectKeywords)
+	case reflect.PtrTo(t).Implements(objectKeywordsType):
+		kw = reflect.New(t).Interface().(ObjectKeywords)
+	}
+	if kw != nil {
+		for k, value := range kw.JSONSchemaObject() {
+			o.Set(k, value)
+		}
+	}
+
+	return nil
+}
+
+var objectKeywordsType = reflect.TypeOf((*ObjectKeywords)(nil)).Elem()
+
+// StrictObjects sets "additionalProperties": false to objects of all structs,
+// so unknown properties are rejected by validation.
+// A struct can allow them by a tag of a blank field such as
+//
+//	type Extensible struct {
+//		_ struct{} `jsonschema:"additionalProperties=true"`
+//	}
+//
+// or ObjectKeywords, and a struct can also reject them by the tag without StrictObjects.
+// Schemas of maps are not affected.
+func StrictObjects() Option {
+	return configOption(func(c *config) {
+		c.strictObjects = true
+	})
+}
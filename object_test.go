@@ -0,0 +1,190 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type closedLabels struct {
+	Name string `json:"name"`
+}
+
+func (*closedLabels) JSONSchemaObject() map[string]interface{} {
+	return map[string]interface{}{
+		"additionalProperties": false,
+		"maxProperties":        1,
+	}
+}
+
+func TestObjectKeywords(t *testing.T) {
+	type Marker struct {
+		_    struct{} `jsonschema:"additionalProperties=false,minProperties=1,propertyNames=^[a-z]+$"`
+		Name string   `json:"name"`
+	}
+	type Bad struct {
+		_ struct{} `jsonschema:"minProperties"`
+	}
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		expect string
+		kind   error
+	}{
+		{
+			name: "marker field",
+			v:    Marker{},
+			expect: `{
+				"type": "object",
+				"title": "Marker",
+				"required": ["name"],
+				"properties": {"name": {"type": "string", "propertyOrder": 0}},
+				"additionalProperties": false,
+				"minProperties": 1,
+				"propertyNames": {"pattern": "^[a-z]+$"}
+			}`,
+		},
+		{
+			name: "interface",
+			v:    closedLabels{},
+			expect: `{
+				"type": "object",
+				"title": "closedLabels",
+				"required": ["name"],
+				"properties": {"name": {"type": "string", "propertyOrder": 0}},
+				"additionalProperties": false,
+				"maxProperties": 1
+			}`,
+		},
+		{
+			name: "no value",
+			v:    Bad{},
+			kind: ErrTagSyntax,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v)
+			if tt.kind != nil {
+				if !errors.Is(err, tt.kind) {
+					t.Fatalf("want %v but got %v", tt.kind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestStrictObjects(t *testing.T) {
+	type Extensible struct {
+		_    struct{} `jsonschema:"additionalProperties=true"`
+		Name string   `json:"name"`
+	}
+	type Address struct {
+		City string `json:"city"`
+	}
+	type T struct {
+		Address Address           `json:"address"`
+		Labels  map[string]string `json:"labels"`
+		Ext     Extensible        `json:"ext"`
+		Zip     string            `json:"zip" group:"location"`
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "default",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["address", "labels", "ext", "location"],
+				"properties": {
+					"address": {
+						"title": "Address",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string", "propertyOrder": 0}},
+						"propertyOrder": 0
+					},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}, "propertyOrder": 1},
+					"ext": {
+						"title": "Extensible",
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string", "propertyOrder": 0}},
+						"additionalProperties": true,
+						"propertyOrder": 2
+					},
+					"location": {
+						"type": "object",
+						"required": ["zip"],
+						"properties": {"zip": {"type": "string", "propertyOrder": 0}},
+						"propertyOrder": 3
+					}
+				}
+			}`,
+		},
+		{
+			name: "strict",
+			opts: []Option{StrictObjects()},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["address", "labels", "ext", "location"],
+				"properties": {
+					"address": {
+						"title": "Address",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string", "propertyOrder": 0}},
+						"additionalProperties": false,
+						"propertyOrder": 0
+					},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}, "propertyOrder": 1},
+					"ext": {
+						"title": "Extensible",
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string", "propertyOrder": 0}},
+						"additionalProperties": true,
+						"propertyOrder": 2
+					},
+					"location": {
+						"type": "object",
+						"required": ["zip"],
+						"properties": {"zip": {"type": "string", "propertyOrder": 0}},
+						"additionalProperties": false,
+						"propertyOrder": 3
+					}
+				},
+				"additionalProperties": false
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{Labels: map[string]string{}}, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
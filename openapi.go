@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+)
+
+// annotationKeys are keywords which are merged from OpenAPI documents.
+var annotationKeys = []string{"title", "description", "examples"}
+
+// OpenAPIAnnotations reads an OpenAPI document in JSON or YAML from r
+// and merges annotations of its components.schemas into generated schemas,
+// which eases migration from hand-written specs without losing curated prose.
+// A component whose name is same as the name of a struct type such as "User"
+// gives title, description and examples to the object of the type and its properties.
+// An example of OpenAPI 3.0 becomes examples.
+// Annotations which are given by tags and directives have priority
+// and other options are applied after the merge.
+func OpenAPIAnnotations(r io.Reader) Option {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot read OpenAPI document: %w", err))
+	}
+	// YAML is a superset of JSON
+	doc, err := yamlToJSON(b)
+	if err != nil {
+		return errOption(err)
+	}
+	v, err := decodeJSON(doc)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot decode OpenAPI document: %w", err))
+	}
+
+	root, _ := v.(map[string]interface{})
+	components, _ := root["components"].(map[string]interface{})
+	schemas, ok := components["schemas"].(map[string]interface{})
+	if !ok {
+		return errOption(fmt.Errorf("jsonschema: OpenAPI document does not have components.schemas"))
+	}
+
+	return configOption(func(c *config) {
+		if c.annotations == nil {
+			c.annotations = map[string]map[string]interface{}{}
+		}
+		for name, s := range schemas {
+			if m, ok := s.(map[string]interface{}); ok {
+				c.annotations[name] = m
+			}
+		}
+	})
+}
+
+// OpenAPIAnnotationsFS is same as OpenAPIAnnotations but it reads the document
+// from the file of the name in fsys.
+func OpenAPIAnnotationsFS(fsys fs.FS, name string) Option {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot open OpenAPI document: %w", err))
+	}
+	defer f.Close()
+	return OpenAPIAnnotations(f)
+}
+
+// annotate merges annotations of the component of the struct type t into o.
+func (c *config) annotate(o Object, t reflect.Type) {
+	component, ok := c.annotations[t.Name()]
+	if !ok || t.Name() == "" {
+		return
+	}
+	mergeAnnotations(o, component)
+}
+
+// mergeAnnotations sets annotations of the component which o does not have yet
+// and merges annotations of properties and items recursively.
+func mergeAnnotations(o Object, component map[string]interface{}) {
+	for _, k := range annotationKeys {
+		v, ok := component[k]
+		if k == "examples" && !ok {
+			if example, ok2 := component["example"]; ok2 {
+				v, ok = []interface{}{example}, true
+			}
+		}
+		if _, exists := o.Get(k); ok && !exists {
+			o.Set(k, copyValue(v))
+		}
+	}
+
+	props, _ := o.Get("properties")
+	generated, _ := props.(map[string]interface{})
+	cprops, _ := component["properties"].(map[string]interface{})
+	for name, p := range generated {
+		pm, ok := p.(map[string]interface{})
+		cp, ok2 := cprops[name].(map[string]interface{})
+		if ok && ok2 {
+			mergeAnnotations(&obj{m: pm}, cp)
+		}
+	}
+
+	items, _ := o.Get("items")
+	im, ok := items.(map[string]interface{})
+	ci, ok2 := component["items"].(map[string]interface{})
+	if ok && ok2 {
+		mergeAnnotations(&obj{m: im}, ci)
+	}
+}
@@ -0,0 +1,79 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestOpenAPIAnnotations(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		ID      string    `json:"id" jsonschema:"description=identifier by tag"`
+		Name    string    `json:"name"`
+		Tags    []string  `json:"tags"`
+		Home    Address   `json:"home"`
+		Offices []Address `json:"offices"`
+	}
+
+	spec := `
+openapi: 3.0.3
+components:
+  schemas:
+    User:
+      description: A user of the service.
+      example: {id: "1", name: gopher}
+      properties:
+        id:
+          description: identifier by the spec
+        name:
+          description: Display name.
+          example: gopher
+        tags:
+          items:
+            description: A tag.
+        home:
+          $ref: '#/components/schemas/Address'
+    Address:
+      title: Postal address
+      properties:
+        city:
+          description: City name.
+`
+
+	got, err := GenerateString(User{Tags: []string{""}, Offices: []Address{{}}}, OpenAPIAnnotations(strings.NewReader(spec)))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	address := `"type": "object", "title": "Address", "required": ["city"],
+		"properties": {"city": {"type": "string", "description": "City name.", "propertyOrder": 0}}`
+	expect := `{
+		"type": "object",
+		"title": "User",
+		"description": "A user of the service.",
+		"examples": [{"id": "1", "name": "gopher"}],
+		"required": ["id", "name", "tags", "home", "offices"],
+		"properties": {
+			"id": {"type": "string", "description": "identifier by tag", "propertyOrder": 0},
+			"name": {"type": "string", "description": "Display name.", "examples": ["gopher"], "propertyOrder": 1},
+			"tags": {"type": "array", "items": {"type": "string", "description": "A tag."}, "propertyOrder": 2},
+			"home": {` + address + `, "propertyOrder": 3},
+			"offices": {"type": "array", "items": {` + address + `}, "propertyOrder": 4}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	if _, err := GenerateString(User{}, OpenAPIAnnotations(strings.NewReader(`{"openapi": "3.1.0"}`))); err == nil {
+		t.Error("expected error does not occur")
+	}
+	if _, err := GenerateString(User{}, OpenAPIAnnotations(strings.NewReader(`{`))); err == nil || errors.Is(err, ErrTagSyntax) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,17 +1,52 @@
 package jsonschema
 
-import "github.com/minio/pkg/wildcard"
+import (
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/minio/pkg/wildcard"
+)
 
 // Object is interface of JSON object.
+// Options receive objects of schemas and can change them by the methods.
 type Object interface {
+	// Set sets the value of the keyword.
 	Set(key string, value interface{})
+	// Get returns the value of the keyword and reports whether the object has it.
 	Get(key string) (interface{}, bool)
+	// Delete deletes the keyword.
+	Delete(key string)
+	// Merge merges the schema fragment into the object as JSON Merge Patch (RFC 7396):
+	// objects are merged recursively and null values delete keywords.
+	// Values of the fragment are copied.
+	Merge(fragment map[string]interface{})
+	// Walk calls f with the object and its subschemas such as properties and items
+	// in depth-first order. Keywords and names are visited in alphabetical order.
+	// References of subschemas are joined to the reference of the object by PathRefs.
+	// It stops at the first error of f and returns it.
+	Walk(f func(o Object) error) error
+	// Ref returns the reference of the object which is matched by ByReference.
 	Ref() string
 }
 
+// NewObject creates an Object of the schema m whose reference is ref,
+// which is useful to build schemas and to test custom options.
+// Changes of the object are applied to m. If m is nil, the object is empty.
+func NewObject(ref string, m map[string]interface{}) Object {
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	return &obj{m: m, ref: ref}
+}
+
 type obj struct {
 	m   map[string]interface{}
 	ref string
+	// root is true for the root object.
+	root bool
 }
 
 func (o *obj) Set(key string, value interface{}) {
@@ -23,11 +58,156 @@ func (o *obj) Get(key string) (value interface{}, ok bool) {
 	return
 }
 
+func (o *obj) Delete(key string) {
+	delete(o.m, key)
+}
+
+func (o *obj) Merge(fragment map[string]interface{}) {
+	applyPatch(o, fragment)
+}
+
+func (o *obj) Walk(f func(o Object) error) error {
+	return walkObject(o, f)
+}
+
 func (o *obj) Ref() string {
 	return o.ref
 }
 
+func (o *obj) isRoot() bool {
+	return o.root
+}
+
+// subschemaKeys are keywords whose values are subschemas.
+// Values of keywords in subschemaMapKeys are objects of subschemas
+// and values of keywords in subschemaListKeys are arrays of subschemas
+// and values of others are subschemas or arrays of them.
+var (
+	subschemaMapKeys = map[string]bool{
+		"properties": true, "patternProperties": true, "$defs": true, "definitions": true,
+		"dependentSchemas": true, "dependencies": true,
+	}
+	subschemaListKeys = map[string]bool{
+		"allOf": true, "anyOf": true, "oneOf": true, "prefixItems": true,
+	}
+	subschemaKeys = map[string]bool{
+		"items": true, "additionalItems": true, "additionalProperties": true, "contains": true,
+		"propertyNames": true, "not": true, "if": true, "then": true, "else": true,
+		"unevaluatedItems": true, "unevaluatedProperties": true, "contentSchema": true,
+	}
+)
+
+// walkObject calls f with o and its subschemas.
+func walkObject(o Object, f func(o Object) error) error {
+	if err := f(o); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(subschemaMapKeys)+len(subschemaListKeys)+len(subschemaKeys))
+	for _, m := range []map[string]bool{subschemaMapKeys, subschemaListKeys, subschemaKeys} {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, ok := o.Get(k)
+		if !ok {
+			continue
+		}
+		for _, sub := range subschemas(o.Ref(), k, v) {
+			if err := walkObject(sub, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// subschemas returns objects of subschemas in the value of the keyword of the object of ref.
+func subschemas(ref, key string, v interface{}) []Object {
+	var objs []Object
+	switch {
+	case subschemaMapKeys[key]:
+		m, _ := v.(map[string]interface{})
+		for _, name := range sortedKeys(m) {
+			if sm, ok := m[name].(map[string]interface{}); ok {
+				objs = append(objs, &obj{m: sm, ref: PathRefs{}.Join(ref, key, name)})
+			}
+		}
+	case subschemaListKeys[key] || subschemaKeys[key]:
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if subschemaKeys[key] {
+				objs = append(objs, &obj{m: v, ref: PathRefs{}.Join(ref, key)})
+			}
+		case []interface{}:
+			for i, e := range v {
+				if sm, ok := e.(map[string]interface{}); ok {
+					objs = append(objs, &obj{m: sm, ref: PathRefs{}.Join(ref, key, strconv.Itoa(i))})
+				}
+			}
+		case []map[string]interface{}:
+			for i, sm := range v {
+				objs = append(objs, &obj{m: sm, ref: PathRefs{}.Join(ref, key, strconv.Itoa(i))})
+			}
+		}
+	}
+	return objs
+}
+
+// Subschema returns the subschema of the object which is located by tokens
+// such as Subschema(o, "properties", "address", "properties", "city") and Subschema(o, "allOf", "0").
+// It reports false if there is no such subschema.
+func Subschema(o Object, tokens ...string) (Object, bool) {
+	for len(tokens) != 0 {
+		key := tokens[0]
+		v, ok := o.Get(key)
+		if !ok {
+			return nil, false
+		}
+
+		var name string
+		n := 1
+		if subschemaMapKeys[key] || subschemaListKeys[key] || subschemaKeys[key] && isArray(v) {
+			if len(tokens) < 2 {
+				return nil, false
+			}
+			name, n = tokens[1], 2
+		}
+		var found Object
+		for _, sub := range subschemas(o.Ref(), key, v) {
+			if n == 1 || path.Base(sub.Ref()) == name {
+				found = sub
+				break
+			}
+		}
+		if found == nil {
+			return nil, false
+		}
+		o, tokens = found, tokens[n:]
+	}
+	return o, true
+}
+
+// isArray reports whether v is an array of subschemas.
+func isArray(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, []map[string]interface{}:
+		return true
+	}
+	return false
+}
+
 // Option is options for JSON Schema.
+//
+// Built-in options do not keep states between generations and values which are given to them
+// such as schemas of RegisterTypeSchema, patches of Overlay and keywords of directives
+// are copied into generated schemas, so a slice of options can be reused
+// by concurrent generations and the values are never changed by generations.
+// Options which are given functions of callers such as TypeMapping, MapKeywords and WithMeter
+// are as safe as the functions are.
 type Option func(o Object) (Object, error)
 
 // ByReference explicits refrence of adding option.
@@ -41,6 +221,54 @@ func ByReference(pattern string, opt Option) Option {
 	}
 }
 
+// AtPath applies the options in order only to the object whose reference is ref such as
+// "#/properties/user/properties/email".
+// Unlike ByReference, ref is not a pattern, so it matches names which contain "*" and "?" as they are.
+func AtPath(ref string, opts ...Option) Option {
+	return func(o Object) (Object, error) {
+		if o.Ref() != ref {
+			return o, nil
+		}
+		for _, opt := range opts {
+			var err error
+			if o, err = opt(o); err != nil {
+				return nil, err
+			}
+		}
+		return o, nil
+	}
+}
+
+// ForType applies the options to objects which are generated from the Go type t
+// after other options, e.g. objects of time.Time can be described with
+//
+//	ForType(reflect.TypeOf(time.Time{}), func(o Object) (Object, error) {
+//		o.Set("description", "RFC 3339 date-time")
+//		return o, nil
+//	})
+//
+// The options are not applied to subschemas of the objects such as properties of structs;
+// a pointer type and its element type are distinct types.
+// Options of a shared type of SharedTypes are applied to its definition in $defs
+// instead of references to it.
+func ForType(t reflect.Type, opts ...Option) Option {
+	return configOption(func(c *config) {
+		if c.forTypes == nil {
+			c.forTypes = map[reflect.Type][]Option{}
+		}
+		c.forTypes[t] = append(c.forTypes[t], opts...)
+	})
+}
+
+// withTypeOptions returns the options followed by options of ForType for t.
+func (c *config) withTypeOptions(t reflect.Type, options []Option) []Option {
+	opts, ok := c.forTypes[t]
+	if !ok {
+		return options
+	}
+	return append(append([]Option{}, options...), opts...)
+}
+
 // PropertyOrder is add propertyOrder to schema.
 func PropertyOrder(order int) Option {
 	return func(o Object) (Object, error) {
@@ -62,6 +290,18 @@ func (o *refWrapper) Get(key string) (interface{}, bool) {
 	return o.obj.Get(key)
 }
 
+func (o *refWrapper) Delete(key string) {
+	o.obj.Delete(key)
+}
+
+func (o *refWrapper) Merge(fragment map[string]interface{}) {
+	applyPatch(o, fragment)
+}
+
+func (o *refWrapper) Walk(f func(o Object) error) error {
+	return walkObject(o, f)
+}
+
 func (o *refWrapper) Ref() string {
 	return o.ref
 }
@@ -75,3 +315,171 @@ func Ref(ref string) Option {
 		}, nil
 	}
 }
+
+// EmbedRequired is a policy which decides required of promoted fields.
+type EmbedRequired int
+
+const (
+	// EmbedRequiredIndividual treats each promoted field individually
+	// as same as fields which are not promoted.
+	EmbedRequiredIndividual EmbedRequired = iota
+	// EmbedRequiredInherit makes promoted fields inherit optionality of the embedded field.
+	// All fields which are promoted through a pointer embed become optional.
+	EmbedRequiredInherit
+)
+
+// NestEmbeddedStructs generates embedded structs as properties named after their types
+// instead of promoting their fields as encoding/json.
+// It is useful for types whose MarshalJSON encodes embedded structs as nested objects.
+func NestEmbeddedStructs() Option {
+	return configOption(func(c *config) {
+		c.nestEmbedded = true
+	})
+}
+
+// PromotedRequired sets the policy of required for fields which are promoted from embedded structs.
+// The default policy is EmbedRequiredIndividual.
+func PromotedRequired(policy EmbedRequired) Option {
+	return configOption(func(c *config) {
+		c.promotedRequired = policy
+	})
+}
+
+// StrictNames validates names of properties and makes generation fail if a name is invalid.
+// A valid name is a valid UTF-8 string which encoding/json accepts as a name in a tag,
+// so broken tags such as `json:"a\"b"` are detected.
+// Empty names such as `json:",omitempty"` are not errors because the names of fields are used.
+// If patterns are given, a name must also match all of them.
+func StrictNames(patterns ...*regexp.Regexp) Option {
+	return configOption(func(c *config) {
+		c.strictNames = true
+		c.namePatterns = patterns
+	})
+}
+
+// HoistAnonymousStructs generates schemas of fields whose types are anonymous structs
+// into $defs and refers them by $ref instead of inlining them.
+// A name of each definition is synthesized as ParentType_FieldName.
+func HoistAnonymousStructs() Option {
+	return configOption(func(c *config) {
+		c.hoistAnonymous = true
+	})
+}
+
+// NullablePointers makes objects of pointers accept null which encoding/json encodes nil pointers into.
+// Their types become arrays such as ["string", "null"] and null is added to their enums.
+// A reference such as a shared struct type becomes oneOf of the reference and null.
+func NullablePointers() Option {
+	return configOption(func(c *config) {
+		c.nullablePointers = true
+	})
+}
+
+// HashedDefNames appends a short hash of the structure of a type to names of definitions
+// which are synthesized for anonymous structs such as ParentType_FieldName_1a2b3c4d.
+// A named type whose name is already used by another type in $defs
+// also gets a name with the hash such as User_5e6f7a8b instead of causing ErrNameCollision.
+// The hash only changes when the structure of the type changes including its fields and tags,
+// so names and references to them stay same across runs and versions.
+func HashedDefNames() Option {
+	return configOption(func(c *config) {
+		c.hashDefNames = true
+	})
+}
+
+// TypeOverride forces objects whose reference matches the pattern to the type such as "string".
+// It is useful for types whose MarshalJSON changes their representation.
+// The pattern is same as ByReference.
+// A field also can be overridden by `jsonschema:"type=string"` tag.
+func TypeOverride(pattern, typ string) Option {
+	return configOption(func(c *config) {
+		c.typeOverrides = append(c.typeOverrides, typeOverride{
+			pattern: pattern,
+			typ:     typ,
+		})
+	})
+}
+
+// GoTypeOverride forces objects of the Go type t to the type such as "string".
+// For example, time.Duration can be generated as a string with
+// GoTypeOverride(reflect.TypeOf(time.Duration(0)), "string").
+func GoTypeOverride(t reflect.Type, typ string) Option {
+	return configOption(func(c *config) {
+		c.typeOverrides = append(c.typeOverrides, typeOverride{
+			goType: t,
+			typ:    typ,
+		})
+	})
+}
+
+// RequiredOrder is an order of names in required arrays.
+type RequiredOrder int
+
+const (
+	// RequiredDeclaration orders required names in declaration order of fields.
+	RequiredDeclaration RequiredOrder = iota
+	// RequiredAlphabetical orders required names alphabetically.
+	RequiredAlphabetical
+)
+
+// OrderRequired sets the order of names in required arrays.
+// The default is RequiredDeclaration.
+// Regardless of the order, required arrays never contain duplicated names.
+func OrderRequired(order RequiredOrder) Option {
+	return configOption(func(c *config) {
+		c.requiredOrder = order
+	})
+}
+
+// MaxNodes limits the number of objects in a generated schema.
+// Generation fails with ErrBudgetExceeded rather than exhausting memory
+// when a type such as a deeply nested struct or a large array of structs expands enormously.
+// The size of generated schemas can be observed by MetricNodes and MetricSize of WithMeter.
+// A limit which is zero or less means no limit.
+func MaxNodes(n int) Option {
+	return configOption(func(c *config) {
+		c.maxNodes = n
+	})
+}
+
+// setNullable makes the object accept null.
+func setNullable(o Object) {
+	if ref, ok := o.Get("$ref"); ok {
+		o.Delete("$ref")
+		o.Set("oneOf", []interface{}{
+			map[string]interface{}{"$ref": ref},
+			map[string]interface{}{"type": "null"},
+		})
+		return
+	}
+
+	typ, _ := o.Get("type")
+	switch typ := typ.(type) {
+	case string:
+		if typ != "null" {
+			o.Set("type", []interface{}{typ, "null"})
+		}
+	case []interface{}:
+		for _, e := range typ {
+			if e == "null" {
+				return
+			}
+		}
+		o.Set("type", append(append([]interface{}{}, typ...), "null"))
+	default:
+		// objects without types such as interfaces already accept null
+		return
+	}
+
+	if enum, ok := o.Get("enum"); ok {
+		es, _ := enum.([]interface{})
+		for _, e := range es {
+			if e == nil {
+				return
+			}
+		}
+		if es != nil {
+			o.Set("enum", append(append([]interface{}{}, es...), nil))
+		}
+	}
+}
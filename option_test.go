@@ -0,0 +1,448 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestObject(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type T struct {
+		Name    string    `json:"name"`
+		Address Address   `json:"address"`
+		Tags    []Address `json:"tags"`
+	}
+
+	// noPropertyOrder removes propertyOrder from all subschemas.
+	noPropertyOrder := func(o Object) (Object, error) {
+		err := o.Walk(func(o Object) error {
+			o.Delete("propertyOrder")
+			return nil
+		})
+		return o, err
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+		isErr  bool
+	}{
+		{
+			name: "delete",
+			opts: []Option{ByReference(RefRoot, noPropertyOrder)},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["name", "address", "tags"],
+				"properties": {
+					"name": {"type": "string"},
+					"address": {
+						"title": "Address",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string"}}
+					},
+					"tags": {
+						"type": "array",
+						"items": {
+							"title": "Address",
+							"type": "object",
+							"required": ["city"],
+							"properties": {"city": {"type": "string"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "merge",
+			opts: []Option{ByReference(RefRoot, noPropertyOrder), ByReference(RefRoot, func(o Object) (Object, error) {
+				o.Merge(map[string]interface{}{
+					"title": nil,
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"minLength": 1},
+						"tags": nil,
+					},
+				})
+				return o, nil
+			})},
+			expect: `{
+				"type": "object",
+				"required": ["name", "address", "tags"],
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"address": {
+						"title": "Address",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string"}}
+					}
+				}
+			}`,
+		},
+		{
+			name: "subschema",
+			opts: []Option{ByReference(RefRoot, noPropertyOrder), ByReference(RefRoot, func(o Object) (Object, error) {
+				city, ok := Subschema(o, "properties", "tags", "items", "properties", "city")
+				if !ok {
+					return nil, errors.New("city is not found")
+				}
+				city.Set("format", "city")
+				return o, nil
+			})},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["name", "address", "tags"],
+				"properties": {
+					"name": {"type": "string"},
+					"address": {
+						"title": "Address",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string"}}
+					},
+					"tags": {
+						"type": "array",
+						"items": {
+							"title": "Address",
+							"type": "object",
+							"required": ["city"],
+							"properties": {"city": {"type": "string", "format": "city"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "walk error",
+			opts: []Option{ByReference(RefRoot, func(o Object) (Object, error) {
+				return o, o.Walk(func(o Object) error {
+					return errors.New("error")
+				})
+			})},
+			isErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{Tags: []Address{{}}}, tt.opts...)
+			switch {
+			case tt.isErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case !tt.isErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.isErr:
+				return
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewObject(t *testing.T) {
+	m := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"age":  map[string]interface{}{"type": "integer"},
+		},
+		"allOf": []interface{}{
+			map[string]interface{}{"required": []interface{}{"name"}},
+		},
+	}
+	o := NewObject(RefRoot, m)
+
+	var refs []string
+	if err := o.Walk(func(o Object) error {
+		refs = append(refs, o.Ref())
+		return nil
+	}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := []string{"#/", "#/allOf/0", "#/properties/age", "#/properties/name"}
+	if !sort.StringsAreSorted(refs) || len(refs) != len(expect) {
+		t.Fatalf("walked references are %v, but want %v", refs, expect)
+	}
+	for i := range expect {
+		if refs[i] != expect[i] {
+			t.Fatalf("walked references are %v, but want %v", refs, expect)
+		}
+	}
+
+	name, ok := Subschema(o, "properties", "name")
+	if !ok {
+		t.Fatal("subschema of name is not found")
+	}
+	name.Set("minLength", 1)
+	if _, ok := Subschema(o, "properties", "email"); ok {
+		t.Error("subschema of email must not be found")
+	}
+
+	o.Delete("allOf")
+	if _, ok := o.Get("allOf"); ok {
+		t.Error("allOf must be deleted")
+	}
+	got := m["properties"].(map[string]interface{})["name"].(map[string]interface{})
+	if got["minLength"] != 1 {
+		t.Errorf("changes of the subschema must be applied to the map: %v", got)
+	}
+}
+
+func TestOption_reusable(t *testing.T) {
+	type T struct {
+		Point point    `json:"point"`
+		Tags  []string `json:"tags"`
+	}
+
+	registered := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"x"},
+		"properties": map[string]interface{}{
+			"x": map[string]interface{}{"type": "integer"},
+		},
+	}
+	before, err := json.Marshal(registered)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	opts := []Option{
+		RegisterTypeSchema(point{}, registered),
+		ByReference("#/properties/tags", ItemsEnum(true, "a", "b")),
+		Overlay(strings.NewReader(`{"#/properties/point": {"properties": {"x": {"minimum": 0}}}}`)),
+		NullablePointers(),
+		MapKeywords(KeywordRenames{"properties": "fields", "propertyOrder": ""}),
+	}
+	v := T{Tags: []string{}}
+	expect, err := GenerateString(v, opts...)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := GenerateString(v, opts...)
+			if err != nil {
+				t.Error("unexpected error:", err)
+				return
+			}
+			if diff := jsonDiff(t, got, expect); diff != "" {
+				t.Errorf("generations with the same options must be same: %v", diff)
+			}
+		}()
+	}
+	wg.Wait()
+
+	after, err := json.Marshal(registered)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("registered schema is changed by generations: %s", after)
+	}
+}
+
+func TestOption_calls(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	// options of schema objects are not applied to settings of the generator
+	var refs []string
+	count := func(o Object) (Object, error) {
+		refs = append(refs, o.Ref())
+		return o, nil
+	}
+	if _, err := GenerateBytes(T{}, count, Draft(Draft07)); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if want := []string{"#/properties/name", "#/"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("want %q but got %q", want, refs)
+	}
+}
+
+func TestAtPath(t *testing.T) {
+	type User struct {
+		Email string `json:"email"`
+		Name  string `json:"*"`
+	}
+	type T struct {
+		User  User   `json:"user"`
+		Email string `json:"email"`
+	}
+
+	format := func(format string) Option {
+		return func(o Object) (Object, error) {
+			o.Set("format", format)
+			return o, nil
+		}
+	}
+	got, err := GenerateString(T{},
+		MapKeywords(KeywordRenames{"propertyOrder": "", "required": "", "title": ""}),
+		AtPath("#/properties/user/properties/email", format("email"), func(o Object) (Object, error) {
+			o.Set("maxLength", 254)
+			return o, nil
+		}),
+		AtPath("#/properties/user/properties/*", format("name")),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"properties": {
+			"user": {
+				"type": "object",
+				"properties": {
+					"email": {"type": "string", "format": "email", "maxLength": 254},
+					"*": {"type": "string", "format": "name"}
+				}
+			},
+			"email": {"type": "string"}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	_, err = GenerateString(T{}, AtPath("#/properties/email", func(o Object) (Object, error) {
+		return nil, errors.New("error")
+	}))
+	if err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+type forTypeID string
+
+type forTypeItem struct {
+	ID forTypeID `json:"id"`
+}
+
+func TestForType(t *testing.T) {
+	type T struct {
+		Created time.Time     `json:"created"`
+		Updated *time.Time    `json:"updated"`
+		ID      forTypeID     `json:"id"`
+		Item    forTypeItem   `json:"item"`
+		Items   []forTypeItem `json:"items"`
+	}
+
+	describe := func(desc string) Option {
+		return func(o Object) (Object, error) {
+			o.Set("description", desc)
+			return o, nil
+		}
+	}
+	noise := MapKeywords(KeywordRenames{"propertyOrder": "", "required": "", "title": ""})
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "types",
+			opts: []Option{
+				ForType(reflect.TypeOf(time.Time{}), describe("time")),
+				ForType(reflect.TypeOf(forTypeID("")), describe("ID"), func(o Object) (Object, error) {
+					o.Set("pattern", "^[a-z]+$")
+					return o, nil
+				}),
+				ForType(reflect.TypeOf(forTypeItem{}), describe("item")),
+			},
+			expect: `{
+				"type": "object",
+				"properties": {
+					"created": {"type": "string", "format": "date-time", "description": "time"},
+					"updated": {"type": "string", "format": "date-time", "description": "time"},
+					"id": {"type": "string", "description": "ID", "pattern": "^[a-z]+$"},
+					"item": {
+						"type": "object",
+						"description": "item",
+						"properties": {"id": {"type": "string", "description": "ID", "pattern": "^[a-z]+$"}}
+					},
+					"items": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"description": "item",
+							"properties": {"id": {"type": "string", "description": "ID", "pattern": "^[a-z]+$"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "pointer",
+			opts: []Option{
+				ForType(reflect.TypeOf(&time.Time{}), describe("optional time")),
+			},
+			expect: `{
+				"type": "object",
+				"properties": {
+					"created": {"type": "string", "format": "date-time"},
+					"updated": {"type": "string", "format": "date-time", "description": "optional time"},
+					"id": {"type": "string"},
+					"item": {"type": "object", "properties": {"id": {"type": "string"}}},
+					"items": {"type": "array", "items": {"type": "object", "properties": {"id": {"type": "string"}}}}
+				}
+			}`,
+		},
+		{
+			name: "shared types",
+			opts: []Option{
+				SharedTypes(SharedTypesRef),
+				ForType(reflect.TypeOf(forTypeItem{}), describe("item")),
+				ByReference("#/properties/item", describe("the item")),
+			},
+			expect: `{
+				"type": "object",
+				"properties": {
+					"created": {"type": "string", "format": "date-time"},
+					"updated": {"type": "string", "format": "date-time"},
+					"id": {"type": "string"},
+					"item": {"$ref": "#/$defs/forTypeItem", "description": "the item"},
+					"items": {"type": "array", "items": {"$ref": "#/$defs/forTypeItem"}}
+				},
+				"$defs": {
+					"forTypeItem": {"type": "object", "description": "item", "properties": {"id": {"type": "string"}}}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v := T{Updated: &time.Time{}, Items: []forTypeItem{{}}}
+			got, err := GenerateString(v, append([]Option{noise}, tt.opts...)...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tenntenn/jsonschema/pointer"
+)
+
+// OutputFormat is a standard output format of validation results
+// which is defined by the JSON Schema 2020-12 specification.
+type OutputFormat int
+
+const (
+	// OutputFlag only reports whether the document is valid.
+	OutputFlag OutputFormat = iota
+	// OutputBasic reports a flat list of errors.
+	OutputBasic
+	// OutputDetailed reports errors in a hierarchy of schema locations
+	// and omits intermediate units which have only one child.
+	OutputDetailed
+	// OutputVerbose reports errors in a full hierarchy of schema locations.
+	OutputVerbose
+)
+
+// OutputUnit is an output unit of validation results.
+// Keyword locations are derived from instance locations by following
+// properties, items, additionalProperties and local $ref in the schema,
+// so they may be approximate for schemas which use other applicators.
+type OutputUnit struct {
+	Valid            bool          `json:"valid"`
+	KeywordLocation  string        `json:"keywordLocation"`
+	InstanceLocation string        `json:"instanceLocation"`
+	Error            string        `json:"error,omitempty"`
+	Errors           []*OutputUnit `json:"errors,omitempty"`
+
+	// flag marshals only validity.
+	flag bool
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u *OutputUnit) MarshalJSON() ([]byte, error) {
+	if u.flag {
+		return json.Marshal(map[string]bool{"valid": u.Valid})
+	}
+	type unit OutputUnit
+	return json.Marshal((*unit)(u))
+}
+
+// Output validates the document and reports the result in the format.
+func (s *Schema) Output(doc []byte, format OutputFormat) (*OutputUnit, error) {
+	var verr *ValidationError
+	if err := s.Validate(doc); err != nil && !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	root := &OutputUnit{Valid: verr == nil}
+	switch format {
+	case OutputFlag:
+		root.flag = true
+		return root, nil
+	}
+	if verr == nil {
+		return root, nil
+	}
+
+	units := s.outputUnits(verr)
+	if format == OutputBasic {
+		root.Errors = units
+		return root, nil
+	}
+
+	// build a hierarchy of schema locations
+	nodes := map[string]*OutputUnit{"": root}
+	var node func(kloc, iloc string) *OutputUnit
+	node = func(kloc, iloc string) *OutputUnit {
+		if n, ok := nodes[kloc]; ok {
+			return n
+		}
+		n := &OutputUnit{KeywordLocation: kloc, InstanceLocation: iloc}
+		nodes[kloc] = n
+		parent := node(parentLocation(kloc), parentInstance(kloc, iloc))
+		parent.Errors = append(parent.Errors, n)
+		return n
+	}
+	for _, u := range units {
+		parent := node(parentSchema(u.KeywordLocation), u.InstanceLocation)
+		parent.Errors = append(parent.Errors, u)
+	}
+
+	if format == OutputDetailed {
+		root.Errors = condense(root.Errors)
+	}
+	return root, nil
+}
+
+// outputUnits converts errors to output units which are sorted by their locations.
+func (s *Schema) outputUnits(verr *ValidationError) []*OutputUnit {
+	units := make([]*OutputUnit, len(verr.Errors))
+	for i, fe := range verr.Errors {
+		iloc := fe.Pointer
+		keyword := fe.Keyword
+		if ex, ok := explanations[fe.Keyword]; ok {
+			keyword = ex.keyword
+		}
+		units[i] = &OutputUnit{
+			KeywordLocation:  schemaLocation(s.doc, iloc) + "/" + keyword,
+			InstanceLocation: iloc,
+			Error:            fe.Message,
+		}
+	}
+
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].KeywordLocation < units[j].KeywordLocation
+	})
+	return units
+}
+
+// condense removes units which have only one child from the hierarchy.
+func condense(units []*OutputUnit) []*OutputUnit {
+	for i, u := range units {
+		for len(u.Errors) == 1 && u.Error == "" {
+			u = u.Errors[0]
+		}
+		u.Errors = condense(u.Errors)
+		units[i] = u
+	}
+	return units
+}
+
+// schemaLocation returns a location of the subschema which is applied to
+// the instance location by following properties, items, additionalProperties and local $ref.
+func schemaLocation(doc interface{}, iloc string) string {
+	var loc strings.Builder
+	resolveLocalRef(doc, walkSchema(doc, iloc, &loc), &loc)
+	return loc.String()
+}
+
+// walkSchema follows the instance location in the schema as same as schemaLocation
+// and returns the subschema before resolving its local reference.
+func walkSchema(doc interface{}, iloc string, loc *strings.Builder) map[string]interface{} {
+	cur, _ := doc.(map[string]interface{})
+	for _, seg := range splitPointer(iloc) {
+		cur = resolveLocalRef(doc, cur, loc)
+		if cur == nil {
+			break
+		}
+
+		if props, ok := cur["properties"].(map[string]interface{}); ok {
+			if p, ok := props[seg].(map[string]interface{}); ok {
+				loc.WriteString("/properties/" + escapePointer(seg))
+				cur = p
+				continue
+			}
+		}
+
+		if _, err := strconv.Atoi(seg); err == nil {
+			if items, ok := cur["items"].(map[string]interface{}); ok {
+				loc.WriteString("/items")
+				cur = items
+				continue
+			}
+		}
+
+		if ap, ok := cur["additionalProperties"].(map[string]interface{}); ok {
+			loc.WriteString("/additionalProperties")
+			cur = ap
+			continue
+		}
+
+		break
+	}
+	return cur
+}
+
+// resolveLocalRef follows a local reference of the schema and appends "/$ref" to the location.
+func resolveLocalRef(doc interface{}, cur map[string]interface{}, loc *strings.Builder) map[string]interface{} {
+	for i := 0; cur != nil && i < 32; i++ {
+		ref, ok := cur["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return cur
+		}
+		target, ok := lookupPointer(doc, strings.TrimPrefix(ref, "#")).(map[string]interface{})
+		if !ok {
+			return cur
+		}
+		loc.WriteString("/$ref")
+		cur = target
+	}
+	return cur
+}
+
+func lookupPointer(doc interface{}, ptr string) interface{} {
+	cur := doc
+	for _, seg := range splitPointer(ptr) {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			cur = c[seg]
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil
+			}
+			cur = c[i]
+		default:
+			return nil
+		}
+	}
+	return cur
+}
+
+func splitPointer(ptr string) []string {
+	ptr = strings.TrimPrefix(ptr, "/")
+	if ptr == "" {
+		return nil
+	}
+	parts := strings.Split(ptr, "/")
+	for i := range parts {
+		parts[i] = pointer.Unescape(parts[i])
+	}
+	return parts
+}
+
+// parentSchema returns a location of the subschema which has the keyword of the location.
+func parentSchema(kloc string) string {
+	return kloc[:strings.LastIndex(kloc, "/")]
+}
+
+// parentLocation returns a location of the subschema which applies the subschema of kloc.
+func parentLocation(kloc string) string {
+	parent := parentSchema(kloc)
+	switch {
+	case strings.HasSuffix(parent, "/properties"):
+		return parentSchema(parent)
+	}
+	return parent
+}
+
+// parentInstance returns an instance location of the parent of the subschema.
+func parentInstance(kloc, iloc string) string {
+	last := kloc[strings.LastIndex(kloc, "/")+1:]
+	switch {
+	case strings.HasSuffix(parentSchema(kloc), "/properties"), last == "items", last == "additionalProperties":
+		if i := strings.LastIndex(iloc, "/"); i >= 0 {
+			return iloc[:i]
+		}
+	}
+	return iloc
+}
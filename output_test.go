@@ -0,0 +1,118 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestSchema_Output(t *testing.T) {
+	s, err := CompileBytes([]byte(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}}
+		},
+		"$defs": {
+			"tag": {"type": "string"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	const doc = `{"name": "a", "tags": ["a", 1]}`
+	cases := []struct {
+		name   string
+		doc    string
+		format OutputFormat
+		expect string
+	}{
+		{
+			name:   "flag valid",
+			doc:    `{"id": 1}`,
+			format: OutputFlag,
+			expect: `{"valid": true}`,
+		},
+		{
+			name:   "flag",
+			doc:    doc,
+			format: OutputFlag,
+			expect: `{"valid": false}`,
+		},
+		{
+			name:   "basic",
+			doc:    doc,
+			format: OutputBasic,
+			expect: `{
+				"valid": false, "keywordLocation": "", "instanceLocation": "",
+				"errors": [
+					{"valid": false, "keywordLocation": "/properties/name/minLength", "instanceLocation": "/name", "error": "String length must be greater than or equal to 2"},
+					{"valid": false, "keywordLocation": "/properties/tags/items/$ref/type", "instanceLocation": "/tags/1", "error": "Invalid type. Expected: string, given: integer"},
+					{"valid": false, "keywordLocation": "/required", "instanceLocation": "", "error": "id is required"}
+				]
+			}`,
+		},
+		{
+			name:   "detailed",
+			doc:    doc,
+			format: OutputDetailed,
+			expect: `{
+				"valid": false, "keywordLocation": "", "instanceLocation": "",
+				"errors": [
+					{"valid": false, "keywordLocation": "/properties/name/minLength", "instanceLocation": "/name", "error": "String length must be greater than or equal to 2"},
+					{"valid": false, "keywordLocation": "/properties/tags/items/$ref/type", "instanceLocation": "/tags/1", "error": "Invalid type. Expected: string, given: integer"},
+					{"valid": false, "keywordLocation": "/required", "instanceLocation": "", "error": "id is required"}
+				]
+			}`,
+		},
+		{
+			name:   "verbose",
+			doc:    doc,
+			format: OutputVerbose,
+			expect: `{
+				"valid": false, "keywordLocation": "", "instanceLocation": "",
+				"errors": [
+					{
+						"valid": false, "keywordLocation": "/properties/name", "instanceLocation": "/name",
+						"errors": [
+							{"valid": false, "keywordLocation": "/properties/name/minLength", "instanceLocation": "/name", "error": "String length must be greater than or equal to 2"}
+						]
+					},
+					{
+						"valid": false, "keywordLocation": "/properties/tags", "instanceLocation": "/tags",
+						"errors": [{
+							"valid": false, "keywordLocation": "/properties/tags/items", "instanceLocation": "/tags/1",
+							"errors": [{
+								"valid": false, "keywordLocation": "/properties/tags/items/$ref", "instanceLocation": "/tags/1",
+								"errors": [
+									{"valid": false, "keywordLocation": "/properties/tags/items/$ref/type", "instanceLocation": "/tags/1", "error": "Invalid type. Expected: string, given: integer"}
+								]
+							}]
+						}]
+					},
+					{"valid": false, "keywordLocation": "/required", "instanceLocation": "", "error": "id is required"}
+				]
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := s.Output([]byte(tt.doc), tt.format)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			got, err := json.Marshal(out)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, string(got), tt.expect); diff != "" {
+				t.Errorf("output does not match to expected one: %v\n%s", diff, got)
+			}
+		})
+	}
+}
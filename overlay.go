@@ -0,0 +1,151 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/minio/pkg/wildcard"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) which is read from r
+// to the root of the generated schema.
+// It can be used to enrich generated schemas without changing Go code,
+// e.g. adding descriptions or tightening constraints.
+func MergePatch(r io.Reader) Option {
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&patch); err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot decode merge patch: %w", err))
+	}
+
+	return func(o Object) (Object, error) {
+		if isRoot(o) {
+			applyPatch(o, patch)
+		}
+		return o, nil
+	}
+}
+
+// Overlay applies an overlay document which is read from r.
+// An overlay document is a JSON object whose keys are patterns of references
+// and values are JSON Merge Patches (RFC 7396).
+// Each patch is applied to objects whose reference matches the pattern
+// in the same manner as ByReference.
+//
+//	{
+//		"#/properties/name": {"description": "name of the user", "minLength": 1},
+//		"#/properties/*/properties/id": {"format": "uuid"}
+//	}
+func Overlay(r io.Reader) Option {
+	var overlay map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&overlay); err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot decode overlay: %w", err))
+	}
+
+	patterns := make([]string, 0, len(overlay))
+	for pattern, patch := range overlay {
+		if !strings.HasPrefix(pattern, "#") && !strings.HasPrefix(pattern, "*") {
+			return errOption(newError(ErrRefInvalid, pattern, fmt.Errorf("pattern in overlay must begin with # or *")))
+		}
+		if _, ok := patch.(map[string]interface{}); !ok {
+			return errOption(fmt.Errorf("jsonschema: patch for %q in overlay must be an object", pattern))
+		}
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	return func(o Object) (Object, error) {
+		for _, pattern := range patterns {
+			if wildcard.MatchSimple(pattern, o.Ref()) {
+				applyPatch(o, overlay[pattern].(map[string]interface{}))
+			}
+		}
+		return o, nil
+	}
+}
+
+// MergePatchFS is same as MergePatch but it reads the patch from the file of the name in fsys.
+// It can read patches which are embedded by go:embed.
+func MergePatchFS(fsys fs.FS, name string) Option {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot open merge patch: %w", err))
+	}
+	defer f.Close()
+	return MergePatch(f)
+}
+
+// OverlayFS is same as Overlay but it reads the overlay document from the file of the name in fsys.
+// It can read overlay documents which are embedded by go:embed.
+func OverlayFS(fsys fs.FS, name string) Option {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot open overlay: %w", err))
+	}
+	defer f.Close()
+	return Overlay(f)
+}
+
+// errOption creates an Option which always returns err.
+func errOption(err error) Option {
+	return func(o Object) (Object, error) {
+		return nil, err
+	}
+}
+
+func applyPatch(o Object, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			o.Delete(k)
+			continue
+		}
+		cur, _ := o.Get(k)
+		o.Set(k, mergePatch(cur, v))
+	}
+}
+
+// mergePatch applies patch to target according to RFC 7396.
+// Values of patch are copied so that the patch can be applied repeatedly.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return copyValue(patch)
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+
+	return t
+}
+
+// copyValue deeply copies a decoded JSON value.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = copyValue(e)
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,186 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestMergePatch(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	cases := []struct {
+		name   string
+		patch  string
+		expect string
+		isErr  bool
+	}{
+		{
+			name: "add and delete",
+			patch: `{
+				"description": "a person",
+				"properties": {
+					"name": {"minLength": 1},
+					"age": {"propertyOrder": null}
+				},
+				"title": null
+			}`,
+			expect: `{
+				"type": "object",
+				"description": "a person",
+				"required": ["name", "age"],
+				"properties": {
+					"name": {"type": "string", "propertyOrder": 0, "minLength": 1},
+					"age": {"type": "number"}
+				}
+			}`,
+		},
+		{
+			name:   "replace array",
+			patch:  `{"required": ["name"], "title": null, "properties": null}`,
+			expect: `{"type": "object", "required": ["name"]}`,
+		},
+		{
+			name:  "invalid patch",
+			patch: `{`,
+			isErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{}, MergePatch(strings.NewReader(tt.patch)))
+			switch {
+			case tt.isErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case !tt.isErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.isErr:
+				return
+			}
+
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	type Address struct {
+		Zip string `json:"zip"`
+	}
+	type T struct {
+		Name string  `json:"name"`
+		Home Address `json:"home"`
+		Work Address `json:"work"`
+	}
+
+	cases := []struct {
+		name    string
+		overlay string
+		expect  string
+		isErr   bool
+	}{
+		{
+			name: "patterns",
+			overlay: `{
+				"#/": {"title": null},
+				"#/properties/name": {"description": "name of the user"},
+				"#/properties/*/properties/zip": {"pattern": "^[0-9]{7}$"},
+				"#/properties/*": {"title": null}
+			}`,
+			expect: `{
+				"type": "object",
+				"required": ["name", "home", "work"],
+				"properties": {
+					"name": {"type": "string", "propertyOrder": 0, "description": "name of the user"},
+					"home": {
+						"type": "object",
+						"propertyOrder": 1,
+						"required": ["zip"],
+						"properties": {
+							"zip": {"type": "string", "propertyOrder": 0, "pattern": "^[0-9]{7}$"}
+						}
+					},
+					"work": {
+						"type": "object",
+						"propertyOrder": 2,
+						"required": ["zip"],
+						"properties": {
+							"zip": {"type": "string", "propertyOrder": 0, "pattern": "^[0-9]{7}$"}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:    "not object patch",
+			overlay: `{"#/": 1}`,
+			isErr:   true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{}, Overlay(strings.NewReader(tt.overlay)))
+			switch {
+			case tt.isErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case !tt.isErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.isErr:
+				return
+			}
+
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestOverlayFS(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	fsys := fstest.MapFS{
+		"overlay.json": {Data: []byte(`{"#/properties/name": {"minLength": 1}}`)},
+		"patch.json":   {Data: []byte(`{"description": "T"}`)},
+	}
+
+	got, err := GenerateString(T{}, OverlayFS(fsys, "overlay.json"), MergePatchFS(fsys, "patch.json"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"description": "T",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "propertyOrder": 0}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	if _, err := GenerateString(T{}, OverlayFS(fsys, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("want fs.ErrNotExist but got %v", err)
+	}
+	if _, err := GenerateString(T{}, MergePatchFS(fsys, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("want fs.ErrNotExist but got %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parquetNumberTypes are primitive and logical types of Parquet for kinds of Go numbers.
+var parquetNumberTypes = map[reflect.Kind]string{
+	reflect.Int:     "int64",
+	reflect.Int8:    "int32 (INTEGER(8,true))",
+	reflect.Int16:   "int32 (INTEGER(16,true))",
+	reflect.Int32:   "int32",
+	reflect.Int64:   "int64",
+	reflect.Uint:    "int64 (INTEGER(64,false))",
+	reflect.Uint8:   "int32 (INTEGER(8,false))",
+	reflect.Uint16:  "int32 (INTEGER(16,false))",
+	reflect.Uint32:  "int32 (INTEGER(32,false))",
+	reflect.Uint64:  "int64 (INTEGER(64,false))",
+	reflect.Uintptr: "int64 (INTEGER(64,false))",
+	reflect.Float32: "float",
+	reflect.Float64: "double",
+}
+
+// decimalTag is a parquet tag of decimals such as "decimal(18,2)".
+var decimalTag = regexp.MustCompile(`^decimal\(\s*([0-9]+)\s*,\s*([0-9]+)\s*\)$`)
+
+// GenerateParquet generates a Parquet schema of records of the struct v into w
+// in the message format of parquet-mr such as:
+//
+//	message User {
+//	  required binary name (STRING);
+//	  optional int64 age;
+//	}
+//
+// Each field is generated in the same way as Generate with struct tags and options,
+// then it is converted into a field of Parquet.
+// Pointers and fields which are not required are optional and slices are repeated.
+// Structs and groups become groups and maps become MAP groups.
+// Date-time formats such as time.Time become TIMESTAMP and date formats become DATE.
+//
+// A parquet tag overrides the type of a field such as `parquet:"decimal(18,2)"`
+// and `parquet:"fixed_len_byte_array(16) (UUID)"`, and `parquet:"-"` excludes the field.
+// Other fields which cannot be converted such as interfaces, slices of slices
+// and references of shared struct types cause an error which matches ErrUnsupportedType.
+func GenerateParquet(w io.Writer, v interface{}, opts ...Option) error {
+	rv, err := structValue(v, "record of Parquet")
+	if err != nil {
+		return err
+	}
+
+	g := gen{cfg: newConfig(opts)}
+	root := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+
+	name := rv.Type().Name()
+	if name == "" {
+		name = "schema"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "message %s {\n", name)
+	if err := g.parquetFields(&b, root, rv, "  ", opts); err != nil {
+		return err
+	}
+	b.WriteString("}\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// parquetFields writes fields of the struct v into b.
+func (g *gen) parquetFields(b *strings.Builder, parent Object, v reflect.Value, indent string, options []Option) error {
+	// a group is written at the position of its first member
+	fields, err := g.fields(v)
+	if err != nil {
+		return withRef(err, parent.Ref())
+	}
+	members := map[string][]field{}
+	for _, f := range fields {
+		if f.group != "" {
+			members[f.group] = append(members[f.group], f)
+		}
+	}
+
+	for i, f := range fields {
+		if f.group == "" {
+			if err := g.parquetGroup(b, parent, v, fields[i:i+1], i, indent, options); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ms := members[f.group]
+		if ms == nil {
+			continue
+		}
+		delete(members, f.group)
+
+		required := false
+		for _, m := range ms {
+			required = required || !m.optional
+		}
+		group := &obj{
+			m:   map[string]interface{}{},
+			ref: g.cfg.refs().Join(parent.Ref(), "properties", f.group),
+		}
+		fmt.Fprintf(b, "%s%s group %s {\n", indent, parquetRepetition(!required), f.group)
+		if err := g.parquetGroup(b, group, v, ms, i, indent+"  ", options); err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+	return nil
+}
+
+// parquetGroup writes the fields of the struct v, which have the same parent, into b.
+func (g *gen) parquetGroup(b *strings.Builder, parent Object, v reflect.Value, fields []field, order int, indent string, options []Option) error {
+	for i, f := range fields {
+		tag := strings.TrimSpace(f.rawTag.Get("parquet"))
+		if tag == "-" {
+			continue
+		}
+
+		optional := f.optional || f.value.Kind() == reflect.Ptr
+		// types of fields are needed even if they are nil
+		if isNil(f.value) && f.value.Kind() != reflect.Interface {
+			f.value = empty(f.value.Type())
+		}
+
+		o, err := g.propertyGen(parent, f, order+i, v.Type().Name(), options)
+		if err != nil {
+			return err
+		}
+
+		var s Object = o
+		ev := reflect.Indirect(f.value)
+		rep := parquetRepetition(optional)
+		if isSlice(ev) {
+			items, ok := o.m["items"].(map[string]interface{})
+			if !ok {
+				return &Error{Kind: ErrUnsupportedType, Ref: o.Ref(), Field: f.goName, Err: fmt.Errorf("array without items cannot be a field of Parquet")}
+			}
+			s = &obj{m: items, ref: g.cfg.refs().Join(o.Ref(), "items")}
+			ev = reflect.Indirect(empty(ev.Type().Elem()))
+			if isSlice(ev) {
+				return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Field: f.goName, Err: fmt.Errorf("array of arrays cannot be a field of Parquet")}
+			}
+			rep = "repeated"
+		}
+
+		if err := g.parquetNode(b, s, ev, rep, f.name, tag, indent, options); err != nil {
+			return withField(err, f.goName)
+		}
+	}
+	return nil
+}
+
+// parquetNode writes a field whose schema is s into b.
+// typ is a type which is given by the parquet tag.
+func (g *gen) parquetNode(b *strings.Builder, s Object, v reflect.Value, rep, name, typ, indent string, options []Option) error {
+	if typ != "" {
+		typ, err := parquetTagType(typ)
+		if err != nil {
+			return &Error{Kind: ErrTagSyntax, Ref: s.Ref(), Err: err}
+		}
+		writeParquetField(b, indent, rep, typ, name)
+		return nil
+	}
+
+	if ref, ok := s.Get("$ref"); ok {
+		return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("reference %v cannot be a field of Parquet", ref)}
+	}
+
+	t, _ := s.Get("type")
+	switch t {
+	case "string":
+		format, _ := s.Get("format")
+		_, isEnum := s.Get("enum")
+		switch {
+		case format == "date-time":
+			typ = "int64 (TIMESTAMP(MICROS,true))"
+		case format == "date":
+			typ = "int32 (DATE)"
+		case isEnum:
+			typ = "binary (ENUM)"
+		case isBytes(v.Type()):
+			typ = "binary"
+		default:
+			typ = "binary (STRING)"
+		}
+	case "integer", "number":
+		typ = parquetNumberTypes[v.Kind()]
+		if typ == "" && t == "integer" {
+			typ = "int64"
+		} else if typ == "" {
+			typ = "double"
+		}
+	case "boolean":
+		typ = "boolean"
+	case "array":
+		// only byte slices of BytesAsArrays are arrays here
+		if !isBytes(v.Type()) {
+			return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("array of arrays cannot be a field of Parquet")}
+		}
+		typ = "binary"
+	case "object":
+		if _, ok := s.Get("properties"); ok && v.Kind() == reflect.Struct {
+			fmt.Fprintf(b, "%s%s group %s {\n", indent, rep, name)
+			if err := g.parquetFields(b, s, v, indent+"  ", options); err != nil {
+				return err
+			}
+			fmt.Fprintf(b, "%s}\n", indent)
+			return nil
+		}
+		return g.parquetMap(b, s, v, rep, name, indent, options)
+	case nil:
+		return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("schema without type cannot be a field of Parquet")}
+	default:
+		return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("type %v cannot be a field of Parquet", t)}
+	}
+
+	writeParquetField(b, indent, rep, typ, name)
+	return nil
+}
+
+// parquetMap writes a MAP group of the map v whose schema is s into b.
+func (g *gen) parquetMap(b *strings.Builder, s Object, v reflect.Value, rep, name, indent string, options []Option) error {
+	ap, _ := s.Get("additionalProperties")
+	values, ok := ap.(map[string]interface{})
+	if !ok || v.Kind() != reflect.Map {
+		return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Err: fmt.Errorf("object without properties cannot be a field of Parquet")}
+	}
+
+	vs := &obj{m: values, ref: g.cfg.refs().Join(s.Ref(), "additionalProperties")}
+	ev := reflect.Indirect(empty(v.Type().Elem()))
+	vrep := parquetRepetition(v.Type().Elem().Kind() == reflect.Ptr)
+	if isSlice(ev) {
+		return &Error{Kind: ErrUnsupportedType, Ref: vs.Ref(), Err: fmt.Errorf("map of arrays cannot be a field of Parquet")}
+	}
+
+	fmt.Fprintf(b, "%s%s group %s (MAP) {\n", indent, rep, name)
+	fmt.Fprintf(b, "%s  repeated group key_value {\n", indent)
+	fmt.Fprintf(b, "%s    required binary key (STRING);\n", indent)
+	if err := g.parquetNode(b, vs, ev, vrep, "value", "", indent+"    ", options); err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "%s  }\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+	return nil
+}
+
+// parquetTagType returns a type which is given by the parquet tag.
+// Decimals such as "decimal(18,2)" are stored in the smallest primitive type.
+func parquetTagType(tag string) (string, error) {
+	m := decimalTag.FindStringSubmatch(tag)
+	if m == nil {
+		return tag, nil
+	}
+
+	precision, err := strconv.Atoi(m[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid parquet tag %q: %w", tag, err)
+	}
+	scale, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid parquet tag %q: %w", tag, err)
+	}
+	if precision == 0 || scale > precision {
+		return "", fmt.Errorf("invalid parquet tag %q: scale must not be greater than precision", tag)
+	}
+
+	annotation := fmt.Sprintf("(DECIMAL(%d,%d))", precision, scale)
+	switch {
+	case precision <= 9:
+		return "int32 " + annotation, nil
+	case precision <= 18:
+		return "int64 " + annotation, nil
+	}
+	// the smallest size whose signed integers have digits of the precision
+	size := int(math.Ceil((float64(precision)*math.Log2(10) + 1) / 8))
+	return fmt.Sprintf("fixed_len_byte_array(%d) %s", size, annotation), nil
+}
+
+// writeParquetField writes a primitive field into b.
+// typ is a primitive type which can be followed by a logical type such as "binary (STRING)".
+func writeParquetField(b *strings.Builder, indent, rep, typ, name string) {
+	fs := strings.Fields(typ)
+	fmt.Fprintf(b, "%s%s %s %s", indent, rep, fs[0], name)
+	if len(fs) > 1 {
+		fmt.Fprintf(b, " %s", strings.Join(fs[1:], " "))
+	}
+	b.WriteString(";\n")
+}
+
+func parquetRepetition(optional bool) string {
+	if optional {
+		return "optional"
+	}
+	return "required"
+}
+
+// isSlice reports whether v is a slice or an array which is not encoded as a string.
+func isSlice(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateParquet(t *testing.T) {
+	type Line struct {
+		SKU      string `json:"sku"`
+		Quantity uint16 `json:"quantity"`
+	}
+	type Order struct {
+		ID       string            `json:"id" parquet:"fixed_len_byte_array(16)  (UUID)"`
+		Name     string            `json:"name"`
+		Note     *string           `json:"note"`
+		Count    int8              `json:"count,omitempty"`
+		Total    string            `json:"total" parquet:"decimal(10,2)"`
+		Rate     float32           `json:"rate"`
+		Paid     bool              `json:"paid"`
+		Created  time.Time         `json:"created"`
+		Day      string            `json:"day" jsonschema:"format=date"`
+		Data     []byte            `json:"data"`
+		Tags     []string          `json:"tags"`
+		Lines    []Line            `json:"lines"`
+		Labels   map[string]*int32 `json:"labels"`
+		City     string            `json:"city" group:"address"`
+		Zip      string            `json:"zip,omitempty" group:"address"`
+		Internal interface{}       `json:"internal" parquet:"-"`
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateParquet(&buf, Order{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `message Order {
+  required fixed_len_byte_array(16) id (UUID);
+  required binary name (STRING);
+  optional binary note (STRING);
+  optional int32 count (INTEGER(8,true));
+  required int64 total (DECIMAL(10,2));
+  required float rate;
+  required boolean paid;
+  required int64 created (TIMESTAMP(MICROS,true));
+  required int32 day (DATE);
+  required binary data;
+  repeated binary tags (STRING);
+  repeated group lines {
+    required binary sku (STRING);
+    required int32 quantity (INTEGER(16,false));
+  }
+  required group labels (MAP) {
+    repeated group key_value {
+      required binary key (STRING);
+      optional int32 value;
+    }
+  }
+  required group address {
+    required binary city (STRING);
+    optional binary zip (STRING);
+  }
+}
+`
+	if got := buf.String(); got != expect {
+		t.Errorf("want\n%s\nbut got\n%s", expect, got)
+	}
+}
+
+func TestGenerateParquet_error(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+		kind error
+	}{
+		{"nil", nil, ErrUnsupportedType},
+		{"not struct", "a", ErrUnsupportedType},
+		{"interface", struct {
+			Any interface{} `json:"any"`
+		}{}, ErrUnsupportedType},
+		{"slice of slices", struct {
+			Matrix [][]int `json:"matrix"`
+		}{}, ErrUnsupportedType},
+		{"ref", struct {
+			Meta tableMeta `json:"meta"`
+		}{}, ErrUnsupportedType},
+		{"decimal", struct {
+			Total string `json:"total" parquet:"decimal(2,3)"`
+		}{}, ErrTagSyntax},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateParquet(&buf, tt.v, SharedTypes(SharedTypesRef))
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+		})
+	}
+}
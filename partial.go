@@ -0,0 +1,34 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// GeneratePartialSchema generates a companion schema of v for partial documents
+// such as defaults files, templates and overlays into w.
+// The schema has the same type constraints as Generate but no fields are required:
+// required and dependentRequired are removed from all objects recursively
+// including nested objects, items, definitions and schemas of Generator.
+// Other constraints such as additionalProperties are kept,
+// so unknown fields and fields of wrong types are still invalid.
+func GeneratePartialSchema(w io.Writer, v interface{}, opts ...Option) error {
+	schema, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return err
+	}
+
+	if root, ok := doc.(map[string]interface{}); ok {
+		walkSubschemas(root, func(s map[string]interface{}) {
+			delete(s, "required")
+			delete(s, "dependentRequired")
+		})
+	}
+
+	return json.NewEncoder(w).Encode(doc)
+}
@@ -0,0 +1,85 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestGeneratePartialSchema(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip" jsonschema:"pattern=^[0-9]{3}-[0-9]{4}$"`
+	}
+	type T struct {
+		Name      string    `json:"name"`
+		Age       int       `json:"age" jsonschema:"minimum=0"`
+		Address   *Address  `json:"address"`
+		Addresses []Address `json:"addresses"`
+		Region    string    `json:"region" group:"area"`
+	}
+
+	var buf bytes.Buffer
+	v := T{Address: &Address{}, Addresses: []Address{{}}}
+	if err := GeneratePartialSchema(&buf, v); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	address := `
+		"type": "object",
+		"title": "Address",
+		"properties": {
+			"city": {"type": "string", "propertyOrder": 0},
+			"zip": {"type": "string", "pattern": "^[0-9]{3}-[0-9]{4}$", "propertyOrder": 1}
+		}`
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"properties": {
+			"name": {"type": "string", "propertyOrder": 0},
+			"age": {"type": "number", "minimum": 0, "propertyOrder": 1},
+			"address": {` + address + `, "propertyOrder": 2},
+			"addresses": {"type": "array", "items": {` + address + `}, "propertyOrder": 3},
+			"area": {
+				"type": "object",
+				"propertyOrder": 4,
+				"properties": {
+					"region": {"type": "string", "propertyOrder": 0}
+				}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(buf.Bytes()))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		name  string
+		doc   string
+		valid bool
+	}{
+		{"empty", `{}`, true},
+		{"nested partial", `{"address": {"city": "Tokyo"}, "addresses": [{}]}`, true},
+		{"wrong type", `{"age": "20"}`, false},
+		{"nested constraint", `{"address": {"zip": "1234"}}`, false},
+	}
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := s.Validate(gojsonschema.NewStringLoader(tt.doc))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if r.Valid() != tt.valid {
+				t.Errorf("want valid=%t but got %t: %v", tt.valid, r.Valid(), r.Errors())
+			}
+		})
+	}
+}
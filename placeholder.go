@@ -0,0 +1,115 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// placeholderPrefix is a prefix of names of placeholder definitions.
+const placeholderPrefix = "TODO_"
+
+// emptyInterfaceType is a Go type of placeholder definitions.
+var emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// PlaceholderRef makes an interface value such as an interface{} field a reference to
+// an empty definition of the name with the "TODO_" prefix, e.g. "#/$defs/TODO_Payload".
+// It leaves a visible TODO in published schemas instead of a silently permissive schema,
+// and the definition can be filled in later by Overlay with "#/$defs/TODO_Payload".
+// It is usually used with ByReference and does nothing for other values.
+// The name consists of letters, digits, "_", "-" and ".", otherwise an error which matches ErrRefInvalid occurs.
+func PlaceholderRef(name string) Option {
+	return func(o Object) (Object, error) {
+		if p, ok := o.(*placeholderProbe); ok {
+			p.name = name
+		}
+		return o, nil
+	}
+}
+
+// isDefName reports whether the name can be a segment of a definition name
+// which is also used as a file name by WriteTree.
+// It consists of letters, digits, "_", "-" and "." but it does not contain "..".
+func isDefName(name string) bool {
+	if name == "" || strings.Contains(name, "..") {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-.", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// placeholderProbe is a special Object which finds a name given by PlaceholderRef.
+// Other options are also applied, but their changes are ignored.
+type placeholderProbe struct {
+	ref  string
+	name string
+}
+
+func (p *placeholderProbe) Set(key string, value interface{}) {}
+
+func (p *placeholderProbe) Get(key string) (interface{}, bool) {
+	return nil, false
+}
+
+func (p *placeholderProbe) Delete(key string) {}
+
+func (p *placeholderProbe) Merge(fragment map[string]interface{}) {}
+
+func (p *placeholderProbe) Walk(f func(o Object) error) error {
+	return nil
+}
+
+func (p *placeholderProbe) Ref() string {
+	return p.ref
+}
+
+// placeholder generates a reference to a placeholder definition
+// if PlaceholderRef is applied to the object of the nil interface value.
+func (g *gen) placeholder(o Object, options []Option) (bool, error) {
+	p := &placeholderProbe{ref: o.Ref()}
+	for _, opt := range options {
+		// errors are reported when the option is applied to schema objects
+		_, _ = opt(p)
+	}
+	if p.name == "" {
+		return false, nil
+	}
+	if !isDefName(p.name) {
+		return false, newError(ErrRefInvalid, o.Ref(), fmt.Errorf("invalid placeholder name %q", p.name))
+	}
+
+	name := placeholderPrefix + p.name
+	ref := RefRoot + "$defs/" + escapePointer(name)
+	if _, ok := g.defs[name]; ok {
+		if t := g.defTypes[name]; t != emptyInterfaceType {
+			return false, newError(ErrNameCollision, ref, fmt.Errorf("%s and a placeholder have the same name %q", t, name))
+		}
+	} else {
+		if g.defs == nil {
+			g.defs = map[string]interface{}{}
+		}
+		if g.defTypes == nil {
+			g.defTypes = map[string]reflect.Type{}
+		}
+		def := &obj{
+			m:   map[string]interface{}{},
+			ref: g.cfg.refs().Join(g.cfg.refs().Root(), "$defs", name),
+		}
+		g.defs[name] = def.m
+		g.defTypes[name] = emptyInterfaceType
+		if err := g.applyOptions(def, options); err != nil {
+			return false, err
+		}
+	}
+	if g.plan != nil {
+		g.plan.note(o.Ref(), "refers to placeholder %s", name)
+	}
+
+	o.Set("$ref", ref)
+	return true, g.applyOptions(o, options)
+}
@@ -0,0 +1,100 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestPlaceholderRef(t *testing.T) {
+	type T struct {
+		Kind    string      `json:"kind"`
+		Payload interface{} `json:"payload"`
+		Extra   interface{} `json:"extra"`
+	}
+
+	got, err := GenerateString(T{},
+		ByReference("#/properties/payload", PlaceholderRef("Payload")),
+		ByReference("#/properties/kind", PlaceholderRef("Kind")),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"required": ["kind", "payload", "extra"],
+		"properties": {
+			"kind": {"type": "string", "propertyOrder": 0},
+			"payload": {"$ref": "#/$defs/TODO_Payload", "propertyOrder": 1},
+			"extra": {}
+		},
+		"$defs": {
+			"TODO_Payload": {}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	// placeholders can be filled in by overlays
+	got, err = GenerateString(T{},
+		ByReference("#/properties/payload", PlaceholderRef("Payload")),
+		Overlay(strings.NewReader(`{"#/$defs/TODO_Payload": {"type": "object"}}`)),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if !strings.Contains(got, `"TODO_Payload":{"type":"object"}`) {
+		t.Errorf("placeholder is not filled in: %s", got)
+	}
+}
+
+func TestPlaceholderRef_collision(t *testing.T) {
+	type U struct {
+		A interface{} `json:"a"`
+		B interface{} `json:"b"`
+	}
+	got, err := GenerateString(U{}, PlaceholderRef("Any"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if strings.Count(got, `"$ref":"#/$defs/TODO_Any"`) != 2 {
+		t.Errorf("placeholders are not shared: %s", got)
+	}
+
+	// TODO_B is also a name of the hoisted anonymous struct
+	type TODO struct {
+		A interface{} `json:"a"`
+		B struct {
+			Name string `json:"name"`
+		} `json:"b"`
+	}
+	_, err = GenerateString(TODO{}, HoistAnonymousStructs(), ByReference("#/properties/a", PlaceholderRef("B")))
+	if !errors.Is(err, ErrNameCollision) {
+		t.Errorf("want ErrNameCollision but got %v", err)
+	}
+}
+
+func TestPlaceholderRef_invalidName(t *testing.T) {
+	type T struct {
+		Payload interface{} `json:"payload"`
+	}
+
+	for _, name := range []string{"../../../evil", "a/b", "a b", "a..b"} {
+		_, err := GenerateString(T{}, PlaceholderRef(name))
+		if !errors.Is(err, ErrRefInvalid) {
+			t.Errorf("want ErrRefInvalid for %q but got %v", name, err)
+		}
+		if _, err := WriteTree(t.TempDir(), T{}, TreeOptions{}, PlaceholderRef(name)); !errors.Is(err, ErrRefInvalid) {
+			t.Errorf("want ErrRefInvalid by WriteTree for %q but got %v", name, err)
+		}
+	}
+
+	if _, err := GenerateString(T{}, PlaceholderRef("Payload-v1.2")); err != nil {
+		t.Error("unexpected error:", err)
+	}
+}
@@ -0,0 +1,160 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// PlanReport is a report of generation which describes what would be generated.
+type PlanReport struct {
+	// Types are Go types which are encountered in generation.
+	Types []string
+	// Nodes are objects of the schema in the order of generation.
+	Nodes []*PlanNode
+	// Skipped are fields which are not generated as properties.
+	Skipped []SkippedField
+	// Warnings are messages for suspicious generation.
+	Warnings []string
+
+	index map[string]*PlanNode
+	types map[string]bool
+}
+
+// PlanNode describes an object of a schema.
+type PlanNode struct {
+	// Ref is the reference of the object.
+	Ref string
+	// GoType is the Go type which the object is generated from.
+	GoType string
+	// Keys are keys which are set by the generator.
+	Keys []string
+	// OptionKeys are keys which are set, changed or deleted by options.
+	OptionKeys []string
+	// Notes are additional information of generation such as overrides.
+	Notes []string
+}
+
+// SkippedField describes a field which is not generated as a property.
+type SkippedField struct {
+	// Field is a Go field such as "pkg.T.Field".
+	Field string
+	// Reason is why the field is skipped.
+	Reason string
+}
+
+// Plan reports what would be generated from v with the options
+// without producing any JSON output.
+// It is useful to understand and debug generation for complex models.
+func Plan(v interface{}, opts ...Option) (*PlanReport, error) {
+	p := &PlanReport{
+		index: map[string]*PlanNode{},
+		types: map[string]bool{},
+	}
+
+	g := gen{cfg: newConfig(opts), plan: p}
+	o := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+
+	if err := g.do(o, reflect.ValueOf(v), opts...); err != nil {
+		return nil, err
+	}
+
+	for t := range p.types {
+		p.Types = append(p.Types, t)
+	}
+	sort.Strings(p.Types)
+
+	return p, nil
+}
+
+func (p *PlanReport) node(ref string) *PlanNode {
+	if n, ok := p.index[ref]; ok {
+		return n
+	}
+	n := &PlanNode{Ref: ref}
+	p.index[ref] = n
+	p.Nodes = append(p.Nodes, n)
+	return n
+}
+
+func (p *PlanReport) visit(ref string, t reflect.Type) {
+	n := p.node(ref)
+	if n.GoType == "" {
+		n.GoType = t.String()
+	}
+	p.types[t.String()] = true
+}
+
+func (p *PlanReport) note(ref, format string, args ...interface{}) {
+	n := p.node(ref)
+	n.Notes = append(n.Notes, fmt.Sprintf(format, args...))
+}
+
+func (p *PlanReport) warn(format string, args ...interface{}) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *PlanReport) skip(field, format string, args ...interface{}) {
+	p.Skipped = append(p.Skipped, SkippedField{
+		Field:  field,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// beforeOptions records keys which are set by the generator
+// and returns a snapshot to find changes by options.
+func (p *PlanReport) beforeOptions(o Object) map[string]interface{} {
+	m := objectMap(o)
+	snapshot := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		snapshot[k] = v
+	}
+
+	n := p.node(o.Ref())
+	n.Keys = sortedKeys(snapshot)
+
+	return snapshot
+}
+
+// afterOptions records keys which are changed by options.
+func (p *PlanReport) afterOptions(o Object, snapshot map[string]interface{}) {
+	m := objectMap(o)
+	changed := map[string]interface{}{}
+	for k, v := range m {
+		if old, ok := snapshot[k]; !ok || !reflect.DeepEqual(old, v) {
+			changed[k] = v
+		}
+	}
+	for k := range snapshot {
+		if _, ok := m[k]; !ok {
+			changed[k] = nil
+		}
+	}
+
+	n := p.node(o.Ref())
+	n.OptionKeys = sortedKeys(changed)
+}
+
+// objectMap returns the underlying map of o if it is known.
+func objectMap(o Object) map[string]interface{} {
+	switch o := o.(type) {
+	case *obj:
+		return o.m
+	case *refWrapper:
+		return objectMap(o.obj)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestPlan(t *testing.T) {
+	type Base struct {
+		ID   int
+		Name string
+	}
+
+	type T struct {
+		Base
+		Name  string
+		Items []string
+		Next  *T
+	}
+
+	p, err := Plan(T{Items: []string{}}, ByReference("#/properties/Name", describeRef))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	wantTypes := []string{
+		"*jsonschema_test.T",
+		"[]string",
+		"int",
+		"jsonschema_test.T",
+		"string",
+	}
+	if !reflect.DeepEqual(p.Types, wantTypes) {
+		t.Errorf("Types = %v, want %v", p.Types, wantTypes)
+	}
+
+	wantRefs := []string{
+		"#/",
+		"#/properties/ID",
+		"#/properties/Name",
+		"#/properties/Items",
+		"#/properties/Items/items",
+		"#/properties/Next",
+	}
+	var refs []string
+	for _, n := range p.Nodes {
+		refs = append(refs, n.Ref)
+	}
+	if !reflect.DeepEqual(refs, wantRefs) {
+		t.Errorf("refs of Nodes = %v, want %v", refs, wantRefs)
+	}
+
+	name := p.Nodes[2]
+	if want := []string{"type"}; !reflect.DeepEqual(name.Keys, want) {
+		t.Errorf("Keys = %v, want %v", name.Keys, want)
+	}
+	if want := []string{"description", "propertyOrder"}; !reflect.DeepEqual(name.OptionKeys, want) {
+		t.Errorf("OptionKeys = %v, want %v", name.OptionKeys, want)
+	}
+
+	wantSkipped := []SkippedField{{
+		Field:  "jsonschema_test.Base.Name",
+		Reason: `shadowed by another field named "Name"`,
+	}}
+	if !reflect.DeepEqual(p.Skipped, wantSkipped) {
+		t.Errorf("Skipped = %v, want %v", p.Skipped, wantSkipped)
+	}
+
+	if len(p.Warnings) != 1 {
+		t.Errorf("nil field must be warned: %v", p.Warnings)
+	}
+
+	if _, err := Plan(make(chan int)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
@@ -0,0 +1,135 @@
+// Package pointer provides JSON Pointers (RFC 6901) such as "/properties/name"
+// which options, post-processors and validators of schemas use to refer to their parts.
+//
+// Tokens are escaped by Escape and joined by Join, and pointers are parsed by Parse:
+//
+//	ptr := pointer.Join("/$defs", "a/b", "properties") // "/$defs/a~1b/properties"
+//	tokens, err := pointer.Parse(ptr)                  // ["$defs", "a/b", "properties"]
+//
+// Resolve returns the value which a pointer refers in decoded JSON documents
+// and objects of schemas such as Object of github.com/tenntenn/jsonschema:
+//
+//	v, err := pointer.Resolve(schema.Root(), "#/properties/name")
+package pointer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrSyntax means that a pointer is malformed.
+	ErrSyntax = errors.New("invalid pointer syntax")
+	// ErrNotFound means that a pointer does not refer to any values of a document.
+	ErrNotFound = errors.New("pointer not found")
+)
+
+// Getter is an object which returns values of its keys such as Object of github.com/tenntenn/jsonschema.
+type Getter interface {
+	Get(key string) (interface{}, bool)
+}
+
+// Escape escapes "~" and "/" of the token as "~0" and "~1".
+func Escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// Unescape unescapes "~1" and "~0" of the token. Other sequences of "~" are kept.
+func Unescape(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// Join appends the tokens to the pointer after escaping them.
+// The pointer can be a URI fragment such as "#/properties", which is kept,
+// but the tokens are not percent-encoded.
+func Join(ptr string, tokens ...string) string {
+	var b strings.Builder
+	b.WriteString(ptr)
+	for _, t := range tokens {
+		b.WriteString("/")
+		b.WriteString(Escape(t))
+	}
+	return b.String()
+}
+
+// Parse returns unescaped tokens of the pointer.
+// The pointer can be a URI fragment such as "#/properties/a%20b", which is percent-decoded.
+// The empty pointer and "#" refer to the whole document and have no tokens.
+// Errors match ErrSyntax.
+func Parse(ptr string) ([]string, error) {
+	s := ptr
+	if strings.HasPrefix(s, "#") {
+		var err error
+		if s, err = url.PathUnescape(s[len("#"):]); err != nil {
+			return nil, fmt.Errorf("pointer: %q: %w: %v", ptr, ErrSyntax, err)
+		}
+	}
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("pointer: %q: %w: must start with /", ptr, ErrSyntax)
+	}
+
+	tokens := strings.Split(s[len("/"):], "/")
+	for i, t := range tokens {
+		for j := 0; j < len(t); j++ {
+			if t[j] == '~' && (j+1 == len(t) || t[j+1] != '0' && t[j+1] != '1') {
+				return nil, fmt.Errorf("pointer: %q: %w: invalid escape in %q", ptr, ErrSyntax, t)
+			}
+		}
+		tokens[i] = Unescape(t)
+	}
+	return tokens, nil
+}
+
+// Resolve returns the value which the pointer refers in the document.
+// The document is a decoded JSON value of maps, slices and scalars or a Getter,
+// whose values are decoded JSON values.
+// Indexes of arrays are decimal numbers without leading zeros.
+// Errors match ErrSyntax or ErrNotFound.
+func Resolve(doc interface{}, ptr string) (interface{}, error) {
+	tokens, err := Parse(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := doc
+	for i, t := range tokens {
+		var ok bool
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			cur, ok = c[t]
+		case []interface{}:
+			var n int
+			if n, ok = index(t); ok && n < len(c) {
+				cur = c[n]
+			} else {
+				ok = false
+			}
+		case Getter:
+			cur, ok = c.Get(t)
+		}
+		if !ok {
+			return nil, fmt.Errorf("pointer: %q: %w at %q", ptr, ErrNotFound, Join("", tokens[:i+1]...))
+		}
+	}
+	return cur, nil
+}
+
+// index parses the token as an index of arrays.
+func index(token string) (int, bool) {
+	if token == "" || len(token) > 1 && token[0] == '0' {
+		return 0, false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(token)
+	return n, err == nil
+}
@@ -0,0 +1,163 @@
+package pointer_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tenntenn/jsonschema"
+	"github.com/tenntenn/jsonschema/pointer"
+)
+
+func TestEscape(t *testing.T) {
+	cases := []struct {
+		token   string
+		escaped string
+	}{
+		{"name", "name"},
+		{"a/b", "a~1b"},
+		{"m~n", "m~0n"},
+		{"~1", "~01"},
+		{"", ""},
+	}
+
+	for _, tt := range cases {
+		if got := pointer.Escape(tt.token); got != tt.escaped {
+			t.Errorf("Escape(%q): want %q but got %q", tt.token, tt.escaped, got)
+		}
+		if got := pointer.Unescape(tt.escaped); got != tt.token {
+			t.Errorf("Unescape(%q): want %q but got %q", tt.escaped, tt.token, got)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	cases := []struct {
+		ptr    string
+		tokens []string
+		expect string
+	}{
+		{"", []string{"properties", "a/b"}, "/properties/a~1b"},
+		{"#", []string{"$defs", "User"}, "#/$defs/User"},
+		{"/items", nil, "/items"},
+		{"/", []string{"x"}, "//x"},
+	}
+
+	for _, tt := range cases {
+		got := pointer.Join(tt.ptr, tt.tokens...)
+		if got != tt.expect {
+			t.Errorf("Join(%q, %q): want %q but got %q", tt.ptr, tt.tokens, tt.expect, got)
+		}
+		tokens, err := pointer.Parse(got)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		original, _ := pointer.Parse(tt.ptr)
+		if want := append(original, tt.tokens...); !reflect.DeepEqual(tokens, want) {
+			t.Errorf("Parse(%q): want %q but got %q", got, want, tokens)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		ptr    string
+		expect []string
+		err    bool
+	}{
+		{ptr: "", expect: nil},
+		{ptr: "#", expect: nil},
+		{ptr: "/", expect: []string{""}},
+		{ptr: "/properties/a~1b/m~0n", expect: []string{"properties", "a/b", "m~n"}},
+		{ptr: "#/properties/a%20b", expect: []string{"properties", "a b"}},
+		{ptr: "/items/0", expect: []string{"items", "0"}},
+		{ptr: "properties", err: true},
+		{ptr: "/a~2", err: true},
+		{ptr: "/a~", err: true},
+		{ptr: "#/a%zz", err: true},
+	}
+
+	for _, tt := range cases {
+		got, err := pointer.Parse(tt.ptr)
+		switch {
+		case tt.err && !errors.Is(err, pointer.ErrSyntax):
+			t.Errorf("Parse(%q): want %v but got %v", tt.ptr, pointer.ErrSyntax, err)
+		case !tt.err && err != nil:
+			t.Errorf("Parse(%q): unexpected error: %v", tt.ptr, err)
+		case !reflect.DeepEqual(got, tt.expect):
+			t.Errorf("Parse(%q): want %q but got %q", tt.ptr, tt.expect, got)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{
+		"properties": {
+			"a/b": {"type": "string"},
+			"tags": {"type": "array", "items": {"enum": ["x", "y"]}}
+		},
+		"": "empty",
+		"list": [10, 20]
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		ptr    string
+		expect interface{}
+		err    error
+	}{
+		{ptr: "", expect: doc},
+		{ptr: "/properties/a~1b/type", expect: "string"},
+		{ptr: "#/properties/tags/items/enum/1", expect: "y"},
+		{ptr: "/", expect: "empty"},
+		{ptr: "/list/0", expect: float64(10)},
+		{ptr: "/list/2", err: pointer.ErrNotFound},
+		{ptr: "/list/01", err: pointer.ErrNotFound},
+		{ptr: "/list/-", err: pointer.ErrNotFound},
+		{ptr: "/properties/missing", err: pointer.ErrNotFound},
+		{ptr: "/properties/a~1b/type/x", err: pointer.ErrNotFound},
+		{ptr: "properties", err: pointer.ErrSyntax},
+	}
+
+	for _, tt := range cases {
+		got, err := pointer.Resolve(doc, tt.ptr)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Resolve(%q): want %v but got %v", tt.ptr, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%q): unexpected error: %v", tt.ptr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("Resolve(%q): want %v but got %v", tt.ptr, tt.expect, got)
+		}
+	}
+}
+
+func TestResolve_object(t *testing.T) {
+	s, err := jsonschema.Reflect(struct {
+		Name string `json:"name" jsonschema:"minLength=1"`
+	}{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	got, err := pointer.Resolve(s.Root(), "#/properties/name/minLength")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	// numbers of compiled schemas are json.Number
+	if got != json.Number("1") {
+		t.Errorf("want 1 but got %#v", got)
+	}
+
+	if _, err := pointer.Resolve(s.Root(), "/properties/age"); !errors.Is(err, pointer.ErrNotFound) {
+		t.Errorf("want %v but got %v", pointer.ErrNotFound, err)
+	}
+}
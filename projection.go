@@ -0,0 +1,200 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GenerateProjection generates a schema of the projection of v which has only the selected fields
+// and their ancestors into w, e.g. for responses of sparse fieldsets such as ?fields=name,address.city.
+// A field is a path of JSON property names which is dotted such as "address.city"
+// or a JSON Pointer such as "/address/city". A selected field keeps all its descendants.
+// Items of arrays and values of maps are passed through, so "tags.name" selects
+// name of the elements of tags.
+//
+// Properties which are not selected are removed from properties, required and dependentRequired.
+// Definitions which are referred by the selected fields are inlined into them
+// and definitions which are no longer referred are removed.
+// An error which matches ErrRefInvalid is returned if a field is not found.
+func GenerateProjection(w io.Writer, v interface{}, fields []string, opts ...Option) error {
+	schema, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return err
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return newError(ErrUnsupportedType, RefRoot, fmt.Errorf("schema of %T cannot be projected", v))
+	}
+
+	sel := selection{}
+	for _, f := range fields {
+		sel.add(fieldTokens(f))
+	}
+	if err := project(root, root, sel, RefRoot); err != nil {
+		return err
+	}
+	removeUnreferenced(root)
+
+	return json.NewEncoder(w).Encode(root)
+}
+
+// selection is a tree of selected properties.
+// A property whose selection is nil is selected with all its descendants.
+type selection map[string]selection
+
+func (s selection) add(tokens []string) {
+	for i, t := range tokens {
+		child, ok := s[t]
+		switch {
+		case ok && child == nil:
+			// an ancestor is already selected
+			return
+		case i == len(tokens)-1:
+			s[t] = nil
+		case !ok:
+			child = selection{}
+			s[t] = child
+		}
+		s = child
+	}
+}
+
+// fieldTokens splits a dotted path or a JSON Pointer into property names.
+func fieldTokens(field string) []string {
+	if strings.HasPrefix(field, "/") {
+		return splitPointer(field)
+	}
+	return strings.Split(field, ".")
+}
+
+// project removes properties of the schema which are not selected.
+func project(root, s map[string]interface{}, sel selection, ref string) error {
+	if err := inlineRef(root, s, ref); err != nil {
+		return err
+	}
+
+	props, ok := s["properties"].(map[string]interface{})
+	if !ok {
+		for _, k := range []string{"items", "additionalProperties"} {
+			if sub, ok := s[k].(map[string]interface{}); ok {
+				return project(root, sub, sel, PathRefs{}.Join(ref, k))
+			}
+		}
+		return newError(ErrRefInvalid, ref, fmt.Errorf("schema does not have properties to project"))
+	}
+
+	for name := range props {
+		if _, ok := sel[name]; !ok {
+			delete(props, name)
+		}
+	}
+	names := make([]string, 0, len(sel))
+	for name := range sel {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p, ok := props[name].(map[string]interface{})
+		if !ok {
+			return newError(ErrRefInvalid, ref, fmt.Errorf("field %q is not found", name))
+		}
+		if sel[name] == nil {
+			continue
+		}
+		if err := project(root, p, sel[name], PathRefs{}.Join(ref, "properties", name)); err != nil {
+			return err
+		}
+	}
+
+	if required, ok := s["required"].([]interface{}); ok {
+		var kept []interface{}
+		for _, r := range required {
+			if name, _ := r.(string); props[name] != nil {
+				kept = append(kept, r)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s, "required")
+		} else {
+			s["required"] = kept
+		}
+	}
+	if deps, ok := s["dependentRequired"].(map[string]interface{}); ok {
+		for name := range deps {
+			if props[name] == nil {
+				delete(deps, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// inlineRef replaces a local reference of the schema with a copy of the referred schema,
+// so that projections of shared definitions do not affect other references.
+// Keywords of the schema besides $ref have priority over the referred ones.
+func inlineRef(root, s map[string]interface{}, ref string) error {
+	for i := 0; i < 32; i++ {
+		r, ok := s["$ref"].(string)
+		if !ok || !strings.HasPrefix(r, "#") {
+			return nil
+		}
+		target, ok := lookupPointer(root, strings.TrimPrefix(r, "#")).(map[string]interface{})
+		if !ok {
+			return newError(ErrRefInvalid, ref, fmt.Errorf("reference %q is not found", r))
+		}
+		// the target is copied before s is changed because it may contain s such as "#"
+		target = copyValue(target).(map[string]interface{})
+		delete(s, "$ref")
+		for k, v := range target {
+			if _, ok := s[k]; !ok {
+				s[k] = v
+			}
+		}
+	}
+	return newError(ErrRefInvalid, ref, fmt.Errorf("too many references"))
+}
+
+// removeUnreferenced removes definitions of the root schema which are not referred.
+func removeUnreferenced(root map[string]interface{}) {
+	for _, k := range []string{"$defs", "definitions"} {
+		defs, ok := root[k].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		prefix := "#/" + k + "/"
+
+		referred := map[string]bool{}
+		var visit func(doc interface{})
+		visit = func(doc interface{}) {
+			rewriteRefs(doc, func(ref string) string {
+				name := strings.TrimPrefix(ref, prefix)
+				if name != ref && !referred[name] {
+					referred[name] = true
+					visit(defs[name])
+				}
+				return ref
+			})
+		}
+		delete(root, k)
+		visit(root)
+		root[k] = defs
+
+		for name := range defs {
+			if !referred[name] {
+				delete(defs, name)
+			}
+		}
+		if len(defs) == 0 {
+			delete(root, k)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type projectionAddress struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type projectionUser struct {
+	Name    string              `json:"name"`
+	Email   string              `json:"email,omitempty"`
+	Home    projectionAddress   `json:"home"`
+	Offices []projectionAddress `json:"offices"`
+}
+
+func TestGenerateProjection(t *testing.T) {
+	v := projectionUser{Offices: []projectionAddress{{}}}
+	noOrder := MapKeywords(KeywordRenames{"propertyOrder": ""})
+
+	cases := []struct {
+		name   string
+		fields []string
+		opts   []Option
+		expect string
+	}{
+		{
+			name:   "top-level fields",
+			fields: []string{"name", "email"},
+			expect: `{
+				"title": "projectionUser",
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"email": {"type": "string"}
+				}
+			}`,
+		},
+		{
+			name:   "nested fields",
+			fields: []string{"home.city", "/offices/country", "home"},
+			expect: `{
+				"title": "projectionUser",
+				"type": "object",
+				"required": ["home", "offices"],
+				"properties": {
+					"home": {
+						"title": "projectionAddress",
+						"type": "object",
+						"required": ["city", "country"],
+						"properties": {
+							"city": {"type": "string"},
+							"country": {"type": "string"}
+						}
+					},
+					"offices": {
+						"type": "array",
+						"items": {
+							"title": "projectionAddress",
+							"type": "object",
+							"required": ["country"],
+							"properties": {"country": {"type": "string"}}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:   "shared types",
+			fields: []string{"home.city", "offices"},
+			opts:   []Option{SharedTypes(SharedTypesRef)},
+			expect: `{
+				"title": "projectionUser",
+				"type": "object",
+				"required": ["home", "offices"],
+				"properties": {
+					"home": {
+						"title": "projectionAddress",
+						"type": "object",
+						"required": ["city"],
+						"properties": {"city": {"type": "string"}}
+					},
+					"offices": {
+						"type": "array",
+						"items": {"$ref": "#/$defs/projectionAddress"}
+					}
+				},
+				"$defs": {
+					"projectionAddress": {
+						"title": "projectionAddress",
+						"type": "object",
+						"required": ["city", "country"],
+						"properties": {
+							"city": {"type": "string"},
+							"country": {"type": "string"}
+						}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]Option{noOrder}, tt.opts...)
+			if err := GenerateProjection(&buf, v, tt.fields, opts...); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, buf.String(), tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateProjection_error(t *testing.T) {
+	cases := []struct {
+		name   string
+		fields []string
+	}{
+		{name: "unknown field", fields: []string{"phone"}},
+		{name: "unknown nested field", fields: []string{"home.zip"}},
+		{name: "scalar", fields: []string{"name.first"}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateProjection(&buf, projectionUser{}, tt.fields)
+			if !errors.Is(err, ErrRefInvalid) {
+				t.Errorf("want ErrRefInvalid but got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RediSearch field types which are given by redisearch tags.
+var rediSearchTypes = map[string]bool{
+	"TEXT":    true,
+	"TAG":     true,
+	"NUMERIC": true,
+	"GEO":     true,
+}
+
+// RediSearch field options which are given by redisearch tags.
+var rediSearchFlags = map[string]bool{
+	"SORTABLE":       true,
+	"UNF":            true,
+	"NOSTEM":         true,
+	"NOINDEX":        true,
+	"CASESENSITIVE":  true,
+	"WITHSUFFIXTRIE": true,
+	"INDEXEMPTY":     true,
+	"INDEXMISSING":   true,
+}
+
+// GenerateRediSearch writes the SCHEMA clause of FT.CREATE of RediSearch for RedisJSON documents
+// of the struct v into w such as:
+//
+//	SCHEMA $.name AS name TEXT $.age AS age NUMERIC
+//
+// Arguments are quoted if they have spaces or quotes, so the clause can be given to redis-cli.
+// See RediSearchSchema for fields of the schema.
+func GenerateRediSearch(w io.Writer, v interface{}, opts ...Option) error {
+	args, err := RediSearchSchema(v, opts...)
+	if err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n\"'\\") {
+			a = strconv.Quote(a)
+		}
+		quoted[i] = a
+	}
+	_, err = fmt.Fprintln(w, strings.Join(quoted, " "))
+	return err
+}
+
+// RediSearchSchema returns arguments of the SCHEMA clause of FT.CREATE of RediSearch
+// for RedisJSON documents of the struct v, which can be given to Redis clients.
+//
+// Each field is generated in the same way as Generate with struct tags and options
+// and it is indexed by its JSONPath such as "$.address.city" as the name such as "address_city".
+// Fields of nested structs and groups are indexed by their own fields
+// and elements of slices are indexed by paths such as "$.tags[*]".
+// Strings become TEXT fields, but strings which have enum, const or format become TAG fields.
+// Numbers become NUMERIC fields and booleans become TAG fields.
+//
+// A redisearch tag overrides the type and adds options such as `redisearch:"TAG,SORTABLE"`
+// and `redisearch:"-"` excludes the field.
+// Other fields which cannot be indexed such as maps and references of shared struct types
+// cause an error which matches ErrUnsupportedType.
+func RediSearchSchema(v interface{}, opts ...Option) ([]string, error) {
+	rv, err := structValue(v, "document of RediSearch")
+	if err != nil {
+		return nil, err
+	}
+
+	g := gen{cfg: newConfig(opts)}
+	root := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+
+	args := []string{"SCHEMA"}
+	names := map[string]bool{}
+	err = g.rediSearchFields(root, rv, "$", "", opts, func(path, name string, spec []string) error {
+		if names[name] {
+			return fmt.Errorf("field name %q is used by more than one paths", name)
+		}
+		names[name] = true
+		args = append(append(args, path, "AS", name), spec...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// rediSearchFields calls add for each field of the struct v which can be indexed.
+func (g *gen) rediSearchFields(parent Object, v reflect.Value, path, name string, options []Option, add func(path, name string, spec []string) error) error {
+	groups := map[string]Object{}
+	fields, err := g.fields(v)
+	if err != nil {
+		return withRef(err, parent.Ref())
+	}
+	for i, f := range fields {
+		spec, ok, err := parseRediSearchTag(f.rawTag.Get("redisearch"))
+		if err != nil {
+			return &Error{Kind: ErrTagSyntax, Ref: parent.Ref(), Field: f.goName, Err: err}
+		}
+		if !ok {
+			continue
+		}
+
+		p, fpath, fname := parent, path, name
+		if f.group != "" {
+			if groups[f.group] == nil {
+				groups[f.group] = &obj{
+					m:   map[string]interface{}{},
+					ref: g.cfg.refs().Join(parent.Ref(), "properties", f.group),
+				}
+			}
+			p, fpath, fname = groups[f.group], fpath+jsonPathName(f.group), joinRediSearchName(fname, f.group)
+		}
+		fpath, fname = fpath+jsonPathName(f.name), joinRediSearchName(fname, f.name)
+
+		// types of fields are needed even if they are nil
+		if isNil(f.value) && f.value.Kind() != reflect.Interface {
+			f.value = empty(f.value.Type())
+		}
+
+		o, err := g.propertyGen(p, f, i, v.Type().Name(), options)
+		if err != nil {
+			return err
+		}
+
+		var s Object = o
+		ev := reflect.Indirect(f.value)
+		if items, ok := o.m["items"].(map[string]interface{}); ok && o.m["type"] == "array" {
+			s = &obj{m: items, ref: g.cfg.refs().Join(o.Ref(), "items")}
+			fpath += "[*]"
+			ev = reflect.Indirect(empty(ev.Type().Elem()))
+		}
+
+		if _, ok := s.Get("properties"); ok && ev.Kind() == reflect.Struct && spec == nil {
+			if err := g.rediSearchFields(s, ev, fpath, fname, options, add); err != nil {
+				return withField(err, f.goName)
+			}
+			continue
+		}
+
+		if spec == nil || !rediSearchTypes[spec[0]] {
+			typ, err := rediSearchType(s)
+			if err != nil {
+				return &Error{Kind: ErrUnsupportedType, Ref: s.Ref(), Field: f.goName, Err: err}
+			}
+			spec = append([]string{typ}, spec...)
+		}
+
+		if err := add(fpath, fname, spec); err != nil {
+			return &Error{Kind: ErrNameCollision, Ref: s.Ref(), Field: f.goName, Err: err}
+		}
+	}
+	return nil
+}
+
+// rediSearchType returns the type of a RediSearch field for the schema.
+func rediSearchType(s Object) (string, error) {
+	if ref, ok := s.Get("$ref"); ok {
+		return "", fmt.Errorf("reference %v cannot be indexed by RediSearch", ref)
+	}
+
+	typ, _ := s.Get("type")
+	switch typ {
+	case "string":
+		for _, k := range []string{"enum", "const", "format"} {
+			if _, ok := s.Get(k); ok {
+				return "TAG", nil
+			}
+		}
+		return "TEXT", nil
+	case "number", "integer":
+		return "NUMERIC", nil
+	case "boolean":
+		return "TAG", nil
+	case nil:
+		return "", fmt.Errorf("schema without type cannot be indexed by RediSearch")
+	}
+	return "", fmt.Errorf("type %v cannot be indexed by RediSearch", typ)
+}
+
+// parseRediSearchTag parses a redisearch tag such as "TAG,SORTABLE".
+// It returns false if the field is excluded by "-".
+func parseRediSearchTag(tag string) ([]string, bool, error) {
+	tag = strings.TrimSpace(tag)
+	switch tag {
+	case "":
+		return nil, true, nil
+	case "-":
+		return nil, false, nil
+	}
+
+	spec := strings.Split(tag, ",")
+	for i, s := range spec {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		switch {
+		case i == 0 && rediSearchTypes[s]:
+		case rediSearchFlags[s]:
+		default:
+			return nil, false, fmt.Errorf("invalid redisearch tag %q: unknown %q", tag, s)
+		}
+		spec[i] = s
+	}
+	return spec, true, nil
+}
+
+// jsonPathName returns a child member of JSONPath such as ".name" or `["first name"]`.
+func jsonPathName(name string) string {
+	for i, r := range name {
+		if r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && (i == 0 || !('0' <= r && r <= '9')) {
+			return "[" + strconv.Quote(name) + "]"
+		}
+	}
+	if name == "" {
+		return `[""]`
+	}
+	return "." + name
+}
+
+// joinRediSearchName joins names of nested fields by "_".
+func joinRediSearchName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "_" + name
+}
@@ -0,0 +1,95 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateRediSearch(t *testing.T) {
+	type Item struct {
+		SKU   string  `json:"sku" jsonschema:"pattern=^[A-Z]+$"`
+		Price float64 `json:"price"`
+	}
+	type Doc struct {
+		Title   string            `json:"title"`
+		ID      string            `json:"id" jsonschema:"format=uuid"`
+		Views   int               `json:"views" redisearch:"SORTABLE"`
+		Public  bool              `json:"public"`
+		Author  string            `json:"author name" redisearch:"TAG,CASESENSITIVE"`
+		Created time.Time         `json:"created"`
+		Tags    []string          `json:"tags"`
+		Items   []Item            `json:"items"`
+		City    string            `json:"city" group:"address"`
+		Meta    map[string]string `json:"meta" redisearch:"-"`
+		Author2 *struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateRediSearch(&buf, Doc{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `SCHEMA $.title AS title TEXT $.id AS id TAG $.views AS views NUMERIC SORTABLE` +
+		` $.public AS public TAG "$[\"author name\"]" AS "author name" TAG CASESENSITIVE $.created AS created TAG` +
+		` $.tags[*] AS tags TEXT $.items[*].sku AS items_sku TEXT $.items[*].price AS items_price NUMERIC` +
+		` $.address.city AS address_city TEXT $.author.name AS author_name TEXT` + "\n"
+	if got := buf.String(); got != expect {
+		t.Errorf("want %q but got %q", expect, got)
+	}
+
+	args, err := RediSearchSchema(&struct {
+		Name string `json:"name"`
+	}{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if want := []string{"SCHEMA", "$.name", "AS", "name", "TEXT"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("want %q but got %q", want, args)
+	}
+}
+
+func TestGenerateRediSearch_error(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+		kind error
+	}{
+		{"nil", nil, ErrUnsupportedType},
+		{"not struct", []string{}, ErrUnsupportedType},
+		{"map", struct {
+			Meta map[string]string `json:"meta"`
+		}{}, ErrUnsupportedType},
+		{"interface", struct {
+			Any interface{} `json:"any"`
+		}{}, ErrUnsupportedType},
+		{"ref", struct {
+			Meta tableMeta `json:"meta"`
+		}{}, ErrUnsupportedType},
+		{"collision", struct {
+			AB string `json:"a_b"`
+			A  struct {
+				B string `json:"b"`
+			} `json:"a"`
+		}{}, ErrNameCollision},
+		{"tag", struct {
+			Name string `json:"name" redisearch:"TEXT,FAST"`
+		}{}, ErrTagSyntax},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RediSearchSchema(tt.v, SharedTypes(SharedTypesRef))
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+		})
+	}
+}
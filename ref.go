@@ -0,0 +1,114 @@
+package jsonschema
+
+import (
+	"path"
+	"strings"
+)
+
+// RefBuilder builds references of objects which are given by Object.Ref
+// and matched by ByReference and Overlay.
+// It does not change values of $ref in generated schemas,
+// which are always JSON Pointers such as "#/$defs/User".
+type RefBuilder interface {
+	// Root returns the reference of the root object.
+	Root() string
+	// Join returns the reference of a descendant of the parent object
+	// which is located by keywords and names such as "properties" and "name".
+	Join(parent string, tokens ...string) string
+}
+
+// WithRefBuilder builds references of objects by b instead of PathRefs.
+func WithRefBuilder(b RefBuilder) Option {
+	return configOption(func(c *config) {
+		c.refBuilder = b
+	})
+}
+
+// PathRefs builds references such as "#/properties/name" by path.Join.
+// It is the default RefBuilder. Tokens are not escaped,
+// so names which contain "/" cannot be distinguished from nested objects.
+type PathRefs struct{}
+
+// Root implements RefBuilder.
+func (PathRefs) Root() string {
+	return RefRoot
+}
+
+// Join implements RefBuilder.
+func (PathRefs) Join(parent string, tokens ...string) string {
+	return path.Join(append([]string{parent}, tokens...)...)
+}
+
+// PointerRefs builds references which are JSON Pointers in URI fragments
+// such as "#/properties/a~1b". The root is "#".
+// "~" and "/" in tokens are escaped as RFC 6901, but tokens which are already escaped
+// such as "a~1b" are kept as they are.
+type PointerRefs struct{}
+
+// Root implements RefBuilder.
+func (PointerRefs) Root() string {
+	return "#"
+}
+
+// Join implements RefBuilder.
+func (PointerRefs) Join(parent string, tokens ...string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(parent, "/"))
+	for _, t := range tokens {
+		b.WriteByte('/')
+		if isEscapedToken(t) {
+			b.WriteString(t)
+		} else {
+			b.WriteString(escapePointer(t))
+		}
+	}
+	return b.String()
+}
+
+// isEscapedToken reports whether the token is already escaped as a reference token of JSON Pointer,
+// i.e. it has escape sequences and has neither "/" nor "~" which are not escaped.
+func isEscapedToken(t string) bool {
+	if strings.Contains(t, "/") || !strings.Contains(t, "~") {
+		return false
+	}
+	for i := 0; i < len(t); i++ {
+		if t[i] == '~' && (i+1 == len(t) || t[i+1] != '0' && t[i+1] != '1') {
+			return false
+		}
+	}
+	return true
+}
+
+// DottedRefs builds dotted paths such as "properties.name.items"
+// which are used by some UI libraries. The value is the root token:
+// DottedRefs("#") builds "#.properties.name" and DottedRefs("") builds "properties.name".
+type DottedRefs string
+
+// Root implements RefBuilder.
+func (r DottedRefs) Root() string {
+	return string(r)
+}
+
+// Join implements RefBuilder.
+func (r DottedRefs) Join(parent string, tokens ...string) string {
+	if parent == "" {
+		return strings.Join(tokens, ".")
+	}
+	return parent + "." + strings.Join(tokens, ".")
+}
+
+// refs returns the RefBuilder of the generator.
+func (c *config) refs() RefBuilder {
+	if c.refBuilder == nil {
+		return PathRefs{}
+	}
+	return c.refBuilder
+}
+
+// isRoot reports whether the object is the root object.
+func isRoot(o Object) bool {
+	if r, ok := o.(interface{ isRoot() bool }); ok {
+		return r.isRoot()
+	}
+	return o.Ref() == RefRoot
+}
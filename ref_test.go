@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestRefBuilder(t *testing.T) {
+	cases := []struct {
+		name   string
+		b      RefBuilder
+		root   string
+		join   []string
+		joined string
+	}{
+		{"path", PathRefs{}, "#/", []string{"properties", "a/b"}, "#/properties/a/b"},
+		{"pointer", PointerRefs{}, "#", []string{"properties", "a/b~c"}, "#/properties/a~1b~0c"},
+		{"pointer escaped", PointerRefs{}, "#", []string{"properties", "a~1b"}, "#/properties/a~1b"},
+		{"dotted", DottedRefs(""), "", []string{"properties", "name"}, "properties.name"},
+		{"dotted root", DottedRefs("#"), "#", []string{"properties", "name"}, "#.properties.name"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.Root(); got != tt.root {
+				t.Errorf("Root() = %q, want %q", got, tt.root)
+			}
+			if got := tt.b.Join(tt.b.Root(), tt.join...); got != tt.joined {
+				t.Errorf("Join() = %q, want %q", got, tt.joined)
+			}
+		})
+	}
+}
+
+func TestWithRefBuilder(t *testing.T) {
+	type T struct {
+		Path  string `json:"a/b"`
+		Inner struct {
+			Name string `json:"name"`
+		} `json:"inner"`
+	}
+
+	var refs []string
+	collect := func(o Object) (Object, error) {
+		// options are also applied to the configuration of the generator
+		if o.Ref() != "" {
+			refs = append(refs, o.Ref())
+		}
+		return o, nil
+	}
+
+	got, err := GenerateString(T{},
+		WithRefBuilder(PointerRefs{}),
+		HoistAnonymousStructs(),
+		collect,
+		ByReference("#/properties/a~1b", func(o Object) (Object, error) {
+			o.Set("minLength", 1)
+			return o, nil
+		}),
+		MergePatch(strings.NewReader(`{"description": "T"}`)),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"type": "object",
+		"title": "T",
+		"description": "T",
+		"required": ["a/b", "inner"],
+		"properties": {
+			"a/b": {"type": "string", "minLength": 1, "propertyOrder": 0},
+			"inner": {"$ref": "#/$defs/T_Inner", "propertyOrder": 1}
+		},
+		"$defs": {
+			"T_Inner": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string", "propertyOrder": 0}}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	want := map[string]bool{
+		"#":                               true,
+		"#/properties/a~1b":               true,
+		"#/properties/inner":              true,
+		"#/$defs/T_Inner":                 true,
+		"#/$defs/T_Inner/properties/name": true,
+	}
+	for _, ref := range refs {
+		if !want[ref] {
+			t.Errorf("unexpected reference %q", ref)
+		}
+		delete(want, ref)
+	}
+	for ref := range want {
+		t.Errorf("reference %q is not found", ref)
+	}
+}
@@ -0,0 +1,62 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Reflect generates the schema of v as Generate and compiles it,
+// so it can be inspected by Root, changed by Edit and validate documents without encoding it.
+// Extensions of custom error messages which are given by ErrorMessageExtension are also compiled.
+func Reflect(v interface{}, opts ...Option) (*Schema, error) {
+	schema, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return CompileBytes(schema, WithErrorMessageKey(newConfig(opts).errorMessageKey()))
+}
+
+// Root returns a copy of the root schema as an Object whose reference is RefRoot.
+// Changes of the copy do not affect the compiled schema; Edit compiles them.
+// A boolean schema true is an empty object and false is {"not": {}}.
+func (s *Schema) Root() Object {
+	return NewObject(RefRoot, rootMap(copyValue(s.doc)))
+}
+
+// Edit changes a copy of the root schema by f and compiles it into a new schema with the options
+// such as WithLoader. The error message key of the schema is kept.
+func (s *Schema) Edit(f func(o Object) error, opts ...CompileOption) (*Schema, error) {
+	m := rootMap(copyValue(s.doc))
+	if err := f(NewObject(RefRoot, m)); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot encode schema: %w", err)
+	}
+	return CompileBytes(b, append([]CompileOption{WithErrorMessageKey(s.errMsgKey)}, opts...)...)
+}
+
+// MarshalJSON implements json.Marshaler. Keys of objects are written in alphabetical order.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.doc)
+}
+
+// MarshalIndent is like MarshalJSON but indents the schema as json.MarshalIndent.
+func (s *Schema) MarshalIndent(prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(s.doc, prefix, indent)
+}
+
+// rootMap returns the root schema as a map.
+func rootMap(doc interface{}) map[string]interface{} {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		return doc
+	case bool:
+		if !doc {
+			return map[string]interface{}{"not": map[string]interface{}{}}
+		}
+	}
+	return map[string]interface{}{}
+}
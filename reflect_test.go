@@ -0,0 +1,104 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestReflect(t *testing.T) {
+	type T struct {
+		Name string `json:"name" jsonschema:"minLength=1"`
+		Age  int    `json:"age,omitempty"`
+	}
+
+	s, err := Reflect(T{}, MapKeywords(KeywordRenames{"propertyOrder": ""}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"title": "T",
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"age": {"type": "number"}
+		}
+	}`
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if diff := jsonDiff(t, string(got), expect); diff != "" {
+		t.Errorf("reflected JSON Schema does not match to expected one: %v", diff)
+	}
+
+	indented, err := s.MarshalIndent("", "  ")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if diff := jsonDiff(t, string(indented), expect); diff != "" {
+		t.Errorf("indented JSON Schema does not match to expected one: %v", diff)
+	}
+
+	if err := s.Validate([]byte(`{"name": ""}`)); err == nil {
+		t.Error("expected validation error does not occur")
+	}
+
+	// changes of the root do not affect the schema
+	name, ok := Subschema(s.Root(), "properties", "name")
+	if !ok {
+		t.Fatal("subschema of name is not found")
+	}
+	if v, _ := name.Get("minLength"); v != json.Number("1") {
+		t.Errorf("minLength of name is %v, but want 1", v)
+	}
+	name.Delete("minLength")
+	if err := s.Validate([]byte(`{"name": ""}`)); err == nil {
+		t.Error("changes of the root must not affect the schema")
+	}
+
+	edited, err := s.Edit(func(o Object) error {
+		name, _ := Subschema(o, "properties", "name")
+		name.Delete("minLength")
+		o.Set("required", []string{"name", "age"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := edited.Validate([]byte(`{"name": "", "age": 1}`)); err != nil {
+		t.Error("unexpected validation error:", err)
+	}
+	if err := edited.Validate([]byte(`{"name": ""}`)); err == nil {
+		t.Error("expected validation error does not occur")
+	}
+	if err := s.Validate([]byte(`{"name": ""}`)); err == nil {
+		t.Error("edits must not affect the original schema")
+	}
+}
+
+func TestReflect_error(t *testing.T) {
+	if _, err := Reflect(nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want ErrUnsupportedType but got %v", err)
+	}
+
+	s, err := Reflect(0)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if _, err := s.Edit(func(o Object) error {
+		return errors.New("error")
+	}); err == nil {
+		t.Error("expected error does not occur")
+	}
+	if _, err := s.Edit(func(o Object) error {
+		o.Set("type", 1)
+		return nil
+	}); err == nil {
+		t.Error("expected compilation error does not occur")
+	}
+}
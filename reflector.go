@@ -0,0 +1,93 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Reflector generates schemas of types with the options and memoizes them by their types,
+// so repeated generations of the same types such as schemas which are served
+// for each HTTP request do not cost reflection.
+// It is safe for concurrent use and each type is generated once even if it is requested concurrently.
+//
+// Schemas are generated from types instead of values: a value is replaced with
+// the zero value of its type and nil pointers, slices and maps are generated from their types
+// as SharedTypes(SharedTypesRecursive), which the options can override.
+// Thus values such as defaults of Defaults and dynamic types of interfaces do not affect schemas.
+// Unlike Cache, schemas are kept in memory without hashing values and options,
+// so the options must not depend on values.
+// Types of fields which are shared by SharedTypes are generated once in each schema
+// and referenced from its "$defs".
+type Reflector struct {
+	opts    []Option
+	schemas sync.Map // map[reflect.Type]*reflected
+}
+
+// reflected is a memoized generation of a type.
+type reflected struct {
+	once   sync.Once
+	schema []byte
+	err    error
+
+	compileOnce sync.Once
+	compiled    *Schema
+	compileErr  error
+}
+
+// NewReflector creates a Reflector which generates schemas with the options.
+func NewReflector(opts ...Option) *Reflector {
+	return &Reflector{opts: append([]Option{SharedTypes(SharedTypesRecursive)}, opts...)}
+}
+
+// Generate writes the schema of the type of v to w like Generate.
+func (r *Reflector) Generate(w io.Writer, v interface{}) error {
+	schema, err := r.GenerateBytes(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(schema)
+	return err
+}
+
+// GenerateBytes returns the schema of the type of v like GenerateBytes.
+// The returned slice can be changed by callers.
+func (r *Reflector) GenerateBytes(v interface{}) ([]byte, error) {
+	e, err := r.generate(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), e.schema...), nil
+}
+
+// Reflect returns the compiled schema of the type of v like Reflect.
+// Compiled schemas are shared by callers because Schema cannot be changed.
+func (r *Reflector) Reflect(v interface{}) (*Schema, error) {
+	e, err := r.generate(v)
+	if err != nil {
+		return nil, err
+	}
+	e.compileOnce.Do(func() {
+		e.compiled, e.compileErr = CompileBytes(e.schema, WithErrorMessageKey(newConfig(r.opts).errorMessageKey()))
+	})
+	return e.compiled, e.compileErr
+}
+
+// generate returns the memoized generation of the type of v.
+func (r *Reflector) generate(v interface{}) (*reflected, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, newError(ErrUnsupportedType, RefRoot, fmt.Errorf("untyped nil cannot be encoded in JSON Schema"))
+	}
+
+	cached, _ := r.schemas.LoadOrStore(t, &reflected{})
+	e := cached.(*reflected)
+	e.once.Do(func() {
+		e.schema, e.err = GenerateBytes(reflect.Zero(t).Interface(), r.opts...)
+	})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e, nil
+}
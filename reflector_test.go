@@ -0,0 +1,175 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type reflectorAddress struct {
+	City string `json:"city"`
+}
+
+type reflectorUser struct {
+	Name    string             `json:"name"`
+	Home    *reflectorAddress  `json:"home"`
+	Offices []reflectorAddress `json:"offices"`
+}
+
+func TestReflector(t *testing.T) {
+	address := `{
+		"title": "reflectorAddress",
+		"type": "object",
+		"required": ["city"],
+		"properties": {"city": {"type": "string"}}
+	}`
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "zero value",
+			v:    reflectorUser{},
+			expect: `{
+				"title": "reflectorUser",
+				"type": "object",
+				"required": ["name", "offices"],
+				"properties": {
+					"name": {"type": "string"},
+					"home": ` + address + `,
+					"offices": {"type": "array", "items": ` + address + `}
+				}
+			}`,
+		},
+		{
+			name: "values are ignored",
+			v:    &reflectorUser{Name: "tenntenn", Home: &reflectorAddress{}},
+			opts: []Option{Defaults(DefaultOmitZero)},
+			expect: `{
+				"title": "reflectorUser",
+				"type": "object",
+				"required": ["name", "offices"],
+				"properties": {
+					"name": {"type": "string"},
+					"home": ` + address + `,
+					"offices": {"type": "array", "items": ` + address + `}
+				}
+			}`,
+		},
+		{
+			name: "shared types",
+			v:    reflectorUser{},
+			opts: []Option{SharedTypes(SharedTypesRef)},
+			expect: `{
+				"title": "reflectorUser",
+				"type": "object",
+				"required": ["name", "offices"],
+				"properties": {
+					"name": {"type": "string"},
+					"home": {"$ref": "#/$defs/reflectorAddress"},
+					"offices": {"type": "array", "items": {"$ref": "#/$defs/reflectorAddress"}}
+				},
+				"$defs": {"reflectorAddress": ` + address + `}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{MapKeywords(KeywordRenames{"propertyOrder": ""})}, tt.opts...)
+			r := NewReflector(opts...)
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				if err := r.Generate(&buf, tt.v); err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if diff := jsonDiff(t, buf.String(), tt.expect); diff != "" {
+					t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestReflector_GenerateBytes(t *testing.T) {
+	r := NewReflector()
+	b, err := r.GenerateBytes(reflectorAddress{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := string(b)
+
+	// changes of the returned slice do not affect the memoized schema
+	for i := range b {
+		b[i] = ' '
+	}
+	got, err := r.GenerateBytes(reflectorAddress{City: "Tokyo"})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if string(got) != expect {
+		t.Errorf("memoized schema is changed: %s", got)
+	}
+}
+
+func TestReflector_concurrent(t *testing.T) {
+	r := NewReflector()
+	expect, err := GenerateString(reflectorUser{}, SharedTypes(SharedTypesRecursive))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var wg sync.WaitGroup
+	schemas := make([]*Schema, 8)
+	for i := range schemas {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := r.GenerateBytes(reflectorUser{})
+			if err != nil {
+				t.Error("unexpected error:", err)
+				return
+			}
+			if string(got) != expect {
+				t.Errorf("generated JSON Schema does not match to expected one: %s", got)
+			}
+			schemas[i], err = r.Reflect(reflectorUser{})
+			if err != nil {
+				t.Error("unexpected error:", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range schemas[1:] {
+		if s != schemas[0] {
+			t.Error("compiled schemas must be shared")
+		}
+	}
+	if err := schemas[0].Validate([]byte(`{"name": "tenntenn", "offices": [{}]}`)); err == nil {
+		t.Error("expected validation error does not occur")
+	}
+}
+
+func TestReflector_error(t *testing.T) {
+	r := NewReflector()
+	if _, err := r.GenerateBytes(nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want ErrUnsupportedType but got %v", err)
+	}
+
+	r = NewReflector(StrictNames(regexp.MustCompile("^[a-z]+$")))
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reflect(struct{ N int }{}); !errors.Is(err, ErrTagSyntax) {
+			t.Errorf("want ErrTagSyntax but got %v", err)
+		}
+	}
+}
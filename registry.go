@@ -0,0 +1,136 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds schemas of registered Go types.
+// Schemas are generated on registration and cached until they are regenerated.
+// It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[RegistryKey]*registryEntry
+}
+
+// RegistryKey identifies a schema in a Registry.
+type RegistryKey struct {
+	Name    string
+	Version string
+}
+
+func (k RegistryKey) String() string {
+	return k.Name + "@" + k.Version
+}
+
+type registryEntry struct {
+	v      interface{}
+	opts   []Option
+	schema []byte
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: map[RegistryKey]*registryEntry{},
+	}
+}
+
+// Register generates a schema of v and registers it with the name and version.
+// The options are kept and used again when the schema is regenerated.
+// It returns an error if the name and version have already been registered.
+func (r *Registry) Register(name, version string, v interface{}, opts ...Option) error {
+	key := RegistryKey{Name: name, Version: version}
+
+	schema, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot register %s: %w", key, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[key]; ok {
+		return &Error{Kind: ErrNameCollision, Err: fmt.Errorf("%s has already been registered", key)}
+	}
+	r.entries[key] = &registryEntry{
+		v:      v,
+		opts:   opts,
+		schema: schema,
+	}
+
+	return nil
+}
+
+// Get returns a copy of the cached schema which is registered with the name and version,
+// so changes of the returned schema do not affect other callers.
+func (r *Registry) Get(name, version string) ([]byte, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.entries[RegistryKey{Name: name, Version: version}]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), e.schema...), true
+}
+
+// List returns keys of registered schemas sorted by name and version.
+func (r *Registry) List() []RegistryKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]RegistryKey, 0, len(r.entries))
+	for k := range r.entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].Version < keys[j].Version
+	})
+	return keys
+}
+
+// Regenerate regenerates the schema which is registered with the name and version.
+// If opts are given, they replace the registered options.
+// The cached schema is kept when an error occurs.
+func (r *Registry) Regenerate(name, version string, opts ...Option) error {
+	key := RegistryKey{Name: name, Version: version}
+
+	r.mu.RLock()
+	e, ok := r.entries[key]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("jsonschema: %s has not been registered", key)
+	}
+
+	if len(opts) == 0 {
+		opts = e.opts
+	}
+
+	schema, err := GenerateBytes(e.v, opts...)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot regenerate %s: %w", key, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[key] = &registryEntry{
+		v:      e.v,
+		opts:   opts,
+		schema: schema,
+	}
+
+	return nil
+}
+
+// RegenerateAll regenerates all registered schemas with their options.
+// It stops at the first error.
+func (r *Registry) RegenerateAll() error {
+	for _, k := range r.List() {
+		if err := r.Regenerate(k.Name, k.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestRegistry(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	r := NewRegistry()
+	errCheck(r.Register("user", "v1", User{}))
+	errCheck(r.Register("user", "v2", User{}, describeRef))
+	errCheck(r.Register("count", "v1", 0))
+
+	if err := r.Register("user", "v1", User{}); err == nil {
+		t.Error("expected error does not occur for duplicated registration")
+	}
+
+	if err := r.Register("invalid", "v1", make(chan int)); err == nil {
+		t.Error("expected error does not occur for unsupported type")
+	}
+
+	wantKeys := []RegistryKey{
+		{Name: "count", Version: "v1"},
+		{Name: "user", Version: "v1"},
+		{Name: "user", Version: "v2"},
+	}
+	if got := r.List(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("List() = %v, want %v", got, wantKeys)
+	}
+
+	got, ok := r.Get("count", "v1")
+	if !ok {
+		t.Fatal("registered schema cannot be got")
+	}
+	if diff := jsonDiff(t, string(got), `{"type":"number"}`); diff != "" {
+		t.Errorf("registered schema does not match to expected one: %v", diff)
+	}
+
+	// changes of the got schema do not affect the registry
+	for i := range got {
+		got[i] = ' '
+	}
+	got, _ = r.Get("count", "v1")
+	if diff := jsonDiff(t, string(got), `{"type":"number"}`); diff != "" {
+		t.Errorf("registered schema is changed by the caller: %v", diff)
+	}
+
+	if _, ok := r.Get("user", "v3"); ok {
+		t.Error("unregistered schema is got")
+	}
+
+	patch := MergePatch(strings.NewReader(`{"description":"regenerated"}`))
+	errCheck(r.Regenerate("count", "v1", patch))
+	got, _ = r.Get("count", "v1")
+	if diff := jsonDiff(t, string(got), `{"type":"number","description":"regenerated"}`); diff != "" {
+		t.Errorf("regenerated schema does not match to expected one: %v", diff)
+	}
+
+	// registered options are kept
+	errCheck(r.RegenerateAll())
+	got, _ = r.Get("count", "v1")
+	if diff := jsonDiff(t, string(got), `{"type":"number","description":"regenerated"}`); diff != "" {
+		t.Errorf("regenerated schema does not match to expected one: %v", diff)
+	}
+
+	if err := r.Regenerate("user", "v3"); err == nil {
+		t.Error("expected error does not occur for unregistered schema")
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	r := NewRegistry()
+	errCheck(r.Register("count", "v1", 0))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, ok := r.Get("count", "v1"); !ok {
+				t.Error("registered schema cannot be got")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := r.Regenerate("count", "v1"); err != nil {
+				t.Error("unexpected error:", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,254 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Resolver resolves references of schemas to external documents
+// such as {"$ref": "https://example.com/user.json#/$defs/name"} and {"$ref": "address.json"}
+// by its loader, so schemas which are generated or written separately
+// can be shipped as a single self-contained schema.
+// Loaders such as HTTPLoader, FSLoader and MapLoader can be combined by LoaderFunc.
+type Resolver struct {
+	loader Loader
+}
+
+// NewResolver creates a Resolver which loads external documents by the loader.
+// Without loaders, schemas which refer to external documents cannot be resolved.
+func NewResolver(l Loader) *Resolver {
+	return &Resolver{loader: l}
+}
+
+// Bundle returns the self-contained schema of the schema whose URI is base.
+// Each external document which is referred directly or indirectly is loaded once
+// and put into $defs by the name of its file such as "address" for "defs/address.json",
+// which is suffixed such as "address_2" if it is already used.
+// References to them are rewritten to local references such as "#/$defs/address/properties/city",
+// so circular references between documents are kept as references.
+// Relative references are resolved against base or $id of the schema and the documents.
+// Documents lose their $id and $schema and fragments of references to them must be JSON Pointers.
+func (r *Resolver) Bundle(ctx context.Context, schema []byte, base string) ([]byte, error) {
+	doc, err := r.bundle(ctx, schema, base)
+	if err != nil {
+		return nil, err
+	}
+	return encodeResolved(doc)
+}
+
+// Dereference returns the schema whose URI is base without any references like Bundle
+// but each reference is replaced with a copy of the referred schema and $defs are removed.
+// Keywords besides $ref have priority over the referred ones.
+// Recursive schemas cannot be dereferenced and reported as errors which match ErrCycle.
+func (r *Resolver) Dereference(ctx context.Context, schema []byte, base string) ([]byte, error) {
+	doc, err := r.bundle(ctx, schema, base)
+	if err != nil {
+		return nil, err
+	}
+	if root, ok := doc.(map[string]interface{}); ok {
+		// the root is being inlined as "#"
+		if err := inlineRefs(root, &obj{m: root, ref: RefRoot}, []string{"#"}); err != nil {
+			return nil, err
+		}
+		delete(root, "$defs")
+		delete(root, "definitions")
+	}
+	return encodeResolved(doc)
+}
+
+func encodeResolved(doc interface{}) ([]byte, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot encode schema: %w", err)
+	}
+	return b, nil
+}
+
+// bundle decodes the schema and bundles external documents which it refers.
+func (r *Resolver) bundle(ctx context.Context, schema []byte, base string) (interface{}, error) {
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot resolve schema: %w", err)
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return doc, nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, newError(ErrRefInvalid, base, err)
+	}
+	if u, err = withID(u, root); err != nil {
+		return nil, err
+	}
+
+	defs, _ := root["$defs"].(map[string]interface{})
+	res := &resolution{
+		ctx:     ctx,
+		loader:  r.loader,
+		defs:    defs,
+		bundled: map[string]interface{}{},
+		names:   map[string]string{u.String(): ""},
+	}
+	if err := res.resolve(root, u); err != nil {
+		return nil, err
+	}
+
+	// documents are added after references of the root are rewritten
+	if len(res.bundled) != 0 {
+		if defs == nil {
+			defs = map[string]interface{}{}
+			root["$defs"] = defs
+		}
+		for name, d := range res.bundled {
+			defs[name] = d
+		}
+	}
+	return root, nil
+}
+
+// withID resolves $id of the document against the URL and removes the fragment.
+func withID(u *url.URL, doc map[string]interface{}) (*url.URL, error) {
+	if id, ok := doc["$id"].(string); ok {
+		r, err := url.Parse(id)
+		if err != nil {
+			return nil, newError(ErrRefInvalid, id, err)
+		}
+		u = resolveURL(u, r)
+	}
+	copied := *u
+	copied.Fragment = ""
+	copied.RawFragment = ""
+	return &copied, nil
+}
+
+// resolveURL resolves the reference against the base.
+// References from relative bases such as paths of files are kept relative.
+func resolveURL(base, ref *url.URL) *url.URL {
+	u := base.ResolveReference(ref)
+	if !base.IsAbs() && base.Host == "" && !strings.HasPrefix(base.Path, "/") &&
+		!ref.IsAbs() && ref.Host == "" && !strings.HasPrefix(ref.Path, "/") {
+		u.Path = strings.TrimPrefix(u.Path, "/")
+	}
+	return u
+}
+
+// resolution is a state of Bundle.
+type resolution struct {
+	ctx    context.Context
+	loader Loader
+	// defs are definitions of the root schema.
+	defs map[string]interface{}
+	// bundled are loaded documents by their names in $defs.
+	bundled map[string]interface{}
+	// names are names of documents by their URIs. The name of the root schema is empty.
+	names map[string]string
+}
+
+// resolve rewrites references of the doc whose URI is the base into local references of the bundle.
+// Keys are visited in order, so names of documents are stable.
+func (res *resolution) resolve(doc interface{}, base *url.URL) error {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		if ref, ok := doc["$ref"].(string); ok {
+			local, err := res.ref(base, ref)
+			if err != nil {
+				return err
+			}
+			doc["$ref"] = local
+		}
+		for _, k := range sortedKeys(doc) {
+			if err := res.resolve(doc[k], base); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, v := range doc {
+			if err := res.resolve(v, base); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ref returns the local reference of the bundle which the reference from the document of the base refers.
+func (res *resolution) ref(base *url.URL, ref string) (string, error) {
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", newError(ErrRefInvalid, ref, err)
+	}
+	u := resolveURL(base, r)
+	fragment := u.EscapedFragment()
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	name, ok := res.names[u.String()]
+	if !ok {
+		if name, err = res.load(u); err != nil {
+			return "", err
+		}
+	}
+	if name == "" {
+		return "#" + fragment, nil
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", newError(ErrRefInvalid, ref, fmt.Errorf("anchor %q of an external document cannot be bundled", fragment))
+	}
+	return "#/$defs/" + escapePointer(name) + fragment, nil
+}
+
+// load loads the document of the URL into the bundle and returns its name.
+func (res *resolution) load(u *url.URL) (string, error) {
+	uri := u.String()
+	if res.loader == nil {
+		return "", newError(ErrRefInvalid, uri, fmt.Errorf("no loader for external references"))
+	}
+	b, err := res.loader.Load(res.ctx, uri)
+	if err != nil {
+		return "", err
+	}
+	doc, err := decodeJSON(b)
+	if err != nil {
+		return "", fmt.Errorf("jsonschema: cannot resolve %s: %w", uri, err)
+	}
+
+	name := res.name(u)
+	res.names[uri] = name
+	res.bundled[name] = doc
+
+	base := u
+	if m, ok := doc.(map[string]interface{}); ok {
+		if base, err = withID(u, m); err != nil {
+			return "", err
+		}
+		res.names[base.String()] = name
+		// the document is a part of the bundle
+		delete(m, "$id")
+		delete(m, "$schema")
+	}
+	return name, res.resolve(doc, base)
+}
+
+// name returns an unused name in $defs for the document of the URL.
+func (res *resolution) name(u *url.URL) string {
+	name := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	if name == "" || name == "." || name == "/" {
+		name = "schema"
+	}
+	used := func(name string) bool {
+		_, inDefs := res.defs[name]
+		_, inBundle := res.bundled[name]
+		return inDefs || inBundle
+	}
+	candidate := name
+	for i := 2; used(candidate); i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
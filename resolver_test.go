@@ -0,0 +1,223 @@
+package jsonschema_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestResolver_Bundle(t *testing.T) {
+	loader := MapLoader{
+		"https://example.com/schemas/user.json": []byte(`{
+			"$id": "https://example.com/schemas/user.json",
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"id": {"$ref": "order.json#/$defs/id"},
+				"address": {"$ref": "address.json"},
+				"orders": {"type": "array", "items": {"$ref": "order.json"}}
+			}
+		}`),
+		"https://example.com/schemas/address.json": []byte(`{"type": "object", "properties": {"city": {"type": "string"}}}`),
+		"https://example.com/schemas/order.json": []byte(`{
+			"type": "object",
+			"properties": {
+				"id": {"$ref": "#/$defs/id"},
+				"buyer": {"$ref": "user.json"}
+			},
+			"$defs": {"id": {"type": "integer"}}
+		}`),
+		"https://example.com/other/address.json": []byte(`{"type": "string"}`),
+	}
+
+	cases := []struct {
+		name   string
+		schema string
+		base   string
+		expect string
+	}{
+		{
+			name:   "bundle",
+			schema: `{"type": "object", "properties": {"user": {"$ref": "schemas/user.json"}, "id": {"$ref": "#/$defs/id"}}, "$defs": {"id": {"type": "string"}}}`,
+			base:   "https://example.com/root.json",
+			expect: `{
+				"type": "object",
+				"properties": {
+					"user": {"$ref": "#/$defs/user"},
+					"id": {"$ref": "#/$defs/id"}
+				},
+				"$defs": {
+					"id": {"type": "string"},
+					"user": {
+						"type": "object",
+						"properties": {
+							"id": {"$ref": "#/$defs/order/$defs/id"},
+							"address": {"$ref": "#/$defs/address"},
+							"orders": {"type": "array", "items": {"$ref": "#/$defs/order"}}
+						}
+					},
+					"order": {
+						"type": "object",
+						"properties": {
+							"id": {"$ref": "#/$defs/order/$defs/id"},
+							"buyer": {"$ref": "#/$defs/user"}
+						},
+						"$defs": {"id": {"type": "integer"}}
+					},
+					"address": {"type": "object", "properties": {"city": {"type": "string"}}}
+				}
+			}`,
+		},
+		{
+			name:   "names",
+			schema: `{"$id": "https://example.com/root.json", "properties": {"a": {"$ref": "schemas/address.json"}, "b": {"$ref": "other/address.json"}}, "$defs": {"address": {}}}`,
+			expect: `{
+				"$id": "https://example.com/root.json",
+				"properties": {
+					"a": {"$ref": "#/$defs/address_2"},
+					"b": {"$ref": "#/$defs/address_3"}
+				},
+				"$defs": {
+					"address": {},
+					"address_2": {"type": "object", "properties": {"city": {"type": "string"}}},
+					"address_3": {"type": "string"}
+				}
+			}`,
+		},
+		{
+			name:   "local",
+			schema: `{"properties": {"a": {"$ref": "#/$defs/a"}}, "$defs": {"a": {"type": "string"}}}`,
+			expect: `{"properties": {"a": {"$ref": "#/$defs/a"}}, "$defs": {"a": {"type": "string"}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResolver(loader).Bundle(context.Background(), []byte(tt.schema), tt.base)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, string(got), tt.expect); diff != "" {
+				t.Errorf("bundled schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestResolver_BundleFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"defs/address.json": {Data: []byte(`{"type": "object", "required": ["city"], "properties": {"city": {"$ref": "city.json"}}}`)},
+		"defs/city.json":    {Data: []byte(`{"type": "string", "minLength": 1}`)},
+	}
+	schema := []byte(`{"type": "object", "properties": {"address": {"$ref": "defs/address.json"}}}`)
+
+	got, err := NewResolver(FSLoader(fsys)).Bundle(context.Background(), schema, "")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `{
+		"type": "object",
+		"properties": {"address": {"$ref": "#/$defs/address"}},
+		"$defs": {
+			"address": {"type": "object", "required": ["city"], "properties": {"city": {"$ref": "#/$defs/city"}}},
+			"city": {"type": "string", "minLength": 1}
+		}
+	}`
+	if diff := jsonDiff(t, string(got), expect); diff != "" {
+		t.Errorf("bundled schema does not match to expected one: %v", diff)
+	}
+
+	// the bundle is self-contained
+	s, err := CompileBytes(got)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"address": {"city": "Tokyo"}}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"address": {"city": ""}}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestResolver_Dereference(t *testing.T) {
+	loader := MapLoader{
+		"address.json": []byte(`{"type": "object", "properties": {"city": {"$ref": "#/$defs/city"}}, "$defs": {"city": {"type": "string"}}}`),
+	}
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"home": {"$ref": "address.json", "description": "home address"},
+			"work": {"$ref": "address.json"},
+			"id": {"$ref": "#/$defs/id"}
+		},
+		"$defs": {"id": {"type": "integer"}}
+	}`)
+
+	got, err := NewResolver(loader).Dereference(context.Background(), schema, "")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `{
+		"type": "object",
+		"properties": {
+			"home": {"type": "object", "description": "home address", "properties": {"city": {"type": "string"}}, "$defs": {"city": {"type": "string"}}},
+			"work": {"type": "object", "properties": {"city": {"type": "string"}}, "$defs": {"city": {"type": "string"}}},
+			"id": {"type": "integer"}
+		}
+	}`
+	if diff := jsonDiff(t, string(got), expect); diff != "" {
+		t.Errorf("dereferenced schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestResolver_errors(t *testing.T) {
+	loader := MapLoader{
+		"node.json":   []byte(`{"type": "object", "properties": {"next": {"$ref": "node.json"}}}`),
+		"anchor.json": []byte(`{"$defs": {"a": {"$anchor": "a"}}}`),
+		"broken.json": []byte(`{`),
+	}
+
+	cases := []struct {
+		name   string
+		loader Loader
+		schema string
+		deref  bool
+		kind   error
+	}{
+		{"cycle", loader, `{"$ref": "node.json"}`, true, ErrCycle},
+		{"self", loader, `{"properties": {"self": {"$ref": "#"}}}`, true, ErrCycle},
+		{"not found", loader, `{"$ref": "missing.json"}`, false, ErrRefInvalid},
+		{"anchor", loader, `{"$ref": "anchor.json#a"}`, false, ErrRefInvalid},
+		{"no loader", nil, `{"$ref": "node.json"}`, false, ErrRefInvalid},
+		{"invalid path", FSLoader(fstest.MapFS{}), `{"$ref": "../node.json"}`, false, ErrRefInvalid},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResolver(tt.loader)
+			var err error
+			if tt.deref {
+				_, err = r.Dereference(context.Background(), []byte(tt.schema), "")
+			} else {
+				_, err = r.Bundle(context.Background(), []byte(tt.schema), "")
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("want %v but got %v", tt.kind, err)
+			}
+		})
+	}
+
+	// recursive documents can be bundled
+	if _, err := NewResolver(loader).Bundle(context.Background(), []byte(`{"$ref": "node.json"}`), ""); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if _, err := NewResolver(loader).Bundle(context.Background(), []byte(`{"$ref": "broken.json"}`), ""); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
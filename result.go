@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// GenerationResult describes a schema which is written by GenerateResult.
+type GenerationResult struct {
+	// Size is the number of bytes of the written schema.
+	Size int64
+	// Fingerprint is the digest of the canonicalized schema such as "sha256:<hex>",
+	// which is same as Digest of the written schema.
+	Fingerprint string
+	// Draft is the draft which the schema targets.
+	Draft SchemaDraft
+	// RootTitle is the title of the root object. It is empty if the root does not have a title.
+	RootTitle string
+}
+
+// GenerateResult writes a schema of v to w like Generate and returns its size, fingerprint, draft and title,
+// which are captured while the schema is written, so publishing pipelines do not have to read
+// the written schema again to record them.
+func GenerateResult(w io.Writer, v interface{}, opts ...Option) (*GenerationResult, error) {
+	rw := &resultWriter{w: w}
+	if err := Generate(rw, v, opts...); err != nil {
+		return nil, err
+	}
+	return rw.result(DraftOf(opts...))
+}
+
+// resultWriter writes a schema to w and keeps it to compute its fingerprint.
+type resultWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+	n   int64
+}
+
+func (rw *resultWriter) Write(p []byte) (int, error) {
+	n, err := rw.w.Write(p)
+	rw.n += int64(n)
+	rw.buf.Write(p[:n])
+	return n, err
+}
+
+// result returns the result of the written schema which targets the draft.
+func (rw *resultWriter) result(d SchemaDraft) (*GenerationResult, error) {
+	dec := json.NewDecoder(&rw.buf)
+	dec.UseNumber()
+	var schema interface{}
+	if err := dec.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot decode the written schema: %w", err)
+	}
+	canonical, err := canonicalize(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &GenerationResult{
+		Size:        rw.n,
+		Fingerprint: digest(canonical),
+		Draft:       d,
+	}
+	if root, ok := schema.(map[string]interface{}); ok {
+		r.RootTitle, _ = root["title"].(string)
+	}
+	return r, nil
+}
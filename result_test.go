@@ -0,0 +1,71 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type resultUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age,omitempty"`
+}
+
+func TestGenerateResult(t *testing.T) {
+	cases := []struct {
+		name  string
+		v     interface{}
+		opts  []Option
+		draft SchemaDraft
+		title string
+	}{
+		{"struct", resultUser{}, nil, DraftUnspecified, "resultUser"},
+		{"draft", resultUser{}, []Option{Draft(Draft07), SortedKeys()}, Draft07, "resultUser"},
+		{"no title", []string{}, nil, DraftUnspecified, ""},
+		{"generator", rawGenerator(`{"title": "Raw", "type": "string"}`), nil, DraftUnspecified, "Raw"},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			got, err := GenerateResult(&buf, tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			want, err := GenerateBytes(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("want %s but got %s", want, buf.Bytes())
+			}
+			fp, err := Digest(want)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			expect := GenerationResult{Size: int64(len(want)), Fingerprint: fp, Draft: tt.draft, RootTitle: tt.title}
+			if *got != expect {
+				t.Errorf("want %+v but got %+v", expect, *got)
+			}
+		})
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestGenerateResult_errors(t *testing.T) {
+	if _, err := GenerateResult(failWriter{}, resultUser{}); err == nil || err.Error() != "disk full" {
+		t.Errorf("want the error of the writer but got %v", err)
+	}
+	if _, err := GenerateResult(&bytes.Buffer{}, make(chan int)); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want %v but got %v", ErrUnsupportedType, err)
+	}
+}
@@ -0,0 +1,38 @@
+package jsonschema
+
+import "reflect"
+
+// Secret is implemented by types which hold secret values such as passwords or tokens.
+// Schemas of secret types are marked as writeOnly and
+// default and example values are always removed from them,
+// so that real credentials are not published by accident.
+// A field also can be marked as a secret by `jsonschema:"secret"` tag.
+type Secret interface {
+	JSONSchemaSecret()
+}
+
+// Password is a string type which holds a password.
+// It implements Secret.
+type Password string
+
+// JSONSchemaSecret implements Secret.
+func (Password) JSONSchemaSecret() {}
+
+// secretKeys are keys which are removed from schemas of secrets.
+var secretKeys = []string{"default", "examples", "example", "const"}
+
+func isSecret(v reflect.Value) bool {
+	if !v.IsValid() || !v.CanInterface() {
+		return false
+	}
+	_, ok := v.Interface().(Secret)
+	return ok
+}
+
+// scrubSecret marks o as writeOnly and removes values which may contain secrets.
+func scrubSecret(o Object) {
+	o.Set("writeOnly", true)
+	for _, k := range secretKeys {
+		o.Delete(k)
+	}
+}
@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
+// Email is a string of an email address.
+// Fields of the type are generated as strings of the format "email".
+type Email string
+
+// URI is a string of an absolute URI of RFC 3986.
+// Fields of the type are generated as strings of the format "uri".
+type URI string
+
+// Currency is a currency code of ISO 4217 such as "USD" and "JPY".
+// Fields of the type are generated as strings of three uppercase letters.
+type Currency string
+
+// TimeZone is a name of a time zone of the IANA time zone database such as "Asia/Tokyo" and "UTC".
+// Fields of the type are generated as strings of the format "time-zone" and the pattern of names.
+// Validation of the format checks that the zone is in the database which time.LoadLocation uses.
+type TimeZone string
+
+// Location returns the location of the time zone.
+func (z TimeZone) Location() (*time.Location, error) {
+	return time.LoadLocation(string(z))
+}
+
+// timeZonePattern matches names of the IANA time zone database such as "America/Port-au-Prince" and "Etc/GMT+9".
+const timeZonePattern = `^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9][A-Za-z0-9_+-]*)*$`
+
+var timeZoneRegexp = regexp.MustCompile(timeZonePattern)
+
+// timeZoneChecker checks the format "time-zone".
+// "Local" is not a name of the database but the zone of the system.
+type timeZoneChecker struct{}
+
+func (timeZoneChecker) IsFormat(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	if s == "Local" || !timeZoneRegexp.MatchString(s) {
+		return false
+	}
+	_, err := time.LoadLocation(s)
+	return err == nil
+}
+
+// AnyValue is any JSON value such as an object, an array or null,
+// which is an explicit alternative of interface{} for fields such as metadata.
+// Fields of the type are generated as objects whose type is all types of JSON
+// instead of empty schemas of interface{}.
+// Its zero value is null.
+type AnyValue struct {
+	// Value is a decoded JSON value such as map[string]interface{}.
+	Value interface{}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v AnyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *AnyValue) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &v.Value)
+}
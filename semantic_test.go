@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerate_semanticTypes(t *testing.T) {
+	type Price struct {
+		Amount   float64  `json:"amount"`
+		Currency Currency `json:"currency"`
+	}
+	type Contact struct {
+		Email    Email    `json:"email" jsonschema:"maxLength=254"`
+		Homepage URI      `json:"homepage,omitempty"`
+		Aliases  []Email  `json:"aliases"`
+		Price    Price    `json:"price"`
+		Default  Currency `json:"default" jsonschema:"pattern=^(USD|JPY)$"`
+	}
+
+	got, err := GenerateString(Contact{Aliases: []Email{""}}, MapKeywords(KeywordRenames{"propertyOrder": ""}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"title": "Contact",
+		"type": "object",
+		"required": ["email", "aliases", "price", "default"],
+		"properties": {
+			"email": {"type": "string", "format": "email", "maxLength": 254},
+			"homepage": {"type": "string", "format": "uri"},
+			"aliases": {"type": "array", "items": {"type": "string", "format": "email"}},
+			"price": {
+				"title": "Price",
+				"type": "object",
+				"required": ["amount", "currency"],
+				"properties": {
+					"amount": {"type": "number"},
+					"currency": {"type": "string", "pattern": "^[A-Z]{3}$"}
+				}
+			},
+			"default": {"type": "string", "pattern": "^(USD|JPY)$"}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestGenerate_anyValue(t *testing.T) {
+	type Event struct {
+		Name     string              `json:"name"`
+		Metadata AnyValue            `json:"metadata" jsonschema:"description=anything"`
+		Extra    map[string]AnyValue `json:"extra,omitempty"`
+	}
+
+	v := Event{Extra: map[string]AnyValue{}}
+	got, err := GenerateString(v, MapKeywords(KeywordRenames{"propertyOrder": ""}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"title": "Event",
+		"type": "object",
+		"required": ["name", "metadata"],
+		"properties": {
+			"name": {"type": "string"},
+			"metadata": {
+				"type": ["object", "array", "string", "number", "boolean", "null"],
+				"description": "anything"
+			},
+			"extra": {
+				"type": "object",
+				"additionalProperties": {"type": ["object", "array", "string", "number", "boolean", "null"]}
+			}
+		}
+	}`
+	if diff := jsonDiff(t, got, expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	s, err := CompileBytes([]byte(got))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for _, doc := range []string{
+		`{"name": "a", "metadata": null}`,
+		`{"name": "a", "metadata": {"nested": [1, "x", true]}, "extra": {"k": [null]}}`,
+	} {
+		var e Event
+		if err := json.Unmarshal([]byte(doc), &e); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		b, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if err := s.Validate(b); err != nil {
+			t.Errorf("%s: unexpected error: %v", b, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/pkg/wildcard"
+)
+
+// shapeTypes are Go types which can be declared in shape documents by their names.
+var shapeTypes = map[string]reflect.Type{
+	"bool":            reflect.TypeOf(false),
+	"string":          reflect.TypeOf(""),
+	"int":             reflect.TypeOf(int(0)),
+	"int8":            reflect.TypeOf(int8(0)),
+	"int16":           reflect.TypeOf(int16(0)),
+	"int32":           reflect.TypeOf(int32(0)),
+	"int64":           reflect.TypeOf(int64(0)),
+	"uint":            reflect.TypeOf(uint(0)),
+	"uint8":           reflect.TypeOf(uint8(0)),
+	"uint16":          reflect.TypeOf(uint16(0)),
+	"uint32":          reflect.TypeOf(uint32(0)),
+	"uint64":          reflect.TypeOf(uint64(0)),
+	"byte":            reflect.TypeOf(byte(0)),
+	"rune":            reflect.TypeOf(rune(0)),
+	"float32":         reflect.TypeOf(float32(0)),
+	"float64":         reflect.TypeOf(float64(0)),
+	"any":             reflect.TypeOf((*interface{})(nil)).Elem(),
+	"interface{}":     reflect.TypeOf((*interface{})(nil)).Elem(),
+	"time.Time":       timeType,
+	"time.Duration":   reflect.TypeOf(time.Duration(0)),
+	"json.RawMessage": reflect.TypeOf(json.RawMessage{}),
+}
+
+// shape declares keys of maps whose reference matches the pattern and their Go types.
+type shape struct {
+	pattern string
+	keys    map[string]reflect.Type
+}
+
+// names returns the declared keys in alphabetical order.
+func (s shape) names() []string {
+	names := make([]string, 0, len(s.keys))
+	for name := range s.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shapes reads a shape document in JSON or YAML from r and merges declared shapes
+// into objects of maps such as map[string]interface{} which are dynamic in Go
+// but have known contracts.
+// A shape document is an object whose keys are patterns of references as Overlay
+// and values are objects which declare keys of the maps and their Go types:
+//
+//	"#/properties/attributes":
+//	  name: string
+//	  age: int
+//	  tags: "[]string"
+//	  nickname: "*string"
+//
+// Declared keys become properties which are generated from their types,
+// and they are required unless their types are pointers as fields of structs.
+// Other keys are still allowed by additionalProperties of the maps.
+// Types are built-in types of Go, any, time.Time, time.Duration and json.RawMessage,
+// and pointers, slices and maps of string keys of them such as "map[string][]int".
+func Shapes(r io.Reader) Option {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot read shapes: %w", err))
+	}
+	// YAML is a superset of JSON
+	doc, err := yamlToJSON(b)
+	if err != nil {
+		return errOption(err)
+	}
+	v, err := decodeJSON(doc)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot decode shapes: %w", err))
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return errOption(fmt.Errorf("jsonschema: shapes must be an object"))
+	}
+
+	shapes := make([]shape, 0, len(m))
+	for _, pattern := range sortedKeys(m) {
+		if !strings.HasPrefix(pattern, "#") && !strings.HasPrefix(pattern, "*") {
+			return errOption(newError(ErrRefInvalid, pattern, fmt.Errorf("pattern in shapes must begin with # or *")))
+		}
+		decl, ok := m[pattern].(map[string]interface{})
+		if !ok {
+			return errOption(fmt.Errorf("jsonschema: shape for %q must be an object", pattern))
+		}
+
+		s := shape{pattern: pattern, keys: make(map[string]reflect.Type, len(decl))}
+		for key, typ := range decl {
+			name, ok := typ.(string)
+			if !ok {
+				return errOption(fmt.Errorf("jsonschema: type of %q in shape for %q must be a string", key, pattern))
+			}
+			t, err := parseShapeType(name)
+			if err != nil {
+				return errOption(fmt.Errorf("jsonschema: type of %q in shape for %q: %w", key, pattern, err))
+			}
+			s.keys[key] = t
+		}
+		shapes = append(shapes, s)
+	}
+
+	return configOption(func(c *config) {
+		c.shapes = append(c.shapes, shapes...)
+	})
+}
+
+// ShapesFS is same as Shapes but it reads the shape document from the file of the name in fsys.
+// It can read shape documents which are embedded by go:embed.
+func ShapesFS(fsys fs.FS, name string) Option {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return errOption(fmt.Errorf("jsonschema: cannot open shapes: %w", err))
+	}
+	defer f.Close()
+	return Shapes(f)
+}
+
+// parseShapeType parses a name of a Go type in shape documents.
+func parseShapeType(name string) (reflect.Type, error) {
+	name = strings.TrimSpace(name)
+	var (
+		elem string
+		wrap func(reflect.Type) reflect.Type
+	)
+	switch {
+	case strings.HasPrefix(name, "*"):
+		elem, wrap = name[len("*"):], reflect.PtrTo
+	case strings.HasPrefix(name, "[]"):
+		elem, wrap = name[len("[]"):], reflect.SliceOf
+	case strings.HasPrefix(name, "map[string]"):
+		elem = name[len("map[string]"):]
+		wrap = func(t reflect.Type) reflect.Type {
+			return reflect.MapOf(reflect.TypeOf(""), t)
+		}
+	default:
+		t, ok := shapeTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+		return t, nil
+	}
+
+	t, err := parseShapeType(elem)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(t), nil
+}
+
+// shapeGen generates properties of the object of a map by the shapes which match its reference.
+func (g *gen) shapeGen(o Object, options []Option) error {
+	for _, s := range g.cfg.shapes {
+		if !wildcard.MatchSimple(s.pattern, o.Ref()) {
+			continue
+		}
+
+		props, _ := o.Get("properties")
+		properties, ok := props.(map[string]interface{})
+		if !ok {
+			properties = map[string]interface{}{}
+		}
+		var required []string
+		if r, ok := o.Get("required"); ok {
+			required, _ = r.([]string)
+		}
+		isRequired := make(map[string]bool, len(required))
+		for _, name := range required {
+			isRequired[name] = true
+		}
+
+		for _, name := range s.names() {
+			t := s.keys[name]
+			p := &obj{
+				m:   map[string]interface{}{},
+				ref: g.cfg.refs().Join(o.Ref(), "properties", name),
+			}
+			if err := g.do(p, empty(t), options...); err != nil {
+				return err
+			}
+			properties[name] = p.m
+			if t.Kind() != reflect.Ptr && !isRequired[name] {
+				isRequired[name] = true
+				required = append(required, name)
+			}
+		}
+
+		o.Set("properties", properties)
+		if len(required) != 0 {
+			o.Set("required", required)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type shapeModel struct {
+	ID         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Labels     map[string]string      `json:"labels"`
+}
+
+func TestShapes(t *testing.T) {
+	noise := MapKeywords(KeywordRenames{"propertyOrder": "", "title": ""})
+	v := shapeModel{Attributes: map[string]interface{}{}, Labels: map[string]string{}}
+
+	cases := []struct {
+		name   string
+		shapes string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "YAML",
+			shapes: `
+"#/properties/attributes":
+  name: string
+  age: int
+  tags: "[]string"
+  nickname: "*string"
+  created: time.Time
+  scores: "map[string]float64"
+  extra: any
+`,
+			expect: `{
+				"type": "object",
+				"required": ["id", "attributes", "labels"],
+				"properties": {
+					"id": {"type": "string"},
+					"attributes": {
+						"type": "object",
+						"additionalProperties": {},
+						"required": ["age", "created", "extra", "name", "scores", "tags"],
+						"properties": {
+							"age": {"type": "number"},
+							"created": {"type": "string", "format": "date-time"},
+							"extra": {},
+							"name": {"type": "string"},
+							"nickname": {"type": "string"},
+							"scores": {"type": "object", "additionalProperties": {"type": "number"}},
+							"tags": {"type": "array", "items": {"type": "string"}}
+						}
+					},
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+				}
+			}`,
+		},
+		{
+			name:   "JSON with patterns and options",
+			shapes: `{"#/properties/*": {"env": "string"}}`,
+			opts: []Option{ByReference("#/properties/labels/properties/env", func(o Object) (Object, error) {
+				o.Set("enum", []string{"dev", "prod"})
+				return o, nil
+			})},
+			expect: `{
+				"type": "object",
+				"required": ["id", "attributes", "labels"],
+				"properties": {
+					"id": {"type": "string"},
+					"attributes": {
+						"type": "object",
+						"additionalProperties": {},
+						"required": ["env"],
+						"properties": {"env": {"type": "string"}}
+					},
+					"labels": {
+						"type": "object",
+						"additionalProperties": {"type": "string"},
+						"required": ["env"],
+						"properties": {"env": {"type": "string", "enum": ["dev", "prod"]}}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{noise, Shapes(strings.NewReader(tt.shapes))}, tt.opts...)
+			got, err := GenerateString(v, opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestShapesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"shapes.json": {Data: []byte(`{"#/": {"name": "string"}}`)},
+	}
+	var buf bytes.Buffer
+	if err := Generate(&buf, map[string]interface{}{}, ShapesFS(fsys, "shapes.json")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	expect := `{
+		"type": "object",
+		"additionalProperties": {},
+		"required": ["name"],
+		"properties": {"name": {"type": "string"}}
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+	}
+
+	if err := Generate(&buf, map[string]interface{}{}, ShapesFS(fsys, "missing.json")); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestShapes_error(t *testing.T) {
+	cases := []struct {
+		name   string
+		shapes string
+	}{
+		{name: "syntax", shapes: `{`},
+		{name: "not object", shapes: `[]`},
+		{name: "pattern", shapes: `{"properties/a": {}}`},
+		{name: "shape", shapes: `{"#/": "string"}`},
+		{name: "type", shapes: `{"#/": {"a": 1}}`},
+		{name: "unknown type", shapes: `{"#/": {"a": "[]User"}}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateString(map[string]interface{}{}, Shapes(strings.NewReader(tt.shapes))); err == nil {
+				t.Error("expected error does not occur")
+			}
+		})
+	}
+}
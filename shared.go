@@ -0,0 +1,119 @@
+package jsonschema
+
+import "reflect"
+
+// SharedTypePolicy is a policy of named struct types which can appear more than once in a schema.
+type SharedTypePolicy int
+
+const (
+	// SharedTypesInline generates a schema of a struct type at every occurrence. It is the default.
+	// Recursion is limited by values: nil pointers, slices and maps become empty schemas
+	// and cyclic values cause an error which matches ErrCycle.
+	SharedTypesInline SharedTypePolicy = iota
+	// SharedTypesRecursive puts struct types which refer to themselves such as
+	// type Node struct { Children []*Node } into $defs and refers to them by $ref.
+	SharedTypesRecursive
+	// SharedTypesRef puts all named struct types except the root into $defs and refers to them by $ref.
+	SharedTypesRef
+)
+
+// SharedTypes sets the policy of named struct types.
+// With SharedTypesRecursive and SharedTypesRef, schemas of nil pointers, slices and maps
+// are generated from their types, so recursive types are described by references.
+// A name of a definition is the name of the type such as "#/$defs/Node"
+// and the root type is referred by "#".
+// Each instantiation of a generic type has its own definition which is named by the type
+// and its type arguments such as "#/$defs/Page_User" for Page[User]
+// and the type arguments are qualified by their package names such as "#/$defs/Page_app_User"
+// if another instantiation has the same name.
+func SharedTypes(policy SharedTypePolicy) Option {
+	return configOption(func(c *config) {
+		c.sharedTypes = policy
+	})
+}
+
+// isShared reports whether the type is generated into $defs.
+func (g *gen) isShared(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.Name() == "" || t == timeType || g.cfg.isMapped(t) {
+		return false
+	}
+
+	switch g.cfg.sharedTypes {
+	case SharedTypesRef:
+		return true
+	case SharedTypesRecursive:
+		if r, ok := g.recursive[t]; ok {
+			return r
+		}
+		if g.recursive == nil {
+			g.recursive = map[reflect.Type]bool{}
+		}
+		r := refersTo(t, t, map[reflect.Type]bool{})
+		g.recursive[t] = r
+		return r
+	}
+	return false
+}
+
+// refersTo reports whether the type from refers to the type to via fields and elements.
+func refersTo(from, to reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	switch from.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		elem := from.Elem()
+		return elem == to || refersTo(elem, to, visited)
+	case reflect.Struct:
+		for i := 0; i < from.NumField(); i++ {
+			ft := from.Field(i).Type
+			if ft == to || refersTo(ft, to, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sharedRef sets a reference to the definition of the struct v into o if the type is shared.
+// It reports whether the reference is set.
+func (g *gen) sharedRef(o Object, v reflect.Value, options []Option) (bool, error) {
+	t := v.Type()
+	// the def itself is generated as a struct
+	if !g.isShared(t) || o == g.hoisted {
+		return false, nil
+	}
+
+	if isRoot(o) {
+		g.rootType = t
+		return false, nil
+	}
+
+	ref := "#"
+	if t != g.rootType {
+		name := typeName(t)
+		// instantiations by types of the same name in different packages
+		if dt, ok := g.defTypes[name]; ok && dt != t && isGenericType(t) {
+			name = qualifiedTypeName(t)
+		}
+		var err error
+		if ref, err = g.hoist(name, v, options); err != nil {
+			return true, err
+		}
+	}
+	if g.plan != nil {
+		g.plan.note(o.Ref(), "%s is referred by %s", t, ref)
+	}
+	o.Set("$ref", ref)
+
+	return true, g.applyOptions(o, options)
+}
+
+// canExpand reports whether the nil value v can be generated from its type.
+// A type which is being expanded cannot be expanded again
+// because it refers to itself without any shared struct types.
+func (g *gen) canExpand(v reflect.Value) bool {
+	return g.cfg.sharedTypes != SharedTypesInline && v.Kind() != reflect.Interface && !g.expanding[v.Type()]
+}
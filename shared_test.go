@@ -0,0 +1,288 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/tenntenn/jsonschema/internal/directivetest"
+	other "github.com/tenntenn/jsonschema/internal/directivetest/other"
+)
+
+type sharedNode struct {
+	Name     string        `json:"name"`
+	Children []*sharedNode `json:"children"`
+}
+
+type sharedA struct {
+	B *sharedB `json:"b,omitempty"`
+}
+
+type sharedB struct {
+	A *sharedA `json:"a,omitempty"`
+	N int      `json:"n"`
+}
+
+type sharedMeta struct {
+	Key string `json:"key"`
+}
+
+type sharedDoc struct {
+	First  sharedMeta `json:"first"`
+	Second sharedMeta `json:"second"`
+}
+
+func TestSharedTypes(t *testing.T) {
+	cases := []struct {
+		name    string
+		v       interface{}
+		policy  SharedTypePolicy
+		expect  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:   "inline",
+			v:      sharedDoc{},
+			policy: SharedTypesInline,
+			expect: `{
+				"type": "object",
+				"title": "sharedDoc",
+				"required": ["first", "second"],
+				"properties": {
+					"first": {"type": "object", "title": "sharedMeta", "required": ["key"], "properties": {"key": {"type": "string", "propertyOrder": 0}}, "propertyOrder": 0},
+					"second": {"type": "object", "title": "sharedMeta", "required": ["key"], "properties": {"key": {"type": "string", "propertyOrder": 0}}, "propertyOrder": 1}
+				}
+			}`,
+			valid:   []string{`{"first": {"key": "a"}, "second": {"key": "b"}}`},
+			invalid: []string{`{"first": {"key": 1}, "second": {"key": "b"}}`},
+		},
+		{
+			name:   "recursive root",
+			v:      sharedNode{},
+			policy: SharedTypesRecursive,
+			expect: `{
+				"type": "object",
+				"title": "sharedNode",
+				"required": ["name", "children"],
+				"properties": {
+					"name": {"type": "string", "propertyOrder": 0},
+					"children": {"type": "array", "items": {"$ref": "#"}, "propertyOrder": 1}
+				}
+			}`,
+			valid:   []string{`{"name": "a", "children": [{"name": "b", "children": []}]}`},
+			invalid: []string{`{"name": "a", "children": [{"name": 1, "children": []}]}`},
+		},
+		{
+			name:   "recursive items",
+			v:      []sharedNode{},
+			policy: SharedTypesRef,
+			expect: `{
+				"type": "array",
+				"items": {"$ref": "#/$defs/sharedNode"},
+				"$defs": {
+					"sharedNode": {
+						"type": "object",
+						"title": "sharedNode",
+						"required": ["name", "children"],
+						"properties": {
+							"name": {"type": "string", "propertyOrder": 0},
+							"children": {"type": "array", "items": {"$ref": "#/$defs/sharedNode"}, "propertyOrder": 1}
+						}
+					}
+				}
+			}`,
+			valid:   []string{`[{"name": "a", "children": [{"name": "b", "children": []}]}]`},
+			invalid: []string{`[{"name": "a", "children": [{"children": []}]}]`},
+		},
+		{
+			name:   "mutual recursion",
+			v:      sharedA{},
+			policy: SharedTypesRecursive,
+			expect: `{
+				"type": "object",
+				"title": "sharedA",
+				"required": [],
+				"properties": {
+					"b": {"$ref": "#/$defs/sharedB", "propertyOrder": 0}
+				},
+				"$defs": {
+					"sharedB": {
+						"type": "object",
+						"title": "sharedB",
+						"required": ["n"],
+						"properties": {
+							"a": {"$ref": "#", "propertyOrder": 0},
+							"n": {"type": "number", "propertyOrder": 1}
+						}
+					}
+				}
+			}`,
+			valid:   []string{`{"b": {"a": {"b": {"n": 2}}, "n": 1}}`},
+			invalid: []string{`{"b": {"a": {"b": {}}, "n": 1}}`},
+		},
+		{
+			name:   "ref",
+			v:      sharedDoc{},
+			policy: SharedTypesRef,
+			expect: `{
+				"type": "object",
+				"title": "sharedDoc",
+				"required": ["first", "second"],
+				"properties": {
+					"first": {"$ref": "#/$defs/sharedMeta", "propertyOrder": 0},
+					"second": {"$ref": "#/$defs/sharedMeta", "propertyOrder": 1}
+				},
+				"$defs": {
+					"sharedMeta": {"type": "object", "title": "sharedMeta", "required": ["key"], "properties": {"key": {"type": "string", "propertyOrder": 0}}}
+				}
+			}`,
+			valid:   []string{`{"first": {"key": "a"}, "second": {"key": "b"}}`},
+			invalid: []string{`{"first": {"key": "a"}, "second": {}}`},
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateBytes(tt.v, SharedTypes(tt.policy))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, string(got), tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+
+			s, err := CompileBytes(got)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			for _, doc := range tt.valid {
+				if err := s.Validate([]byte(doc)); err != nil {
+					t.Errorf("%s must be valid: %v", doc, err)
+				}
+			}
+			for _, doc := range tt.invalid {
+				if err := s.Validate([]byte(doc)); err == nil {
+					t.Errorf("%s must be invalid", doc)
+				}
+			}
+		})
+	}
+}
+
+func TestSharedTypes_cycle(t *testing.T) {
+	n := &sharedNode{Name: "a"}
+	n.Children = []*sharedNode{n}
+
+	if _, err := GenerateBytes(n); err == nil {
+		t.Error("expected error does not occur")
+	}
+	if _, err := GenerateBytes(n, SharedTypes(SharedTypesRecursive)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+}
+
+func TestHashedDefNames(t *testing.T) {
+	type Collision struct {
+		A struct {
+			B struct {
+				C int `json:"c"`
+			} `json:"b"`
+		} `json:"a"`
+		// hoisted as Collision_A_B same as A.B without hashes
+		A_B struct {
+			D int `json:"d"`
+		} `json:"a_b"`
+	}
+	type Changed struct {
+		A struct {
+			B struct {
+				C string `json:"c"`
+			} `json:"b"`
+		} `json:"a"`
+	}
+	type Configs struct {
+		A directivetest.Config `json:"a"`
+		B other.Config         `json:"b"`
+	}
+
+	defsOf := func(t *testing.T, v interface{}, opts ...Option) map[string]interface{} {
+		t.Helper()
+		s, err := GenerateBytes(v, append([]Option{HashedDefNames()}, opts...)...)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		again, err := GenerateBytes(v, append([]Option{HashedDefNames()}, opts...)...)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if !bytes.Equal(s, again) {
+			t.Errorf("names are not stable: %s and %s", s, again)
+		}
+
+		var doc struct {
+			Defs map[string]interface{} `json:"$defs"`
+		}
+		if err := json.Unmarshal(s, &doc); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		// all references must be resolved
+		for _, ref := range regexp.MustCompile(`"\$ref":"#/\$defs/([^"]+)"`).FindAllSubmatch(s, -1) {
+			if _, ok := doc.Defs[string(ref[1])]; !ok {
+				t.Errorf("%s is not defined", ref[1])
+			}
+		}
+		return doc.Defs
+	}
+
+	names := func(defs map[string]interface{}, pattern string) []string {
+		var matched []string
+		for name := range defs {
+			if regexp.MustCompile(pattern).MatchString(name) {
+				matched = append(matched, name)
+			}
+		}
+		sort.Strings(matched)
+		return matched
+	}
+
+	t.Run("anonymous structs", func(t *testing.T) {
+		defs := defsOf(t, Collision{}, HoistAnonymousStructs())
+		if len(defs) != 3 {
+			t.Errorf("want 3 definitions but got %v", defs)
+		}
+		if got := names(defs, `^Collision_A_[0-9a-f]{8}$`); len(got) != 1 {
+			t.Errorf("Collision_A is not hashed: %v", defs)
+		}
+		bs := names(defs, `^Collision_A_B_[0-9a-f]{8}$`)
+		if len(bs) != 2 {
+			t.Fatalf("want 2 hashed Collision_A_B but got %v", defs)
+		}
+
+		changed := defsOf(t, Changed{}, HoistAnonymousStructs())
+		for _, name := range names(changed, `^Changed_A_B_`) {
+			if suffix := name[len("Changed_"):]; suffix == bs[0][len("Collision_"):] || suffix == bs[1][len("Collision_"):] {
+				t.Errorf("hash of %s is not changed by the structure", name)
+			}
+		}
+	})
+
+	t.Run("named types", func(t *testing.T) {
+		defs := defsOf(t, Configs{}, SharedTypes(SharedTypesRef))
+		if _, ok := defs["Config"]; !ok {
+			t.Errorf("the first Config must keep its name: %v", defs)
+		}
+		if got := names(defs, `^Config_[0-9a-f]{8}$`); len(got) != 1 {
+			t.Errorf("the other Config is not hashed: %v", defs)
+		}
+	})
+
+	if _, err := GenerateBytes(Configs{}, SharedTypes(SharedTypesRef)); !errors.Is(err, ErrNameCollision) {
+		t.Errorf("want ErrNameCollision without HashedDefNames but got %v", err)
+	}
+}
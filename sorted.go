@@ -0,0 +1,195 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// SortedKeys makes Generate write keys of all objects in alphabetical order,
+// so generated schemas are canonical for hashing and reviews.
+// Objects which are built from maps are already sorted by encoding/json,
+// but values which options and Generator give such as structs and json.RawMessage
+// are encoded in their own orders. With SortedKeys, they are also sorted.
+func SortedKeys() Option {
+	return configOption(func(c *config) {
+		c.sortedKeys = true
+	})
+}
+
+// PropertiesOrder is an order of properties in generated schemas.
+type PropertiesOrder int
+
+const (
+	// PropertiesAlphabetical writes properties in alphabetical order of their names.
+	PropertiesAlphabetical PropertiesOrder = iota
+	// PropertiesDeclaration writes properties in order of their propertyOrder,
+	// which is declaration order of fields.
+	// Properties without propertyOrder follow them in alphabetical order.
+	PropertiesDeclaration
+)
+
+// OrderProperties sets the order of properties in generated schemas.
+// The default is PropertiesAlphabetical.
+// Other keys are always written in alphabetical order, so outputs are same between runs.
+func OrderProperties(order PropertiesOrder) Option {
+	return configOption(func(c *config) {
+		c.propertiesOrder = order
+	})
+}
+
+// encode writes the schema into w in the orders of the settings.
+func (c *config) encode(w io.Writer, schema interface{}) error {
+	if !c.sortedKeys && c.propertiesOrder == PropertiesAlphabetical {
+		return json.NewEncoder(w).Encode(schema)
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	return c.reencode(w, b)
+}
+
+// reencode writes the encoded schema into w in the orders of the settings.
+func (c *config) reencode(w io.Writer, schema []byte) error {
+	// decoded objects are maps, so their keys are sorted
+	v, err := decodeJSON(schema)
+	if err != nil {
+		return err
+	}
+	if c.propertiesOrder != PropertiesDeclaration {
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	var buf bytes.Buffer
+	if err := writeOrdered(&buf, v, jsonSchema); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// jsonKind is a kind of decoded JSON values in schemas.
+type jsonKind int
+
+const (
+	// jsonPlain is a value which is not a schema such as an enum.
+	jsonPlain jsonKind = iota
+	// jsonSchema is a schema or a list of schemas.
+	jsonSchema
+	// jsonSchemaMap is a map of schemas such as $defs.
+	jsonSchemaMap
+)
+
+// keywordKind returns the kind of values of the keyword.
+func keywordKind(k string) jsonKind {
+	switch k {
+	case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+		return jsonSchemaMap
+	case "items", "additionalItems", "additionalProperties", "contains", "propertyNames",
+		"not", "if", "then", "else", "unevaluatedItems", "unevaluatedProperties",
+		"allOf", "anyOf", "oneOf", "prefixItems":
+		return jsonSchema
+	}
+	return jsonPlain
+}
+
+// writeOrdered writes the decoded JSON value v of the kind into buf.
+// Properties of schemas are written in order of their propertyOrder
+// and other keys are written in alphabetical order.
+func writeOrdered(buf *bytes.Buffer, v interface{}, kind jsonKind) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, k := range sortedKeys(v) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONValue(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+
+			var err error
+			switch {
+			case kind == jsonSchema && k == "properties":
+				err = writeProperties(buf, v[k])
+			case kind == jsonSchema:
+				err = writeOrdered(buf, v[k], keywordKind(k))
+			case kind == jsonSchemaMap:
+				err = writeOrdered(buf, v[k], jsonSchema)
+			default:
+				err = writeOrdered(buf, v[k], jsonPlain)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeOrdered(buf, e, kind); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	return writeJSONValue(buf, v)
+}
+
+// writeProperties writes properties of a schema in order of their propertyOrder.
+func writeProperties(buf *bytes.Buffer, v interface{}) error {
+	props, ok := v.(map[string]interface{})
+	if !ok {
+		return writeOrdered(buf, v, jsonPlain)
+	}
+
+	names := sortedKeys(props)
+	order := func(name string) (*big.Rat, bool) {
+		p, _ := props[name].(map[string]interface{})
+		return numberRat(p["propertyOrder"])
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		x, okx := order(names[i])
+		y, oky := order(names[j])
+		if okx && oky {
+			return x.Cmp(y) < 0
+		}
+		return okx && !oky
+	})
+
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeJSONValue(buf, name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := writeOrdered(buf, props[name], jsonSchema); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
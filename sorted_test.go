@@ -0,0 +1,114 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestSortedKeys(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	// values which options give are encoded in their own orders
+	example := ByReference("#/properties/name", func(o Object) (Object, error) {
+		o.Set("x-example", struct {
+			Z string `json:"z"`
+			A string `json:"a"`
+		}{"z", "a"})
+		o.Set("x-raw", json.RawMessage(`{"y":1,"b":2.50}`))
+		return o, nil
+	})
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		opts   []Option
+		expect string
+	}{
+		{
+			name:   "unsorted",
+			v:      T{},
+			opts:   []Option{example},
+			expect: `{"properties":{"name":{"propertyOrder":0,"type":"string","x-example":{"z":"z","a":"a"},"x-raw":{"y":1,"b":2.50}}},"required":["name"],"title":"T","type":"object"}` + "\n",
+		},
+		{
+			name:   "sorted",
+			v:      T{},
+			opts:   []Option{example, SortedKeys()},
+			expect: `{"properties":{"name":{"propertyOrder":0,"type":"string","x-example":{"a":"a","z":"z"},"x-raw":{"b":2.50,"y":1}}},"required":["name"],"title":"T","type":"object"}` + "\n",
+		},
+		{
+			name:   "generator",
+			v:      &generator{schema: `{"type":"object","properties":{"z":{},"a":{}}}`},
+			opts:   []Option{SortedKeys()},
+			expect: `{"properties":{"a":{},"z":{}},"type":"object"}` + "\n",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(tt.v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got != tt.expect {
+				t.Errorf("want %s but got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestOrderProperties(t *testing.T) {
+	type Address struct {
+		Zip  string `json:"zip"`
+		City string `json:"city"`
+	}
+	type T struct {
+		Name       string   `json:"name"`
+		Address    Address  `json:"address"`
+		Properties []string `json:"properties"`
+		Age        int      `json:"age"`
+	}
+
+	v := T{Properties: []string{""}}
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name:   "alphabetical",
+			expect: `{"properties":{"address":{"properties":{"city":{"propertyOrder":1,"type":"string"},"zip":{"propertyOrder":0,"type":"string"}},"propertyOrder":1,"required":["zip","city"],"title":"Address","type":"object"},"age":{"propertyOrder":3,"type":"number"},"name":{"propertyOrder":0,"type":"string"},"properties":{"items":{"type":"string"},"propertyOrder":2,"type":"array"}},"required":["name","address","properties","age"],"title":"T","type":"object"}` + "\n",
+		},
+		{
+			name:   "declaration",
+			opts:   []Option{OrderProperties(PropertiesDeclaration)},
+			expect: `{"properties":{"name":{"propertyOrder":0,"type":"string"},"address":{"properties":{"zip":{"propertyOrder":0,"type":"string"},"city":{"propertyOrder":1,"type":"string"}},"propertyOrder":1,"required":["zip","city"],"title":"Address","type":"object"},"properties":{"items":{"type":"string"},"propertyOrder":2,"type":"array"},"age":{"propertyOrder":3,"type":"number"}},"required":["name","address","properties","age"],"title":"T","type":"object"}` + "\n",
+		},
+		{
+			name: "declaration of defs",
+			opts: []Option{OrderProperties(PropertiesDeclaration), SharedTypes(SharedTypesRef), ByReference("#/properties/name", func(o Object) (Object, error) {
+				o.Set("x-example", map[string]interface{}{"z": 1, "a": 2})
+				return o, nil
+			})},
+			expect: `{"$defs":{"Address":{"properties":{"zip":{"propertyOrder":0,"type":"string"},"city":{"propertyOrder":1,"type":"string"}},"required":["zip","city"],"title":"Address","type":"object"}},"properties":{"name":{"propertyOrder":0,"type":"string","x-example":{"a":2,"z":1}},"address":{"$ref":"#/$defs/Address","propertyOrder":1},"properties":{"items":{"type":"string"},"propertyOrder":2,"type":"array"},"age":{"propertyOrder":3,"type":"number"}},"required":["name","address","properties","age"],"title":"T","type":"object"}` + "\n",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(v, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got != tt.expect {
+				t.Errorf("want %s but got %s", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SourceLocationKey is a key of source locations of Go types and fields which are given by WithSourceLocations.
+const SourceLocationKey = "x-go-source"
+
+// WithSourceLocations annotates objects of struct types and their fields with locations of their declarations
+// such as {"x-go-source": "example.com/app/models/user.go:12"},
+// so reviewers of large generated schemas can jump from properties to the Go code of them.
+// Locations are file names in import paths of packages and lines.
+//
+// Reflection cannot see source code, so the option analyzes source files of packages of struct types
+// which are found by go/build with the go command, including test files.
+// Types of packages whose source files are not found are not annotated.
+// Each package is analyzed once for generations with the same option,
+// so the option should be reused for debugging builds which generate many schemas.
+// Cache does not cache generated schemas without CacheKey because locations are not given by Go types.
+func WithSourceLocations() Option {
+	sources := &sourceIndex{pkgs: map[string]map[string]string{}}
+	return configOption(func(c *config) {
+		c.sources = sources
+	})
+}
+
+// sourceIndex is locations of declarations by keys of directives, which are analyzed by packages lazily.
+type sourceIndex struct {
+	mu   sync.Mutex
+	pkgs map[string]map[string]string
+}
+
+// sourceOf returns the location of the field of the struct type t
+// or the location of t if the field is empty.
+func (c *config) sourceOf(t reflect.Type, field string) (string, bool) {
+	if c.sources == nil || t.Name() == "" || t.PkgPath() == "" {
+		return "", false
+	}
+	locs := c.sources.load(t.PkgPath())
+	for _, key := range sourceKeys(t, field) {
+		if loc, ok := locs[key]; ok {
+			return loc, true
+		}
+	}
+	return "", false
+}
+
+// load returns locations of declarations of the package of the import path.
+// A package of external tests such as "example.com/app_test" is analyzed with the package in the same directory.
+func (idx *sourceIndex) load(pkgPath string) map[string]string {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if locs, ok := idx.pkgs[pkgPath]; ok {
+		return locs
+	}
+
+	locs := map[string]string{}
+	dirPath := strings.TrimSuffix(pkgPath, "_test")
+	idx.pkgs[dirPath] = locs
+	idx.pkgs[dirPath+"_test"] = locs
+
+	bp, err := build.Import(dirPath, ".", 0)
+	if err != nil {
+		return locs
+	}
+	fset := token.NewFileSet()
+	for _, names := range [][]string{bp.GoFiles, bp.CgoFiles, bp.TestGoFiles, bp.XTestGoFiles} {
+		for _, name := range names {
+			f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.SkipObjectResolution)
+			if err != nil {
+				continue
+			}
+			addSourceLocations(locs, fset, testPackagePath(dirPath, f), path.Join(dirPath, name), f)
+		}
+	}
+	return locs
+}
+
+// addSourceLocations adds locations of struct types and their fields of the file of the package
+// whose name is given by file.
+func addSourceLocations(locs map[string]string, fset *token.FileSet, pkg, file string, f *ast.File) {
+	loc := func(pos token.Pos) string {
+		return file + ":" + strconv.Itoa(fset.Position(pos).Line)
+	}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			locs[directiveKey(pkg, ts.Name.Name, "")] = loc(ts.Pos())
+			for _, field := range st.Fields.List {
+				for _, name := range fieldNames(field) {
+					locs[directiveKey(pkg, ts.Name.Name, name)] = loc(field.Pos())
+				}
+			}
+		}
+	}
+}
+
+// sourceOption creates an Option which sets the location of the declaration.
+func sourceOption(loc string) Option {
+	return func(o Object) (Object, error) {
+		o.Set(SourceLocationKey, loc)
+		return o, nil
+	}
+}
@@ -0,0 +1,94 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/tenntenn/jsonschema/internal/directivetest"
+)
+
+type sourceUser struct {
+	Name    string        `json:"name"`
+	Address sourceAddress `json:"address"`
+}
+
+type sourceAddress struct {
+	City string `json:"city"`
+}
+
+func TestWithSourceLocations(t *testing.T) {
+	const (
+		testFile = "github.com/tenntenn/jsonschema/source_test.go"
+		pkgFile  = "github.com/tenntenn/jsonschema/internal/directivetest/doc.go"
+	)
+
+	cases := []struct {
+		name   string
+		v      interface{}
+		expect string
+	}{
+		{"test package", sourceUser{}, `{
+			"title": "sourceUser",
+			"type": "object",
+			"x-go-source": "` + testFile + `:10",
+			"required": ["name", "address"],
+			"properties": {
+				"name": {"type": "string", "x-go-source": "` + testFile + `:11"},
+				"address": {
+					"title": "sourceAddress",
+					"type": "object",
+					"x-go-source": "` + testFile + `:12",
+					"required": ["city"],
+					"properties": {
+						"city": {"type": "string", "x-go-source": "` + testFile + `:16"}
+					}
+				}
+			}
+		}`},
+		{"package", directivetest.Config{}, `{
+			"title": "Config",
+			"type": "object",
+			"x-go-source": "` + pkgFile + `:6",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string", "x-go-source": "` + pkgFile + `:8"}
+			}
+		}`},
+		{"anonymous", struct {
+			Name string `json:"name"`
+		}{}, `{
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string"}
+			}
+		}`},
+	}
+
+	opt := WithSourceLocations()
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateBytes(tt.v, opt, MapKeywords(KeywordRenames{"propertyOrder": ""}))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, string(got), tt.expect); diff != "" {
+				t.Errorf("generated schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestWithSourceLocations_cache(t *testing.T) {
+	c := NewStoreCache(nil)
+	opt := WithSourceLocations()
+	for i := 0; i < 2; i++ {
+		if _, err := c.GenerateBytes(sourceUser{}, opt); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+	if hits, misses := c.Stats(); hits != 0 || misses != 2 {
+		t.Errorf("want 0 hits and 2 misses but got %d and %d", hits, misses)
+	}
+}
@@ -0,0 +1,256 @@
+package jsonschema
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Items returns a schema of items of the array schema,
+// which can be given to ValidateStream.
+// References in items are resolved in the same way as the schema.
+// If the schema does not restrict items, the returned schema accepts any values.
+// Errors of compilation such as items of tuples are reported
+// when the returned schema validates documents.
+func (s *Schema) Items() *Schema {
+	s.itemsOnce.Do(func() {
+		s.items = s.compileItems()
+	})
+	return s.items
+}
+
+func (s *Schema) compileItems() *Schema {
+	if s.err != nil {
+		return s
+	}
+
+	root, ok := s.doc.(map[string]interface{})
+	if !ok {
+		return s.subschema(map[string]interface{}{}, "")
+	}
+
+	// follow local references of the root such as {"$ref": "#/$defs/List"}
+	var ptr string
+	cur := root
+	for i := 0; i < 32; i++ {
+		ref, ok := cur["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			break
+		}
+		next, ok := lookupPointer(s.doc, strings.TrimPrefix(ref, "#")).(map[string]interface{})
+		if !ok {
+			break
+		}
+		ptr, cur = strings.TrimPrefix(ref, "#"), next
+	}
+
+	switch cur["items"].(type) {
+	case nil:
+		return s.subschema(map[string]interface{}{}, "")
+	case []interface{}:
+		return &Schema{err: fmt.Errorf("jsonschema: items of tuples are not supported")}
+	}
+
+	// the root is kept to resolve references in items
+	doc := make(map[string]interface{}, len(root)+1)
+	for k, v := range root {
+		doc[k] = v
+	}
+	doc["$ref"] = "#" + ptr + "/items"
+
+	// the same URI as the root cannot be used twice in a schema loader
+	idKey, base := "$id", s.base
+	if id, ok := root["$id"].(string); ok {
+		base = id
+	} else if id, ok := root["id"].(string); ok {
+		idKey, base = "id", id
+	}
+	if base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return &Schema{err: newError(ErrRefInvalid, base, err)}
+		}
+		u.RawQuery, u.Fragment = "items", ""
+		base = u.String()
+		doc[idKey] = base
+	}
+
+	return s.subschema(doc, base)
+}
+
+// subschema compiles the decoded schema with the schema loader of s.
+func (s *Schema) subschema(doc map[string]interface{}, base string) *Schema {
+	sl := s.sl
+	if sl == nil || base == "" {
+		// a schema without URIs cannot be added to the loader which has the root
+		sl = gojsonschema.NewSchemaLoader()
+		for _, uri := range sortedKeys(s.remotes) {
+			if err := sl.AddSchema(uri, gojsonschema.NewGoLoader(s.remotes[uri])); err != nil {
+				return &Schema{err: fmt.Errorf("jsonschema: cannot compile %s: %w", uri, err)}
+			}
+		}
+	}
+
+	gs, err := sl.Compile(gojsonschema.NewGoLoader(doc))
+	if err != nil {
+		return &Schema{err: fmt.Errorf("jsonschema: cannot compile items: %w", err)}
+	}
+
+	fast, _ := compileValidator(doc)
+	return &Schema{schema: gs, doc: doc, errMsgKey: s.errMsgKey, sl: sl, base: base, remotes: s.remotes, fast: fast}
+}
+
+// ValidateStream validates each record of a JSON array or a stream of JSON values such as NDJSON
+// one by one without loading the whole document, so it can validate huge files.
+// The stream is regarded as an array if it begins with "[".
+// A schema of elements of an array schema is given by Schema.Items.
+//
+// Invalid records are reported by a *StreamError with their indexes, which begin with 0.
+// It stops at malformed JSON and after MaxStreamErrors invalid records,
+// then StreamError.Truncated is true. ValidateStreamFunc handles all invalid records without keeping them.
+func ValidateStream(s *Schema, r io.Reader) error {
+	var serr StreamError
+	err := ValidateStreamFunc(s, r, func(rerr RecordError) error {
+		if len(serr.Records) == MaxStreamErrors {
+			serr.Truncated = true
+			return errStreamTruncated
+		}
+		serr.Records = append(serr.Records, rerr)
+		return nil
+	})
+	if err != nil && err != errStreamTruncated {
+		return err
+	}
+
+	if len(serr.Records) != 0 {
+		return &serr
+	}
+	return nil
+}
+
+// MaxStreamErrors is the maximum number of invalid records which are reported by ValidateStream.
+const MaxStreamErrors = 100
+
+// errStreamTruncated stops ValidateStream.
+var errStreamTruncated = errors.New("jsonschema: too many invalid records")
+
+// ValidateStreamFunc is same as ValidateStream but it calls f for each invalid record
+// instead of collecting them, so memory usage does not depend on the number of invalid records.
+// If f returns an error, it stops and returns the error.
+func ValidateStreamFunc(s *Schema, r io.Reader, f func(RecordError) error) error {
+	br := bufio.NewReader(r)
+	isArray, err := beginsWithArray(br)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot read stream: %w", err)
+	}
+
+	dec := json.NewDecoder(br)
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("jsonschema: cannot decode stream: %w", err)
+		}
+	}
+
+	for i := 0; ; i++ {
+		if isArray && !dec.More() {
+			break
+		}
+
+		var record json.RawMessage
+		switch err := dec.Decode(&record); {
+		case err == io.EOF && !isArray:
+			return nil
+		case err != nil:
+			return fmt.Errorf("jsonschema: cannot decode record %d: %w", i, err)
+		}
+
+		err := s.Validate(record)
+		var verr *ValidationError
+		switch {
+		case errors.As(err, &verr):
+			if err := f(RecordError{Index: i, Err: verr}); err != nil {
+				return err
+			}
+		case err != nil:
+			return fmt.Errorf("jsonschema: cannot validate record %d: %w", i, err)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("jsonschema: cannot decode stream: %w", err)
+	}
+
+	return nil
+}
+
+// beginsWithArray skips leading whitespaces and reports whether the next value is an array.
+func beginsWithArray(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.Peek(1)
+		switch {
+		case err == io.EOF:
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			if _, err := br.Discard(1); err != nil {
+				return false, err
+			}
+		default:
+			return b[0] == '[', nil
+		}
+	}
+}
+
+// StreamError is an error which reports invalid records of a stream.
+type StreamError struct {
+	Records []RecordError
+	// Truncated is true if validation is stopped by MaxStreamErrors,
+	// which means following records are not validated.
+	Truncated bool
+}
+
+func (e *StreamError) Error() string {
+	msgs := make([]string, len(e.Records))
+	for i := range e.Records {
+		msgs[i] = e.Records[i].Error()
+	}
+	more := ""
+	if e.Truncated {
+		more = " or more"
+	}
+	return fmt.Sprintf("jsonschema: %d%s invalid records: %s", len(e.Records), more, strings.Join(msgs, "; "))
+}
+
+// RecordError is an error of a record of a stream.
+type RecordError struct {
+	// Index is an index of the record which begins with 0.
+	Index int
+	// Err is a *ValidationError of the record.
+	Err error
+}
+
+func (e RecordError) Error() string {
+	var verr *ValidationError
+	if errors.As(e.Err, &verr) {
+		msgs := make([]string, len(verr.Errors))
+		for i := range verr.Errors {
+			msgs[i] = verr.Errors[i].String()
+		}
+		return fmt.Sprintf("record %d: %s", e.Index, strings.Join(msgs, ", "))
+	}
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,152 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestValidateStream(t *testing.T) {
+	type T struct {
+		Name string `json:"name"`
+	}
+
+	schema, err := GenerateBytes([]T{{}})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	s, err := CompileBytes(schema)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	users, err := Compile(os.DirFS("testdata/compile"), "users.json")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		name    string
+		schema  *Schema
+		stream  string
+		invalid []int
+		isErr   bool
+	}{
+		{"array", s.Items(), `[{"name": "a"}, {"name": 1}, {}, {"name": "b"}]`, []int{1, 2}, false},
+		{"ndjson", s.Items(), "{\"name\": \"a\"}\n{\"name\": 1}\n\n{\"name\": \"b\"}\n", []int{1}, false},
+		{"empty array", s.Items(), ` [ ] `, nil, false},
+		{"empty stream", s.Items(), ``, nil, false},
+		{"file references", users.Items(), `[{"id": 1, "name": "a"}, {"id": 0, "name": "b"}]`, []int{1}, false},
+		{"malformed", s.Items(), `[{"name": "a"}, {`, nil, true},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStream(tt.schema, strings.NewReader(tt.stream))
+			var serr *StreamError
+			switch {
+			case tt.isErr && err == nil:
+				t.Fatal("expected error does not occur")
+			case tt.isErr && errors.As(err, &serr):
+				t.Fatal("want a decoding error but got", err)
+			case tt.isErr:
+				return
+			case len(tt.invalid) == 0 && err != nil:
+				t.Fatal("unexpected error:", err)
+			case len(tt.invalid) == 0:
+				return
+			case !errors.As(err, &serr):
+				t.Fatalf("want *StreamError but got %v", err)
+			}
+
+			var got []int
+			for _, r := range serr.Records {
+				got = append(got, r.Index)
+				var verr *ValidationError
+				if !errors.As(r, &verr) {
+					t.Errorf("record %d: want *ValidationError but got %v", r.Index, r.Err)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.invalid) {
+				t.Errorf("want invalid records %v but got %v", tt.invalid, got)
+			}
+		})
+	}
+}
+
+func TestValidateStream_limit(t *testing.T) {
+	s, err := CompileBytes([]byte(`{"type": "string"}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	stream := strings.Repeat("1\n", MaxStreamErrors+10)
+
+	err = ValidateStream(s, strings.NewReader(stream))
+	var serr *StreamError
+	if !errors.As(err, &serr) {
+		t.Fatalf("want *StreamError but got %v", err)
+	}
+	if len(serr.Records) != MaxStreamErrors || !serr.Truncated {
+		t.Errorf("want %d records and truncated but got %d records and truncated %v", MaxStreamErrors, len(serr.Records), serr.Truncated)
+	}
+
+	var n int
+	stop := errors.New("stop")
+	err = ValidateStreamFunc(s, strings.NewReader(stream), func(rerr RecordError) error {
+		if rerr.Index != n {
+			t.Errorf("want record %d but got %d", n, rerr.Index)
+		}
+		n++
+		if n == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 3 {
+		t.Errorf("want to stop at 3 records by %v but got %d records and %v", stop, n, err)
+	}
+
+	n = 0
+	err = ValidateStreamFunc(s, strings.NewReader(stream), func(RecordError) error {
+		n++
+		return nil
+	})
+	if err != nil || n != MaxStreamErrors+10 {
+		t.Errorf("want %d records without error but got %d records and %v", MaxStreamErrors+10, n, err)
+	}
+}
+
+func TestSchema_Items(t *testing.T) {
+	s, err := CompileBytes([]byte(`{"$defs": {"list": {"type": "array", "items": {"$ref": "#/$defs/id"}}, "id": {"type": "integer"}}, "$ref": "#/$defs/list"}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Items().Validate([]byte(`1`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Items().Validate([]byte(`"a"`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+
+	// no restrictions
+	s, err = CompileBytes([]byte(`{"type": "array"}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Items().Validate([]byte(`"a"`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	// tuples
+	s, err = CompileBytes([]byte(`{"items": [{"type": "string"}]}`))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Items().Validate([]byte(`"a"`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
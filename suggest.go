@@ -0,0 +1,418 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TagSuggestions are suggestions of struct tags which make generation match a schema.
+type TagSuggestions struct {
+	// Items are suggestions sorted by their fields.
+	Items []TagSuggestion
+	// Notes describe differences which tags cannot resolve such as properties which are not fields,
+	// sorted by their references.
+	Notes []string
+}
+
+// TagSuggestion is a suggested struct tag of a Go field.
+type TagSuggestion struct {
+	// Field is a Go field such as "pkg.User.Name".
+	Field string
+	// Ref is the reference of the property in the schema such as "#/properties/name".
+	Ref string
+	// Tag is the whole struct tag which replaces the current one such as
+	// `json:"name" jsonschema:"minLength=1,format=email" required:"false"`.
+	// Keys of the jsonschema tag which are not suggested and other tags are kept.
+	Tag string
+	// Keywords are keywords which the suggestion adds, changes or removes.
+	Keywords []string
+}
+
+// String returns the suggestions such as:
+//
+//	pkg.User.Name: `json:"name" jsonschema:"minLength=1"` (minLength)
+//	#/properties/age: property is not a field of pkg.User
+func (s *TagSuggestions) String() string {
+	if len(s.Items) == 0 && len(s.Notes) == 0 {
+		return "the schemas match"
+	}
+	var b strings.Builder
+	for _, item := range s.Items {
+		fmt.Fprintf(&b, "%s: `%s` (%s)\n", item.Field, item.Tag, strings.Join(item.Keywords, ", "))
+	}
+	for _, note := range s.Notes {
+		fmt.Fprintln(&b, note)
+	}
+	return b.String()
+}
+
+// suggestedKeywords are keywords of properties which tags can give.
+var suggestedKeywords = append([]string{"type", "title", "description", "format", "default", "examples", "enum"}, constraintKeys...)
+
+// SuggestTags compares the hand-written schema with the schema of v which is generated with the options
+// and suggests struct tags of fields of v and its nested structs which make generation match it,
+// so teams which have existing schema contracts can switch to generating them from Go types.
+// Keywords of properties which jsonschema tags can give such as format and minLength
+// and requirements of properties by required tags are compared.
+// Nil pointers, slices and maps of v are generated from their types as SharedTypes(SharedTypesRecursive).
+// Local references of both schemas are followed. Each struct type is compared once,
+// so a struct which is used for different properties is compared by the first one.
+// Fields of groups, json.Marshaler and types of mappings are not compared.
+func SuggestTags(v interface{}, schema []byte, opts ...Option) (*TagSuggestions, error) {
+	hand, err := decodeJSON(schema)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: cannot decode schema: %w", err)
+	}
+	// nil pointers, slices and maps are compared by their types
+	opts = append([]Option{SharedTypes(SharedTypesRecursive)}, opts...)
+	b, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &suggester{
+		g:         gen{cfg: newConfig(opts)},
+		generated: generated,
+		hand:      hand,
+		seen:      map[reflect.Type]bool{},
+	}
+	if err := s.walk(reflect.TypeOf(v), generated, hand, "#"); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(s.result.Items, func(i, j int) bool {
+		return s.result.Items[i].Field < s.result.Items[j].Field
+	})
+	sort.Strings(s.result.Notes)
+	return &s.result, nil
+}
+
+// suggester is a state of SuggestTags.
+type suggester struct {
+	g         gen
+	generated interface{}
+	hand      interface{}
+	seen      map[reflect.Type]bool
+	result    TagSuggestions
+}
+
+func (s *suggester) note(ref, format string, args ...interface{}) {
+	s.result.Notes = append(s.result.Notes, ref+": "+fmt.Sprintf(format, args...))
+}
+
+// walk compares the generated schema of t with the hand-written one of the reference.
+func (s *suggester) walk(t reflect.Type, generated, hand interface{}, ref string) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	gs, _ := resolveRef(s.generated, generated)
+	hs, _ := resolveRef(s.hand, hand)
+	gm, _ := gs.(map[string]interface{})
+	hm, ok := hs.(map[string]interface{})
+	if t == nil || gm == nil || !ok || t == timeType || s.g.cfg.isMapped(t) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if s.seen[t] {
+			return nil
+		}
+		s.seen[t] = true
+		return s.structFields(t, gm, hm, ref)
+	case reflect.Slice, reflect.Array:
+		return s.walk(t.Elem(), gm["items"], hm["items"], ref+"/items")
+	case reflect.Map:
+		return s.walk(t.Elem(), gm["additionalProperties"], hm["additionalProperties"], ref+"/additionalProperties")
+	}
+	return nil
+}
+
+// structFields compares properties of the struct type t.
+func (s *suggester) structFields(t reflect.Type, generated, hand map[string]interface{}, ref string) error {
+	fields, err := s.g.fields(reflect.New(t).Elem())
+	if err != nil {
+		return err
+	}
+	gprops, _ := generated["properties"].(map[string]interface{})
+	hprops, ok := hand["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	required := requiredSet(hand)
+
+	names := map[string]bool{}
+	for _, f := range fields {
+		pref := ref + "/properties/" + escapePointer(f.name)
+		if f.group != "" {
+			names[f.group] = true
+			s.note(pref, "%s is a field of the group %q which is not compared", f.goName, f.group)
+			continue
+		}
+		names[f.name] = true
+
+		if _, ok := hprops[f.name]; !ok {
+			s.note(pref, "property of %s is not in the schema", f.goName)
+			continue
+		}
+		hp := withRefTarget(s.hand, hprops[f.name])
+		if hp == nil {
+			continue
+		}
+		s.suggest(f, pref, withRefTarget(s.generated, gprops[f.name]), hp, required[f.name])
+
+		if err := s.walk(f.value.Type(), gprops[f.name], hprops[f.name], pref); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(hprops) {
+		if !names[name] {
+			s.note(ref+"/properties/"+escapePointer(name), "property is not a field of %s", t)
+		}
+	}
+	return nil
+}
+
+// withRefTarget returns keywords of the schema and ones of the schema which it refers locally.
+// Keywords of the schema have priority. It is nil if the schema is not an object.
+func withRefTarget(root, schema interface{}) map[string]interface{} {
+	m, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	target, _ := resolveRef(root, m)
+	tm, ok := target.(map[string]interface{})
+	if !ok || len(tm) == 0 {
+		return m
+	}
+	merged := make(map[string]interface{}, len(m)+len(tm))
+	for k, v := range tm {
+		merged[k] = v
+	}
+	for k, v := range m {
+		merged[k] = v
+	}
+	delete(merged, "$ref")
+	return merged
+}
+
+// requiredSet returns required properties of the schema.
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	required := map[string]bool{}
+	names, _ := schema["required"].([]interface{})
+	for _, name := range names {
+		if name, ok := name.(string); ok {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+// suggest suggests the tag of the field whose generated property is different from the hand-written one.
+func (s *suggester) suggest(f field, ref string, generated, hand map[string]interface{}, required bool) {
+	if generated == nil {
+		generated = map[string]interface{}{}
+	}
+
+	set := map[string]string{}
+	var keywords []string
+	removed := map[string]bool{}
+	for _, k := range suggestedKeywords {
+		hv, inHand := hand[k]
+		gv, inGenerated := generated[k]
+		switch {
+		case inHand && inGenerated && reflect.DeepEqual(hv, gv):
+		case inHand:
+			value, ok := tagString(k, hv)
+			if !ok {
+				s.note(ref, "%s of %s cannot be given by a tag", k, f.goName)
+				continue
+			}
+			set[k] = value
+			keywords = append(keywords, k)
+		case inGenerated && f.tag.has(k):
+			removed[k] = true
+			keywords = append(keywords, k)
+		case inGenerated && k != "type":
+			s.note(ref, "%s of %s is generated but it is not in the schema", k, f.goName)
+		}
+	}
+
+	requiredTag := ""
+	if required == f.optional {
+		requiredTag = strconv.FormatBool(required)
+		keywords = append(keywords, "required")
+	}
+	if len(keywords) == 0 {
+		return
+	}
+
+	s.result.Items = append(s.result.Items, TagSuggestion{
+		Field:    f.goName,
+		Ref:      ref,
+		Tag:      suggestedTag(f.rawTag, set, removed, requiredTag),
+		Keywords: keywords,
+	})
+}
+
+// tagString returns the value of the keyword in a jsonschema tag.
+// It reports false if the value cannot be given by tags.
+func tagString(keyword string, v interface{}) (string, bool) {
+	switch keyword {
+	case "enum", "examples":
+		items, ok := v.([]interface{})
+		if !ok || len(items) == 0 {
+			return "", false
+		}
+		values := make([]string, len(items))
+		for i, item := range items {
+			s, ok := tagScalar(item)
+			if !ok || strings.Contains(s, "|") {
+				return "", false
+			}
+			values[i] = s
+		}
+		return strings.Join(values, "|"), true
+	case "default":
+		if s, ok := tagScalar(v); ok {
+			return s, true
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+	return tagScalar(v)
+}
+
+// tagScalar returns the string of a scalar value in a tag.
+func tagScalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// suggestedTag returns the struct tag whose jsonschema tag has the values and does not have the removed keys.
+// If required is not empty, the required tag is set.
+func suggestedTag(tag reflect.StructTag, set map[string]string, removed map[string]bool, required string) string {
+	var items []string
+	done := map[string]bool{}
+	for _, item := range splitCompatTag(tag.Get("jsonschema")) {
+		key := strings.TrimSpace(item.key)
+		value, ok := set[key]
+		switch {
+		case removed[key]:
+			continue
+		case ok:
+			items = append(items, tagItem(key, value))
+			done[key] = true
+		case item.flag:
+			items = append(items, key)
+		default:
+			items = append(items, tagItem(key, item.value))
+		}
+	}
+	for _, k := range suggestedKeywords {
+		if value, ok := set[k]; ok && !done[k] {
+			items = append(items, tagItem(k, value))
+		}
+	}
+
+	values := map[string]string{"jsonschema": strings.Join(items, ",")}
+	if required != "" {
+		values["required"] = required
+	}
+	return replaceTags(tag, values)
+}
+
+// tagItem returns an item of a jsonschema tag whose commas are escaped.
+// uniqueItems which is true is a flag.
+func tagItem(key, value string) string {
+	if key == "uniqueItems" && value == "true" {
+		return key
+	}
+	return key + "=" + strings.ReplaceAll(value, ",", `\,`)
+}
+
+// replaceTags replaces values of keys of the struct tag in place and appends new keys.
+// An empty value removes the key.
+func replaceTags(tag reflect.StructTag, values map[string]string) string {
+	var pairs []string
+	done := map[string]bool{}
+	for _, kv := range structTagPairs(string(tag)) {
+		value, ok := values[kv[0]]
+		if !ok {
+			value = kv[1]
+		}
+		done[kv[0]] = true
+		if value != "" {
+			pairs = append(pairs, kv[0]+":"+strconv.Quote(value))
+		}
+	}
+	for _, k := range []string{"jsonschema", "required"} {
+		if value := values[k]; value != "" && !done[k] {
+			pairs = append(pairs, k+":"+strconv.Quote(value))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
+
+// structTagPairs returns keys and unquoted values of the struct tag in order
+// in the same way as reflect.StructTag.Lookup.
+func structTagPairs(tag string) [][2]string {
+	var pairs [][2]string
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tag = tag[i+1:]
+		pairs = append(pairs, [2]string{name, value})
+	}
+	return pairs
+}
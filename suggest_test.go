@@ -0,0 +1,143 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type suggestAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type suggestUser struct {
+	Name      string           `json:"name" jsonschema:"description=name of the user"`
+	Email     string           `json:"email"`
+	Age       int              `json:"age,omitempty" jsonschema:"maximum=200"`
+	Tags      []string         `json:"tags"`
+	Addresses []suggestAddress `json:"addresses"`
+	Nickname  *string          `json:"nickname"`
+	Internal  string           `json:"internal"`
+}
+
+func TestSuggestTags(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["name", "email", "tags", "addresses", "nickname"],
+		"properties": {
+			"name": {"type": "string", "description": "name of the user", "minLength": 1},
+			"email": {"type": "string", "format": "email", "pattern": "^[^,]+@example\\.com$"},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}, "uniqueItems": true},
+			"addresses": {"type": "array", "items": {"$ref": "#/$defs/address"}},
+			"nickname": {"type": "string", "default": "anonymous"},
+			"deleted": {"type": "boolean"}
+		},
+		"$defs": {
+			"address": {
+				"type": "object",
+				"required": ["city", "zip"],
+				"properties": {
+					"city": {"type": "string", "examples": ["Tokyo", "Osaka"]},
+					"zip": {"type": "string", "pattern": "^[0-9]{3}-[0-9]{4}$"}
+				}
+			}
+		}
+	}`
+
+	got, err := SuggestTags(suggestUser{}, []byte(schema))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := []TagSuggestion{
+		{
+			Field:    "jsonschema_test.suggestAddress.City",
+			Ref:      "#/properties/addresses/items/properties/city",
+			Tag:      `json:"city" jsonschema:"examples=Tokyo|Osaka"`,
+			Keywords: []string{"examples"},
+		},
+		{
+			Field:    "jsonschema_test.suggestAddress.Zip",
+			Ref:      "#/properties/addresses/items/properties/zip",
+			Tag:      `json:"zip,omitempty" jsonschema:"pattern=^[0-9]{3}-[0-9]{4}$" required:"true"`,
+			Keywords: []string{"pattern", "required"},
+		},
+		{
+			Field:    "jsonschema_test.suggestUser.Age",
+			Ref:      "#/properties/age",
+			Tag:      `json:"age,omitempty" jsonschema:"type=integer,minimum=0"`,
+			Keywords: []string{"type", "minimum", "maximum"},
+		},
+		{
+			Field:    "jsonschema_test.suggestUser.Email",
+			Ref:      "#/properties/email",
+			Tag:      `json:"email" jsonschema:"format=email,pattern=^[^\\,]+@example\\.com$"`,
+			Keywords: []string{"format", "pattern"},
+		},
+		{
+			Field:    "jsonschema_test.suggestUser.Name",
+			Ref:      "#/properties/name",
+			Tag:      `json:"name" jsonschema:"description=name of the user,minLength=1"`,
+			Keywords: []string{"minLength"},
+		},
+		{
+			Field:    "jsonschema_test.suggestUser.Nickname",
+			Ref:      "#/properties/nickname",
+			Tag:      `json:"nickname" jsonschema:"default=anonymous" required:"true"`,
+			Keywords: []string{"default", "required"},
+		},
+		{
+			Field:    "jsonschema_test.suggestUser.Tags",
+			Ref:      "#/properties/tags",
+			Tag:      `json:"tags" jsonschema:"uniqueItems"`,
+			Keywords: []string{"uniqueItems"},
+		},
+	}
+	if !reflect.DeepEqual(got.Items, expect) {
+		t.Errorf("want suggestions\n%v\nbut got\n%v", (&TagSuggestions{Items: expect}).String(), got.String())
+	}
+
+	notes := []string{
+		"#/properties/deleted: property is not a field of jsonschema_test.suggestUser",
+		"#/properties/internal: property of jsonschema_test.suggestUser.Internal is not in the schema",
+	}
+	if !reflect.DeepEqual(got.Notes, notes) {
+		t.Errorf("want notes %q but got %q", notes, got.Notes)
+	}
+}
+
+func TestSuggestTags_apply(t *testing.T) {
+	type T struct {
+		Name string `json:"name,omitempty" jsonschema:"minLength=1"`
+	}
+	schema := `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "format": "email"}}}`
+
+	got, err := SuggestTags(T{}, []byte(schema))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(got.Items) != 1 {
+		t.Fatalf("want a suggestion but got %v", got)
+	}
+
+	// the type with the suggested tag generates the schema
+	suggested := reflect.StructOf([]reflect.StructField{{
+		Name: "Name",
+		Type: reflect.TypeOf(""),
+		Tag:  reflect.StructTag(got.Items[0].Tag),
+	}})
+	again, err := SuggestTags(reflect.New(suggested).Elem().Interface(), []byte(schema))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(again.Items) != 0 || len(again.Notes) != 0 {
+		t.Errorf("want no suggestions but got %v", again)
+	}
+	if s := again.String(); !strings.Contains(s, "the schemas match") {
+		t.Errorf("unexpected report %q", s)
+	}
+}
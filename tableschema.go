@@ -0,0 +1,160 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// GenerateTableSchema generates a Table Schema of Frictionless Data from the flat struct v,
+// which describes CSV files of records of the struct.
+// See https://specs.frictionlessdata.io/table-schema/.
+//
+// Each field of the struct becomes a field of the table in declaration order.
+// Its schema is generated in the same way as Generate with struct tags and options,
+// then the type, format and constraints such as required, minLength and enum are converted.
+// Date-time, date and time formats become datetime, date and time types.
+// Fields whose types are objects or arrays and references such as shared struct types
+// cause an error which matches ErrUnsupportedType. Interface fields become the any type.
+func GenerateTableSchema(w io.Writer, v interface{}, opts ...Option) error {
+	rv, err := structValue(v, "record of Table Schema")
+	if err != nil {
+		return err
+	}
+
+	g := gen{cfg: newConfig(opts)}
+	root := &obj{
+		m:    map[string]interface{}{},
+		ref:  g.cfg.refs().Root(),
+		root: true,
+	}
+
+	fields, err := g.fields(rv)
+	if err != nil {
+		return withRef(err, root.Ref())
+	}
+	tableFields := make([]interface{}, 0, len(fields))
+	for i, f := range fields {
+		if f.group != "" {
+			return &Error{Kind: ErrUnsupportedType, Ref: root.Ref(), Field: f.goName, Err: fmt.Errorf("group %q cannot be a field of Table Schema", f.group)}
+		}
+		// types of fields are needed even if they are nil
+		if isNil(f.value) && f.value.Kind() != reflect.Interface {
+			f.value = empty(f.value.Type())
+		}
+
+		o, err := g.propertyGen(root, f, i, rv.Type().Name(), opts)
+		if err != nil {
+			return err
+		}
+		tf, err := tableField(f.name, o.m, !f.optional, f.value.Kind() == reflect.Interface)
+		if err != nil {
+			return &Error{Kind: ErrUnsupportedType, Ref: o.Ref(), Field: f.goName, Err: err}
+		}
+		// integers are numbers in JSON Schema but Table Schema distinguishes them
+		if tf["type"] == "number" && isIntegerKind(reflect.Indirect(f.value).Kind()) {
+			tf["type"] = "integer"
+		}
+		tableFields = append(tableFields, tf)
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{"fields": tableFields})
+}
+
+// tableTypes are types of Table Schema which are given by formats of strings.
+var tableTypes = map[string]string{
+	"date-time": "datetime",
+	"date":      "date",
+	"time":      "time",
+	"duration":  "duration",
+}
+
+// tableFormats are formats of strings which are supported by Table Schema.
+var tableFormats = map[string]bool{
+	"email": true,
+	"uri":   true,
+	"uuid":  true,
+}
+
+// tableField converts the schema of a property into a field descriptor of Table Schema.
+// Only interface values whose schemas do not have types can be any values.
+func tableField(name string, s map[string]interface{}, required, isInterface bool) (map[string]interface{}, error) {
+	if ref, ok := s["$ref"]; ok {
+		return nil, fmt.Errorf("reference %v cannot be a field of Table Schema", ref)
+	}
+
+	f := map[string]interface{}{"name": name}
+	for _, k := range []string{"title", "description"} {
+		if v, ok := s[k]; ok {
+			f[k] = v
+		}
+	}
+
+	format, _ := s["format"].(string)
+	switch typ := s["type"]; typ {
+	case "string":
+		f["type"] = "string"
+		if t, ok := tableTypes[format]; ok {
+			f["type"] = t
+		} else if tableFormats[format] {
+			f["format"] = format
+		} else if _, ok := s["contentEncoding"]; ok {
+			f["format"] = "binary"
+		}
+	case "number", "integer", "boolean":
+		f["type"] = typ
+	case nil:
+		if !isInterface {
+			return nil, fmt.Errorf("schema without type cannot be a field of Table Schema")
+		}
+		f["type"] = "any"
+	default:
+		return nil, fmt.Errorf("type %v cannot be a field of Table Schema", typ)
+	}
+
+	constraints := map[string]interface{}{}
+	if required {
+		constraints["required"] = true
+	}
+	for _, k := range []string{"minLength", "maxLength", "minimum", "maximum", "pattern", "enum"} {
+		if v, ok := s[k]; ok {
+			constraints[k] = v
+		}
+	}
+	if len(constraints) != 0 {
+		f["constraints"] = constraints
+	}
+
+	return f, nil
+}
+
+// structValue returns the struct which is pointed by v.
+// Nil pointers become zero values. what describes the struct in errors.
+func structValue(v interface{}, what string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv = reflect.Zero(rv.Type().Elem())
+			continue
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return rv, newError(ErrUnsupportedType, RefRoot, fmt.Errorf("%s must be a struct: nil", what))
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, newError(ErrUnsupportedType, RefRoot, fmt.Errorf("%s must be a struct: %v", what, rv.Type()))
+	}
+	return rv, nil
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateTableSchema(t *testing.T) {
+	type Record struct {
+		ID      string      `json:"id" jsonschema:"format=uuid,description=record id"`
+		Name    string      `json:"name,omitempty" jsonschema:"maxLength=64"`
+		Age     int         `json:"age" jsonschema:"minimum=0"`
+		Score   *float64    `json:"score,omitempty"`
+		Active  bool        `json:"active"`
+		Created time.Time   `json:"created"`
+		Born    time.Time   `json:"born" jsonschema:"format=date"`
+		Extra   interface{} `json:"extra,omitempty"`
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateTableSchema(&buf, Record{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expect := `{
+		"fields": [
+			{"name": "id", "type": "string", "format": "uuid", "description": "record id", "constraints": {"required": true}},
+			{"name": "name", "type": "string", "constraints": {"maxLength": 64}},
+			{"name": "age", "type": "integer", "constraints": {"required": true, "minimum": 0}},
+			{"name": "score", "type": "number"},
+			{"name": "active", "type": "boolean", "constraints": {"required": true}},
+			{"name": "created", "type": "datetime", "constraints": {"required": true}},
+			{"name": "born", "type": "date", "constraints": {"required": true}},
+			{"name": "extra", "type": "any"}
+		]
+	}`
+	if diff := jsonDiff(t, buf.String(), expect); diff != "" {
+		t.Errorf("generated Table Schema does not match to expected one: %v", diff)
+	}
+}
+
+func TestGenerateTableSchema_unsupported(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+	}{
+		{"not struct", []string{}},
+		{"nil", nil},
+		{"ref", struct {
+			Meta tableMeta `json:"meta"`
+		}{}},
+		{"nested", struct {
+			Tags []string `json:"tags"`
+		}{}},
+		{"group", struct {
+			City string `json:"city" group:"address"`
+		}{}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := GenerateTableSchema(&buf, tt.v, SharedTypes(SharedTypesRef))
+			if !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("want ErrUnsupportedType but got %v", err)
+			}
+		})
+	}
+}
+
+type tableMeta struct {
+	Key string `json:"key"`
+}
@@ -0,0 +1,250 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaTag is a parsed jsonschema struct tag.
+// A tag is a comma separated list of keys or key=value pairs such as `jsonschema:"secret"`.
+// A comma which is escaped by a backslash such as `jsonschema:"pattern=^[a-z]{1\\,8}$"` is not a separator.
+type schemaTag map[string]string
+
+func parseSchemaTag(tag string) schemaTag {
+	t := schemaTag{}
+	for _, item := range splitCompatTag(tag) {
+		if item.flag {
+			if key := strings.TrimSpace(item.key); key != "" {
+				t[key] = ""
+			}
+			continue
+		}
+		t[strings.TrimSpace(item.key)] = strings.TrimSpace(item.value)
+	}
+	return t
+}
+
+// constraintKeys are validation keywords which can be given by jsonschema tags
+// such as `jsonschema:"minLength=1,maxLength=64,pattern=^[a-z]+$"`.
+var constraintKeys = []string{
+	"pattern",
+	"minLength", "maxLength",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"minItems", "maxItems", "uniqueItems", "minContains", "maxContains",
+	"minProperties", "maxProperties",
+}
+
+// jsonTag is a parsed json struct tag such as `json:"name,omitempty"`.
+type jsonTag struct {
+	// name is a name of the property. It is empty if the tag does not give it.
+	name string
+	// ignored is true if the tag is "-", which means encoding/json ignores the field.
+	ignored   bool
+	omitEmpty bool
+	// quoted is true if the tag has the string option,
+	// which means encoding/json encodes a scalar value as a JSON string.
+	quoted bool
+}
+
+// parseJSONTag parses the tag in the same way as encoding/json.
+// A tag "-," gives the name "-".
+func parseJSONTag(tag string) jsonTag {
+	if tag == "-" {
+		return jsonTag{ignored: true}
+	}
+	items := strings.Split(tag, ",")
+	t := jsonTag{name: items[0]}
+	for _, opt := range items[1:] {
+		switch opt {
+		case "omitempty":
+			t.omitEmpty = true
+		case "string":
+			t.quoted = true
+		}
+	}
+	return t
+}
+
+func (t schemaTag) has(key string) bool {
+	_, ok := t[key]
+	return ok
+}
+
+// merge returns a new tag whose values are overridden by the other tag.
+func (t schemaTag) merge(other schemaTag) schemaTag {
+	merged := make(schemaTag, len(t)+len(other))
+	for k, v := range t {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// without returns a new tag which does not have the keys.
+func (t schemaTag) without(keys ...string) schemaTag {
+	tag := make(schemaTag, len(t))
+	for k, v := range t {
+		tag[k] = v
+	}
+	for _, k := range keys {
+		delete(tag, k)
+	}
+	return tag
+}
+
+// overrides returns tags of promoted properties which are given by keys such as "name.description".
+// A tag of an embedded field can override tags of its promoted fields as follows:
+//
+//	type Child struct {
+//		Base `jsonschema:"name.description=name of the child,id.format=uuid"`
+//	}
+func (t schemaTag) overrides() map[string]schemaTag {
+	var overrides map[string]schemaTag
+	for k, v := range t {
+		i := strings.LastIndex(k, ".")
+		if i <= 0 || i == len(k)-1 {
+			continue
+		}
+		if overrides == nil {
+			overrides = map[string]schemaTag{}
+		}
+		name := k[:i]
+		if overrides[name] == nil {
+			overrides[name] = schemaTag{}
+		}
+		overrides[name][k[i+1:]] = v
+	}
+	return overrides
+}
+
+// option creates an Option which applies the tag to the object of the field.
+func (t schemaTag) option() Option {
+	return func(o Object) (Object, error) {
+		if title, ok := t["title"]; ok {
+			o.Set("title", title)
+		}
+
+		if description, ok := t["description"]; ok {
+			o.Set("description", description)
+		}
+
+		if format, ok := t["format"]; ok {
+			o.Set("format", format)
+		}
+
+		if s, ok := t["default"]; ok {
+			d, err := tagValue(o, s)
+			if err != nil {
+				return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid tag default: %w", err))
+			}
+			o.Set("default", d)
+		}
+
+		if examples, err := tagExamples(o, t); err != nil {
+			return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid tag examples: %w", err))
+		} else if len(examples) != 0 {
+			o.Set("examples", examples)
+		}
+
+		if enum, ok := t["enum"]; ok {
+			if err := setTagEnum(o, enum); err != nil {
+				return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid tag enum: %w", err))
+			}
+		}
+
+		if values, ok := t["containsEnum"]; ok {
+			if err := setTagContains(o, values); err != nil {
+				return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid tag containsEnum: %w", err))
+			}
+		}
+
+		for _, k := range constraintKeys {
+			v, ok := t[k]
+			if !ok {
+				continue
+			}
+			if err := applyCompatItem(o, compatItem{key: k, value: v, flag: v == ""}); err != nil {
+				return nil, newError(ErrTagSyntax, o.Ref(), fmt.Errorf("invalid tag %s: %w", k, err))
+			}
+		}
+
+		if mediaType, ok := t["file"]; ok {
+			var err error
+			if o, err = FileRef(mediaType)(o); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, ok := t["preserveUnknownFields"]; ok {
+			o.Set(crdPreserveUnknownFields, true)
+		}
+		if _, ok := t["intOrString"]; ok {
+			o.Set(crdIntOrString, true)
+		}
+
+		if layout, ok := t["layout"]; ok {
+			o.Set("pattern", layoutPattern(layout))
+			o.Delete("format")
+		}
+
+		return o, nil
+	}
+}
+
+// tagValue converts the value of a tag such as `jsonschema:"default=10"` according to the type of the object,
+// so it is the number 10 for numbers and the string "10" for strings.
+// Values for other types such as arrays and objects are decoded as JSON, otherwise they are strings.
+func tagValue(o Object, s string) (interface{}, error) {
+	typ, _ := o.Get("type")
+	switch typ {
+	case "string":
+		return s, nil
+	case "number", "integer", "boolean":
+		return compatValue(typ, s)
+	}
+	return directiveValue(s), nil
+}
+
+// tagExamples returns examples which are given by tags such as `jsonschema:"examples=1|2,example=3"`.
+// Values are converted as tagValue and an example is appended to examples.
+func tagExamples(o Object, t schemaTag) ([]interface{}, error) {
+	var items []string
+	if s, ok := t["examples"]; ok {
+		items = strings.Split(s, "|")
+	}
+	if s, ok := t["example"]; ok {
+		items = append(items, s)
+	}
+
+	var examples []interface{}
+	for _, s := range items {
+		v, err := tagValue(o, s)
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, v)
+	}
+	return examples, nil
+}
+
+// setTagEnum sets enum of values which are separated by "|" such as `jsonschema:"enum=red|green|blue"`.
+// Values are converted according to the type of the object
+// and they replace enum which is given by Enumer and EnumDescriber.
+func setTagEnum(o Object, values string) error {
+	target, typ := enumTarget(o)
+	target.Delete(EnumVarNamesKey)
+	target.Delete(EnumDescriptionsKey)
+	items := strings.Split(values, "|")
+	enum := make([]interface{}, len(items))
+	for i, s := range items {
+		v, err := compatValue(typ, s)
+		if err != nil {
+			return err
+		}
+		enum[i] = v
+	}
+	target.Set("enum", enum)
+	return nil
+}
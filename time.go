@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// TimeFormat sets the format of time.Time values such as "date-time" (default), "date" or "time".
+// A field can override it by `jsonschema:"format=date"` tag
+// or `jsonschema:"layout=2006/01/02"` tag which gives a layout of the time package.
+// A layout is converted into a pattern instead of a format.
+func TimeFormat(format string) Option {
+	return configOption(func(c *config) {
+		c.timeFormat = format
+	})
+}
+
+func (g *gen) timeGen(o Object) {
+	format := "date-time"
+	if g.cfg.timeFormat != "" {
+		format = g.cfg.timeFormat
+	}
+	o.Set("type", "string")
+	o.Set("format", format)
+}
+
+// layoutElems are elements of layouts of the time package and their patterns.
+// Longer elements must be placed before shorter ones which have same prefix.
+var layoutElems = []struct {
+	elem    string
+	pattern string
+}{
+	{"January", `(January|February|March|April|May|June|July|August|September|October|November|December)`},
+	{"Jan", `(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)`},
+	{"Monday", `(Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday)`},
+	{"Mon", `(Mon|Tue|Wed|Thu|Fri|Sat|Sun)`},
+	{"MST", `[A-Z]{3,4}`},
+	{"2006", `[0-9]{4}`},
+	{"002", `[0-9]{3}`},
+	{"01", `(0[1-9]|1[0-2])`},
+	{"02", `(0[1-9]|[12][0-9]|3[01])`},
+	{"_2", `( [1-9]|[12][0-9]|3[01])`},
+	{"06", `[0-9]{2}`},
+	{"15", `([01][0-9]|2[0-3])`},
+	{"03", `(0[1-9]|1[0-2])`},
+	{"04", `[0-5][0-9]`},
+	{"05", `[0-5][0-9]`},
+	{"PM", `(AM|PM)`},
+	{"pm", `(am|pm)`},
+	{"-07:00:00", `[+-][0-9]{2}:[0-9]{2}:[0-9]{2}`},
+	{"-070000", `[+-][0-9]{6}`},
+	{"-07:00", `[+-][0-9]{2}:[0-9]{2}`},
+	{"-0700", `[+-][0-9]{4}`},
+	{"-07", `[+-][0-9]{2}`},
+	{"Z07:00:00", `(Z|[+-][0-9]{2}:[0-9]{2}:[0-9]{2})`},
+	{"Z070000", `(Z|[+-][0-9]{6})`},
+	{"Z07:00", `(Z|[+-][0-9]{2}:[0-9]{2})`},
+	{"Z0700", `(Z|[+-][0-9]{4})`},
+	{"Z07", `(Z|[+-][0-9]{2})`},
+	{"1", `([1-9]|1[0-2])`},
+	{"2", `([1-9]|[12][0-9]|3[01])`},
+	{"3", `([1-9]|1[0-2])`},
+	{"4", `[0-5]?[0-9]`},
+	{"5", `[0-5]?[0-9]`},
+}
+
+var layoutFraction = regexp.MustCompile(`^[.,](0+|9+)`)
+
+// layoutPattern converts a layout of the time package into a regular expression.
+func layoutPattern(layout string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+L:
+	for layout != "" {
+		if m := layoutFraction.FindString(layout); m != "" {
+			n := len(m) - 1
+			if m[1] == '0' {
+				sb.WriteString(regexp.QuoteMeta(m[:1]) + "[0-9]{" + strconv.Itoa(n) + "}")
+			} else {
+				// trailing zeros of 9s are omitted
+				sb.WriteString("(" + regexp.QuoteMeta(m[:1]) + "[0-9]{1," + strconv.Itoa(n) + "})?")
+			}
+			layout = layout[len(m):]
+			continue
+		}
+
+		for _, e := range layoutElems {
+			if strings.HasPrefix(layout, e.elem) {
+				sb.WriteString(e.pattern)
+				layout = layout[len(e.elem):]
+				continue L
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(layout)
+		sb.WriteString(regexp.QuoteMeta(layout[:size]))
+		layout = layout[size:]
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
@@ -0,0 +1,128 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestTimeFormat(t *testing.T) {
+	type T struct {
+		CreatedAt time.Time
+		Birthday  time.Time `jsonschema:"format=date"`
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "default",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["CreatedAt", "Birthday"],
+				"properties": {
+					"CreatedAt": {"type": "string", "format": "date-time", "propertyOrder": 0},
+					"Birthday": {"type": "string", "format": "date", "propertyOrder": 1}
+				}
+			}`,
+		},
+		{
+			name: "time format option",
+			opts: []Option{TimeFormat("time")},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["CreatedAt", "Birthday"],
+				"properties": {
+					"CreatedAt": {"type": "string", "format": "time", "propertyOrder": 0},
+					"Birthday": {"type": "string", "format": "date", "propertyOrder": 1}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{}, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestTimeLayout(t *testing.T) {
+	layouts := []string{
+		"2006-01-02",
+		"2006/1/2 15:04",
+		time.Kitchen,
+		time.ANSIC,
+		time.RFC3339,
+		time.RFC3339Nano,
+		time.StampMilli,
+		"2006年01月02日",
+	}
+
+	times := []time.Time{
+		time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(1999, 12, 31, 23, 59, 59, 123456789, time.FixedZone("JST", 9*60*60)),
+	}
+
+	for _, layout := range layouts {
+		layout := layout
+		t.Run(layout, func(t *testing.T) {
+			tag := `jsonschema:"layout=` + layout + `"`
+			typ := reflect.StructOf([]reflect.StructField{{
+				Name: "At",
+				Type: reflect.TypeOf(time.Time{}),
+				Tag:  reflect.StructTag(tag),
+			}})
+
+			b, err := GenerateBytes(reflect.Zero(typ).Interface())
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			var s struct {
+				Properties map[string]struct {
+					Format  *string
+					Pattern string
+				}
+			}
+			if err := json.Unmarshal(b, &s); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			at := s.Properties["At"]
+			if at.Format != nil {
+				t.Errorf("format must be removed: %s", *at.Format)
+			}
+
+			re, err := regexp.Compile(at.Pattern)
+			if err != nil {
+				t.Fatal("invalid pattern:", err)
+			}
+
+			for _, tm := range times {
+				if str := tm.Format(layout); !re.MatchString(str) {
+					t.Errorf("%q does not match %s", str, at.Pattern)
+				}
+			}
+
+			if re.MatchString("invalid") {
+				t.Errorf("pattern %s must not match invalid value", at.Pattern)
+			}
+		})
+	}
+}
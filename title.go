@@ -0,0 +1,162 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PropertyTitles sets a human-friendly title to each property
+// which is derived from its Go field name such as "CreatedAt" to "Created At".
+// UI form generators use titles of properties as labels.
+// A title of a property has priority over a title of its struct type.
+func PropertyTitles() Option {
+	return PropertyTitlesFunc(Humanize)
+}
+
+// PropertyTitlesFunc is same as PropertyTitles but the title is derived by transform.
+func PropertyTitlesFunc(transform func(fieldName string) string) Option {
+	return configOption(func(c *config) {
+		c.propertyTitle = transform
+	})
+}
+
+// Humanize converts a Go identifier into words which are separated by spaces.
+// For example, "CreatedAt" becomes "Created At", "UserID" becomes "User ID"
+// and "user_name" becomes "User Name".
+func Humanize(name string) string {
+	rs := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i <= len(rs); i++ {
+		if i < len(rs) && !isWordBoundary(rs, i) {
+			continue
+		}
+		word := strings.Trim(string(rs[start:i]), "_")
+		if word != "" {
+			words = append(words, word)
+		}
+		start = i
+	}
+
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// isWordBoundary reports whether a new word begins at rs[i].
+func isWordBoundary(rs []rune, i int) bool {
+	prev, cur := rs[i-1], rs[i]
+	switch {
+	case cur == '_' || prev == '_':
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	case unicode.IsDigit(prev) != unicode.IsDigit(cur):
+		return true
+	case unicode.IsUpper(prev) && unicode.IsUpper(cur):
+		// "HTTPServer" becomes "HTTP Server"
+		return i+1 < len(rs) && unicode.IsLower(rs[i+1])
+	}
+	return false
+}
+
+// titleOption creates an Option which sets a title of a property.
+func titleOption(title string) Option {
+	return func(o Object) (Object, error) {
+		o.Set("title", title)
+		return o, nil
+	}
+}
+
+// OmitGenericTitles omits titles of instantiations of generic types such as "Page[User]",
+// e.g. for readers who do not know generics.
+// Titles of other named types are emitted as usual
+// and anonymous structs never have titles.
+func OmitGenericTitles() Option {
+	return TypeTitlesFunc(func(t reflect.Type) string {
+		if isGenericType(t) {
+			return ""
+		}
+		return t.Name()
+	})
+}
+
+// TypeTitlesFunc derives titles of struct types by title instead of their names.
+// If title returns an empty string, the title is omitted.
+// It is not called for anonymous structs.
+func TypeTitlesFunc(title func(t reflect.Type) string) Option {
+	return configOption(func(c *config) {
+		c.typeTitle = title
+	})
+}
+
+// isGenericType reports whether t is an instantiation of a generic type.
+func isGenericType(t reflect.Type) bool {
+	return strings.Contains(t.Name(), "[")
+}
+
+// typeName returns the name of the named type which names its definition such as "#/$defs/User".
+// An instantiation of a generic type is named by the type and its type arguments
+// without package paths such as "Page_User" for Page[example.com/app.User],
+// so that each instantiation has its own definition whose name is valid in references and file names.
+func typeName(t reflect.Type) string {
+	if !isGenericType(t) {
+		return t.Name()
+	}
+	return strings.Join(strings.FieldsFunc(shortTypeName(t), isTypeNameDelim), "_")
+}
+
+// qualifiedTypeName returns the name of the instantiation of the generic type t
+// whose type arguments are qualified by their package names such as "Page_app_User",
+// which distinguishes instantiations by types of the same name in different packages.
+func qualifiedTypeName(t reflect.Type) string {
+	return strings.Join(strings.FieldsFunc(typeArgsName(t.Name(), true), func(r rune) bool {
+		return isTypeNameDelim(r) || r == '.'
+	}), "_")
+}
+
+// shortTypeName returns the name of the type whose type arguments do not have package paths
+// such as "Page[User]" for Page[example.com/app.User].
+func shortTypeName(t reflect.Type) string {
+	if !isGenericType(t) {
+		return t.Name()
+	}
+	return typeArgsName(t.Name(), false)
+}
+
+// typeArgsName removes package paths from qualified identifiers of the name of a type.
+// If qualify is true, identifiers keep their package names such as "app.User".
+func typeArgsName(name string, qualify bool) string {
+	var b strings.Builder
+	start := 0
+	flush := func(end int) {
+		// a qualified identifier such as example.com/app.User
+		ident := name[start:end]
+		if qualify {
+			ident = ident[strings.LastIndex(ident, "/")+1:]
+		} else {
+			ident = ident[strings.LastIndex(ident, ".")+1:]
+		}
+		b.WriteString(ident)
+	}
+	for i, r := range name {
+		if isTypeNameDelim(r) {
+			flush(i)
+			b.WriteRune(r)
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	flush(len(name))
+	return b.String()
+}
+
+// isTypeNameDelim reports whether the rune delimits identifiers in names of types.
+func isTypeNameDelim(r rune) bool {
+	return strings.ContainsRune("[]*,; (){}", r)
+}
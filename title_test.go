@@ -0,0 +1,185 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestHumanize(t *testing.T) {
+	cases := map[string]string{
+		"Name":       "Name",
+		"CreatedAt":  "Created At",
+		"UserID":     "User ID",
+		"HTTPServer": "HTTP Server",
+		"user_name":  "User Name",
+		"Version2":   "Version 2",
+		"OAuth2Code": "O Auth 2 Code",
+		"ID":         "ID",
+		"":           "",
+	}
+
+	for name, want := range cases {
+		if got := Humanize(name); got != want {
+			t.Errorf("Humanize(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPropertyTitles(t *testing.T) {
+	type Address struct {
+		ZipCode string `json:"zip_code"`
+	}
+	type T struct {
+		CreatedAt string  `json:"created_at"`
+		Address   Address `json:"address"`
+	}
+
+	cases := []struct {
+		name   string
+		opt    Option
+		expect string
+	}{
+		{
+			name: "default",
+			opt:  PropertyTitles(),
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["created_at", "address"],
+				"properties": {
+					"created_at": {"type": "string", "title": "Created At", "propertyOrder": 0},
+					"address": {
+						"type": "object",
+						"title": "Address",
+						"propertyOrder": 1,
+						"required": ["zip_code"],
+						"properties": {
+							"zip_code": {"type": "string", "title": "Zip Code", "propertyOrder": 0}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name: "custom",
+			opt:  PropertyTitlesFunc(strings.ToUpper),
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["created_at", "address"],
+				"properties": {
+					"created_at": {"type": "string", "title": "CREATEDAT", "propertyOrder": 0},
+					"address": {
+						"type": "object",
+						"title": "ADDRESS",
+						"propertyOrder": 1,
+						"required": ["zip_code"],
+						"properties": {
+							"zip_code": {"type": "string", "title": "ZIPCODE", "propertyOrder": 0}
+						}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{}, tt.opt)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestTypeTitles(t *testing.T) {
+	type Address struct {
+		Zip string `json:"zip"`
+	}
+	type T struct {
+		Home  Address `json:"home"`
+		Inner struct {
+			Name string `json:"name"`
+		} `json:"inner"`
+	}
+
+	cases := []struct {
+		name   string
+		opt    Option
+		expect string
+	}{
+		{
+			name: "omit generic titles",
+			opt:  OmitGenericTitles(),
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["home", "inner"],
+				"properties": {
+					"home": {
+						"type": "object",
+						"title": "Address",
+						"propertyOrder": 0,
+						"required": ["zip"],
+						"properties": {"zip": {"type": "string", "propertyOrder": 0}}
+					},
+					"inner": {
+						"type": "object",
+						"propertyOrder": 1,
+						"required": ["name"],
+						"properties": {"name": {"type": "string", "propertyOrder": 0}}
+					}
+				}
+			}`,
+		},
+		{
+			name: "func",
+			opt: TypeTitlesFunc(func(t reflect.Type) string {
+				if t.Name() == "T" {
+					return ""
+				}
+				return strings.ToUpper(t.Name())
+			}),
+			expect: `{
+				"type": "object",
+				"required": ["home", "inner"],
+				"properties": {
+					"home": {
+						"type": "object",
+						"title": "ADDRESS",
+						"propertyOrder": 0,
+						"required": ["zip"],
+						"properties": {"zip": {"type": "string", "propertyOrder": 0}}
+					},
+					"inner": {
+						"type": "object",
+						"propertyOrder": 1,
+						"required": ["name"],
+						"properties": {"name": {"type": "string", "propertyOrder": 0}}
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{}, tt.opt)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ToolPlatform is a platform of LLM tools whose parameters are described by schemas.
+type ToolPlatform int
+
+const (
+	// ToolMCP describes inputSchema of tools of the Model Context Protocol.
+	ToolMCP ToolPlatform = iota
+	// ToolAnthropic describes input_schema of tools of the Anthropic Messages API.
+	ToolAnthropic
+	// ToolOpenAI describes parameters of function tools of the OpenAI API in strict mode,
+	// so all properties are required and optional ones accept null instead,
+	// and oneOf becomes anyOf which strict mode supports.
+	// Maps cannot be parameters because objects cannot have additional properties,
+	// and interfaces cannot be parameters because schemas must have types.
+	ToolOpenAI
+)
+
+// Tool is a definition of an LLM tool whose parameters are described by a schema.
+// It is encoded in JSON as the platform defines tools such as
+// {"name": ..., "description": ..., "inputSchema": ...} of MCP.
+type Tool struct {
+	Platform    ToolPlatform
+	Name        string
+	Description string
+	// Schema is the schema of parameters which is generated by ToolSchema.
+	Schema json.RawMessage
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t *Tool) MarshalJSON() ([]byte, error) {
+	switch t.Platform {
+	case ToolAnthropic:
+		return json.Marshal(struct {
+			Name        string          `json:"name"`
+			Description string          `json:"description,omitempty"`
+			InputSchema json.RawMessage `json:"input_schema"`
+		}{t.Name, t.Description, t.Schema})
+	case ToolOpenAI:
+		type function struct {
+			Name        string          `json:"name"`
+			Description string          `json:"description,omitempty"`
+			Parameters  json.RawMessage `json:"parameters"`
+			Strict      bool            `json:"strict"`
+		}
+		return json.Marshal(struct {
+			Type     string   `json:"type"`
+			Function function `json:"function"`
+		}{"function", function{t.Name, t.Description, t.Schema, true}})
+	}
+	return json.Marshal(struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		InputSchema json.RawMessage `json:"inputSchema"`
+	}{t.Name, t.Description, t.Schema})
+}
+
+// ToolSchema generates the schema of parameters of the platform from v, which must be a struct of parameters.
+// Objects reject additional properties as StrictObjects, $schema and propertyOrder are not emitted,
+// and nil pointers, slices and maps are generated from their types as SharedTypes(SharedTypesRecursive).
+// The options are applied after them.
+// If the root is not an object or the schema violates rules of the platform,
+// it returns an error which matches ErrInvalidOutput.
+func ToolSchema(v interface{}, p ToolPlatform, opts ...Option) ([]byte, error) {
+	opts = append([]Option{
+		StrictObjects(),
+		SharedTypes(SharedTypesRecursive),
+		MapKeywords(KeywordRenames{"propertyOrder": ""}),
+	}, opts...)
+	schema, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok || root["type"] != "object" {
+		return nil, newError(ErrInvalidOutput, RefRoot, fmt.Errorf("parameters of tools must be an object"))
+	}
+	delete(root, "$schema")
+
+	if p == ToolOpenAI {
+		if err := NewObject(RefRoot, root).Walk(strictToolObject); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(root)
+}
+
+// ToolFromFunc derives the tool from the handler function whose parameters are a struct of parameters
+// or a pointer to it after an optional context.Context such as
+//
+//	func(ctx context.Context, p SearchParams) (*SearchResult, error)
+//
+// The schema is generated from the zero value of the struct by ToolSchema with the options.
+func ToolFromFunc(name, description string, fn interface{}, p ToolPlatform, opts ...Option) (*Tool, error) {
+	t, err := toolParams(reflect.TypeOf(fn))
+	if err != nil {
+		return nil, newError(ErrUnsupportedType, RefRoot, fmt.Errorf("tool %s: %w", name, err))
+	}
+	schema, err := ToolSchema(reflect.Zero(t).Interface(), p, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Tool{Platform: p, Name: name, Description: description, Schema: schema}, nil
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// toolParams returns the struct type of parameters of the handler function type.
+func toolParams(fn reflect.Type) (reflect.Type, error) {
+	if fn == nil || fn.Kind() != reflect.Func {
+		return nil, fmt.Errorf("handler %v is not a function", fn)
+	}
+	var params []reflect.Type
+	for i := 0; i < fn.NumIn(); i++ {
+		if in := fn.In(i); i != 0 || in != contextType {
+			params = append(params, in)
+		}
+	}
+	if len(params) != 1 || indirectType(params[0]).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("handler %v must have a struct of parameters", fn)
+	}
+	return indirectType(params[0]), nil
+}
+
+// strictToolObject makes the object follow strict mode of OpenAI.
+// Properties are all required and optional ones become nullable.
+func strictToolObject(o Object) error {
+	if oneOf, ok := o.Get("oneOf"); ok {
+		if _, ok := o.Get("anyOf"); ok {
+			return newError(ErrInvalidOutput, o.Ref(), fmt.Errorf("objects of strict mode cannot have both oneOf and anyOf"))
+		}
+		o.Delete("oneOf")
+		o.Set("anyOf", oneOf)
+	}
+	if !isTypedToolSchema(o) {
+		return newError(ErrInvalidOutput, o.Ref(), fmt.Errorf("objects of strict mode must have types unlike interfaces"))
+	}
+
+	props, ok := o.Get("properties")
+	if !ok {
+		if ap, ok := o.Get("additionalProperties"); ok && ap != false {
+			return newError(ErrInvalidOutput, o.Ref(), fmt.Errorf("objects of strict mode cannot have additional properties such as maps"))
+		}
+		return nil
+	}
+	m, _ := props.(map[string]interface{})
+
+	required := map[string]bool{}
+	var names []string
+	if r, ok := o.Get("required"); ok {
+		rs, _ := r.([]interface{})
+		for _, n := range rs {
+			if s, ok := n.(string); ok {
+				required[s] = true
+				names = append(names, s)
+			}
+		}
+	}
+	var optional []string
+	for name := range m {
+		if !required[name] {
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(optional)
+	for _, name := range optional {
+		if sm, ok := m[name].(map[string]interface{}); ok {
+			nullableToolSchema(NewObject(PathRefs{}.Join(o.Ref(), "properties", name), sm))
+		}
+		names = append(names, name)
+	}
+
+	rs := make([]interface{}, len(names))
+	for i, n := range names {
+		rs[i] = n
+	}
+	o.Set("required", rs)
+	o.Set("additionalProperties", false)
+	return nil
+}
+
+// isTypedToolSchema reports whether the object restricts types of values.
+func isTypedToolSchema(o Object) bool {
+	for _, k := range []string{"type", "$ref", "anyOf", "enum", "const"} {
+		if _, ok := o.Get(k); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nullableToolSchema makes the object accept null like setNullable
+// but references and subschemas are any of them and null.
+func nullableToolSchema(o Object) {
+	null := map[string]interface{}{"type": "null"}
+	if ref, ok := o.Get("$ref"); ok {
+		o.Delete("$ref")
+		o.Set("anyOf", []interface{}{map[string]interface{}{"$ref": ref}, null})
+		return
+	}
+	if _, ok := o.Get("type"); ok {
+		setNullable(o)
+		return
+	}
+	for _, k := range []string{"anyOf", "oneOf"} {
+		if subs, ok := o.Get(k); ok {
+			ss, _ := subs.([]interface{})
+			o.Set(k, append(append([]interface{}{}, ss...), null))
+			return
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package jsonschema_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+type toolFilter struct {
+	Tag string `json:"tag"`
+}
+
+type searchParams struct {
+	Query   string       `json:"query" jsonschema:"description=words to search"`
+	Limit   int          `json:"limit,omitempty"`
+	Filters []toolFilter `json:"filters,omitempty"`
+}
+
+type searchResult struct {
+	URLs []string `json:"urls"`
+}
+
+func search(ctx context.Context, p searchParams) (*searchResult, error) {
+	return &searchResult{}, nil
+}
+
+func TestToolFromFunc(t *testing.T) {
+	cases := []struct {
+		name     string
+		platform ToolPlatform
+		expect   string
+	}{
+		{"mcp", ToolMCP, `{
+			"name": "search",
+			"description": "Search documents.",
+			"inputSchema": {
+				"title": "searchParams",
+				"type": "object",
+				"required": ["query"],
+				"additionalProperties": false,
+				"properties": {
+					"query": {"type": "string", "description": "words to search"},
+					"limit": {"type": "number"},
+					"filters": {
+						"type": "array",
+						"items": {
+							"title": "toolFilter",
+							"type": "object",
+							"required": ["tag"],
+							"additionalProperties": false,
+							"properties": {"tag": {"type": "string"}}
+						}
+					}
+				}
+			}
+		}`},
+		{"anthropic", ToolAnthropic, `{
+			"name": "search",
+			"description": "Search documents.",
+			"input_schema": {
+				"title": "searchParams",
+				"type": "object",
+				"required": ["query"],
+				"additionalProperties": false,
+				"properties": {
+					"query": {"type": "string", "description": "words to search"},
+					"limit": {"type": "number"},
+					"filters": {
+						"type": "array",
+						"items": {
+							"title": "toolFilter",
+							"type": "object",
+							"required": ["tag"],
+							"additionalProperties": false,
+							"properties": {"tag": {"type": "string"}}
+						}
+					}
+				}
+			}
+		}`},
+		{"openai", ToolOpenAI, `{
+			"type": "function",
+			"function": {
+				"name": "search",
+				"description": "Search documents.",
+				"strict": true,
+				"parameters": {
+					"title": "searchParams",
+					"type": "object",
+					"required": ["query", "filters", "limit"],
+					"additionalProperties": false,
+					"properties": {
+						"query": {"type": "string", "description": "words to search"},
+						"limit": {"type": ["number", "null"]},
+						"filters": {
+							"type": ["array", "null"],
+							"items": {
+								"title": "toolFilter",
+								"type": "object",
+								"required": ["tag"],
+								"additionalProperties": false,
+								"properties": {"tag": {"type": "string"}}
+							}
+						}
+					}
+				}
+			}
+		}`},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tool, err := ToolFromFunc("search", "Search documents.", search, tt.platform)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			got, err := json.Marshal(tool)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, string(got), tt.expect); diff != "" {
+				t.Errorf("generated tool does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+type toolNode struct {
+	Value string    `json:"value"`
+	Next  *toolNode `json:"next,omitempty"`
+}
+
+func TestToolSchema_openAI(t *testing.T) {
+	type params struct {
+		Head  toolNode    `json:"head"`
+		Shape toolShape   `json:"shape,omitempty"`
+		Nodes []*toolNode `json:"nodes"`
+	}
+
+	schema, err := ToolSchema(params{}, ToolOpenAI, RegisterImplementations((*toolShape)(nil), toolCircle{}, toolSquare{}))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	// strict mode supports neither oneOf nor schemas without types
+	var check func(ptr string, v interface{})
+	check = func(ptr string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if len(v) == 0 {
+				t.Errorf("%s: untyped schema in %s", ptr, schema)
+			}
+			if _, ok := v["oneOf"]; ok {
+				t.Errorf("%s: oneOf in %s", ptr, schema)
+			}
+			for k, e := range v {
+				check(ptr+"/"+k, e)
+			}
+		case []interface{}:
+			for i, e := range v {
+				check(fmt.Sprintf("%s/%d", ptr, i), e)
+			}
+		}
+	}
+	check("", doc)
+
+	// optional references accept null by anyOf
+	next := doc.(map[string]interface{})["$defs"].(map[string]interface{})["toolNode"].(map[string]interface{})["properties"].(map[string]interface{})["next"]
+	if diff := jsonDiff(t, mustJSON(t, next), `{"anyOf": [{"$ref": "#/$defs/toolNode"}, {"type": "null"}]}`); diff != "" {
+		t.Errorf("nullable reference does not match to expected one: %v", diff)
+	}
+}
+
+// toolShape is an interface of parameters whose implementations are registered.
+type toolShape interface {
+	Area() float64
+}
+
+type toolCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c toolCircle) Area() float64 { return c.Radius * c.Radius * 3 }
+
+type toolSquare struct {
+	Side float64 `json:"side"`
+}
+
+func (s toolSquare) Area() float64 { return s.Side * s.Side }
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	return string(b)
+}
+
+func TestToolSchema_errors(t *testing.T) {
+	type withMap struct {
+		Labels map[string]string `json:"labels"`
+	}
+
+	if _, err := ToolSchema(withMap{}, ToolOpenAI); !errors.Is(err, ErrInvalidOutput) {
+		t.Errorf("want %v but got %v", ErrInvalidOutput, err)
+	}
+	if _, err := ToolSchema(withMap{}, ToolMCP); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	type withAny struct {
+		Value interface{} `json:"value"`
+	}
+	if _, err := ToolSchema(withAny{}, ToolOpenAI); !errors.Is(err, ErrInvalidOutput) {
+		t.Errorf("want %v but got %v", ErrInvalidOutput, err)
+	}
+	if _, err := ToolSchema("query", ToolMCP); !errors.Is(err, ErrInvalidOutput) {
+		t.Errorf("want %v but got %v", ErrInvalidOutput, err)
+	}
+
+	for _, fn := range []interface{}{
+		nil,
+		"search",
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context, q string) error { return nil },
+		func(a, b searchParams) error { return nil },
+	} {
+		if _, err := ToolFromFunc("f", "", fn, ToolMCP); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("%T: want %v but got %v", fn, ErrUnsupportedType, err)
+		}
+	}
+	if _, err := ToolFromFunc("f", "", func(p *searchParams) error { return nil }, ToolMCP); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RefStyle is a style of references between files which are written by WriteTree.
+type RefStyle int
+
+const (
+	// RefRelative refers to other files by relative paths such as "User.json".
+	RefRelative RefStyle = iota
+	// RefAbsolute refers to other files by their $id such as "https://example.com/schemas/User.json".
+	// It requires TreeOptions.BaseURI.
+	RefAbsolute
+	// RefFragment keeps definitions in the root file and
+	// refers to them by fragments such as "#/$defs/User".
+	// Each definition is also written into its own file
+	// and it refers to other definitions in the root file such as "schema.json#/$defs/User".
+	RefFragment
+)
+
+// TreeOptions are options of WriteTree.
+type TreeOptions struct {
+	// Root is a file name of the root schema. The default is "schema.json".
+	Root string
+	// BaseURI is a base of $id of each file such as "https://example.com/schemas/".
+	// If it is empty, $id is not set.
+	BaseURI string
+	// Refs is a style of references between files.
+	Refs RefStyle
+}
+
+// WriteTree generates a schema of v and writes it into the directory as multiple files.
+// Each definition in $defs is written into its own file such as "User.json"
+// and references to definitions are rewritten by the style of TreeOptions.Refs.
+// It returns names of the written files.
+func WriteTree(dir string, v interface{}, topts TreeOptions, opts ...Option) ([]string, error) {
+	if topts.Root == "" {
+		topts.Root = "schema.json"
+	}
+	if topts.Refs == RefAbsolute && topts.BaseURI == "" {
+		return nil, newError(ErrRefInvalid, "", fmt.Errorf("absolute references require a base URI"))
+	}
+	if topts.BaseURI != "" && !strings.HasSuffix(topts.BaseURI, "/") {
+		topts.BaseURI += "/"
+	}
+
+	b, err := GenerateBytes(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]interface{}
+	if err := json.Unmarshal(b, &root); err != nil {
+		return nil, err
+	}
+	defs, _ := root["$defs"].(map[string]interface{})
+
+	files := map[string]map[string]interface{}{
+		topts.Root: root,
+	}
+	for name, def := range defs {
+		d, ok := def.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		file, err := defFile(name)
+		if err != nil {
+			return nil, err
+		}
+		// definitions in the root are kept by RefFragment
+		files[file] = copyValue(d).(map[string]interface{})
+	}
+
+	if topts.Refs != RefFragment {
+		delete(root, "$defs")
+	}
+	for name, doc := range files {
+		if topts.Refs == RefFragment && name == topts.Root {
+			continue
+		}
+		rewriteRefs(doc, topts.treeRef)
+	}
+
+	return writeFiles(dir, files, topts.BaseURI)
+}
+
+// writeFiles writes the documents into the directory by their file names
+// and returns names of the written files. $id of each document is set if baseURI is not empty.
+func writeFiles(dir string, files map[string]map[string]interface{}, baseURI string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		doc := files[name]
+		if baseURI != "" {
+			doc["$id"] = baseURI + fileRef(name)
+		}
+
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+// defFile returns a file name of the definition.
+// Names which cannot be used as file names in the directory such as "../User" are reported as errors.
+func defFile(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "/\\\x00") || strings.Contains(name, "..") {
+		return "", newError(ErrRefInvalid, RefRoot+"$defs/"+escapePointer(name), fmt.Errorf("definition %q cannot be a file name", name))
+	}
+	return name + ".json", nil
+}
+
+// fileRef returns a relative reference to the file.
+func fileRef(file string) string {
+	return (&url.URL{Path: file}).String()
+}
+
+// treeRef rewrites a reference to a definition such as "#/$defs/User/properties/name"
+// into a reference to its file such as "User.json#/properties/name".
+func (topts TreeOptions) treeRef(ref string) string {
+	const prefix = "#/$defs/"
+	if !strings.HasPrefix(ref, prefix) {
+		return ref
+	}
+
+	if topts.Refs == RefFragment {
+		return topts.BaseURI + topts.Root + ref
+	}
+
+	token, rest := strings.TrimPrefix(ref, prefix), ""
+	if i := strings.Index(token, "/"); i >= 0 {
+		token, rest = token[:i], "#"+token[i:]
+	}
+
+	// the token is escaped as JSON Pointer but the file is named by the definition
+	file, err := defFile(splitPointer("/" + token)[0])
+	if err != nil {
+		// it does not refer to any written files
+		return ref
+	}
+	file = fileRef(file)
+	if topts.Refs == RefAbsolute {
+		file = topts.BaseURI + file
+	}
+	return file + rest
+}
+
+// rewriteRefs rewrites values of $ref in the doc by f.
+func rewriteRefs(doc interface{}, f func(ref string) string) {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		if ref, ok := doc["$ref"].(string); ok {
+			doc["$ref"] = f(ref)
+		}
+		for _, v := range doc {
+			rewriteRefs(v, f)
+		}
+	case []interface{}:
+		for _, v := range doc {
+			rewriteRefs(v, f)
+		}
+	}
+}
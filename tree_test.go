@@ -0,0 +1,196 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestWriteTree(t *testing.T) {
+	type User struct {
+		Name    string `json:"name"`
+		Address struct {
+			Zip string `json:"zip"`
+		} `json:"address"`
+	}
+
+	cases := []struct {
+		name    string
+		topts   TreeOptions
+		files   []string
+		root    string
+		expect  map[string]string
+		compile bool
+	}{
+		{
+			name:  "relative",
+			topts: TreeOptions{},
+			files: []string{"User_Address.json", "schema.json"},
+			expect: map[string]string{
+				"schema.json": `{
+					"type": "object",
+					"title": "User",
+					"required": ["name", "address"],
+					"properties": {
+						"name": {"type": "string", "propertyOrder": 0},
+						"address": {"$ref": "User_Address.json", "propertyOrder": 1}
+					}
+				}`,
+				"User_Address.json": `{
+					"type": "object",
+					"required": ["zip"],
+					"properties": {"zip": {"type": "string", "propertyOrder": 0}}
+				}`,
+			},
+			compile: true,
+		},
+		{
+			name:  "absolute",
+			topts: TreeOptions{Root: "user.json", BaseURI: "https://example.com/schemas", Refs: RefAbsolute},
+			files: []string{"User_Address.json", "user.json"},
+			expect: map[string]string{
+				"user.json": `{
+					"$id": "https://example.com/schemas/user.json",
+					"type": "object",
+					"title": "User",
+					"required": ["name", "address"],
+					"properties": {
+						"name": {"type": "string", "propertyOrder": 0},
+						"address": {"$ref": "https://example.com/schemas/User_Address.json", "propertyOrder": 1}
+					}
+				}`,
+				"User_Address.json": `{
+					"$id": "https://example.com/schemas/User_Address.json",
+					"type": "object",
+					"required": ["zip"],
+					"properties": {"zip": {"type": "string", "propertyOrder": 0}}
+				}`,
+			},
+		},
+		{
+			name:  "fragment",
+			topts: TreeOptions{Refs: RefFragment},
+			files: []string{"User_Address.json", "schema.json"},
+			expect: map[string]string{
+				"schema.json": `{
+					"type": "object",
+					"title": "User",
+					"required": ["name", "address"],
+					"properties": {
+						"name": {"type": "string", "propertyOrder": 0},
+						"address": {"$ref": "#/$defs/User_Address", "propertyOrder": 1}
+					},
+					"$defs": {
+						"User_Address": {
+							"type": "object",
+							"required": ["zip"],
+							"properties": {"zip": {"type": "string", "propertyOrder": 0}}
+						}
+					}
+				}`,
+			},
+			compile: true,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files, err := WriteTree(dir, User{}, tt.topts, HoistAnonymousStructs())
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if !reflect.DeepEqual(files, tt.files) {
+				t.Errorf("want files %v but got %v", tt.files, files)
+			}
+
+			for name, expect := range tt.expect {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if diff := jsonDiff(t, string(got), expect); diff != "" {
+					t.Errorf("%s does not match to expected one: %v", name, diff)
+				}
+			}
+
+			if !tt.compile {
+				return
+			}
+			root := tt.topts.Root
+			if root == "" {
+				root = "schema.json"
+			}
+			s, err := Compile(os.DirFS(dir), root)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if err := s.Validate([]byte(`{"name":"a","address":{"zip":1}}`)); err == nil {
+				t.Error("expected error does not occur")
+			}
+		})
+	}
+
+	if _, err := WriteTree(t.TempDir(), User{}, TreeOptions{Refs: RefAbsolute}); !errors.Is(err, ErrRefInvalid) {
+		t.Errorf("want ErrRefInvalid but got %v", err)
+	}
+}
+
+// treeDefs is a schema whose definitions have names which are not Go identifiers.
+type treeDefs string
+
+func (d treeDefs) JSONSchema(w io.Writer, opts ...Option) error {
+	_, err := io.WriteString(w, string(d))
+	return err
+}
+
+func TestWriteTree_names(t *testing.T) {
+	dir := t.TempDir()
+	doc := treeDefs(`{
+		"type": "object",
+		"properties": {
+			"x": {"$ref": "#/$defs/a b"},
+			"y": {"$ref": "#/$defs/a~0b"}
+		},
+		"$defs": {
+			"a b": {"type": "string"},
+			"a~b": {"type": "number"}
+		}
+	}`)
+	files, err := WriteTree(dir, doc, TreeOptions{})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if expect := []string{"a b.json", "a~b.json", "schema.json"}; !reflect.DeepEqual(files, expect) {
+		t.Errorf("want files %v but got %v", expect, files)
+	}
+
+	s, err := Compile(os.DirFS(dir), "schema.json")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"x": "a", "y": 1}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if err := s.Validate([]byte(`{"x": 1, "y": "a"}`)); err == nil {
+		t.Error("expected error does not occur")
+	}
+
+	for _, name := range []string{"../../evil", "a/b", `a\b`, ".."} {
+		dir := filepath.Join(t.TempDir(), "a", "b", "c")
+		doc := treeDefs(`{"$defs": {"` + strings.ReplaceAll(name, `\`, `\\`) + `": {}}}`)
+		if _, err := WriteTree(dir, doc, TreeOptions{}); !errors.Is(err, ErrRefInvalid) {
+			t.Errorf("want ErrRefInvalid for %q but got %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "..", "..", "evil.json")); err == nil {
+			t.Errorf("%q is written outside of the directory", name)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ArrayStyle is a style of schemas of Go arrays which have fixed lengths such as [3]float64.
+type ArrayStyle int
+
+const (
+	// ArrayItems generates items of the element type with minItems and maxItems of the length.
+	// It is the default.
+	ArrayItems ArrayStyle = iota
+	// ArrayTuples generates a tuple whose prefixItems are generated from each element
+	// such as {"prefixItems": [...], "items": false, "minItems": 3}.
+	// If schemas of all elements are identical, they are collapsed into items
+	// with minItems and maxItems of the length to keep schemas small.
+	ArrayTuples
+	// ArrayPrefixItems generates a tuple as ArrayTuples but never collapses it,
+	// for consumers which expect prefixItems of arrays.
+	ArrayPrefixItems
+)
+
+// Arrays sets the style of schemas of Go arrays. Slices are not affected.
+// Objects of elements of tuples are referred by references such as "#/properties/point/prefixItems/0"
+// and a collapsed schema is the schema of the first element.
+// Drafts before 2020-12 express tuples by items and additionalItems.
+// A field can also be a tuple regardless of the style by `jsonschema:"tuple"` tag.
+func Arrays(style ArrayStyle) Option {
+	return configOption(func(c *config) {
+		c.arrayStyle = style
+	})
+}
+
+// tupleGen generates the object of the array v as a tuple of its elements.
+// If collapsible is true, identical schemas of the elements are collapsed into items.
+func (g *gen) tupleGen(parent Object, v reflect.Value, collapsible bool, options ...Option) error {
+	elms := make([]reflect.Value, v.Len())
+	for i := range elms {
+		elms[i] = v.Index(i)
+	}
+	return g.elementsGen(parent, elms, collapsible, options...)
+}
+
+// elementsGen generates the object of a tuple whose prefixItems are generated from the elements.
+func (g *gen) elementsGen(parent Object, elms []reflect.Value, collapsible bool, options ...Option) error {
+	n := len(elms)
+	prefix := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		o := &obj{
+			m:   map[string]interface{}{},
+			ref: g.cfg.refs().Join(parent.Ref(), "prefixItems", strconv.Itoa(i)),
+		}
+		elm := elms[i]
+		if isNil(elm) {
+			elm = empty(elm.Type())
+		}
+		if err := g.do(o, elm, options...); err != nil {
+			return err
+		}
+		prefix[i] = o.m
+		collapsible = collapsible && reflect.DeepEqual(prefix[0], o.m)
+	}
+
+	parent.Set("type", "array")
+	parent.Set("minItems", n)
+	if collapsible && n != 0 {
+		if g.plan != nil {
+			g.plan.note(parent.Ref(), "identical schemas of %d elements are collapsed into items", n)
+		}
+		parent.Set("items", prefix[0])
+		parent.Set("maxItems", n)
+		return nil
+	}
+	if n == 0 {
+		parent.Set("maxItems", 0)
+		return nil
+	}
+	parent.Set("prefixItems", prefix)
+	parent.Set("items", false)
+
+	return nil
+}
+
+// tuplePropertyGen generates the object of a field which is tagged by `jsonschema:"tuple"`
+// as a tuple which is never collapsed regardless of Arrays.
+// An array is a tuple of its elements and a struct is a tuple of its exported fields in order,
+// which is for types such as coordinates whose MarshalJSON encode them as arrays like [35.6, 139.7].
+func (g *gen) tuplePropertyGen(o Object, v reflect.Value, options []Option) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = empty(v.Type())
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		if err := g.tupleGen(o, v, false, options...); err != nil {
+			return err
+		}
+	case reflect.Struct:
+		var elms []reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if sf.PkgPath != "" || parseJSONTag(sf.Tag.Get("json")).ignored {
+				continue
+			}
+			elms = append(elms, v.Field(i))
+		}
+		if err := g.elementsGen(o, elms, false, options...); err != nil {
+			return err
+		}
+	default:
+		return newError(ErrTagSyntax, o.Ref(), fmt.Errorf("tuple tag cannot be used for %s", v.Type()))
+	}
+
+	return g.applyOptions(o, options)
+}
@@ -0,0 +1,215 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestArrays(t *testing.T) {
+	type T struct {
+		Point [3]float64 `json:"point"`
+		Pair  [2]string  `json:"pair"`
+		Tags  []string   `json:"tags"`
+	}
+	// the second element of pair is different
+	secondPair := ByReference("#/properties/pair/prefixItems/1", func(o Object) (Object, error) {
+		o.Set("format", "email")
+		return o, nil
+	})
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "items",
+			opts: []Option{secondPair},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["point", "pair", "tags"],
+				"properties": {
+					"point": {"type": "array", "items": {"type": "number"}, "minItems": 3, "maxItems": 3, "propertyOrder": 0},
+					"pair": {"type": "array", "items": {"type": "string"}, "minItems": 2, "maxItems": 2, "propertyOrder": 1},
+					"tags": {"type": "array", "items": {"type": "string"}, "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "tuples",
+			opts: []Option{Arrays(ArrayTuples), secondPair},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["point", "pair", "tags"],
+				"properties": {
+					"point": {"type": "array", "items": {"type": "number"}, "minItems": 3, "maxItems": 3, "propertyOrder": 0},
+					"pair": {
+						"type": "array",
+						"prefixItems": [{"type": "string"}, {"type": "string", "format": "email"}],
+						"items": false,
+						"minItems": 2,
+						"propertyOrder": 1
+					},
+					"tags": {"type": "array", "items": {"type": "string"}, "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "prefixItems",
+			opts: []Option{Arrays(ArrayPrefixItems)},
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["point", "pair", "tags"],
+				"properties": {
+					"point": {
+						"type": "array",
+						"prefixItems": [{"type": "number"}, {"type": "number"}, {"type": "number"}],
+						"items": false,
+						"minItems": 3,
+						"propertyOrder": 0
+					},
+					"pair": {
+						"type": "array",
+						"prefixItems": [{"type": "string"}, {"type": "string"}],
+						"items": false,
+						"minItems": 2,
+						"propertyOrder": 1
+					},
+					"tags": {"type": "array", "items": {"type": "string"}, "propertyOrder": 2}
+				}
+			}`,
+		},
+		{
+			name: "draft-07",
+			opts: []Option{Arrays(ArrayTuples), secondPair, Draft(Draft07)},
+			expect: `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"title": "T",
+				"type": "object",
+				"required": ["point", "pair", "tags"],
+				"properties": {
+					"point": {"type": "array", "items": {"type": "number"}, "minItems": 3, "maxItems": 3, "propertyOrder": 0},
+					"pair": {
+						"type": "array",
+						"items": [{"type": "string"}, {"type": "string", "format": "email"}],
+						"additionalItems": false,
+						"minItems": 2,
+						"propertyOrder": 1
+					},
+					"tags": {"type": "array", "items": {"type": "string"}, "propertyOrder": 2}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateString(T{Tags: []string{}}, tt.opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+type tupleLatLng struct {
+	Lat float64
+	Lng float64
+	Alt *float64
+	_   string
+}
+
+func TestTupleTag(t *testing.T) {
+	type T struct {
+		Point    [2]float64   `json:"point" jsonschema:"tuple"`
+		Position *tupleLatLng `json:"position" jsonschema:"tuple,description=latitude and longitude"`
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "prefixItems",
+			expect: `{
+				"title": "T",
+				"type": "object",
+				"required": ["point"],
+				"properties": {
+					"point": {
+						"type": "array",
+						"prefixItems": [{"type": "number"}, {"type": "number"}],
+						"items": false,
+						"minItems": 2
+					},
+					"position": {
+						"type": "array",
+						"description": "latitude and longitude",
+						"prefixItems": [{"type": "number"}, {"type": "number"}, {"type": "number"}],
+						"items": false,
+						"minItems": 3
+					}
+				}
+			}`,
+		},
+		{
+			name: "draft-07",
+			opts: []Option{Draft(Draft07)},
+			expect: `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"title": "T",
+				"type": "object",
+				"required": ["point"],
+				"properties": {
+					"point": {
+						"type": "array",
+						"items": [{"type": "number"}, {"type": "number"}],
+						"additionalItems": false,
+						"minItems": 2
+					},
+					"position": {
+						"type": "array",
+						"description": "latitude and longitude",
+						"items": [{"type": "number"}, {"type": "number"}, {"type": "number"}],
+						"additionalItems": false,
+						"minItems": 3
+					}
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{MapKeywords(KeywordRenames{"propertyOrder": ""})}, tt.opts...)
+			got, err := GenerateString(T{}, opts...)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if diff := jsonDiff(t, got, tt.expect); diff != "" {
+				t.Errorf("generated JSON Schema does not match to expected one: %v", diff)
+			}
+		})
+	}
+}
+
+func TestTupleTag_error(t *testing.T) {
+	type T struct {
+		Tags []string `json:"tags" jsonschema:"tuple"`
+	}
+	_, err := GenerateString(T{})
+	if !errors.Is(err, ErrTagSyntax) {
+		t.Errorf("want %v but got %v", ErrTagSyntax, err)
+	}
+}
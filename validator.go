@@ -0,0 +1,616 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// errNoFastPath reports that a schema uses keywords which the compiled validator does not support.
+var errNoFastPath = errors.New("no fast path")
+
+// validator is a compiled schema which checks documents faster than gojsonschema.
+// It only reports whether a document is valid or not.
+// Schema falls back to gojsonschema to report errors of invalid documents,
+// so a validator must never accept documents which gojsonschema rejects.
+type validator struct {
+	// pass is the result of a boolean schema such as true or false.
+	pass *bool
+	// ref is the referred schema. Other keywords are ignored as gojsonschema does.
+	ref *validator
+
+	types map[string]bool
+	enum  map[string]bool
+	cnst  *string
+
+	minimum, maximum                   *big.Rat
+	exclusiveMinimum, exclusiveMaximum *big.Rat
+	multipleOf                         *big.Rat
+
+	minLength, maxLength int
+	pattern              *regexp.Regexp
+	format               string
+
+	items              *validator
+	contains           *validator
+	minItems, maxItems int
+	uniqueItems        bool
+
+	required             []string
+	properties           map[string]*validator
+	patternProperties    []patternValidator
+	additionalProperties *validator
+	propertyNames        *validator
+	minProps, maxProps   int
+
+	allOf, anyOf, oneOf []*validator
+	not                 *validator
+}
+
+type patternValidator struct {
+	re *regexp.Regexp
+	v  *validator
+}
+
+// validatorCompiler compiles a root schema into validators.
+type validatorCompiler struct {
+	root interface{}
+	// compiled holds validators by JSON Pointers to share referred schemas
+	// and to compile recursive schemas.
+	compiled map[string]*validator
+	hasID    bool
+}
+
+// compileValidator compiles the decoded root schema.
+// It returns errNoFastPath if the schema cannot be checked by validators.
+func compileValidator(root interface{}) (*validator, error) {
+	c := &validatorCompiler{root: root, compiled: map[string]*validator{}}
+	v, err := c.compile(root, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// the base URI can be changed by $id, then local references are not resolved from the root
+	if c.hasID && len(collectRefs(root, nil)) != 0 {
+		return nil, errNoFastPath
+	}
+
+	return v, nil
+}
+
+func (c *validatorCompiler) compile(node interface{}, ptr string) (*validator, error) {
+	if v := c.compiled[ptr]; v != nil {
+		return v, nil
+	}
+
+	v := &validator{minLength: -1, maxLength: -1, minItems: -1, maxItems: -1, minProps: -1, maxProps: -1}
+	c.compiled[ptr] = v
+
+	switch node := node.(type) {
+	case bool:
+		v.pass = &node
+		return v, nil
+	case map[string]interface{}:
+		if ptr != "" {
+			_, hasID := node["$id"]
+			_, hasDraft4ID := node["id"]
+			c.hasID = c.hasID || hasID || hasDraft4ID
+		}
+
+		if ref, ok := node["$ref"]; ok {
+			return v, c.compileRef(v, ref)
+		}
+
+		for _, k := range sortedKeys(node) {
+			if err := c.keyword(v, k, node[k], ptr+"/"+escapePointer(k)); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	}
+
+	return nil, errNoFastPath
+}
+
+func (c *validatorCompiler) compileRef(v *validator, ref interface{}) error {
+	s, ok := ref.(string)
+	if !ok || s != "#" && !strings.HasPrefix(s, "#/") {
+		return errNoFastPath
+	}
+
+	if strings.Contains(s, "%") {
+		return errNoFastPath
+	}
+
+	ptr := strings.TrimPrefix(s, "#")
+	rv, err := c.compile(lookupPointer(c.root, ptr), ptr)
+	if err != nil {
+		return err
+	}
+	v.ref = rv
+
+	return nil
+}
+
+// keyword compiles a keyword of a schema object.
+// Annotations and unknown keywords are ignored as gojsonschema does.
+func (c *validatorCompiler) keyword(v *validator, k string, value interface{}, ptr string) error {
+	var err error
+	switch k {
+	case "dependencies", "if", "then", "else", "additionalItems":
+		return errNoFastPath
+	case "type":
+		v.types = map[string]bool{}
+		switch t := value.(type) {
+		case string:
+			v.types[t] = true
+		case []interface{}:
+			for _, t := range t {
+				s, ok := t.(string)
+				if !ok {
+					return errNoFastPath
+				}
+				v.types[s] = true
+			}
+		default:
+			return errNoFastPath
+		}
+	case "enum":
+		values, ok := value.([]interface{})
+		if !ok {
+			return errNoFastPath
+		}
+		v.enum = make(map[string]bool, len(values))
+		for _, e := range values {
+			s, err := normalizeJSON(e)
+			if err != nil {
+				return errNoFastPath
+			}
+			v.enum[s] = true
+		}
+	case "const":
+		s, err := normalizeJSON(value)
+		if err != nil {
+			return errNoFastPath
+		}
+		v.cnst = &s
+	case "minimum":
+		v.minimum, err = ratOf(value)
+	case "maximum":
+		v.maximum, err = ratOf(value)
+	case "exclusiveMinimum":
+		// boolean forms of draft-04 are not supported
+		v.exclusiveMinimum, err = ratOf(value)
+	case "exclusiveMaximum":
+		v.exclusiveMaximum, err = ratOf(value)
+	case "multipleOf":
+		v.multipleOf, err = ratOf(value)
+		if err == nil && v.multipleOf.Sign() <= 0 {
+			err = errNoFastPath
+		}
+	case "minLength":
+		v.minLength, err = intOf(value)
+	case "maxLength":
+		v.maxLength, err = intOf(value)
+	case "minItems":
+		v.minItems, err = intOf(value)
+	case "maxItems":
+		v.maxItems, err = intOf(value)
+	case "minProperties":
+		v.minProps, err = intOf(value)
+	case "maxProperties":
+		v.maxProps, err = intOf(value)
+	case "pattern":
+		v.pattern, err = regexpOf(value)
+	case "format":
+		s, ok := value.(string)
+		if !ok {
+			return errNoFastPath
+		}
+		v.format = s
+	case "uniqueItems":
+		b, ok := value.(bool)
+		if !ok {
+			return errNoFastPath
+		}
+		v.uniqueItems = b
+	case "items":
+		// tuple forms are not supported
+		if _, ok := value.([]interface{}); ok {
+			return errNoFastPath
+		}
+		v.items, err = c.compile(value, ptr)
+	case "contains":
+		v.contains, err = c.compile(value, ptr)
+	case "required":
+		names, ok := value.([]interface{})
+		if !ok {
+			return errNoFastPath
+		}
+		for _, name := range names {
+			s, ok := name.(string)
+			if !ok {
+				return errNoFastPath
+			}
+			v.required = append(v.required, s)
+		}
+	case "properties":
+		props, ok := value.(map[string]interface{})
+		if !ok {
+			return errNoFastPath
+		}
+		v.properties = make(map[string]*validator, len(props))
+		for _, name := range sortedKeys(props) {
+			pv, err := c.compile(props[name], ptr+"/"+escapePointer(name))
+			if err != nil {
+				return err
+			}
+			v.properties[name] = pv
+		}
+	case "patternProperties":
+		props, ok := value.(map[string]interface{})
+		if !ok {
+			return errNoFastPath
+		}
+		for _, p := range sortedKeys(props) {
+			re, err := regexpOf(p)
+			if err != nil {
+				return err
+			}
+			pv, err := c.compile(props[p], ptr+"/"+escapePointer(p))
+			if err != nil {
+				return err
+			}
+			v.patternProperties = append(v.patternProperties, patternValidator{re: re, v: pv})
+		}
+	case "additionalProperties":
+		v.additionalProperties, err = c.compile(value, ptr)
+	case "propertyNames":
+		v.propertyNames, err = c.compile(value, ptr)
+	case "allOf":
+		all, err := c.compileAll(value, ptr)
+		if err != nil {
+			return err
+		}
+		for i, sub := range all {
+			// nested schemas which have only allOf are flattened into the parent
+			if m, ok := value.([]interface{})[i].(map[string]interface{}); ok && len(m) == 1 && m["allOf"] != nil {
+				v.allOf = append(v.allOf, sub.allOf...)
+				continue
+			}
+			v.allOf = append(v.allOf, sub)
+		}
+	case "anyOf":
+		v.anyOf, err = c.compileAll(value, ptr)
+	case "oneOf":
+		v.oneOf, err = c.compileAll(value, ptr)
+	case "not":
+		v.not, err = c.compile(value, ptr)
+	}
+	return err
+}
+
+func (c *validatorCompiler) compileAll(value interface{}, ptr string) ([]*validator, error) {
+	schemas, ok := value.([]interface{})
+	if !ok || len(schemas) == 0 {
+		return nil, errNoFastPath
+	}
+
+	vs := make([]*validator, len(schemas))
+	for i, s := range schemas {
+		v, err := c.compile(s, ptr+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+// valid reports whether the document decoded with json.Decoder.UseNumber is valid.
+func (v *validator) valid(doc interface{}) bool {
+	if v.pass != nil {
+		return *v.pass
+	}
+	if v.ref != nil {
+		return v.ref.valid(doc)
+	}
+
+	if v.types != nil && !v.validType(doc) {
+		return false
+	}
+
+	switch doc := doc.(type) {
+	case json.Number:
+		if !v.validNumber(doc) {
+			return false
+		}
+	case string:
+		if !v.validString(doc) {
+			return false
+		}
+	case []interface{}:
+		if !v.validArray(doc) {
+			return false
+		}
+	case map[string]interface{}:
+		if !v.validObject(doc) {
+			return false
+		}
+	}
+
+	if v.enum != nil || v.cnst != nil {
+		s, err := normalizeJSON(doc)
+		switch {
+		case err != nil:
+			return false
+		case v.enum != nil && !v.enum[s]:
+			return false
+		case v.cnst != nil && *v.cnst != s:
+			return false
+		}
+	}
+
+	for _, sub := range v.allOf {
+		if !sub.valid(doc) {
+			return false
+		}
+	}
+
+	if v.anyOf != nil {
+		var ok bool
+		for _, sub := range v.anyOf {
+			if sub.valid(doc) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if v.oneOf != nil {
+		var n int
+		for _, sub := range v.oneOf {
+			if sub.valid(doc) {
+				n++
+			}
+		}
+		if n != 1 {
+			return false
+		}
+	}
+
+	return v.not == nil || !v.not.valid(doc)
+}
+
+func (v *validator) validType(doc interface{}) bool {
+	switch doc := doc.(type) {
+	case nil:
+		return v.types["null"]
+	case bool:
+		return v.types["boolean"]
+	case string:
+		return v.types["string"]
+	case []interface{}:
+		return v.types["array"]
+	case map[string]interface{}:
+		return v.types["object"]
+	case json.Number:
+		if v.types["number"] {
+			return true
+		}
+		r, ok := new(big.Rat).SetString(string(doc))
+		return ok && r.IsInt() && v.types["integer"]
+	}
+	return false
+}
+
+func (v *validator) validNumber(n json.Number) bool {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return false
+	}
+
+	switch {
+	case v.multipleOf != nil && !new(big.Rat).Quo(r, v.multipleOf).IsInt():
+		return false
+	case v.maximum != nil && r.Cmp(v.maximum) > 0:
+		return false
+	case v.exclusiveMaximum != nil && r.Cmp(v.exclusiveMaximum) >= 0:
+		return false
+	case v.minimum != nil && r.Cmp(v.minimum) < 0:
+		return false
+	case v.exclusiveMinimum != nil && r.Cmp(v.exclusiveMinimum) <= 0:
+		return false
+	}
+
+	if v.format != "" {
+		f, err := n.Float64()
+		if err != nil || !gojsonschema.FormatCheckers.IsFormat(v.format, f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *validator) validString(s string) bool {
+	if v.minLength >= 0 || v.maxLength >= 0 {
+		n := utf8.RuneCountInString(s)
+		if v.minLength >= 0 && n < v.minLength || v.maxLength >= 0 && n > v.maxLength {
+			return false
+		}
+	}
+
+	switch {
+	case v.pattern != nil && !v.pattern.MatchString(s):
+		return false
+	case v.format != "" && !gojsonschema.FormatCheckers.IsFormat(v.format, s):
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) validArray(a []interface{}) bool {
+	if v.minItems >= 0 && len(a) < v.minItems || v.maxItems >= 0 && len(a) > v.maxItems {
+		return false
+	}
+
+	if v.uniqueItems {
+		seen := make(map[string]bool, len(a))
+		for _, e := range a {
+			s, err := normalizeJSON(e)
+			if err != nil || seen[s] {
+				return false
+			}
+			seen[s] = true
+		}
+	}
+
+	if v.items != nil {
+		for _, e := range a {
+			if !v.items.valid(e) {
+				return false
+			}
+		}
+	}
+
+	if v.contains != nil {
+		var ok bool
+		for _, e := range a {
+			if v.contains.valid(e) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *validator) validObject(o map[string]interface{}) bool {
+	if v.minProps >= 0 && len(o) < v.minProps || v.maxProps >= 0 && len(o) > v.maxProps {
+		return false
+	}
+
+	for _, name := range v.required {
+		if _, ok := o[name]; !ok {
+			return false
+		}
+	}
+
+	for name, value := range o {
+		if v.propertyNames != nil && !v.propertyNames.valid(name) {
+			return false
+		}
+
+		pv, known := v.properties[name]
+		if known && !pv.valid(value) {
+			return false
+		}
+
+		for _, p := range v.patternProperties {
+			if p.re.MatchString(name) {
+				known = true
+				if !p.v.valid(value) {
+					return false
+				}
+			}
+		}
+
+		if !known && v.additionalProperties != nil && !v.additionalProperties.valid(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeJSON encodes the value as JSON such that equal values have same encodings,
+// for example 1.0 and 1, in the same way as gojsonschema.
+func normalizeJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+
+	b, err = json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// decodeJSON decodes the JSON document keeping numbers as they are written.
+func decodeJSON(doc []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("invalid character after top-level value")
+	}
+
+	return v, nil
+}
+
+func ratOf(value interface{}) (*big.Rat, error) {
+	var s string
+	switch value := value.(type) {
+	case json.Number:
+		s = string(value)
+	case float64:
+		return new(big.Rat).SetFloat64(value), nil
+	default:
+		return nil, errNoFastPath
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, errNoFastPath
+	}
+	return r, nil
+}
+
+func intOf(value interface{}) (int, error) {
+	r, err := ratOf(value)
+	if err != nil || !r.IsInt() || !r.Num().IsInt64() || r.Sign() < 0 {
+		return 0, errNoFastPath
+	}
+	return int(r.Num().Int64()), nil
+}
+
+func regexpOf(value interface{}) (*regexp.Regexp, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errNoFastPath
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, errNoFastPath
+	}
+	return re, nil
+}
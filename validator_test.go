@@ -0,0 +1,136 @@
+package jsonschema_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+		docs   []string
+	}{
+		{"type", `{"type": ["integer", "null"]}`, []string{`1`, `1.0`, `1.5`, `null`, `"1"`}},
+		{"number", `{"minimum": 1, "exclusiveMaximum": 3, "multipleOf": 0.5}`, []string{`1`, `2.5`, `3`, `0.5`, `1.25`, `"a"`}},
+		{"string", `{"minLength": 2, "maxLength": 3, "pattern": "^[a-zあ]+$"}`, []string{`"ab"`, `"あい"`, `"a"`, `"abcd"`, `"AB"`, `1`}},
+		{"format", `{"format": "email"}`, []string{`"a@example.com"`, `"a"`, `1`}},
+		{"enum", `{"enum": [1, "a", {"b": [true]}]}`, []string{`1`, `1.0`, `"a"`, `{"b": [true]}`, `2`}},
+		{"const", `{"const": {"a": 1}}`, []string{`{"a": 1}`, `{"a": 1.0}`, `{"a": 2}`}},
+		{"array", `{"items": {"type": "integer"}, "minItems": 1, "maxItems": 3, "uniqueItems": true, "contains": {"const": 1}}`,
+			[]string{`[1]`, `[1, 2]`, `[]`, `[1, 2, 3, 4]`, `[1, 1.0]`, `[2]`, `[1, "a"]`}},
+		{"object", `{
+			"required": ["a"],
+			"properties": {"a": {"type": "string"}},
+			"patternProperties": {"^x-": {"type": "integer"}},
+			"additionalProperties": false,
+			"propertyNames": {"maxLength": 3},
+			"minProperties": 1,
+			"maxProperties": 2
+		}`, []string{`{"a": "a"}`, `{"a": "a", "x-b": 1}`, `{"a": 1}`, `{"a": "a", "x-b": "b"}`, `{"a": "a", "b": 1}`, `{}`, `{"a": "a", "x-long": 1}`}},
+		{"additional schema", `{"properties": {"a": {}}, "additionalProperties": {"type": "string"}}`, []string{`{"a": 1, "b": "b"}`, `{"b": 1}`}},
+		{"combinators", `{
+			"allOf": [{"allOf": [{"minimum": 0}, {"maximum": 10}]}],
+			"anyOf": [{"type": "integer"}, {"multipleOf": 0.5}],
+			"oneOf": [{"minimum": 5}, {"maximum": 6}],
+			"not": {"const": 3}
+		}`, []string{`1`, `1.5`, `1.2`, `5.5`, `3`, `11`}},
+		{"ref", `{
+			"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}, "v": {"type": "integer"}}}},
+			"$ref": "#/$defs/node",
+			"type": "string"
+		}`, []string{`{"next": {"next": {"v": 1}}}`, `{"next": {"v": "a"}}`, `"a"`}},
+		{"boolean", `{"properties": {"a": true, "b": false}}`, []string{`{"a": 1}`, `{"b": 1}`}},
+		{"fallback", `{"if": {"type": "string"}, "then": {"minLength": 1}, "dependencies": {"a": ["b"]}}`, []string{`""`, `{"a": 1}`, `{"a": 1, "b": 2}`}},
+		{"draft-04", `{"$schema": "http://json-schema.org/draft-04/schema#", "minimum": 1, "exclusiveMinimum": true}`, []string{`1`, `2`}},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := CompileBytes([]byte(tt.schema))
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			ls := gojsonschema.NewStringLoader(tt.schema)
+			for _, doc := range tt.docs {
+				r, err := gojsonschema.Validate(ls, gojsonschema.NewStringLoader(doc))
+				if err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+
+				err = s.Validate([]byte(doc))
+				switch {
+				case r.Valid() && err != nil:
+					t.Errorf("%s: unexpected error: %v", doc, err)
+				case !r.Valid() && err == nil:
+					t.Errorf("%s: expected error does not occur", doc)
+				}
+			}
+		})
+	}
+}
+
+type benchUser struct {
+	ID      int64    `json:"id" jsonschema:"minimum=1"`
+	Name    string   `json:"name" jsonschema:"minLength=1,maxLength=64"`
+	Email   string   `json:"email" jsonschema:"format=email"`
+	Tags    []string `json:"tags" jsonschema:"uniqueItems=true"`
+	Friends []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name" jsonschema:"pattern=^[A-Za-z ]+$"`
+	} `json:"friends"`
+}
+
+func BenchmarkSchema_Validate(b *testing.B) {
+	schema, err := GenerateBytes(benchUser{Tags: []string{}})
+	if err != nil {
+		b.Fatal("unexpected error:", err)
+	}
+
+	friends := ""
+	for i := 0; i < 20; i++ {
+		if i > 0 {
+			friends += ","
+		}
+		friends += fmt.Sprintf(`{"id": %d, "name": "Friend %c"}`, i, 'A'+i)
+	}
+	doc := []byte(`{
+		"id": 1, "name": "Gopher", "email": "gopher@example.com",
+		"tags": ["go", "json", "schema"],
+		"friends": [` + friends + `]
+	}`)
+
+	b.Run("compiled", func(b *testing.B) {
+		s, err := CompileBytes(schema)
+		if err != nil {
+			b.Fatal("unexpected error:", err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := s.Validate(doc); err != nil {
+				b.Fatal("unexpected error:", err)
+			}
+		}
+	})
+
+	b.Run("gojsonschema", func(b *testing.B) {
+		s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+		if err != nil {
+			b.Fatal("unexpected error:", err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			r, err := s.Validate(gojsonschema.NewBytesLoader(doc))
+			if err != nil || !r.Valid() {
+				b.Fatal("unexpected error:", err, r.Errors())
+			}
+		}
+	})
+}
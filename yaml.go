@@ -0,0 +1,87 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+// GenerateYAML generates JSON Schema from a Go type like Generate and writes it into w as YAML
+// for consumers such as OpenAPI documents and CustomResourceDefinitions of Kubernetes.
+// Keys of mappings are written in the same order as Generate writes keys of objects,
+// so orders by SortedKeys and OrderProperties are kept and outputs are same between runs.
+func GenerateYAML(w io.Writer, v interface{}, opts ...Option) error {
+	var buf bytes.Buffer
+	if err := Generate(&buf, v, opts...); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	doc, err := yamlValue(dec)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot decode schema: %w", err)
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("jsonschema: cannot encode schema as YAML: %w", err)
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// yamlValue decodes the next JSON value of dec into a value for YAML.
+// Objects become yaml.MapSlice to keep orders of their keys.
+func yamlValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			m := yaml.MapSlice{}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := yamlValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				m = append(m, yaml.MapItem{Key: key, Value: v})
+			}
+			// the closing delimiter
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return m, nil
+		case '[':
+			a := []interface{}{}
+			for dec.More() {
+				v, err := yamlValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				a = append(a, v)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return a, nil
+		}
+	case json.Number:
+		if n, err := tok.Int64(); err == nil {
+			return n, nil
+		}
+		return tok.Float64()
+	}
+	return tok, nil
+}
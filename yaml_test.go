@@ -0,0 +1,89 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/tenntenn/jsonschema"
+)
+
+func TestGenerateYAML(t *testing.T) {
+	type Item struct {
+		Price float64  `json:"price" jsonschema:"minimum=0.5"`
+		Name  string   `json:"name" jsonschema:"minLength=1"`
+		Tags  []string `json:"tags,omitempty" jsonschema:"enum=new|sale"`
+	}
+
+	cases := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "alphabetical",
+			opts: []Option{MapKeywords(KeywordRenames{"propertyOrder": ""})},
+			expect: `properties:
+  name:
+    minLength: 1
+    type: string
+  price:
+    minimum: 0.5
+    type: number
+  tags:
+    items:
+      enum:
+      - new
+      - sale
+      type: string
+    type: array
+required:
+- price
+- name
+title: Item
+type: object
+`,
+		},
+		{
+			name: "declaration order",
+			opts: []Option{OrderProperties(PropertiesDeclaration), MapKeywords(KeywordRenames{"title": "", "required": ""})},
+			expect: `properties:
+  price:
+    minimum: 0.5
+    propertyOrder: 0
+    type: number
+  name:
+    minLength: 1
+    propertyOrder: 1
+    type: string
+  tags:
+    items:
+      enum:
+      - new
+      - sale
+      type: string
+    propertyOrder: 2
+    type: array
+type: object
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := GenerateYAML(&buf, Item{Tags: []string{""}}, tt.opts...); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got := buf.String(); got != tt.expect {
+				t.Errorf("want\n%s\nbut got\n%s", tt.expect, got)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateYAML(&buf, nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("want ErrUnsupportedType but got %v", err)
+	}
+}